@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/bot"
+)
+
+var serverURL = flag.String("server", "ws://localhost:8080", "服务器地址")
+var count = flag.Int("n", 1, "机器人数量")
+var strategies = flag.String("strategy", "equity", "策略名称，多个用逗号分隔时依次轮流分配（可选: "+strings.Join(bot.StrategyNames(), ", ")+"）")
+var namePrefix = flag.String("name", "Bot", "机器人名称前缀（后面加编号）")
+var think = flag.Duration("think", 500*time.Millisecond, "每次行动前的思考时间")
+var seed = flag.Uint64("seed", uint64(time.Now().UnixNano()), "随机策略的种子（第 i 个机器人使用 seed+i）")
+
+// main 程序入口
+// 启动若干机器人玩家，像真人玩家一样连接服务器入座，用于补足人数或压力测试
+func main() {
+	flag.Parse()
+
+	names := strings.Split(*strategies, ",")
+	runners := make([]*bot.Runner, 0, *count)
+	for i := 0; i < *count; i++ {
+		strategy, err := bot.NewStrategy(strings.TrimSpace(names[i%len(names)]), *seed+uint64(i))
+		if err != nil {
+			log.Fatalf("创建策略失败: %v", err)
+		}
+		r := bot.NewRunner(bot.RunnerConfig{
+			ServerURL: *serverURL,
+			Name:      fmt.Sprintf("%s%d", *namePrefix, i+1),
+			Seat:      -1,
+			Strategy:  strategy,
+			ThinkTime: *think,
+		})
+		if err := r.Start(); err != nil {
+			log.Fatalf("机器人连接失败: %v", err)
+		}
+		runners = append(runners, r)
+	}
+	fmt.Printf("已启动 %d 个机器人，按 Ctrl+C 退出\n", len(runners))
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	<-sigChan
+
+	fmt.Println("\n正在离开牌桌...")
+	for _, r := range runners {
+		r.Stop()
+		fmt.Printf("  %s 参与了 %d 局\n", r.Name(), r.Hands())
+	}
+}
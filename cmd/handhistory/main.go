@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/fair"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/handhistory"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/replay"
+	uireplay "github.com/wilenwang/just_play/Texas-Holdem/ui/replay"
+)
+
+// 手牌历史转换工具
+//
+//	handhistory export [-format pokerstars|ohh] [-hero 玩家] [-o 输出文件] <历史文件>
+//	handhistory import [-history 历史文件] <OHH 文件>...
+//	handhistory replay [-hand 手牌ID] <历史文件>
+//	handhistory verify <历史文件>
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = runExport(os.Args[2:])
+	case "import":
+		err = runImport(os.Args[2:])
+	case "replay":
+		err = runReplay(os.Args[2:])
+	case "verify":
+		err = runVerify(os.Args[2:])
+	case "help", "-h", "--help":
+		usage()
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "未知的子命令: %s\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// usage 打印用法
+func usage() {
+	fmt.Fprintln(os.Stderr, `用法: handhistory <子命令> [参数]
+
+子命令:
+  export    将保存的手牌历史转换为第三方格式
+  import    将 Open Hand History (OHH) 文件导入手牌历史
+  replay    用游戏引擎逐步重放手牌
+  verify    重放所有手牌并检查结果是否与记录一致（包括可验证洗牌的种子）
+
+示例:
+  handhistory export -hero Alice -o session.txt data/hands.jsonl
+  handhistory export -format ohh -o session.ohh data/hands.jsonl
+  handhistory import -history data/hands.jsonl session.ohh
+  handhistory replay -hand 42 data/hands.jsonl`)
+}
+
+// runExport 执行 export 子命令
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "pokerstars", "输出格式: pokerstars / ohh")
+	hero := fs.String("hero", "", "主视角玩家名称或ID（输出其底牌）")
+	table := fs.String("table", "Texas Holdem", "桌名")
+	maxSeats := fs.Int("max-seats", 9, "最大座位数")
+	output := fs.String("o", "", "输出文件（默认输出到标准输出）")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "用法: handhistory export [参数] <历史文件>\n\n历史文件可以是 -history 指定的路径、单个 .jsonl 分段文件或旧版 JSON 文件\n\n参数:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	hands, err := game.LoadHistoryFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	buf := bufio.NewWriter(w)
+
+	switch *format {
+	case "pokerstars", "ps":
+		err = handhistory.WritePokerStars(buf, hands, handhistory.PokerStarsOptions{
+			Hero:      *hero,
+			TableName: *table,
+			MaxSeats:  *maxSeats,
+		})
+	case "ohh":
+		err = handhistory.WriteOHH(buf, hands, handhistory.OHHOptions{
+			Hero:      *hero,
+			TableName: *table,
+			TableSize: *maxSeats,
+		})
+	default:
+		return fmt.Errorf("未知的输出格式: %s", *format)
+	}
+	if err != nil {
+		return err
+	}
+	if err := buf.Flush(); err != nil {
+		return err
+	}
+
+	if *output != "" {
+		fmt.Fprintf(os.Stderr, "已导出 %d 手牌到 %s\n", len(hands), *output)
+	}
+	return nil
+}
+
+// runImport 执行 import 子命令
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	historyFile := fs.String("history", "data/hands.jsonl", "导入到的手牌历史文件（与服务器 -history 相同）")
+	keepIDs := fs.Bool("keep-ids", false, "保留 OHH 中的局号作为手牌ID（默认重新编号）")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "用法: handhistory import [参数] <OHH 文件>...\n\n参数:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	history, err := game.NewHistoryManager(*historyFile)
+	if err != nil {
+		return err
+	}
+	defer history.Close()
+
+	imported := 0
+	for _, path := range fs.Args() {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		hands, err := handhistory.ReadOHH(bufio.NewReader(file))
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		for _, hand := range hands {
+			if !*keepIDs {
+				hand.HandID = 0
+			}
+			if _, err := history.AddHand(hand); err != nil {
+				return err
+			}
+			imported++
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "已导入 %d 手牌到 %s\n", imported, *historyFile)
+	return nil
+}
+
+// runReplay 执行 replay 子命令
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	handID := fs.Int("hand", 0, "从指定手牌开始（默认最近一手）")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "用法: handhistory replay [参数] <历史文件>\n\n参数:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	hands, err := game.LoadHistoryFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if len(hands) == 0 {
+		return fmt.Errorf("%s 中没有手牌", fs.Arg(0))
+	}
+
+	index := len(hands) - 1
+	if *handID > 0 {
+		index = -1
+		for i, hand := range hands {
+			if hand.HandID == *handID {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return fmt.Errorf("手牌 #%d 不存在", *handID)
+		}
+	}
+
+	// 引擎日志会破坏终端渲染
+	log.SetOutput(io.Discard)
+	return uireplay.Start(hands, index)
+}
+
+// runVerify 执行 verify 子命令
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "用法: handhistory verify <历史文件>")
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	hands, err := game.LoadHistoryFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	log.SetOutput(io.Discard)
+	failed := 0
+	for _, hand := range hands {
+		r, err := replay.New(hand)
+		if err == nil {
+			err = r.Verify()
+		}
+		if err == nil && hand.FairSeed != "" {
+			// 可验证洗牌：种子与承诺相符，且牌序与记录一致
+			var deck []card.Card
+			if deck, err = fair.Verify(hand.FairHash, hand.FairSeed); err == nil && len(hand.Deck) == len(deck) {
+				for i := range deck {
+					if deck[i] != hand.Deck[i] {
+						err = fair.ErrDealMismatch
+						break
+					}
+				}
+			}
+		}
+		if err != nil {
+			failed++
+			fmt.Printf("手牌 #%d: %v\n", hand.HandID, err)
+		}
+	}
+
+	fmt.Printf("共 %d 手，%d 手一致，%d 手不一致\n", len(hands), len(hands)-failed, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
@@ -178,10 +178,7 @@ func simulateRound(engine *game.GameEngine) {
 	actions := 0
 	for actions < activeCount*2 { // 简单限制
 		state = engine.GetState()
-		if state.Stage != game.StagePreFlop &&
-		   state.Stage != game.StageFlop &&
-		   state.Stage != game.StageTurn &&
-		   state.Stage != game.StageRiver {
+		if !state.Stage.IsBetting() {
 			break
 		}
 
@@ -1,16 +1,22 @@
 package main
 
 import (
+	"bufio"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/protocol"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/bot"
 	"github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
 	"github.com/wilenwang/just_play/Texas-Holdem/server/host"
+	hostui "github.com/wilenwang/just_play/Texas-Holdem/ui/host"
 )
 
 // 命令行参数
@@ -19,6 +25,23 @@ var sb = flag.Int("sb", 10, "小盲注金额")
 var bb = flag.Int("bb", 20, "大盲注金额")
 var ante = flag.Int("ante", 0, "前注金额（0表示禁用）")
 var chips = flag.Int("chips", 1000, "初始筹码")
+var timeout = flag.Int("timeout", 30, "行动超时秒数（0表示不限时）")
+var maxTimeouts = flag.Int("max-timeouts", 2, "连续超时多少次后自动离座（0表示禁用）")
+var sitOutOrbits = flag.Int("sitout-orbits", 3, "离座超过多少圈后移出牌桌（0表示禁用）")
+var nextHand = flag.String("next-hand", "all_ready", "下一局开局策略: all_ready / timed / manual（manual 在控制台输入 deal 发牌）")
+var nextDelay = flag.Int("next-delay", 10, "timed 策略下的开局倒计时秒数")
+var skipReady = flag.Bool("skip-ready", true, "timed 策略下所有玩家准备后立即开局")
+var hostToken = flag.String("host-token", "", "房主管理令牌（为空时自动生成）")
+var useTUI = flag.Bool("tui", false, "启动房主控制台界面（日志写入 -log 指定的文件）")
+var logFile = flag.String("log", "server.log", "-tui 模式下的日志文件")
+var historyFile = flag.String("history", "", "手牌历史文件路径前缀，如 data/hands.jsonl（为空时只保存在内存中）")
+var historyMaxMB = flag.Int("history-max-mb", 64, "单个手牌历史文件的最大大小（MB，超过后切换新文件，0表示不限）")
+var historyDaily = flag.Bool("history-daily", true, "手牌历史文件按日期切分")
+var statsFile = flag.String("stats", "", "玩家统计文件路径，如 data/stats.json（为空时只保存在内存中）")
+var bots = flag.String("bots", "", "启动时添加的机器人策略，逗号分隔，如 rule,tag（可选: "+strings.Join(bot.StrategyNames(), ", ")+"）")
+var botThink = flag.Int("bot-think", 1500, "机器人每次行动前的思考时间（毫秒）")
+var noOdds = flag.Bool("no-odds", false, "禁用玩家客户端的胜率面板（正式比赛）")
+var provablyFair = flag.Bool("fair", false, "可验证公平洗牌：开局公布种子摘要，本局结束后公布种子")
 
 func main() {
 	flag.Parse()
@@ -36,15 +59,61 @@ func main() {
 		BigBlind:      *bb,
 		Ante:          *ante,
 		StartingChips: *chips,
-		ActionTimeout: 30,
+		ActionTimeout: *timeout,
+		MaxTimeouts:   *maxTimeouts,
+		SitOutOrbits:  *sitOutOrbits,
 	}
 
 	// 创建完整的游戏服务器（包含消息处理和游戏引擎）
 	server := host.NewServer(config)
 
+	policy, ok := host.ParseNextHandPolicy(*nextHand)
+	if !ok {
+		log.Fatalf("未知的开局策略: %s", *nextHand)
+	}
+	server.SetNextHandConfig(host.NextHandConfig{
+		Policy:        policy,
+		Delay:         *nextDelay,
+		SkipWhenReady: *skipReady,
+	})
+	if *hostToken != "" {
+		server.SetHostToken(*hostToken)
+	}
+	if *provablyFair {
+		server.SetProvablyFair(true)
+	}
+	if *noOdds {
+		server.SetOddsDisabled(true)
+	}
+	if *historyFile != "" {
+		opts := game.DefaultHistoryOptions()
+		opts.MaxFileSize = int64(*historyMaxMB) << 20
+		opts.RotateDaily = *historyDaily
+		if err := server.SetHistoryFile(*historyFile, opts); err != nil {
+			log.Fatalf("加载手牌历史失败: %v", err)
+		}
+	}
+	if *statsFile != "" {
+		if err := server.SetStatsFile(*statsFile); err != nil {
+			log.Fatalf("加载玩家统计失败: %v", err)
+		}
+	}
+
 	// 启动服务器主循环（处理注册、注销、消息路由、广播）
 	go server.Run()
 
+	// 添加机器人需要主循环已启动
+	if *bots != "" {
+		for _, name := range strings.Split(*bots, ",") {
+			cmd := protocol.NewAdminCommand(server.HostToken(), protocol.AdminAddBot)
+			cmd.Strategy = strings.TrimSpace(name)
+			cmd.ThinkTime = *botThink
+			if result := server.ExecuteAdmin(cmd); !result.Success {
+				log.Fatalf("添加机器人失败: %s", result.Message)
+			}
+		}
+	}
+
 	// 注册 WebSocket 路由，使用 host.Server 的 ServeHTTP 处理连接
 	http.Handle("/", server)
 
@@ -52,13 +121,29 @@ func main() {
 	fmt.Printf("  盲注: %d/%d\n", *sb, *bb)
 	fmt.Printf("  前注: %d\n", *ante)
 	fmt.Printf("  初始筹码: %d\n", *chips)
+	fmt.Printf("  行动超时: %d秒 (连续%d次自动离座)\n", *timeout, *maxTimeouts)
+	fmt.Printf("  开局策略: %s\n", policy)
+	if *noOdds {
+		fmt.Printf("  胜率面板: 禁用\n")
+	}
 	fmt.Printf("  服务器端口: %d\n", *port)
+	fmt.Printf("  房主令牌: %s\n", server.HostToken())
 	fmt.Println()
 
+	addr := fmt.Sprintf(":%d", *port)
+
+	// 控制台界面接管终端输入，不再读取控制台指令
+	if *useTUI {
+		runWithTUI(server, addr)
+		return
+	}
+
 	// 启动信号处理
-	go handleSignals()
+	go handleSignals(server)
+
+	// 启动控制台指令读取
+	go readConsole(server)
 
-	addr := fmt.Sprintf(":%d", *port)
 	fmt.Printf("服务器启动成功!\n")
 	fmt.Printf("连接地址: ws://localhost:%d\n", *port)
 	fmt.Println()
@@ -71,12 +156,170 @@ func main() {
 	}
 }
 
-// handleSignals 处理系统信号，优雅关闭服务器
-func handleSignals() {
+// runWithTUI 在后台运行 HTTP 服务，前台显示房主控制台（日志重定向到文件，避免干扰界面）
+func runWithTUI(server *host.Server, addr string) {
+	f, err := os.OpenFile(*logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		log.Fatalf("打开日志文件失败: %v", err)
+	}
+	defer f.Close()
+	log.SetOutput(f)
+
+	go func() {
+		if err := http.ListenAndServe(addr, nil); err != nil {
+			log.Fatal("服务器错误:", err)
+		}
+	}()
+
+	err = hostui.Start(server)
+	server.Shutdown()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// handleSignals 处理系统信号，广播本场最终排名后关闭服务器
+func handleSignals(server *host.Server) {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 	<-sigChan
 
 	fmt.Println("\n正在关闭服务器...")
+	server.Shutdown()
 	os.Exit(0)
 }
+
+// consoleHelp 控制台指令说明
+const consoleHelp = `可用指令:
+  deal                     手动发下一局
+  start                    开始游戏
+  pause / resume           暂停 / 继续
+  kick <玩家>              踢出玩家
+  ban <玩家> [ip]          封禁玩家（加 ip 同时封禁其IP）
+  chips <玩家> <增减量>    调整筹码
+  blinds <小盲> <大盲> [前注]  修改盲注
+  say <消息>               广播消息
+  addbot [策略] [思考毫秒] [名称]  添加机器人
+  rmbot <机器人>           移除机器人
+  end                      结束本场并广播最终排名`
+
+// readConsole 读取控制台指令（发牌及房主管理指令）
+func readConsole(server *host.Server) {
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if fields[0] == "deal" {
+			server.DealNextHand()
+			continue
+		}
+
+		cmd, err := parseConsoleCommand(server.HostToken(), fields, line)
+		if err != nil {
+			fmt.Println(err)
+			fmt.Println(consoleHelp)
+			continue
+		}
+
+		result := server.ExecuteAdmin(cmd)
+		if result.Success {
+			fmt.Printf("[成功] %s\n", result.Message)
+		} else {
+			fmt.Printf("[失败] %s (code=%d)\n", result.Message, result.Code)
+		}
+	}
+}
+
+// parseConsoleCommand 将控制台输入解析为管理指令
+func parseConsoleCommand(token string, fields []string, line string) (*protocol.AdminCommand, error) {
+	var cmd *protocol.AdminCommand
+	switch fields[0] {
+	case "start":
+		cmd = protocol.NewAdminCommand(token, protocol.AdminStart)
+	case "pause":
+		cmd = protocol.NewAdminCommand(token, protocol.AdminPause)
+	case "resume":
+		cmd = protocol.NewAdminCommand(token, protocol.AdminResume)
+	case "kick":
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("用法: kick <玩家>")
+		}
+		cmd = protocol.NewAdminCommand(token, protocol.AdminKick)
+		cmd.TargetID = fields[1]
+	case "ban":
+		if len(fields) < 2 || len(fields) > 3 || (len(fields) == 3 && fields[2] != "ip") {
+			return nil, fmt.Errorf("用法: ban <玩家> [ip]")
+		}
+		cmd = protocol.NewAdminCommand(token, protocol.AdminBan)
+		cmd.TargetID = fields[1]
+		cmd.BanIP = len(fields) == 3
+	case "chips":
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("用法: chips <玩家> <增减量>")
+		}
+		amount, err := strconv.Atoi(strings.TrimPrefix(fields[2], "+"))
+		if err != nil {
+			return nil, fmt.Errorf("无效的筹码数: %s", fields[2])
+		}
+		cmd = protocol.NewAdminCommand(token, protocol.AdminAdjustChips)
+		cmd.TargetID = fields[1]
+		cmd.Amount = amount
+	case "blinds":
+		if len(fields) < 3 || len(fields) > 4 {
+			return nil, fmt.Errorf("用法: blinds <小盲> <大盲> [前注]")
+		}
+		values := make([]int, len(fields)-1)
+		for i, f := range fields[1:] {
+			v, err := strconv.Atoi(f)
+			if err != nil {
+				return nil, fmt.Errorf("无效的数字: %s", f)
+			}
+			values[i] = v
+		}
+		cmd = protocol.NewAdminCommand(token, protocol.AdminSetBlinds)
+		cmd.SmallBlind, cmd.BigBlind = values[0], values[1]
+		if len(values) == 3 {
+			cmd.Ante = values[2]
+		}
+	case "end":
+		cmd = protocol.NewAdminCommand(token, protocol.AdminEndSession)
+	case "say":
+		msg := strings.TrimSpace(strings.TrimPrefix(line, "say"))
+		if msg == "" {
+			return nil, fmt.Errorf("用法: say <消息>")
+		}
+		cmd = protocol.NewAdminCommand(token, protocol.AdminBroadcast)
+		cmd.Message = msg
+	case "addbot":
+		if len(fields) > 4 {
+			return nil, fmt.Errorf("用法: addbot [策略] [思考毫秒] [名称]")
+		}
+		cmd = protocol.NewAdminCommand(token, protocol.AdminAddBot)
+		if len(fields) > 1 {
+			cmd.Strategy = fields[1]
+		}
+		if len(fields) > 2 {
+			think, err := strconv.Atoi(fields[2])
+			if err != nil || think < 0 {
+				return nil, fmt.Errorf("无效的思考时间: %s", fields[2])
+			}
+			cmd.ThinkTime = think
+		}
+		if len(fields) > 3 {
+			cmd.TargetID = fields[3]
+		}
+	case "rmbot":
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("用法: rmbot <机器人>")
+		}
+		cmd = protocol.NewAdminCommand(token, protocol.AdminRemoveBot)
+		cmd.TargetID = fields[1]
+	default:
+		return nil, fmt.Errorf("未知指令: %s", fields[0])
+	}
+	return cmd, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/bot"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/sim"
+)
+
+var strategies = flag.String("strategy", strings.Join(bot.StrategyNames(), ","), "参与的策略，逗号分隔，按座位轮流分配（可选: "+strings.Join(bot.StrategyNames(), ", ")+"）")
+var seats = flag.Int("seats", 6, "每张牌桌的玩家数（2-9）")
+var tables = flag.Int("tables", 8, "牌桌数")
+var hands = flag.Int("hands", 10000, "每张牌桌的手数")
+var workers = flag.Int("workers", 0, "并行的协程数（0表示CPU核数）")
+var seed = flag.Uint64("seed", uint64(time.Now().UnixNano()), "随机种子（相同的种子和参数得到相同的结果）")
+var smallBlind = flag.Int("sb", 10, "小盲注")
+var bigBlind = flag.Int("bb", 20, "大盲注")
+var ante = flag.Int("ante", 0, "前注")
+var chips = flag.Int("chips", 2000, "每局开始时的筹码")
+var verbose = flag.Bool("v", false, "输出引擎日志（非常多，只适合少量手数）")
+
+// main 程序入口
+// 在进程内直接驱动游戏引擎让机器人策略互相对战，不经过网络；
+// 输出各策略的 bb/100 和 95% 置信区间，并在每一局结束后校验筹码守恒，发现违例时以非零状态退出
+func main() {
+	flag.Parse()
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	cfg := sim.Config{
+		Seats:         *seats,
+		Tables:        *tables,
+		HandsPerTable: *hands,
+		Workers:       *workers,
+		Seed:          *seed,
+		SmallBlind:    *smallBlind,
+		BigBlind:      *bigBlind,
+		Ante:          *ante,
+		StartingChips: *chips,
+	}
+	for _, name := range strings.Split(*strategies, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			cfg.Strategies = append(cfg.Strategies, name)
+		}
+	}
+
+	// Ctrl+C 时停止发新牌，输出已完成部分的结果
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("模拟 %d 张牌桌 × %d 手，每桌 %d 人，盲注 %d/%d（前注 %d），筹码 %d，种子 %d\n",
+		cfg.Tables, cfg.HandsPerTable, cfg.Seats, cfg.SmallBlind, cfg.BigBlind, cfg.Ante, cfg.StartingChips, cfg.Seed)
+	report, err := sim.Run(ctx, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "模拟失败: %v\n", err)
+		os.Exit(2)
+	}
+	printReport(report, cfg.BigBlind)
+
+	if report.ViolationCount > 0 {
+		os.Exit(1)
+	}
+}
+
+// printReport 输出各策略的结果和发现的违例
+func printReport(r *sim.Report, bigBlind int) {
+	seconds := max(r.Elapsed.Seconds(), 1e-9)
+	fmt.Printf("\n完成 %d 手，耗时 %v（%.0f 手/秒）\n\n", r.Hands, r.Elapsed.Round(time.Millisecond), float64(r.Hands)/seconds)
+
+	fmt.Printf("%-10s %12s %14s %10s %16s\n", "策略", "手数", "净赢(BB)", "bb/100", "95%置信区间")
+	for _, res := range r.Results {
+		bb100 := res.BBPer100(bigBlind)
+		ci := res.CI95(bigBlind)
+		fmt.Printf("%-10s %12d %14.1f %10.2f %16s\n", res.Name, res.Hands, float64(res.Net)/float64(bigBlind), bb100,
+			fmt.Sprintf("[%.2f, %.2f]", bb100-ci, bb100+ci))
+	}
+
+	if r.Rejected > 0 {
+		fmt.Printf("\n⚠ 有 %d 个修正后的动作被引擎拒绝（已代为弃牌）\n", r.Rejected)
+	}
+	if r.ViolationCount == 0 {
+		fmt.Println("\n✓ 每一局结束后筹码守恒")
+		return
+	}
+	fmt.Printf("\n✗ 发现 %d 个不变量违例（用相同的 -seed 和参数重新运行可复现）:\n", r.ViolationCount)
+	for _, v := range r.Violations {
+		fmt.Printf("\n%v\n%s", v, v.Detail)
+	}
+}
@@ -1,6 +1,9 @@
 package card
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Suit 表示扑克牌的花色
 type Suit int
@@ -123,3 +126,55 @@ func (c Card) IsRed() bool {
 func (c Card) FormatCard() string {
 	return fmt.Sprintf("[%s]", c.String())
 }
+
+// 标准文本表示（如 "Ah"、"Td"），用于手牌历史导入导出
+const (
+	shortRanks = "23456789TJQKA"
+	shortSuits = "cdhs"
+)
+
+// ShortString 返回两字符的标准表示（如 "Ah"、"Td"）
+func (c Card) ShortString() string {
+	if c.Rank < Two || c.Rank > Ace || c.Suit < Clubs || c.Suit > Spades {
+		return "??"
+	}
+	return string(shortRanks[c.Rank-Two]) + string(shortSuits[c.Suit])
+}
+
+// ParseCard 解析牌面字符串，支持 "Ah"、"Td"、"10d" 和 "A♠" 等写法（不区分大小写）
+func ParseCard(s string) (Card, error) {
+	s = strings.TrimSpace(s)
+	runes := []rune(s)
+	if len(runes) < 2 {
+		return Card{}, fmt.Errorf("invalid card %q", s)
+	}
+
+	rankStr := strings.ToUpper(string(runes[:len(runes)-1]))
+	suitStr := strings.ToLower(string(runes[len(runes)-1]))
+
+	var rank Rank
+	if rankStr == "10" {
+		rank = Ten
+	} else if len(rankStr) == 1 {
+		if i := strings.Index(shortRanks, rankStr); i >= 0 {
+			rank = Two + Rank(i)
+		}
+	}
+	if rank == 0 {
+		return Card{}, fmt.Errorf("invalid card rank %q", s)
+	}
+
+	suit := Suit(strings.Index(shortSuits, suitStr))
+	if suit < 0 {
+		for i, name := range suitNames {
+			if name == suitStr {
+				suit = Suit(i)
+			}
+		}
+	}
+	if suit < 0 {
+		return Card{}, fmt.Errorf("invalid card suit %q", s)
+	}
+
+	return NewCard(suit, rank), nil
+}
@@ -0,0 +1,61 @@
+package card
+
+import "testing"
+
+func TestCard_ShortString(t *testing.T) {
+	tests := []struct {
+		card Card
+		want string
+	}{
+		{NewCard(Hearts, Ace), "Ah"},
+		{NewCard(Diamonds, Ten), "Td"},
+		{NewCard(Clubs, Two), "2c"},
+		{NewCard(Spades, King), "Ks"},
+		{Card{}, "??"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.card.ShortString(); got != tt.want {
+			t.Errorf("ShortString(%v) = %q, want %q", tt.card, got, tt.want)
+		}
+	}
+}
+
+func TestParseCard(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Card
+	}{
+		{"Ah", NewCard(Hearts, Ace)},
+		{"td", NewCard(Diamonds, Ten)},
+		{"10d", NewCard(Diamonds, Ten)},
+		{"2C", NewCard(Clubs, Two)},
+		{"A♠", NewCard(Spades, Ace)},
+		{" Qs ", NewCard(Spades, Queen)},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseCard(tt.input)
+		if err != nil {
+			t.Errorf("ParseCard(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseCard(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"", "A", "1h", "Ax", "AAh"} {
+		if _, err := ParseCard(input); err == nil {
+			t.Errorf("ParseCard(%q) should fail", input)
+		}
+	}
+
+	// 所有牌都能往返转换
+	for _, c := range NewDeck().Cards() {
+		got, err := ParseCard(c.ShortString())
+		if err != nil || got != c {
+			t.Errorf("round trip %v -> %q -> %v (%v)", c, c.ShortString(), got, err)
+		}
+	}
+}
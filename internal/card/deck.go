@@ -2,8 +2,6 @@ package card
 
 import (
 	"errors"
-	"math/rand"
-	"time"
 )
 
 // Deck 表示一副扑克牌
@@ -15,6 +13,9 @@ type Deck struct {
 // ErrNoCardsLeft 表示牌组已空，无法继续发牌
 var ErrNoCardsLeft = errors.New("no cards left in deck")
 
+// ErrInvalidDeck 表示指定的牌序不是完整的52张牌
+var ErrInvalidDeck = errors.New("deck must contain 52 distinct cards")
+
 // NewDeck 创建一副新的标准52张牌
 func NewDeck() *Deck {
 	d := &Deck{
@@ -36,17 +37,36 @@ func NewDeckWithSeed(seed int64) *Deck {
 	return d
 }
 
-// Shuffle 使用当前时间作为种子洗牌
+// NewDeckFromCards 按指定顺序创建一副牌（必须是不重复的52张牌），用于复现手牌
+func NewDeckFromCards(cards []Card) (*Deck, error) {
+	if len(cards) != 52 {
+		return nil, ErrInvalidDeck
+	}
+	seen := make(map[Card]bool, 52)
+	for _, c := range cards {
+		if c.Suit < Clubs || c.Suit > Spades || c.Rank < Two || c.Rank > Ace || seen[c] {
+			return nil, ErrInvalidDeck
+		}
+		seen[c] = true
+	}
+	return &Deck{cards: append([]Card(nil), cards...)}, nil
+}
+
+// Shuffle 使用 crypto/rand 洗牌
 func (d *Deck) Shuffle() {
-	d.ShuffleWithSeed(time.Now().UnixNano())
+	d.ShuffleWith(NewCryptoSource())
 }
 
 // ShuffleWithSeed 使用指定种子洗牌
 func (d *Deck) ShuffleWithSeed(seed int64) {
-	r := rand.New(rand.NewSource(seed))
+	d.ShuffleWith(NewSeededSource(seed))
+}
+
+// ShuffleWith 使用指定的随机数来源洗牌
+func (d *Deck) ShuffleWith(src RandomSource) {
 	// Fisher-Yates 洗牌算法
 	for i := len(d.cards) - 1; i > 0; i-- {
-		j := r.Intn(i + 1)
+		j := src.Intn(i + 1)
 		d.cards[i], d.cards[j] = d.cards[j], d.cards[i]
 	}
 	d.index = 0
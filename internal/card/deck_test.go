@@ -110,6 +110,30 @@ func TestDeck_ShuffleWithSeed(t *testing.T) {
 	}
 }
 
+func TestNewDeckFromCards(t *testing.T) {
+	order := NewDeckWithSeed(42).Cards()
+	deck, err := NewDeckFromCards(order)
+	if err != nil {
+		t.Fatalf("NewDeckFromCards failed: %v", err)
+	}
+
+	dealt, _ := deck.DealN(52)
+	for i := range dealt {
+		if dealt[i] != order[i] {
+			t.Fatalf("card %d: expected %v, got %v", i, order[i], dealt[i])
+		}
+	}
+
+	if _, err := NewDeckFromCards(order[:51]); err != ErrInvalidDeck {
+		t.Errorf("short deck: expected ErrInvalidDeck, got %v", err)
+	}
+	dup := append([]Card(nil), order...)
+	dup[1] = dup[0]
+	if _, err := NewDeckFromCards(dup); err != ErrInvalidDeck {
+		t.Errorf("duplicate card: expected ErrInvalidDeck, got %v", err)
+	}
+}
+
 func TestDeck_Empty(t *testing.T) {
 	deck := NewDeck()
 	for i := 0; i < 52; i++ {
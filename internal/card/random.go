@@ -0,0 +1,36 @@
+package card
+
+import (
+	crand "crypto/rand"
+	"math/big"
+	"math/rand"
+)
+
+// RandomSource 洗牌使用的随机数来源
+type RandomSource interface {
+	// Intn 返回 [0, n) 内均匀分布的随机数（n > 0）
+	Intn(n int) int
+}
+
+// cryptoSource 基于 crypto/rand 的随机数来源（生产环境默认）
+type cryptoSource struct{}
+
+// NewCryptoSource 创建基于 crypto/rand 的随机数来源，结果不可预测
+func NewCryptoSource() RandomSource {
+	return cryptoSource{}
+}
+
+// Intn 返回 [0, n) 内的随机数
+func (cryptoSource) Intn(n int) int {
+	v, err := crand.Int(crand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		// 系统随机数不可用时无法安全洗牌
+		panic("card: crypto/rand unavailable: " + err.Error())
+	}
+	return int(v.Int64())
+}
+
+// NewSeededSource 创建使用固定种子的随机数来源，相同种子产生相同的洗牌结果（用于测试）
+func NewSeededSource(seed int64) RandomSource {
+	return rand.New(rand.NewSource(seed))
+}
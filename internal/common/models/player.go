@@ -12,10 +12,11 @@ const (
 	PlayerStatusActive                       // 游戏中
 	PlayerStatusFolded                      // 已弃牌
 	PlayerStatusAllIn                       // 全下
+	PlayerStatusSittingOut                  // 暂时离座（保留座位，不参与发牌）
 )
 
 func (s PlayerStatus) String() string {
-	names := []string{"未入座", "游戏中", "已弃牌", "全下"}
+	names := []string{"未入座", "游戏中", "已弃牌", "全下", "离座"}
 	if int(s) < len(names) {
 		return names[s]
 	}
@@ -68,6 +69,11 @@ type Player struct {
 	IsDealer   bool          // 是否为庄家
 	HasActed   bool          // 是否已完成本轮动作
 
+	// 离座信息
+	SitOutPending bool // 本局结束后离座（手牌进行中申请离座时置位）
+	SitOutHands   int  // 离座期间错过的手牌数
+	LeavePending  bool // 本局结束后离开牌桌（手牌进行中离开时置位）
+
 	// 统计信息
 	HandsPlayed int // 参与的手牌数
 	HandsWon    int // 获胜的手牌数
@@ -89,6 +95,11 @@ func (p *Player) IsActive() bool {
 	return p.Status == PlayerStatusActive
 }
 
+// IsSittingOut 判断玩家是否处于离座状态
+func (p *Player) IsSittingOut() bool {
+	return p.Status == PlayerStatusSittingOut
+}
+
 // CanAct 判断玩家是否可以执行动作
 func (p *Player) CanAct() bool {
 	return p.IsActive() && !p.HasActed
@@ -20,6 +20,14 @@ const (
 	MsgTypeChat         MessageType = "chat"            // 发送聊天消息
 	MsgTypePing         MessageType = "ping"           // 心跳检测
 	MsgTypeReadyForNext MessageType = "ready_for_next" // 玩家准备好下一局
+	MsgTypeSitOut       MessageType = "sit_out"        // 玩家暂时离座
+	MsgTypeSitIn        MessageType = "sit_in"         // 玩家回到座位
+	MsgTypeSeatChange   MessageType = "seat_change"    // 玩家申请换座
+	MsgTypeAdminCommand MessageType = "admin_command"  // 房主管理指令
+	MsgTypeHistoryRequest MessageType = "history_request" // 查询手牌历史
+	MsgTypeStatsRequest   MessageType = "stats_request"   // 查询玩家统计
+	MsgTypeLeaderboardRequest MessageType = "leaderboard_request" // 查询排行榜
+	MsgTypeGraphRequest   MessageType = "graph_request"   // 查询资金曲线
 
 	// 服务器 -> 客户端消息类型
 	MsgTypeJoinAck      MessageType = "join_ack"       // 加入游戏确认
@@ -30,10 +38,41 @@ const (
 	MsgTypePlayerActed  MessageType = "player_acted"   // 玩家动作通知
 	MsgTypeShowdown     MessageType = "showdown"      // 摊牌结果
 	MsgTypePlayerReady  MessageType = "player_ready"  // 玩家准备状态通知
+	MsgTypeWaitlist     MessageType = "waitlist"      // 候补队列状态通知
+	MsgTypeAdminResult  MessageType = "admin_result"  // 房主管理指令执行结果
+	MsgTypeHistoryResponse MessageType = "history_response" // 手牌历史查询结果
+	MsgTypeStatsResponse   MessageType = "stats_response"   // 玩家统计查询结果
+	MsgTypeLeaderboard     MessageType = "leaderboard"      // 排行榜（查询结果或本场结束时的最终排名）
+	MsgTypeGraphResponse   MessageType = "graph_response"   // 资金曲线查询结果
 	MsgTypePong         MessageType = "pong"           // 心跳响应
 	MsgTypeError        MessageType = "error"         // 错误消息
 )
 
+// AdminCommandType 房主管理指令类型
+type AdminCommandType string
+
+const (
+	AdminStart       AdminCommandType = "start"        // 开始下一局
+	AdminPause       AdminCommandType = "pause"        // 暂停游戏
+	AdminResume      AdminCommandType = "resume"       // 继续游戏
+	AdminKick        AdminCommandType = "kick"         // 踢出玩家
+	AdminBan         AdminCommandType = "ban"          // 封禁玩家（按名称，可选同时封禁IP）
+	AdminAdjustChips AdminCommandType = "adjust_chips" // 调整玩家筹码
+	AdminSetBlinds   AdminCommandType = "set_blinds"   // 修改盲注（下一局生效）
+	AdminBroadcast   AdminCommandType = "broadcast"    // 广播系统消息
+	AdminEndSession  AdminCommandType = "end_session"  // 结束本场并广播最终排名
+	AdminAddBot      AdminCommandType = "add_bot"      // 添加服务器内置的机器人玩家
+	AdminRemoveBot   AdminCommandType = "remove_bot"   // 移除机器人玩家
+)
+
+// LeaderboardOrder 排行榜排序方式
+type LeaderboardOrder string
+
+const (
+	LeaderboardByProfit  LeaderboardOrder = "profit"   // 按净盈亏排序
+	LeaderboardByWinRate LeaderboardOrder = "win_rate" // 按胜率排序（至少 10 手）
+)
+
 // BaseMessage 消息基类
 type BaseMessage struct {
 	Type      MessageType `json:"type"`       // 消息类型
@@ -100,6 +139,11 @@ type GameState struct {
 	Players       []PlayerInfo      `json:"players"`          // 所有玩家信息
 	MinRaise      int               `json:"min_raise"`        // 最小加注金额
 	MaxRaise      int               `json:"max_raise"`        // 最大加注金额（当前最高下注+玩家筹码）
+	BigBlind       int              `json:"big_blind,omitempty"` // 当前大盲注金额
+	Paused        bool              `json:"paused"`           // 游戏是否被房主暂停
+	OddsDisabled   bool             `json:"odds_disabled,omitempty"` // 房主是否禁用了客户端的胜率面板
+	FairHash      string            `json:"fair_hash,omitempty"` // 可验证洗牌：本局种子的 SHA-256（开局时公布）
+	FairSeed      string            `json:"fair_seed,omitempty"` // 可验证洗牌：本局种子（本局结束后公布）
 }
 
 // PlayerInfo 玩家公开信息
@@ -113,6 +157,8 @@ type PlayerInfo struct {
 	IsDealer   bool                `json:"is_dealer"`    // 是否为庄家
 	HoleCards  [2]card.Card        `json:"hole_cards"`   // 底牌（仅在摊牌或自己可见时发送）
 	IsSelf     bool                `json:"is_self"`      // 是否是请求者自己
+	SitOutPending bool             `json:"sit_out_pending"` // 是否将在本局结束后离座
+	IsBot      bool                `json:"is_bot,omitempty"` // 是否为服务器内置的机器人
 }
 
 // YourTurn 通知玩家轮到其行动
@@ -146,6 +192,7 @@ type PlayerActed struct {
 	Action   models.ActionType `json:"action"`   // 执行的動作
 	Amount   int             `json:"amount"`    // 下注金额
 	TotalBet int             `json:"total_bet"` // 总下注金额
+	Stage    game.Stage      `json:"stage"`     // 动作所在的阶段
 }
 
 // Showdown 摊牌结果
@@ -177,6 +224,9 @@ type ShowdownPlayerDetail struct {
 	IsWinner   bool         `json:"is_winner"`   // 是否赢家
 	IsFolded   bool         `json:"is_folded"`   // 是否已弃牌
 	ChipsAfter int          `json:"chips_after"` // 结算后筹码
+
+	AllInEquity float64 `json:"all_in_equity,omitempty"` // 河牌前全员全下时的权益
+	EVWonAmount float64 `json:"ev_won_amount,omitempty"` // 按全下时的权益期望赢得的筹码
 }
 
 // ChatMessage 聊天消息
@@ -202,6 +252,129 @@ type PlayerReadyNotify struct {
 	ReadyPlayers []string `json:"ready_players"` // 已准备好的玩家名称列表
 	TotalPlayers int      `json:"total_players"` // 总玩家数
 	AllReady     bool     `json:"all_ready"`     // 是否所有玩家都准备好了
+	Countdown    int      `json:"countdown"`     // 距自动开局的剩余秒数（0 表示无倒计时）
+	Policy       string   `json:"policy"`        // 开局策略：all_ready / timed / manual
+}
+
+// SitOutRequest 玩家暂时离座请求
+type SitOutRequest struct {
+	BaseMessage
+	PlayerID string `json:"player_id"` // 玩家ID
+}
+
+// SitInRequest 玩家回到座位请求
+type SitInRequest struct {
+	BaseMessage
+	PlayerID string `json:"player_id"` // 玩家ID
+}
+
+// SeatChangeRequest 玩家换座请求（手牌进行中申请的在本局结束后生效）
+type SeatChangeRequest struct {
+	BaseMessage
+	PlayerID string `json:"player_id"` // 玩家ID
+	Seat     int    `json:"seat"`      // 目标座位号
+}
+
+// WaitlistNotify 候补队列状态通知（牌桌已满时发送给排队的客户端）
+type WaitlistNotify struct {
+	BaseMessage
+	Position int    `json:"position"` // 当前排队位置（从1开始）
+	Total    int    `json:"total"`    // 排队总人数
+	Message  string `json:"message"`  // 附加消息
+}
+
+// AdminCommand 房主管理指令（需携带房主令牌）
+type AdminCommand struct {
+	BaseMessage
+	Token      string           `json:"token"`                 // 房主令牌
+	Command    AdminCommandType `json:"command"`               // 指令类型
+	TargetID   string           `json:"target_id,omitempty"`   // 目标玩家ID或名称（kick/ban/adjust_chips/remove_bot；add_bot 时为机器人名称，可选）
+	Amount     int              `json:"amount,omitempty"`      // 筹码变化量（adjust_chips，负数表示扣除）
+	SmallBlind int              `json:"small_blind,omitempty"` // 小盲注（set_blinds）
+	BigBlind   int              `json:"big_blind,omitempty"`   // 大盲注（set_blinds）
+	Ante       int              `json:"ante,omitempty"`        // 前注（set_blinds）
+	Message    string           `json:"message,omitempty"`     // 广播内容（broadcast）
+	Strategy   string           `json:"strategy,omitempty"`    // 机器人策略（add_bot，为空时使用 equity）
+	ThinkTime  int              `json:"think_ms,omitempty"`    // 机器人每次行动前的思考时间，毫秒（add_bot，0 表示默认）
+	BanIP      bool             `json:"ban_ip,omitempty"`      // 同时封禁玩家的IP（ban，局域网/本机共用地址时会误伤，回环地址不封禁）
+}
+
+// AdminResult 房主管理指令执行结果
+type AdminResult struct {
+	BaseMessage
+	Command AdminCommandType `json:"command"` // 指令类型
+	Success bool             `json:"success"` // 是否成功
+	Code    int              `json:"code"`    // 错误代码（成功为0）
+	Message string           `json:"message"` // 结果描述
+}
+
+// HistoryRequest 手牌历史查询请求（HandID 大于0时查询指定手牌，否则查询最近 Count 手）
+type HistoryRequest struct {
+	BaseMessage
+	Count  int `json:"count,omitempty"`   // 查询最近的手数
+	HandID int `json:"hand_id,omitempty"` // 指定手牌编号
+}
+
+// HistoryResponse 手牌历史查询结果（未亮牌的对手底牌已隐藏）
+type HistoryResponse struct {
+	BaseMessage
+	Hands []game.HandHistory `json:"hands"` // 手牌记录（按时间从早到晚）
+	Total int                `json:"total"` // 服务器保存的总手数
+}
+
+// StatsRequest 玩家统计查询请求（Names 为空时查询牌桌上的所有玩家）
+type StatsRequest struct {
+	BaseMessage
+	Names []string `json:"names,omitempty"` // 玩家名称
+}
+
+// StatsResponse 玩家统计查询结果（没有统计的玩家不包含在内）
+type StatsResponse struct {
+	BaseMessage
+	Stats []game.PlayerStats `json:"stats"` // 玩家统计
+}
+
+// LeaderboardRequest 排行榜查询请求
+type LeaderboardRequest struct {
+	BaseMessage
+	Order LeaderboardOrder `json:"order,omitempty"` // 排序方式（默认按净盈亏）
+	Limit int              `json:"limit,omitempty"` // 返回的名次数（0 表示全部）
+}
+
+// LeaderboardEntry 排行榜中的一名玩家
+type LeaderboardEntry struct {
+	Rank     int     `json:"rank"`      // 名次
+	Name     string  `json:"name"`      // 玩家名称
+	Net      int     `json:"net"`       // 净盈亏
+	Hands    int     `json:"hands"`     // 参与的手数
+	HandsWon int     `json:"hands_won"` // 获胜的手数
+	WinRate  float64 `json:"win_rate"`  // 胜率（0-1）
+	BB100    float64 `json:"bb_100"`    // 每百手赢得的大盲注数
+}
+
+// Leaderboard 排行榜：本场（服务器启动或上次结束本场以来）和历史累计
+type Leaderboard struct {
+	BaseMessage
+	Order     LeaderboardOrder   `json:"order"`                // 排序方式
+	Session   []LeaderboardEntry `json:"session"`              // 本场排名
+	AllTime   []LeaderboardEntry `json:"all_time"`             // 历史累计排名
+	TopWinner *LeaderboardEntry  `json:"top_winner,omitempty"` // 本场最大赢家
+	TopLoser  *LeaderboardEntry  `json:"top_loser,omitempty"`  // 本场最大输家
+	Final     bool               `json:"final"`                // 是否为本场结束时的最终排名
+}
+
+// GraphRequest 资金曲线查询请求（Name 为空时查询自己）
+type GraphRequest struct {
+	BaseMessage
+	Name  string `json:"name,omitempty"`  // 玩家名称
+	Count int    `json:"count,omitempty"` // 查询最近的手数
+}
+
+// GraphResponse 资金曲线查询结果
+type GraphResponse struct {
+	BaseMessage
+	Name   string            `json:"name"`   // 玩家名称
+	Points []game.GraphPoint `json:"points"` // 玩家参与的手牌（按时间从早到晚）
 }
 
 // Pong 心跳响应
@@ -277,3 +450,79 @@ func NewReadyForNextRequest(playerID string) *ReadyForNextRequest {
 		PlayerID:    playerID,
 	}
 }
+
+// NewSitOutRequest 创建暂时离座请求
+func NewSitOutRequest(playerID string) *SitOutRequest {
+	return &SitOutRequest{
+		BaseMessage: NewBaseMessage(MsgTypeSitOut),
+		PlayerID:    playerID,
+	}
+}
+
+// NewSitInRequest 创建回到座位请求
+func NewSitInRequest(playerID string) *SitInRequest {
+	return &SitInRequest{
+		BaseMessage: NewBaseMessage(MsgTypeSitIn),
+		PlayerID:    playerID,
+	}
+}
+
+// NewSeatChangeRequest 创建换座请求
+func NewSeatChangeRequest(playerID string, seat int) *SeatChangeRequest {
+	return &SeatChangeRequest{
+		BaseMessage: NewBaseMessage(MsgTypeSeatChange),
+		PlayerID:    playerID,
+		Seat:        seat,
+	}
+}
+
+// NewAdminCommand 创建房主管理指令
+func NewAdminCommand(token string, command AdminCommandType) *AdminCommand {
+	return &AdminCommand{
+		BaseMessage: NewBaseMessage(MsgTypeAdminCommand),
+		Token:       token,
+		Command:     command,
+	}
+}
+
+// NewHistoryRequest 创建查询最近 count 手牌的请求
+func NewHistoryRequest(count int) *HistoryRequest {
+	return &HistoryRequest{
+		BaseMessage: NewBaseMessage(MsgTypeHistoryRequest),
+		Count:       count,
+	}
+}
+
+// NewHandHistoryRequest 创建查询指定手牌的请求
+func NewHandHistoryRequest(handID int) *HistoryRequest {
+	return &HistoryRequest{
+		BaseMessage: NewBaseMessage(MsgTypeHistoryRequest),
+		HandID:      handID,
+	}
+}
+
+// NewStatsRequest 创建查询玩家统计的请求（不指定名称时查询牌桌上的所有玩家）
+func NewStatsRequest(names ...string) *StatsRequest {
+	return &StatsRequest{
+		BaseMessage: NewBaseMessage(MsgTypeStatsRequest),
+		Names:       names,
+	}
+}
+
+// NewLeaderboardRequest 创建排行榜查询请求
+func NewLeaderboardRequest(order LeaderboardOrder, limit int) *LeaderboardRequest {
+	return &LeaderboardRequest{
+		BaseMessage: NewBaseMessage(MsgTypeLeaderboardRequest),
+		Order:       order,
+		Limit:       limit,
+	}
+}
+
+// NewGraphRequest 创建资金曲线查询请求（name 为空时查询自己）
+func NewGraphRequest(name string, count int) *GraphRequest {
+	return &GraphRequest{
+		BaseMessage: NewBaseMessage(MsgTypeGraphRequest),
+		Name:        name,
+		Count:       count,
+	}
+}
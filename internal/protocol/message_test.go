@@ -2,6 +2,7 @@ package protocol
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
@@ -279,3 +280,130 @@ func TestPong(t *testing.T) {
 		t.Errorf("Expected server time %d, got %d", now, pong.ServerTime)
 	}
 }
+
+// TestNewSitOutRequest 测试创建离座/回座请求
+func TestNewSitOutRequest(t *testing.T) {
+	out := NewSitOutRequest("player123")
+	if out.Type != MsgTypeSitOut {
+		t.Errorf("Expected type %s, got %s", MsgTypeSitOut, out.Type)
+	}
+	if out.PlayerID != "player123" {
+		t.Errorf("Expected player ID 'player123', got '%s'", out.PlayerID)
+	}
+
+	in := NewSitInRequest("player123")
+	if in.Type != MsgTypeSitIn {
+		t.Errorf("Expected type %s, got %s", MsgTypeSitIn, in.Type)
+	}
+	if in.PlayerID != "player123" {
+		t.Errorf("Expected player ID 'player123', got '%s'", in.PlayerID)
+	}
+}
+
+// TestPlayerReadyNotify_Countdown 测试准备通知携带倒计时和开局策略
+func TestPlayerReadyNotify_Countdown(t *testing.T) {
+	notify := &PlayerReadyNotify{
+		BaseMessage:  NewBaseMessage(MsgTypePlayerReady),
+		ReadyPlayers: []string{"Alice"},
+		TotalPlayers: 3,
+		Countdown:    8,
+		Policy:       "timed",
+	}
+
+	data, err := json.Marshal(notify)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	var decoded PlayerReadyNotify
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+
+	if decoded.Countdown != 8 {
+		t.Errorf("Expected countdown 8, got %d", decoded.Countdown)
+	}
+	if decoded.Policy != "timed" {
+		t.Errorf("Expected policy 'timed', got '%s'", decoded.Policy)
+	}
+}
+
+// TestNewSeatChangeRequest 测试创建换座请求
+func TestNewSeatChangeRequest(t *testing.T) {
+	req := NewSeatChangeRequest("player123", 4)
+
+	if req.Type != MsgTypeSeatChange {
+		t.Errorf("Expected type %s, got %s", MsgTypeSeatChange, req.Type)
+	}
+	if req.PlayerID != "player123" {
+		t.Errorf("Expected player ID 'player123', got '%s'", req.PlayerID)
+	}
+	if req.Seat != 4 {
+		t.Errorf("Expected seat 4, got %d", req.Seat)
+	}
+}
+
+func TestNewAdminCommand(t *testing.T) {
+	cmd := NewAdminCommand("secret", AdminSetBlinds)
+
+	if cmd.Type != MsgTypeAdminCommand {
+		t.Errorf("Expected type %s, got %s", MsgTypeAdminCommand, cmd.Type)
+	}
+	if cmd.Token != "secret" {
+		t.Errorf("Expected token 'secret', got '%s'", cmd.Token)
+	}
+	if cmd.Command != AdminSetBlinds {
+		t.Errorf("Expected command %s, got %s", AdminSetBlinds, cmd.Command)
+	}
+}
+
+func TestNewHistoryRequest(t *testing.T) {
+	req := NewHistoryRequest(20)
+	if req.Type != MsgTypeHistoryRequest {
+		t.Errorf("Expected type %s, got %s", MsgTypeHistoryRequest, req.Type)
+	}
+	if req.Count != 20 || req.HandID != 0 {
+		t.Errorf("Expected count 20 and no hand ID, got %d / %d", req.Count, req.HandID)
+	}
+
+	req = NewHandHistoryRequest(7)
+	if req.HandID != 7 {
+		t.Errorf("Expected hand ID 7, got %d", req.HandID)
+	}
+}
+
+func TestNewStatsRequest(t *testing.T) {
+	req := NewStatsRequest()
+	if req.Type != MsgTypeStatsRequest {
+		t.Errorf("Expected type %s, got %s", MsgTypeStatsRequest, req.Type)
+	}
+	data, _ := json.Marshal(req)
+	if strings.Contains(string(data), "names") {
+		t.Errorf("Expected names to be omitted, got %s", data)
+	}
+
+	req = NewStatsRequest("Alice", "Bob")
+	if len(req.Names) != 2 || req.Names[1] != "Bob" {
+		t.Errorf("Expected names [Alice Bob], got %v", req.Names)
+	}
+}
+
+func TestNewLeaderboardRequest(t *testing.T) {
+	req := NewLeaderboardRequest(LeaderboardByWinRate, 10)
+	if req.Type != MsgTypeLeaderboardRequest {
+		t.Errorf("Expected type %s, got %s", MsgTypeLeaderboardRequest, req.Type)
+	}
+	if req.Order != LeaderboardByWinRate || req.Limit != 10 {
+		t.Errorf("Expected win_rate/10, got %s/%d", req.Order, req.Limit)
+	}
+}
+
+func TestNewGraphRequest(t *testing.T) {
+	req := NewGraphRequest("", 200)
+	if req.Type != MsgTypeGraphRequest {
+		t.Errorf("Expected type %s, got %s", MsgTypeGraphRequest, req.Type)
+	}
+	if req.Name != "" || req.Count != 200 {
+		t.Errorf("Expected empty name and count 200, got %q / %d", req.Name, req.Count)
+	}
+}
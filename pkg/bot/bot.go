@@ -0,0 +1,205 @@
+// Package bot 提供机器人玩家：策略接口、几种内置策略，以及像真人玩家一样通过 WebSocket 客户端入座的 Runner
+//
+// 策略只看到玩家视角的 protocol.GameState（自己的底牌、公共牌、各玩家筹码和下注）和 protocol.YourTurn，
+// 与真人客户端收到的信息相同。Situation 把这些信息整理成下注决策常用的数值，并负责把决定修正为合法动作。
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/protocol"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+)
+
+// ==================== 错误定义 ====================
+var (
+	ErrUnknownStrategy = errors.New("未知的机器人策略")
+	ErrNotSeated       = errors.New("游戏状态中没有机器人自己")
+)
+
+// ==================== 策略接口 ====================
+
+// Strategy 机器人策略：根据玩家视角的游戏状态决定行动
+// 同一个策略实例只供一个机器人使用（策略可以保存随机数源或对局记录）
+type Strategy interface {
+	// Name 返回策略名称
+	Name() string
+	// Decide 轮到机器人行动时返回决定（不合法的决定由调用方通过 Situation.Legalize 修正）
+	Decide(state *protocol.GameState, turn *protocol.YourTurn) Decision
+}
+
+// Observer 需要观察对手动作的策略可以实现的接口：运行方在每个玩家（包括机器人自己）行动后调用 Observe
+// 运行方保证 Observe 和 Decide 不会同时调用
+type Observer interface {
+	Observe(acted *protocol.PlayerActed)
+}
+
+// Decision 机器人的行动决定
+type Decision struct {
+	Action models.ActionType // 动作
+	Amount int               // 加注时本轮的下注总额（其他动作忽略）
+}
+
+// String 返回决定的可读形式
+func (d Decision) String() string {
+	if d.Action == models.ActionRaise {
+		return fmt.Sprintf("%s %d", d.Action, d.Amount)
+	}
+	return d.Action.String()
+}
+
+// strategyFactories 内置策略（按名称），seed 用于需要随机数的策略
+var strategyFactories = map[string]func(seed uint64) Strategy{
+	"random":  func(seed uint64) Strategy { return NewRandom(seed) },
+	"station": func(uint64) Strategy { return NewCallingStation() },
+	"tag":     func(uint64) Strategy { return NewTightAggressive() },
+	"rule":    func(uint64) Strategy { return NewRuleBased() },
+	"equity":  func(seed uint64) Strategy { return NewEquityBased(seed) },
+}
+
+// NewStrategy 按名称创建内置策略
+func NewStrategy(name string, seed uint64) (Strategy, error) {
+	factory, ok := strategyFactories[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s（可选: %s）", ErrUnknownStrategy, name, strings.Join(StrategyNames(), ", "))
+	}
+	return factory(seed), nil
+}
+
+// StrategyNames 返回所有内置策略的名称（按字母顺序）
+func StrategyNames() []string {
+	names := make([]string, 0, len(strategyFactories))
+	for name := range strategyFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ==================== 局面 ====================
+
+// Situation 从机器人视角整理的当前局面
+type Situation struct {
+	State      *protocol.GameState // 原始状态
+	Self       protocol.PlayerInfo // 机器人自己
+	Hole       [2]card.Card        // 自己的底牌
+	Board      []card.Card         // 已发出的公共牌
+	Stage      game.Stage          // 当前阶段
+	Pot        int                 // 底池（含本轮下注）
+	BigBlind   int                 // 大盲注（状态中没有时按 1 处理）
+	CurrentBet int                 // 本轮当前最高下注
+	ToCall     int                 // 跟注需要补的筹码（不超过剩余筹码）
+	MinRaise   int                 // 加注的最小下注总额
+	MaxRaise   int                 // 加注的最大下注总额（全下）
+	Opponents  int                 // 未弃牌的对手数
+}
+
+// NewSituation 由游戏状态和行动通知创建局面（下注数值以行动通知为准，避免状态推送滞后）
+func NewSituation(state *protocol.GameState, turn *protocol.YourTurn) (*Situation, error) {
+	s := &Situation{State: state, Stage: state.Stage, Pot: state.Pot, BigBlind: max(state.BigBlind, 1)}
+	found := false
+	for _, p := range state.Players {
+		if p.IsSelf {
+			s.Self = p
+			found = true
+		} else if p.Status == models.PlayerStatusActive || p.Status == models.PlayerStatusAllIn {
+			s.Opponents++
+		}
+	}
+	if !found {
+		return nil, ErrNotSeated
+	}
+	s.Hole = s.Self.HoleCards
+	for _, c := range state.CommunityCards {
+		if c.Rank != 0 {
+			s.Board = append(s.Board, c)
+		}
+	}
+
+	s.CurrentBet = state.CurrentBet
+	if turn != nil {
+		// 行动通知中的最小动作就是本轮需补的差额，由它推出自己本轮已下注的筹码（状态可能还停留在上一轮）
+		s.CurrentBet = turn.CurrentBet
+		s.Self.CurrentBet = max(turn.CurrentBet-turn.MinAction, 0)
+	}
+	s.ToCall = min(max(s.CurrentBet-s.Self.CurrentBet, 0), s.Self.Chips)
+	s.MaxRaise = s.Self.CurrentBet + s.Self.Chips
+	s.MinRaise = min(max(s.CurrentBet*2, s.CurrentBet+s.BigBlind), s.MaxRaise)
+	return s, nil
+}
+
+// PotOdds 跟注所需的最低权益：跟注额 /（底池 + 跟注额），无需跟注时为 0
+func (s *Situation) PotOdds() float64 {
+	if s.ToCall == 0 {
+		return 0
+	}
+	return float64(s.ToCall) / float64(s.Pot+s.ToCall)
+}
+
+// CanRaise 是否还有筹码在跟注之后加注
+func (s *Situation) CanRaise() bool {
+	return s.Self.Chips > s.ToCall
+}
+
+// CheckOrFold 能过牌就过牌，否则弃牌
+func (s *Situation) CheckOrFold() Decision {
+	if s.ToCall == 0 {
+		return Decision{Action: models.ActionCheck}
+	}
+	return Decision{Action: models.ActionFold}
+}
+
+// CheckOrCall 能过牌就过牌，否则跟注（筹码不够时全下）
+func (s *Situation) CheckOrCall() Decision {
+	switch {
+	case s.ToCall == 0:
+		return Decision{Action: models.ActionCheck}
+	case s.ToCall >= s.Self.Chips:
+		return Decision{Action: models.ActionAllIn}
+	}
+	return Decision{Action: models.ActionCall}
+}
+
+// RaiseTo 加注到指定的下注总额（按最小、最大加注修正，达到全部筹码时全下；不能加注时跟注）
+func (s *Situation) RaiseTo(amount int) Decision {
+	if !s.CanRaise() {
+		return s.CheckOrCall()
+	}
+	amount = max(amount, s.MinRaise)
+	if amount >= s.MaxRaise {
+		return Decision{Action: models.ActionAllIn}
+	}
+	return Decision{Action: models.ActionRaise, Amount: amount}
+}
+
+// BetPot 下注或加注底池的一定比例（先跟注，再加上跟注后底池的 fraction 倍）
+func (s *Situation) BetPot(fraction float64) Decision {
+	callTo := s.Self.CurrentBet + s.ToCall
+	return s.RaiseTo(callTo + int(fraction*float64(s.Pot+s.ToCall)))
+}
+
+// Legalize 把任意决定修正为当前合法的动作
+func (s *Situation) Legalize(d Decision) Decision {
+	switch d.Action {
+	case models.ActionFold:
+		return s.CheckOrFold()
+	case models.ActionCheck, models.ActionCall:
+		if d.Action == models.ActionCheck && s.ToCall > 0 {
+			return Decision{Action: models.ActionFold}
+		}
+		return s.CheckOrCall()
+	case models.ActionRaise:
+		return s.RaiseTo(d.Amount)
+	case models.ActionAllIn:
+		if s.Self.Chips == 0 {
+			return s.CheckOrCall()
+		}
+		return d
+	}
+	return s.CheckOrFold()
+}
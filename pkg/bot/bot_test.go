@@ -0,0 +1,192 @@
+package bot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/protocol"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+)
+
+// parseCards 解析空格分隔的牌，如 "Ah Kd"
+func parseCards(t *testing.T, s string) []card.Card {
+	t.Helper()
+	var cards []card.Card
+	for _, f := range strings.Fields(s) {
+		c, err := card.ParseCard(f)
+		if err != nil {
+			t.Fatalf("parse card %q: %v", f, err)
+		}
+		cards = append(cards, c)
+	}
+	return cards
+}
+
+// testState 构造两人牌桌上机器人视角的状态：机器人已下注 selfBet，对手下注 currentBet
+func testState(t *testing.T, stage game.Stage, hole, board string, pot, currentBet, selfBet, chips int) (*protocol.GameState, *protocol.YourTurn) {
+	t.Helper()
+	state := &protocol.GameState{
+		Stage:      stage,
+		Pot:        pot,
+		CurrentBet: currentBet,
+		BigBlind:   20,
+		Players: []protocol.PlayerInfo{
+			{ID: "opp", Name: "Opp", Chips: 1000, CurrentBet: currentBet, Status: models.PlayerStatusActive},
+			{ID: "bot", Name: "Bot", Chips: chips, CurrentBet: selfBet, Status: models.PlayerStatusActive, IsSelf: true},
+		},
+	}
+	h := parseCards(t, hole)
+	state.Players[1].HoleCards = [2]card.Card{h[0], h[1]}
+	copy(state.CommunityCards[:], parseCards(t, board))
+	return state, &protocol.YourTurn{PlayerID: "bot", CurrentBet: currentBet, MinAction: currentBet - selfBet}
+}
+
+func TestSituation(t *testing.T) {
+	state, turn := testState(t, game.StageFlop, "Ah Kd", "2c 7d Jh", 200, 50, 0, 1000)
+	s, err := NewSituation(state, turn)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(s.Board) != 3 || s.ToCall != 50 || s.MinRaise != 100 || s.MaxRaise != 1000 || s.Opponents != 1 {
+		t.Errorf("unexpected situation: %+v", s)
+	}
+	if odds := s.PotOdds(); odds != 0.2 {
+		t.Errorf("expected pot odds 0.2, got %v", odds)
+	}
+
+	// 跟注 50 后底池 250，再加半个底池：加注到 50 + 125
+	if d := s.BetPot(0.5); d.Action != models.ActionRaise || d.Amount != 175 {
+		t.Errorf("expected raise to 175, got %v", d)
+	}
+
+	tests := []struct {
+		in, want Decision
+	}{
+		{Decision{Action: models.ActionCheck}, Decision{Action: models.ActionFold}},
+		{Decision{Action: models.ActionRaise, Amount: 60}, Decision{Action: models.ActionRaise, Amount: 100}},
+		{Decision{Action: models.ActionRaise, Amount: 5000}, Decision{Action: models.ActionAllIn}},
+		{Decision{Action: models.ActionCall}, Decision{Action: models.ActionCall}},
+	}
+	for _, tt := range tests {
+		if got := s.Legalize(tt.in); got != tt.want {
+			t.Errorf("Legalize(%v): expected %v, got %v", tt.in, tt.want, got)
+		}
+	}
+
+	// 筹码不够跟注时只能全下，不能加注
+	state, turn = testState(t, game.StageFlop, "Ah Kd", "2c 7d Jh", 200, 50, 0, 30)
+	s, _ = NewSituation(state, turn)
+	if s.ToCall != 30 || s.CanRaise() {
+		t.Errorf("expected a short stack to only call 30, got %+v", s)
+	}
+	if d := s.Legalize(Decision{Action: models.ActionRaise, Amount: 500}); d.Action != models.ActionAllIn {
+		t.Errorf("expected all-in, got %v", d)
+	}
+
+	// 状态还停留在上一轮（自己下注 40）时，以行动通知的需补差额为准
+	state, turn = testState(t, game.StageTurn, "Ah Kd", "2c 7d Jh 4s", 200, 20, 40, 1000)
+	turn.MinAction = 20
+	s, _ = NewSituation(state, turn)
+	if s.ToCall != 20 || s.CheckOrFold().Action != models.ActionFold {
+		t.Errorf("expected to call 20 despite a stale state, got %+v", s)
+	}
+
+	state.Players[1].IsSelf = false
+	if _, err := NewSituation(state, turn); !errors.Is(err, ErrNotSeated) {
+		t.Errorf("expected ErrNotSeated, got %v", err)
+	}
+}
+
+func TestStrategies(t *testing.T) {
+	tests := []struct {
+		strategy   string
+		stage      game.Stage
+		hole       string
+		board      string
+		pot        int
+		currentBet int
+		selfBet    int
+		want       models.ActionType
+	}{
+		// 跟注站面对大额下注也跟注
+		{"station", game.StageRiver, "7c 2d", "Ah Kd 9s 4h 3c", 100, 500, 0, models.ActionCall},
+		{"station", game.StageFlop, "7c 2d", "Ah Kd 9s", 100, 0, 0, models.ActionCheck},
+		// 紧凶：AA 开局加注，72o 面对加注弃牌，翻牌后暗三条下注
+		{"tag", game.StagePreFlop, "Ac Ad", "", 30, 20, 0, models.ActionRaise},
+		{"tag", game.StagePreFlop, "7c 2d", "", 90, 60, 20, models.ActionFold},
+		{"tag", game.StageFlop, "9c 9d", "9s 4h 2c", 120, 0, 0, models.ActionRaise},
+		{"tag", game.StageFlop, "7c 2d", "Ah Kd 9s", 120, 100, 0, models.ActionFold},
+		// 规则：AKs 加注，同花听牌在赔率合适时跟注，空气牌弃牌
+		{"rule", game.StagePreFlop, "Ah Kh", "", 30, 20, 0, models.ActionRaise},
+		{"rule", game.StageFlop, "Ah 5h", "Kh 9h 2c", 200, 50, 0, models.ActionCall},
+		{"rule", game.StageTurn, "7c 2d", "Ah Kd 9s 4h", 200, 150, 0, models.ActionFold},
+		// 权益：AA 开局加注，空气牌面对河牌大额下注弃牌，坚果牌下注，小额下注时同花听牌跟注
+		{"equity", game.StagePreFlop, "Ac Ad", "", 30, 20, 0, models.ActionRaise},
+		{"equity", game.StageRiver, "7c 2d", "Ah Kd 9s 4h 3s", 200, 200, 0, models.ActionFold},
+		{"equity", game.StageRiver, "As Ks", "Qs Js Ts 2d 3c", 200, 0, 0, models.ActionRaise},
+		{"equity", game.StageFlop, "Ah 5h", "Kh 9h 2c", 200, 50, 0, models.ActionCall},
+	}
+
+	for _, tt := range tests {
+		strategy, err := NewStrategy(tt.strategy, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		state, turn := testState(t, tt.stage, tt.hole, tt.board, tt.pot, tt.currentBet, tt.selfBet, 1000)
+		if d := strategy.Decide(state, turn); d.Action != tt.want {
+			t.Errorf("%s %s [%s]: expected %v, got %v", tt.strategy, tt.hole, tt.board, tt.want, d)
+		}
+	}
+}
+
+func TestRandom_Legal(t *testing.T) {
+	// 随机策略的决定都是合法的，相同种子得到相同的序列
+	a, b := NewRandom(7), NewRandom(7)
+	state, turn := testState(t, game.StageTurn, "Ah Kd", "2c 7d Jh 4s", 300, 80, 20, 500)
+	s, _ := NewSituation(state, turn)
+	seen := make(map[models.ActionType]bool)
+	for i := 0; i < 200; i++ {
+		d := a.Decide(state, turn)
+		if d != b.Decide(state, turn) {
+			t.Fatal("expected the same sequence for the same seed")
+		}
+		if legal := s.Legalize(d); legal != d {
+			t.Errorf("expected a legal decision, got %v (legal: %v)", d, legal)
+		}
+		seen[d.Action] = true
+	}
+	if len(seen) < 3 {
+		t.Errorf("expected a mix of actions, got %v", seen)
+	}
+}
+
+func TestChenScore(t *testing.T) {
+	tests := map[string]float64{
+		"Ac Ad": 20,
+		"Ah Kh": 12,
+		"Ts 9s": 8, // 5 + 2（同花）+ 1（相邻且小于 Q）
+		"2c 2d": 5,
+		"7c 2d": -1,
+	}
+	for hole, want := range tests {
+		h := parseCards(t, hole)
+		if got := chenScore([2]card.Card{h[0], h[1]}); got != want {
+			t.Errorf("%s: expected %v, got %v", hole, want, got)
+		}
+	}
+}
+
+func TestNewStrategy(t *testing.T) {
+	for _, name := range StrategyNames() {
+		s, err := NewStrategy(name, 1)
+		if err != nil || s.Name() != name {
+			t.Errorf("%s: expected strategy, got %v, %v", name, s, err)
+		}
+	}
+	if _, err := NewStrategy("nope", 1); !errors.Is(err, ErrUnknownStrategy) {
+		t.Errorf("expected ErrUnknownStrategy, got %v", err)
+	}
+}
@@ -0,0 +1,350 @@
+package bot
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"sync"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/protocol"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/equity"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/evaluator"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+)
+
+// ==================== 权益策略 ====================
+
+// 权益策略参数
+const (
+	equityIterations = 2000  // 每次决策的蒙特卡洛模拟次数
+	equityExactLimit = 20000 // 评估次数不超过该值时精确枚举（如河牌圈）
+
+	priorWeight     = 10   // 对手统计的先验权重（相当于已观察到的动作次数）
+	priorVPIP       = 0.35 // 翻牌前主动入池比例的先验值
+	priorPFR        = 0.15 // 翻牌前加注比例的先验值
+	priorAggression = 0.35 // 翻牌后下注或加注比例的先验值
+	minRangeShare   = 0.05 // 推断的范围至少包含的起手牌比例
+
+	bluffFrequency      = 0.12 // 单挑时没人下注，用弱牌诈唬的频率
+	semiBluffFrequency  = 0.35 // 翻牌和转牌圈没人下注，用听牌半诈唬的频率
+	raiseBluffFrequency = 0.08 // 单挑面对下注时用听牌加注半诈唬的频率
+)
+
+// EquityBased 权益策略：由对手的动作次数推断每个对手的底牌范围，用蒙特卡洛计算自己对这些范围的权益，
+// 与底池赔率比较后决定弃牌、跟注或加注；强牌按权益选择下注大小，弱牌和听牌按一定频率诈唬
+type EquityBased struct {
+	rng       *rand.Rand
+	selfID    string                    // 自己的玩家ID（第一次决策时记录，用于忽略自己的动作）
+	opponents map[string]*opponentStats // 各对手的动作次数（按玩家ID）
+}
+
+// NewEquityBased 创建权益策略（种子决定模拟和诈唬的随机数）
+func NewEquityBased(seed uint64) *EquityBased {
+	return &EquityBased{
+		rng:       rand.New(rand.NewPCG(seed, 0)),
+		opponents: make(map[string]*opponentStats),
+	}
+}
+
+// Name 返回策略名称
+func (e *EquityBased) Name() string { return "equity" }
+
+// Observe 累计对手的动作次数
+func (e *EquityBased) Observe(acted *protocol.PlayerActed) {
+	if acted.PlayerID == e.selfID {
+		return
+	}
+	e.opponent(acted.PlayerID).record(acted.Stage, acted.Action)
+}
+
+// Decide 计算对推断范围的权益，再按权益和底池赔率决定
+func (e *EquityBased) Decide(state *protocol.GameState, turn *protocol.YourTurn) Decision {
+	s, err := NewSituation(state, turn)
+	if err != nil {
+		return Decision{Action: models.ActionFold}
+	}
+	e.selfID = s.Self.ID
+	return e.choose(s, e.equity(s))
+}
+
+// choose 按权益决定：超过价值线时加注（面对大额下注时只跟注），没人下注时按频率诈唬，
+// 面对下注时权益够支付底池赔率就跟注，单挑时偶尔用听牌加注
+func (e *EquityBased) choose(s *Situation, eq float64) Decision {
+	n := max(s.Opponents, 1)
+	valueLine := valueThreshold(n)
+	postflop := s.Stage == game.StageFlop || s.Stage == game.StageTurn
+
+	if eq >= valueLine && s.CanRaise() {
+		if s.ToCall > s.Pot/2 && eq < valueLine+0.15 {
+			return s.CheckOrCall()
+		}
+		return e.valueBet(s, eq, valueLine)
+	}
+
+	if s.ToCall == 0 {
+		if s.Stage == game.StagePreFlop {
+			return s.CheckOrCall()
+		}
+		switch {
+		case postflop && eq >= 0.7/float64(n+1) && e.rng.Float64() < semiBluffFrequency:
+			return s.BetPot(0.6)
+		case n == 1 && e.rng.Float64() < bluffFrequency:
+			return s.BetPot(0.6)
+		}
+		return s.CheckOrCall()
+	}
+
+	// 跟注全下或大半筹码时要求更高的余量
+	margin := 0.02
+	if s.ToCall*2 >= s.Self.Chips {
+		margin = 0.06
+	}
+	required := s.PotOdds() + margin
+	if s.Stage == game.StagePreFlop && s.ToCall*5 <= s.Self.Chips {
+		// 翻牌前跟注额不大时还有后面几轮的下注可赢，权益达到平均份额即可入池
+		required = min(required, 1.1/float64(n+1))
+	}
+	if eq >= required {
+		return s.CheckOrCall()
+	}
+	if postflop && n == 1 && eq >= 0.3 && s.CanRaise() && e.rng.Float64() < raiseBluffFrequency {
+		return s.BetPot(0.75)
+	}
+	return s.CheckOrFold()
+}
+
+// valueBet 价值下注：翻牌前按大盲或当前下注的倍数加注，翻牌后按权益超出价值线的程度下注半个到一个底池
+func (e *EquityBased) valueBet(s *Situation, eq, valueLine float64) Decision {
+	if s.Stage == game.StagePreFlop {
+		if s.CurrentBet <= s.BigBlind {
+			// 每个跛入的玩家多加一个大盲
+			limpers := max(s.Pot-s.BigBlind*3/2, 0)
+			return s.RaiseTo(3*s.BigBlind + limpers)
+		}
+		return s.RaiseTo(3 * s.CurrentBet)
+	}
+	fraction := 0.5 + 0.5*(eq-valueLine)/(1-valueLine)
+	return s.BetPot(fraction)
+}
+
+// valueThreshold 价值下注需要的权益：单挑时 62%，对手越多要求越低（但始终高于平均分得的份额）
+func valueThreshold(opponents int) float64 {
+	fair := 1 / float64(opponents+1)
+	return min(0.62, 1.25*fair+0.12)
+}
+
+// equity 计算自己对所有未弃牌对手推断范围的权益（计算失败时按平均份额处理）
+func (e *EquityBased) equity(s *Situation) float64 {
+	known := append([]card.Card{s.Hole[0], s.Hole[1]}, s.Board...)
+	ranges := []*equity.Range{equity.NewRange(s.Hole)}
+	for _, p := range s.State.Players {
+		if p.IsSelf || (p.Status != models.PlayerStatusActive && p.Status != models.PlayerStatusAllIn) {
+			continue
+		}
+		ranges = append(ranges, equity.NewRange(e.inferRange(s, p, known)...))
+	}
+	if len(ranges) < 2 {
+		return 1
+	}
+
+	r, err := equity.CalculateRanges(ranges, s.Board, nil, equity.Options{
+		Iterations: equityIterations,
+		Workers:    1,
+		ExactLimit: equityExactLimit,
+		Seed:       e.rng.Uint64() | 1,
+	})
+	if err != nil {
+		return 1 / float64(len(ranges))
+	}
+	return r.Hands[0].Equity
+}
+
+// inferRange 推断对手的底牌范围（去掉已知的牌）：
+// 翻牌前下注超过大盲的对手取最强的“加注比例”部分，其余取“入池比例”部分；
+// 翻牌后从入池范围出发，本轮下注或加注过的对手只保留按当前牌力排序的前“激进比例”部分
+func (e *EquityBased) inferRange(s *Situation, p protocol.PlayerInfo, known []card.Card) [][2]card.Card {
+	stats := e.opponent(p.ID)
+	if s.Stage == game.StagePreFlop {
+		if p.CurrentBet > s.BigBlind {
+			return topRange(stats.pfrRate()*1.5, known)
+		}
+		return topRange(stats.vpipRate(), known)
+	}
+
+	combos := topRange(stats.vpipRate(), known)
+	if p.CurrentBet == 0 {
+		return combos
+	}
+	keep := min(max(2*stats.aggressionRate(), 0.3), 0.9)
+	return strongest(combos, s.Board, keep)
+}
+
+// opponent 返回对手的动作统计（第一次见到时创建）
+func (e *EquityBased) opponent(id string) *opponentStats {
+	o := e.opponents[id]
+	if o == nil {
+		o = &opponentStats{}
+		e.opponents[id] = o
+	}
+	return o
+}
+
+// ==================== 对手统计 ====================
+
+// opponentStats 对手的动作次数
+type opponentStats struct {
+	preflop    int // 翻牌前主动决定的次数（弃牌、跟注、加注或全下）
+	vpip       int // 其中主动入池（跟注、加注或全下）的次数
+	pfr        int // 其中加注或全下的次数
+	postflop   int // 翻牌后的动作次数
+	aggressive int // 其中下注、加注或全下的次数
+}
+
+// record 记录一次动作（翻牌前过牌只会是大盲的选择权，不算主动决定）
+func (o *opponentStats) record(stage game.Stage, action models.ActionType) {
+	aggressive := action == models.ActionRaise || action == models.ActionAllIn
+	if stage == game.StagePreFlop {
+		if action == models.ActionCheck {
+			return
+		}
+		o.preflop++
+		if action != models.ActionFold {
+			o.vpip++
+		}
+		if aggressive {
+			o.pfr++
+		}
+		return
+	}
+	o.postflop++
+	if aggressive {
+		o.aggressive++
+	}
+}
+
+// vpipRate 翻牌前主动入池的比例（按先验值平滑）
+func (o *opponentStats) vpipRate() float64 {
+	return smoothRate(o.vpip, o.preflop, priorVPIP)
+}
+
+// pfrRate 翻牌前加注的比例（按先验值平滑）
+func (o *opponentStats) pfrRate() float64 {
+	return smoothRate(o.pfr, o.preflop, priorPFR)
+}
+
+// aggressionRate 翻牌后下注或加注的比例（按先验值平滑）
+func (o *opponentStats) aggressionRate() float64 {
+	return smoothRate(o.aggressive, o.postflop, priorAggression)
+}
+
+// smoothRate 把先验值当作 priorWeight 次观察加入后的比例
+func smoothRate(hits, total int, prior float64) float64 {
+	return (float64(hits) + prior*priorWeight) / (float64(total) + priorWeight)
+}
+
+// ==================== 起手牌排序 ====================
+
+var (
+	preflopOnce    sync.Once
+	preflopClasses [][][2]card.Card // 169 类起手牌按对随机手牌的权益从高到低排列，每类包含其全部组合
+)
+
+// preflopRanking 返回按强度排列的起手牌类别（第一次调用时计算，结果固定）
+func preflopRanking() [][][2]card.Card {
+	preflopOnce.Do(func() {
+		type class struct {
+			combos [][2]card.Card
+			equity float64
+		}
+		byKey := make(map[[3]int]*class)
+		var classes []*class
+		for _, c := range equity.RandomRange().Combos() {
+			high, low := c[0].Rank, c[1].Rank
+			if low > high {
+				high, low = low, high
+			}
+			suited := 0
+			if c[0].Suit == c[1].Suit {
+				suited = 1
+			}
+			key := [3]int{int(high), int(low), suited}
+			if byKey[key] == nil {
+				byKey[key] = &class{}
+				classes = append(classes, byKey[key])
+			}
+			byKey[key].combos = append(byKey[key].combos, c)
+		}
+
+		random := equity.RandomRange()
+		for _, c := range classes {
+			r, err := equity.CalculateRanges([]*equity.Range{equity.NewRange(c.combos[0]), random}, nil, nil,
+				equity.Options{Iterations: 3000, Workers: 1, ExactLimit: -1, Seed: 1})
+			if err == nil {
+				c.equity = r.Hands[0].Equity
+			}
+		}
+		slices.SortStableFunc(classes, func(a, b *class) int { return cmp.Compare(b.equity, a.equity) })
+
+		preflopClasses = make([][][2]card.Card, len(classes))
+		for i, c := range classes {
+			preflopClasses[i] = c.combos
+		}
+	})
+	return preflopClasses
+}
+
+// topRange 最强的 share 比例的起手牌（按类别整类加入，去掉与已知牌冲突的组合）
+func topRange(share float64, known []card.Card) [][2]card.Card {
+	want := int(min(max(share, minRangeShare), 1) * 1326)
+	var out [][2]card.Card
+	total := 0
+	for _, class := range preflopRanking() {
+		if total >= want {
+			break
+		}
+		total += len(class)
+		for _, c := range class {
+			if !conflicts(c, known) {
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
+
+// strongest 按与公共牌组成的牌力保留最强的 keep 比例的组合（至少一个）
+func strongest(combos [][2]card.Card, board []card.Card, keep float64) [][2]card.Card {
+	type scored struct {
+		combo    [2]card.Card
+		strength evaluator.Strength
+	}
+	ev := evaluator.NewFastEvaluator()
+	cards := append([]card.Card{{}, {}}, board...)
+	list := make([]scored, 0, len(combos))
+	for _, c := range combos {
+		cards[0], cards[1] = c[0], c[1]
+		if h, err := ev.EvaluateCards(cards); err == nil {
+			list = append(list, scored{c, h.Strength})
+		}
+	}
+	slices.SortStableFunc(list, func(a, b scored) int { return cmp.Compare(b.strength, a.strength) })
+
+	n := max(int(float64(len(list))*keep), 1)
+	out := make([][2]card.Card, 0, n)
+	for _, s := range list[:min(n, len(list))] {
+		out = append(out, s.combo)
+	}
+	return out
+}
+
+// conflicts 组合是否用到了已知的牌
+func conflicts(c [2]card.Card, known []card.Card) bool {
+	for _, k := range known {
+		if c[0] == k || c[1] == k {
+			return true
+		}
+	}
+	return false
+}
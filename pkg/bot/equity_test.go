@@ -0,0 +1,104 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/protocol"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+)
+
+func TestPreflopRanking(t *testing.T) {
+	ranking := preflopRanking()
+	if len(ranking) != 169 {
+		t.Fatalf("expected 169 classes, got %d", len(ranking))
+	}
+	total := 0
+	for _, class := range ranking {
+		total += len(class)
+	}
+	if total != 1326 {
+		t.Errorf("expected 1326 combos, got %d", total)
+	}
+
+	// AA 最强，72o 排在最后 10 类里
+	first := ranking[0][0]
+	if first[0].Rank != card.Ace || first[1].Rank != card.Ace {
+		t.Errorf("expected AA first, got %v", first)
+	}
+	pos := -1
+	for i, class := range ranking {
+		c := class[0]
+		if c[0].Suit != c[1].Suit && min(c[0].Rank, c[1].Rank) == card.Two && max(c[0].Rank, c[1].Rank) == card.Seven {
+			pos = i
+		}
+	}
+	if pos < len(ranking)-10 {
+		t.Errorf("expected 72o near the bottom, got position %d", pos)
+	}
+
+	// 范围按整类加入，去掉已知的牌
+	known := parseCards(t, "Ac Kd")
+	top := topRange(0.05, known)
+	for _, c := range top {
+		if conflicts(c, known) {
+			t.Errorf("expected no known cards in range, got %v", c)
+		}
+	}
+	if len(top) == 0 || len(top) > len(topRange(0.2, known)) {
+		t.Errorf("expected a small non-empty top range, got %d combos", len(top))
+	}
+}
+
+func TestOpponentStats(t *testing.T) {
+	var o opponentStats
+	if o.vpipRate() != priorVPIP || o.aggressionRate() != priorAggression {
+		t.Errorf("expected priors without observations, got %v, %v", o.vpipRate(), o.aggressionRate())
+	}
+
+	// 大盲过牌不计入翻牌前的决定
+	o.record(game.StagePreFlop, models.ActionCheck)
+	for i := 0; i < 30; i++ {
+		o.record(game.StagePreFlop, models.ActionFold)
+		o.record(game.StageFlop, models.ActionRaise)
+	}
+	if o.preflop != 30 || o.vpip != 0 || o.postflop != 30 || o.aggressive != 30 {
+		t.Errorf("unexpected counts: %+v", o)
+	}
+	if o.vpipRate() >= priorVPIP || o.aggressionRate() <= priorAggression {
+		t.Errorf("expected a tight and aggressive opponent, got vpip %v, aggression %v", o.vpipRate(), o.aggressionRate())
+	}
+}
+
+func TestEquityBased_Observe(t *testing.T) {
+	// 翻牌后本轮下注过的对手只保留牌力靠前的组合，被动的对手保留得更多
+	state, turn := testState(t, game.StageFlop, "Ah Kd", "2c 7d Jh", 200, 50, 0, 1000)
+	s, _ := NewSituation(state, turn)
+	known := append([]card.Card{s.Hole[0], s.Hole[1]}, s.Board...)
+	opp := state.Players[0]
+
+	passive, aggressive := NewEquityBased(1), NewEquityBased(1)
+	passive.selfID, aggressive.selfID = "bot", "bot"
+	for i := 0; i < 50; i++ {
+		passive.Observe(&protocol.PlayerActed{PlayerID: "opp", Action: models.ActionCall, Stage: game.StageTurn})
+		aggressive.Observe(&protocol.PlayerActed{PlayerID: "opp", Action: models.ActionRaise, Stage: game.StageTurn})
+		// 自己的动作不计入
+		aggressive.Observe(&protocol.PlayerActed{PlayerID: "bot", Action: models.ActionFold, Stage: game.StageTurn})
+	}
+	if _, ok := aggressive.opponents["bot"]; ok {
+		t.Error("expected own actions to be ignored")
+	}
+
+	narrow := len(passive.inferRange(s, opp, known))
+	wide := len(aggressive.inferRange(s, opp, known))
+	if narrow == 0 || narrow >= wide {
+		t.Errorf("expected a passive bettor to have a narrower range, got %d vs %d", narrow, wide)
+	}
+
+	// 对手没有下注时不按牌力收窄
+	opp.CurrentBet = 0
+	if got, all := len(passive.inferRange(s, opp, known)), len(topRange(passive.opponent("opp").vpipRate(), known)); got != all {
+		t.Errorf("expected the full range without a bet, got %d of %d", got, all)
+	}
+}
@@ -0,0 +1,169 @@
+package bot
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/protocol"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+	"github.com/wilenwang/just_play/Texas-Holdem/server/client"
+)
+
+// ==================== 机器人客户端 ====================
+
+// RunnerConfig 机器人客户端配置
+type RunnerConfig struct {
+	ServerURL string        // 服务器地址，如 ws://localhost:8080
+	Name      string        // 玩家名称
+	Seat      int           // 请求座位号（-1表示随机）
+	Strategy  Strategy      // 行动策略
+	ThinkTime time.Duration // 每次行动前的思考时间
+}
+
+// Runner 通过 WebSocket 客户端像真人玩家一样入座的机器人：
+// 轮到自己时调用策略行动，每局结束后自动准备下一局
+type Runner struct {
+	config RunnerConfig
+	client *client.Client
+
+	mu        sync.Mutex
+	state     *protocol.GameState // 最近收到的游戏状态
+	readySent bool                // 本局结束后是否已发送准备
+	hands     int                 // 已参与的局数
+
+	strategyMu sync.Mutex // 保证策略的 Decide 和 Observe 不会同时调用
+
+	done     chan struct{} // 断开连接时关闭
+	doneOnce sync.Once
+}
+
+// NewRunner 创建机器人客户端（调用 Start 后连接服务器）
+func NewRunner(config RunnerConfig) *Runner {
+	r := &Runner{config: config, done: make(chan struct{})}
+	r.client = client.NewClient(&client.Config{
+		ServerURL:     config.ServerURL,
+		PlayerName:    config.Name,
+		Seat:          config.Seat,
+		OnStateChange: r.onState,
+		OnJoinAck:     r.onJoinAck,
+		OnTurn:        r.onTurn,
+		OnPlayerActed: r.onPlayerActed,
+		OnError: func(err error) {
+			log.Printf("[机器人] 错误 | %s | %v", config.Name, err)
+		},
+		OnDisconnect: func() {
+			r.doneOnce.Do(func() { close(r.done) })
+		},
+	})
+	return r
+}
+
+// Start 连接服务器并加入游戏
+func (r *Runner) Start() error {
+	log.Printf("[机器人] 连接服务器 | %s | 策略=%s | 地址=%s", r.config.Name, r.config.Strategy.Name(), r.config.ServerURL)
+	return r.client.Connect()
+}
+
+// Stop 离开牌桌并断开连接
+func (r *Runner) Stop() {
+	r.client.SendLeave()
+	r.client.Disconnect()
+}
+
+// Done 返回在断开连接时关闭的通道
+func (r *Runner) Done() <-chan struct{} {
+	return r.done
+}
+
+// Name 返回机器人的玩家名称
+func (r *Runner) Name() string {
+	return r.config.Name
+}
+
+// Hands 返回机器人已参与的局数
+func (r *Runner) Hands() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.hands
+}
+
+// onJoinAck 记录加入结果（准备由随后的状态推送触发）
+func (r *Runner) onJoinAck(success bool, playerID string, seat int) {
+	if !success {
+		log.Printf("[机器人] 加入失败 | %s", r.config.Name)
+		r.client.Disconnect()
+		return
+	}
+	log.Printf("[机器人] 加入成功 | %s | 座位=%d", r.config.Name, seat+1)
+}
+
+// onState 保存最新状态；在大厅或一局结束后发送一次准备，牌局进行中重置
+func (r *Runner) onState(state *protocol.GameState) {
+	r.mu.Lock()
+	prev := r.state
+	r.state = state
+	waiting := !state.Stage.IsBetting()
+	if !waiting {
+		r.readySent = false
+		if state.Stage == game.StagePreFlop && (prev == nil || prev.Stage != game.StagePreFlop) {
+			r.hands++
+		}
+	}
+	r.mu.Unlock()
+
+	if waiting {
+		r.sendReady()
+	}
+}
+
+// onTurn 轮到自己时在后台思考并行动（不阻塞客户端的读协程）
+func (r *Runner) onTurn(turn *protocol.YourTurn) {
+	go func() {
+		if r.config.ThinkTime > 0 {
+			time.Sleep(r.config.ThinkTime)
+		}
+		r.mu.Lock()
+		state := r.state
+		r.mu.Unlock()
+		if state == nil {
+			return
+		}
+		s, err := NewSituation(state, turn)
+		if err != nil {
+			log.Printf("[机器人] 无法行动 | %s | 原因=%v", r.config.Name, err)
+			return
+		}
+		r.strategyMu.Lock()
+		d := s.Legalize(r.config.Strategy.Decide(state, turn))
+		r.strategyMu.Unlock()
+		log.Printf("[机器人] 行动 | %s | %s | 需补=%d | 底池=%d", r.config.Name, d, s.ToCall, s.Pot)
+		if err := r.client.SendPlayerAction(d.Action, d.Amount); err != nil {
+			log.Printf("[机器人] 发送行动失败 | %s | %v", r.config.Name, err)
+		}
+	}()
+}
+
+// onPlayerActed 把玩家动作交给需要观察对手的策略
+func (r *Runner) onPlayerActed(acted *protocol.PlayerActed) {
+	if o, ok := r.config.Strategy.(Observer); ok {
+		r.strategyMu.Lock()
+		o.Observe(acted)
+		r.strategyMu.Unlock()
+	}
+}
+
+// sendReady 每局只发送一次准备下一局
+func (r *Runner) sendReady() {
+	r.mu.Lock()
+	if r.readySent {
+		r.mu.Unlock()
+		return
+	}
+	r.readySent = true
+	r.mu.Unlock()
+
+	if err := r.client.SendReadyForNext(); err != nil {
+		log.Printf("[机器人] 发送准备失败 | %s | %v", r.config.Name, err)
+	}
+}
@@ -0,0 +1,317 @@
+package bot
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/protocol"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/equity"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/evaluator"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+)
+
+// ==================== 随机策略 ====================
+
+// Random 在合法动作中随机选择（加注额也随机），用于压力测试和回归测试
+type Random struct {
+	rng *rand.Rand
+}
+
+// NewRandom 创建随机策略（相同的种子得到相同的决定序列）
+func NewRandom(seed uint64) *Random {
+	return &Random{rng: rand.New(rand.NewPCG(seed, 0))}
+}
+
+// Name 返回策略名称
+func (r *Random) Name() string { return "random" }
+
+// Decide 随机弃牌、过牌/跟注或加注（能过牌时不弃牌）
+func (r *Random) Decide(state *protocol.GameState, turn *protocol.YourTurn) Decision {
+	s, err := NewSituation(state, turn)
+	if err != nil {
+		return Decision{Action: models.ActionFold}
+	}
+	switch r.rng.IntN(3) {
+	case 0:
+		return s.CheckOrFold()
+	case 1:
+		return s.CheckOrCall()
+	}
+	if !s.CanRaise() {
+		return s.CheckOrCall()
+	}
+	return s.RaiseTo(s.MinRaise + r.rng.IntN(s.MaxRaise-s.MinRaise+1))
+}
+
+// ==================== 跟注站 ====================
+
+// CallingStation 从不弃牌也从不加注：能过牌就过牌，否则跟注
+type CallingStation struct{}
+
+// NewCallingStation 创建跟注站策略
+func NewCallingStation() *CallingStation {
+	return &CallingStation{}
+}
+
+// Name 返回策略名称
+func (c *CallingStation) Name() string { return "station" }
+
+// Decide 过牌或跟注
+func (c *CallingStation) Decide(state *protocol.GameState, turn *protocol.YourTurn) Decision {
+	s, err := NewSituation(state, turn)
+	if err != nil {
+		return Decision{Action: models.ActionFold}
+	}
+	return s.CheckOrCall()
+}
+
+// ==================== 紧凶策略 ====================
+
+// 紧凶策略的翻牌前范围
+var (
+	tagOpenRange   = rangeSet("22+, A2s+, KTs+, QTs+, J9s+, T9s, 98s, ATo+, KJo+, QJo")
+	tagThreeBet    = rangeSet("QQ+, AKs, AKo")
+	tagCallVsRaise = rangeSet("22-JJ, ATs-AQs, KQs, KJs, QJs, JTs, AQo")
+)
+
+// TightAggressive 紧凶策略：翻牌前只玩固定范围内的牌并主动加注；
+// 翻牌后有两对以上或顶对时下注，听牌按底池赔率跟注或半诈唬，其余过牌或弃牌
+type TightAggressive struct{}
+
+// NewTightAggressive 创建紧凶策略
+func NewTightAggressive() *TightAggressive {
+	return &TightAggressive{}
+}
+
+// Name 返回策略名称
+func (t *TightAggressive) Name() string { return "tag" }
+
+// Decide 按范围表和牌力决定
+func (t *TightAggressive) Decide(state *protocol.GameState, turn *protocol.YourTurn) Decision {
+	s, err := NewSituation(state, turn)
+	if err != nil {
+		return Decision{Action: models.ActionFold}
+	}
+	if s.Stage == game.StagePreFlop {
+		return t.preflop(s)
+	}
+
+	h := evaluateHand(s)
+	switch {
+	case h.rank >= evaluator.RankThreeOfAKind && h.usesHole:
+		return s.BetPot(0.75)
+	case h.rank == evaluator.RankTwoPair && h.usesHole:
+		if s.ToCall == 0 {
+			return s.BetPot(0.75)
+		}
+		return s.CheckOrCall()
+	case h.topPair:
+		if s.ToCall == 0 {
+			return s.BetPot(0.5)
+		}
+		if s.PotOdds() <= 0.35 {
+			return s.CheckOrCall()
+		}
+	case h.outs >= 8:
+		if s.ToCall == 0 && s.Stage == game.StageFlop {
+			return s.BetPot(0.5)
+		}
+		if drawEquity(h.outs, len(s.Board)) >= s.PotOdds() {
+			return s.CheckOrCall()
+		}
+	}
+	return s.CheckOrFold()
+}
+
+// preflop 翻牌前：没人加注时用开局范围加注到 3 个大盲，有人加注时再加注或跟注
+func (t *TightAggressive) preflop(s *Situation) Decision {
+	if s.CurrentBet <= s.BigBlind {
+		if tagOpenRange[s.Hole] {
+			return s.RaiseTo(3 * s.BigBlind)
+		}
+		return s.CheckOrFold()
+	}
+	switch {
+	case tagThreeBet[s.Hole]:
+		return s.RaiseTo(3 * s.CurrentBet)
+	case tagCallVsRaise[s.Hole] && s.ToCall <= s.Self.Chips/5:
+		return s.CheckOrCall()
+	}
+	return s.CheckOrFold()
+}
+
+// ==================== 规则策略 ====================
+
+// RuleBased 经验规则策略：翻牌前按 Chen 公式给起手牌打分；
+// 翻牌后按牌型下注，听牌用“2 和 4 法则”估算命中率并与底池赔率比较
+type RuleBased struct{}
+
+// NewRuleBased 创建规则策略
+func NewRuleBased() *RuleBased {
+	return &RuleBased{}
+}
+
+// Name 返回策略名称
+func (r *RuleBased) Name() string { return "rule" }
+
+// Decide 按起手牌分数或牌型决定
+func (r *RuleBased) Decide(state *protocol.GameState, turn *protocol.YourTurn) Decision {
+	s, err := NewSituation(state, turn)
+	if err != nil {
+		return Decision{Action: models.ActionFold}
+	}
+	if s.Stage == game.StagePreFlop {
+		return r.preflop(s)
+	}
+
+	h := evaluateHand(s)
+	switch {
+	case h.rank >= evaluator.RankThreeOfAKind && h.usesHole:
+		return s.BetPot(1)
+	case h.rank == evaluator.RankTwoPair && h.usesHole:
+		if s.ToCall == 0 {
+			return s.BetPot(0.5)
+		}
+		return s.CheckOrCall()
+	case h.rank == evaluator.RankOnePair && h.usesHole:
+		if s.PotOdds() <= 0.3 {
+			return s.CheckOrCall()
+		}
+	}
+
+	// 2 和 4 法则：翻牌圈（还有两张牌）每张补牌约 4%，转牌圈约 2%
+	perOut := 0.02
+	if s.Stage == game.StageFlop {
+		perOut = 0.04
+	}
+	if s.ToCall > 0 && float64(h.outs)*perOut >= s.PotOdds() {
+		return s.CheckOrCall()
+	}
+	return s.CheckOrFold()
+}
+
+// preflop 翻牌前：10 分以上加注，8 分以上在没人加注时加注，6 分以上跟注小额下注
+func (r *RuleBased) preflop(s *Situation) Decision {
+	score := chenScore(s.Hole)
+	raised := s.CurrentBet > s.BigBlind
+	switch {
+	case score >= 10:
+		return s.RaiseTo(3 * s.CurrentBet)
+	case score >= 8 && !raised:
+		return s.RaiseTo(3 * s.BigBlind)
+	case score >= 8 && s.ToCall <= 4*s.BigBlind:
+		return s.CheckOrCall()
+	case score >= 6 && s.ToCall <= s.BigBlind:
+		return s.CheckOrCall()
+	}
+	return s.CheckOrFold()
+}
+
+// ==================== 牌力辅助 ====================
+
+// handStrength 翻牌后自己的牌力
+type handStrength struct {
+	rank     evaluator.HandRank // 底牌和公共牌组成的牌型
+	usesHole bool               // 牌型是否用到了底牌（比只用公共牌的牌型更强）
+	topPair  bool               // 顶对（底牌与最大的公共牌成对）或超对
+	outs     int                // 下一张牌的补牌数（河牌为 0）
+}
+
+// evaluateHand 计算翻牌后的牌力（翻牌前返回零值）
+func evaluateHand(s *Situation) handStrength {
+	var h handStrength
+	if len(s.Board) < 3 {
+		return h
+	}
+	eval, err := evaluator.NewFastEvaluator().EvaluateCards(append([]card.Card{s.Hole[0], s.Hole[1]}, s.Board...))
+	if err != nil {
+		return h
+	}
+	h.rank = eval.Rank
+	h.usesHole = h.rank > equity.BoardRank(s.Board)
+	if outs, err := equity.Outs(s.Hole, s.Board); err == nil {
+		h.outs = len(outs)
+	}
+
+	if h.rank == evaluator.RankOnePair && h.usesHole {
+		top := card.Two
+		for _, c := range s.Board {
+			top = max(top, c.Rank)
+		}
+		overPair := s.Hole[0].Rank == s.Hole[1].Rank && s.Hole[0].Rank > top
+		h.topPair = overPair || s.Hole[0].Rank == top || s.Hole[1].Rank == top
+	}
+	return h
+}
+
+// drawEquity 听牌在剩余公共牌中至少命中一张补牌的概率
+func drawEquity(outs, boardLen int) float64 {
+	unseen := float64(52 - 2 - boardLen)
+	miss := 1 - float64(outs)/unseen
+	if boardLen == 3 {
+		miss *= 1 - float64(outs)/(unseen-1)
+	}
+	return 1 - miss
+}
+
+// chenScore 用 Chen 公式给起手牌打分（AA 为 20 分，72o 为 -1 分）
+func chenScore(hole [2]card.Card) float64 {
+	high, low := hole[0], hole[1]
+	if low.Rank > high.Rank {
+		high, low = low, high
+	}
+
+	// cardPoints 单张牌的分数：A=10，K=8，Q=7，J=6，其余为点数的一半
+	cardPoints := func(r card.Rank) float64 {
+		switch r {
+		case card.Ace:
+			return 10
+		case card.King:
+			return 8
+		case card.Queen:
+			return 7
+		case card.Jack:
+			return 6
+		}
+		return float64(r) / 2
+	}
+
+	score := cardPoints(high.Rank)
+	if high.Rank == low.Rank {
+		return math.Max(score*2, 5)
+	}
+	if high.Suit == low.Suit {
+		score += 2
+	}
+	gap := int(high.Rank-low.Rank) - 1
+	switch {
+	case gap == 1:
+		score--
+	case gap == 2:
+		score -= 2
+	case gap == 3:
+		score -= 4
+	case gap >= 4:
+		score -= 5
+	}
+	if gap <= 1 && high.Rank < card.Queen {
+		score++
+	}
+	return math.Ceil(score)
+}
+
+// rangeSet 把范围写法展开成底牌集合（两种顺序都包含，便于直接查找），写法错误时 panic
+func rangeSet(text string) map[[2]card.Card]bool {
+	r, err := equity.ParseRange(text)
+	if err != nil {
+		panic(err)
+	}
+	set := make(map[[2]card.Card]bool, r.Len()*2)
+	for _, c := range r.Combos() {
+		set[c] = true
+		set[[2]card.Card{c[1], c[0]}] = true
+	}
+	return set
+}
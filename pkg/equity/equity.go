@@ -0,0 +1,442 @@
+// Package equity 计算多手底牌在给定公共牌和死牌下的胜率与权益（equity）
+//
+// 剩余的发牌组合足够少时精确枚举所有可能的公共牌；否则用多个 goroutine 做蒙特卡洛模拟，
+// 按正态近似给出每手牌权益的置信区间，并可在达到目标精度时提前结束。
+// 手牌评估使用 evaluator.FastEvaluator 的查找表，每次评估不分配内存。
+//
+// 玩家的底牌也可以是一个范围（见 ParseRange），用 CalculateRanges 计算范围对范围或手牌对范围的权益。
+package equity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/evaluator"
+)
+
+// 默认参数
+const (
+	DefaultIterations = 100000  // 蒙特卡洛模拟次数
+	DefaultExactLimit = 2000000 // 精确枚举的最大评估次数
+	DefaultConfidence = 0.95    // 置信水平
+)
+
+// batchSize 蒙特卡洛每批的模拟次数（每批使用独立的随机数流）
+const batchSize = 1000
+
+// ==================== 错误定义 ====================
+var (
+	ErrTooFewHands       = errors.New("至少需要两手牌")
+	ErrInvalidCard       = errors.New("无效的牌")
+	ErrDuplicateCard     = errors.New("重复的牌")
+	ErrBoardTooLong      = errors.New("公共牌最多5张")
+	ErrNotEnoughCards    = errors.New("剩余的牌不足以发完公共牌")
+	ErrInvalidConfidence = errors.New("置信水平必须在0和1之间")
+)
+
+// Options 计算参数（零值字段使用默认值）
+type Options struct {
+	Iterations int     // 蒙特卡洛模拟次数上限（默认 DefaultIterations）
+	Workers    int     // 并行的 goroutine 数（默认 GOMAXPROCS）
+	ExactLimit int     // 精确枚举的最大评估次数（发牌组合数 × 手牌数），超过时改用模拟；负数表示总是模拟
+	Confidence float64 // 置信区间的置信水平（默认 DefaultConfidence）
+	Precision  float64 // 目标精度：所有手牌的置信区间半宽都不超过该值时提前结束模拟（0 表示跑满）
+	Seed       uint64  // 随机种子（0 表示每次使用不同的种子；不提前结束时相同种子结果相同）
+}
+
+// HandEquity 一手牌的计算结果
+type HandEquity struct {
+	Win    float64 `json:"win"`    // 独赢的概率
+	Tie    float64 `json:"tie"`    // 与其他人平分底池的概率
+	Equity float64 `json:"equity"` // 期望分得的底池比例（独赢计 1，k 人平分计 1/k）
+	Error  float64 `json:"error"`  // 权益置信区间的半宽（精确枚举时为 0）
+}
+
+// Result 计算结果
+type Result struct {
+	Hands   []HandEquity `json:"hands"`   // 与输入的手牌一一对应
+	Samples int64        `json:"samples"` // 评估的公共牌组合数
+	Exact   bool         `json:"exact"`   // 是否为精确枚举
+}
+
+// Calculate 计算各手底牌的胜率和权益
+// board 为已发出的公共牌（0-5张），dead 为已知不会发出的牌（如弃掉的底牌）
+func Calculate(hands [][2]card.Card, board, dead []card.Card, opts Options) (*Result, error) {
+	if len(hands) < 2 {
+		return nil, fmt.Errorf("%w: 只有%d手", ErrTooFewHands, len(hands))
+	}
+	c, err := newCalc(len(hands), board, dead)
+	if err != nil {
+		return nil, err
+	}
+	used := c.dealt
+	for i, h := range hands {
+		for j, cd := range h {
+			code, err := encodeCard(cd, &used)
+			if err != nil {
+				return nil, err
+			}
+			c.holes[i][j] = code
+		}
+	}
+	c.rest = restExcept(c.base, used)
+
+	z, err := opts.normalize()
+	if err != nil {
+		return nil, err
+	}
+	evals := binomial(len(c.rest), c.missing) * float64(len(hands))
+	if opts.ExactLimit > 0 && evals <= float64(opts.ExactLimit) {
+		return c.exact(opts.Workers).result(true, z), nil
+	}
+	return c.monteCarlo(opts, z).result(false, z), nil
+}
+
+// normalize 填充默认参数，返回置信水平对应的正态分位数
+func (o *Options) normalize() (float64, error) {
+	if o.Iterations <= 0 {
+		o.Iterations = DefaultIterations
+	}
+	if o.Workers <= 0 {
+		o.Workers = runtime.GOMAXPROCS(0)
+	}
+	if o.ExactLimit == 0 {
+		o.ExactLimit = DefaultExactLimit
+	}
+	if o.Confidence == 0 {
+		o.Confidence = DefaultConfidence
+	}
+	if o.Confidence <= 0 || o.Confidence >= 1 {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidConfidence, o.Confidence)
+	}
+	if o.Seed == 0 {
+		o.Seed = rand.Uint64()
+	}
+	return math.Sqrt2 * math.Erfinv(o.Confidence), nil
+}
+
+// ==================== 计算 ====================
+
+// calc 一次计算的输入（编码后的牌）
+type calc struct {
+	eval    *evaluator.FastEvaluator
+	holes   [][2]evaluator.CardCode   // 各玩家的底牌（按范围计算时为当前抽到的组合）
+	ranges  [][][2]evaluator.CardCode // 各玩家的范围（已去掉与公共牌和死牌冲突的组合），固定底牌时为 nil
+	board   [5]evaluator.CardCode     // 前 known 张为已发出的公共牌
+	known   int                       // 已发出的公共牌数
+	missing int                       // 还要发的公共牌数
+	dealt   uint64                    // 公共牌和死牌的位掩码
+	base    []evaluator.CardCode      // 除公共牌和死牌以外的牌
+	rest    []evaluator.CardCode      // 可以发出的牌（再除去底牌）
+}
+
+// newCalc 检查并编码公共牌和死牌（players 为玩家数）
+func newCalc(players int, board, dead []card.Card) (*calc, error) {
+	if len(board) > 5 {
+		return nil, fmt.Errorf("%w: 有%d张", ErrBoardTooLong, len(board))
+	}
+	c := &calc{
+		eval:    evaluator.NewFastEvaluator(),
+		holes:   make([][2]evaluator.CardCode, players),
+		known:   len(board),
+		missing: 5 - len(board),
+	}
+	for i, cd := range board {
+		code, err := encodeCard(cd, &c.dealt)
+		if err != nil {
+			return nil, err
+		}
+		c.board[i] = code
+	}
+	for _, cd := range dead {
+		if _, err := encodeCard(cd, &c.dealt); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, cd := range card.NewDeck().Cards() {
+		if code := evaluator.EncodeCard(cd); c.dealt&(1<<code) == 0 {
+			c.base = append(c.base, code)
+		}
+	}
+	if left := len(c.base) - 2*players; left < c.missing {
+		return nil, fmt.Errorf("%w: 剩余%d张，需要%d张", ErrNotEnoughCards, left, c.missing)
+	}
+	return c, nil
+}
+
+// encodeCard 检查并编码一张牌，used 为已经用到的牌的位掩码
+func encodeCard(cd card.Card, used *uint64) (evaluator.CardCode, error) {
+	if cd.Rank < card.Two || cd.Rank > card.Ace || cd.Suit < card.Clubs || cd.Suit > card.Spades {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidCard, cd)
+	}
+	code := evaluator.EncodeCard(cd)
+	if *used&(1<<code) != 0 {
+		return 0, fmt.Errorf("%w: %s", ErrDuplicateCard, cd)
+	}
+	*used |= 1 << code
+	return code, nil
+}
+
+// holeMask 两张底牌的位掩码
+func holeMask(h [2]evaluator.CardCode) uint64 {
+	return 1<<h[0] | 1<<h[1]
+}
+
+// restExcept 返回 cards 中不在 used 里的牌（新切片）
+func restExcept(cards []evaluator.CardCode, used uint64) []evaluator.CardCode {
+	return appendRest(make([]evaluator.CardCode, 0, len(cards)), cards, used)
+}
+
+// appendRest 把 cards 中不在 used 里的牌追加到 dst
+func appendRest(dst, cards []evaluator.CardCode, used uint64) []evaluator.CardCode {
+	for _, code := range cards {
+		if used&(1<<code) == 0 {
+			dst = append(dst, code)
+		}
+	}
+	return dst
+}
+
+// clone 复制一份供单个 goroutine 修改的计算状态
+func (c *calc) clone() *calc {
+	w := *c
+	w.holes = append([][2]evaluator.CardCode(nil), c.holes...)
+	w.rest = append(make([]evaluator.CardCode, 0, len(c.base)), c.rest...)
+	return &w
+}
+
+// evaluate 评估一组完整的公共牌并计入统计（strengths 为可复用的缓冲区）
+func (c *calc) evaluate(board *[5]evaluator.CardCode, t *tally, strengths []evaluator.Strength) {
+	var cards [7]evaluator.CardCode
+	copy(cards[2:], board[:])
+	for i, h := range c.holes {
+		cards[0], cards[1] = h[0], h[1]
+		strengths[i] = c.eval.Strength7(cards)
+	}
+	t.record(strengths)
+}
+
+// deal 从 rest[start:] 中依次发出第 pos 张及以后的公共牌，枚举所有组合
+func (c *calc) deal(board *[5]evaluator.CardCode, pos, start int, t *tally, strengths []evaluator.Strength) {
+	if pos == 5 {
+		c.evaluate(board, t, strengths)
+		return
+	}
+	for i := start; i+(5-pos) <= len(c.rest); i++ {
+		board[pos] = c.rest[i]
+		c.deal(board, pos+1, i+1, t, strengths)
+	}
+}
+
+// exact 枚举所有可能的公共牌（按第一张要发的牌分给各个 goroutine）
+func (c *calc) exact(workers int) *tally {
+	if c.ranges != nil {
+		return c.exactRanges(workers)
+	}
+	total := newTally(len(c.holes))
+	if c.missing == 0 {
+		board := c.board
+		c.evaluate(&board, total, make([]evaluator.Strength, len(c.holes)))
+		return total
+	}
+
+	firsts := make(chan int, len(c.rest))
+	for i := 0; i+c.missing <= len(c.rest); i++ {
+		firsts <- i
+	}
+	close(firsts)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			t := newTally(len(c.holes))
+			strengths := make([]evaluator.Strength, len(c.holes))
+			board := c.board
+			for first := range firsts {
+				board[c.known] = c.rest[first]
+				c.deal(&board, c.known+1, first+1, t, strengths)
+			}
+
+			mu.Lock()
+			total.add(t)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return total
+}
+
+// monteCarlo 随机发出公共牌模拟（批次按编号使用独立的随机数流，与 goroutine 的调度无关）
+func (c *calc) monteCarlo(opts Options, z float64) *tally {
+	total := newTally(len(c.holes))
+	batches := (opts.Iterations + batchSize - 1) / batchSize
+
+	var next atomic.Int64
+	var stop atomic.Bool
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < opts.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wc := c.clone()
+			strengths := make([]evaluator.Strength, len(c.holes))
+			board := c.board
+
+			for !stop.Load() {
+				b := int(next.Add(1) - 1)
+				if b >= batches {
+					return
+				}
+				n := min(batchSize, opts.Iterations-b*batchSize)
+				rng := rand.New(rand.NewPCG(opts.Seed, uint64(b)))
+				copy(wc.rest, c.rest)
+				t := newTally(len(c.holes))
+
+				for s := 0; s < n; s++ {
+					if wc.ranges != nil {
+						wc.pickHoles(rng)
+					}
+					// 部分 Fisher-Yates：只抽出需要的几张
+					for j := 0; j < c.missing; j++ {
+						k := j + rng.IntN(len(wc.rest)-j)
+						wc.rest[j], wc.rest[k] = wc.rest[k], wc.rest[j]
+						board[c.known+j] = wc.rest[j]
+					}
+					wc.evaluate(&board, t, strengths)
+				}
+
+				mu.Lock()
+				total.add(t)
+				if opts.Precision > 0 && total.maxError(z) <= opts.Precision {
+					stop.Store(true)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return total
+}
+
+// ==================== 统计 ====================
+
+// tally 各手牌的胜负次数（全部用整数累计，合并顺序不影响结果）
+type tally struct {
+	samples int64
+	splits  [][]int64 // splits[i][k] 第 i 手牌与共 k 人平分底池的次数（k=1 即独赢）
+}
+
+// newTally 创建 n 手牌的统计
+func newTally(n int) *tally {
+	t := &tally{splits: make([][]int64, n)}
+	for i := range t.splits {
+		t.splits[i] = make([]int64, n+1)
+	}
+	return t
+}
+
+// record 记录一组公共牌下各手牌的强度
+func (t *tally) record(strengths []evaluator.Strength) {
+	t.samples++
+	best, winners := strengths[0], 0
+	for _, s := range strengths {
+		switch {
+		case s > best:
+			best, winners = s, 1
+		case s == best:
+			winners++
+		}
+	}
+	for i, s := range strengths {
+		if s == best {
+			t.splits[i][winners]++
+		}
+	}
+}
+
+// add 合并另一份统计
+func (t *tally) add(other *tally) {
+	t.samples += other.samples
+	for i := range t.splits {
+		for k, n := range other.splits[i] {
+			t.splits[i][k] += n
+		}
+	}
+}
+
+// equity 返回第 i 手牌的权益及其置信区间半宽
+func (t *tally) equity(i int, z float64) (float64, float64) {
+	if t.samples == 0 {
+		return 0, 0
+	}
+	var sum, sumSq float64
+	for k := 1; k < len(t.splits[i]); k++ {
+		share := 1 / float64(k)
+		sum += float64(t.splits[i][k]) * share
+		sumSq += float64(t.splits[i][k]) * share * share
+	}
+	n := float64(t.samples)
+	mean := sum / n
+	variance := math.Max(sumSq/n-mean*mean, 0)
+	return mean, z * math.Sqrt(variance/n)
+}
+
+// maxError 返回所有手牌中最大的置信区间半宽
+func (t *tally) maxError(z float64) float64 {
+	worst := 0.0
+	for i := range t.splits {
+		_, e := t.equity(i, z)
+		worst = math.Max(worst, e)
+	}
+	return worst
+}
+
+// result 生成计算结果
+func (t *tally) result(exact bool, z float64) *Result {
+	r := &Result{
+		Hands:   make([]HandEquity, len(t.splits)),
+		Samples: t.samples,
+		Exact:   exact,
+	}
+	n := float64(t.samples)
+	for i, splits := range t.splits {
+		var ties int64
+		for _, c := range splits[2:] {
+			ties += c
+		}
+		eq, e := t.equity(i, z)
+		if exact {
+			e = 0
+		}
+		r.Hands[i] = HandEquity{
+			Win:    float64(splits[1]) / n,
+			Tie:    float64(ties) / n,
+			Equity: eq,
+			Error:  e,
+		}
+	}
+	return r
+}
+
+// binomial 组合数 C(n, k)（用浮点数避免溢出，只用于判断规模）
+func binomial(n, k int) float64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	r := 1.0
+	for i := 0; i < k; i++ {
+		r = r * float64(n-i) / float64(i+1)
+	}
+	return r
+}
@@ -0,0 +1,211 @@
+package equity
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+)
+
+// parseCards 解析以空格分隔的牌，如 "As Ks Qs"
+func parseCards(t *testing.T, s string) []card.Card {
+	t.Helper()
+	var cards []card.Card
+	for _, f := range strings.Fields(s) {
+		c, err := card.ParseCard(f)
+		if err != nil {
+			t.Fatalf("parse %q: %v", f, err)
+		}
+		cards = append(cards, c)
+	}
+	return cards
+}
+
+// parseHands 解析多手底牌，如 "AhKh", "QsQc"
+func parseHands(t *testing.T, hands ...string) [][2]card.Card {
+	t.Helper()
+	out := make([][2]card.Card, len(hands))
+	for i, h := range hands {
+		cards := parseCards(t, h[:2]+" "+h[2:])
+		out[i] = [2]card.Card{cards[0], cards[1]}
+	}
+	return out
+}
+
+// allExcept 返回除指定牌以外的所有牌
+func allExcept(t *testing.T, s string) []card.Card {
+	t.Helper()
+	skip := make(map[card.Card]bool)
+	for _, c := range parseCards(t, s) {
+		skip[c] = true
+	}
+	var cards []card.Card
+	for _, c := range card.NewDeck().Cards() {
+		if !skip[c] {
+			cards = append(cards, c)
+		}
+	}
+	return cards
+}
+
+func TestCalculate_ExactTurn(t *testing.T) {
+	// 同花听牌加两张高牌：9 张红桃 + 3 张 A + 3 张 K 共 15 张补牌
+	hands := parseHands(t, "AhKh", "QsQc")
+	r, err := Calculate(hands, parseCards(t, "2h 7h Jd 3c"), nil, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !r.Exact || r.Samples != 44 {
+		t.Fatalf("expected exact enumeration of 44 rivers, got exact=%v samples=%d", r.Exact, r.Samples)
+	}
+	if math.Abs(r.Hands[0].Win-15.0/44) > 1e-9 || math.Abs(r.Hands[1].Win-29.0/44) > 1e-9 {
+		t.Errorf("expected 15/44 vs 29/44, got %+v", r.Hands)
+	}
+	if r.Hands[0].Tie != 0 || r.Hands[0].Error != 0 {
+		t.Errorf("expected no ties and no error, got %+v", r.Hands[0])
+	}
+}
+
+func TestCalculate_DeadCards(t *testing.T) {
+	// 两张红桃和一张 A 已经被弃掉
+	hands := parseHands(t, "AhKh", "QsQc")
+	r, err := Calculate(hands, parseCards(t, "2h 7h Jd 3c"), parseCards(t, "9h Th Ad"), Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Samples != 41 || math.Abs(r.Hands[0].Equity-12.0/41) > 1e-9 {
+		t.Errorf("expected 12/41 over 41 rivers, got %d %+v", r.Samples, r.Hands[0])
+	}
+}
+
+func TestCalculate_Ties(t *testing.T) {
+	// 河牌：前两手都是 A 高平分，第三手输
+	hands := parseHands(t, "AhQd", "AsQc", "4h5h")
+	r, err := Calculate(hands, parseCards(t, "2c 3d 8h 9s Kc"), nil, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []HandEquity{{Tie: 1, Equity: 0.5}, {Tie: 1, Equity: 0.5}, {}}
+	for i, w := range want {
+		if r.Hands[i] != w {
+			t.Errorf("hand %d: expected %+v, got %+v", i, w, r.Hands[i])
+		}
+	}
+
+	// 公共牌是皇家同花顺，所有人平分
+	r, err = Calculate(parseHands(t, "AhAd", "2c3c"), parseCards(t, "Ts Js Qs Ks As"), nil, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, h := range r.Hands {
+		if h.Win != 0 || h.Tie != 1 || h.Equity != 0.5 {
+			t.Errorf("hand %d: expected a split, got %+v", i, h)
+		}
+	}
+}
+
+func TestCalculate_MonteCarloMatchesExact(t *testing.T) {
+	if testing.Short() {
+		t.Skip("exact preflop enumeration is slow in short mode")
+	}
+	hands := parseHands(t, "AhAd", "KsKc")
+	exact, err := Calculate(hands, nil, nil, Options{ExactLimit: math.MaxInt})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !exact.Exact || exact.Samples != 1712304 {
+		t.Fatalf("expected 1712304 exact runouts, got exact=%v samples=%d", exact.Exact, exact.Samples)
+	}
+	sum := exact.Hands[0].Equity + exact.Hands[1].Equity
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("expected equities to sum to 1, got %v", sum)
+	}
+
+	mc, err := Calculate(hands, nil, nil, Options{Iterations: 200000, Seed: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mc.Exact || mc.Samples != 200000 {
+		t.Fatalf("expected 200000 Monte Carlo samples, got exact=%v samples=%d", mc.Exact, mc.Samples)
+	}
+	for i := range hands {
+		h := mc.Hands[i]
+		if h.Error <= 0 || math.Abs(h.Equity-exact.Hands[i].Equity) > 2*h.Error {
+			t.Errorf("hand %d: Monte Carlo %.4f ± %.4f too far from exact %.4f",
+				i, h.Equity, h.Error, exact.Hands[i].Equity)
+		}
+	}
+}
+
+func TestCalculate_Seeded(t *testing.T) {
+	hands := parseHands(t, "AhKd", "7c7s", "QhJh")
+	opts := Options{Iterations: 20000, Seed: 42, ExactLimit: -1}
+	r1, err1 := Calculate(hands, nil, nil, opts)
+	opts.Workers = 1
+	r2, err2 := Calculate(hands, nil, nil, opts)
+	if err1 != nil || err2 != nil {
+		t.Fatalf("unexpected error: %v / %v", err1, err2)
+	}
+	for i := range hands {
+		if r1.Hands[i] != r2.Hands[i] {
+			t.Errorf("hand %d: expected same result for same seed, got %+v and %+v", i, r1.Hands[i], r2.Hands[i])
+		}
+	}
+}
+
+func TestCalculate_Precision(t *testing.T) {
+	hands := parseHands(t, "AhKd", "7c7s")
+	r, err := Calculate(hands, nil, nil, Options{Iterations: 1000000, Precision: 0.01, Seed: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Samples >= 1000000 {
+		t.Errorf("expected to stop early, ran %d samples", r.Samples)
+	}
+	for i, h := range r.Hands {
+		if h.Error > 0.01 {
+			t.Errorf("hand %d: expected error <= 0.01, got %v", i, h.Error)
+		}
+	}
+}
+
+func TestCalculate_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		hands [][2]card.Card
+		board []card.Card
+		dead  []card.Card
+		opts  Options
+		want  error
+	}{
+		{"one hand", parseHands(t, "AhKd"), nil, nil, Options{}, ErrTooFewHands},
+		{"board too long", parseHands(t, "AhKd", "7c7s"), parseCards(t, "2c 3c 4c 5c 6c 8c"), nil, Options{}, ErrBoardTooLong},
+		{"duplicate in hands", parseHands(t, "AhKd", "AhQs"), nil, nil, Options{}, ErrDuplicateCard},
+		{"duplicate on board", parseHands(t, "AhKd", "7c7s"), parseCards(t, "7c 2d 3d"), nil, Options{}, ErrDuplicateCard},
+		{"duplicate dead card", parseHands(t, "AhKd", "7c7s"), nil, parseCards(t, "Kd"), Options{}, ErrDuplicateCard},
+		{"undealt card", [][2]card.Card{{}, parseHands(t, "7c7s")[0]}, nil, nil, Options{}, ErrInvalidCard},
+		{"bad confidence", parseHands(t, "AhKd", "7c7s"), nil, nil, Options{Confidence: 1.5}, ErrInvalidConfidence},
+		{"not enough cards", parseHands(t, "AhKd", "7c7s"), nil, allExcept(t, "Ah Kd 7c 7s")[:46], Options{}, ErrNotEnoughCards},
+	}
+
+	for _, tt := range tests {
+		if _, err := Calculate(tt.hands, tt.board, tt.dead, tt.opts); !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func BenchmarkCalculate_Flop(b *testing.B) {
+	hands := [][2]card.Card{
+		{card.NewCard(card.Hearts, card.Ace), card.NewCard(card.Hearts, card.King)},
+		{card.NewCard(card.Spades, card.Queen), card.NewCard(card.Clubs, card.Queen)},
+	}
+	board := []card.Card{
+		card.NewCard(card.Hearts, card.Two), card.NewCard(card.Hearts, card.Seven), card.NewCard(card.Diamonds, card.Jack),
+	}
+	for i := 0; i < b.N; i++ {
+		Calculate(hands, board, nil, Options{})
+	}
+}
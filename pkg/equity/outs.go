@@ -0,0 +1,79 @@
+package equity
+
+import (
+	"fmt"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/evaluator"
+)
+
+// ==================== 补牌 ====================
+
+// Outs 返回翻牌圈或转牌圈下一张牌中能让牌型升级的补牌
+// 只靠公共牌自身组成的牌型（例如公共牌成对）不算补牌
+func Outs(hole [2]card.Card, board []card.Card) ([]card.Card, error) {
+	if len(board) < 3 || len(board) > 4 {
+		return nil, fmt.Errorf("%w: 只能在翻牌圈或转牌圈计算补牌", ErrBoardTooLong)
+	}
+	e := evaluator.NewFastEvaluator()
+	known := append(append([]card.Card{hole[0], hole[1]}, board...), card.Card{})
+	current, err := e.EvaluateCards(known[:len(known)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[card.Card]bool, len(known))
+	for _, c := range known {
+		used[c] = true
+	}
+	var outs []card.Card
+	for _, c := range card.NewDeck().Cards() {
+		if used[c] {
+			continue
+		}
+		known[len(known)-1] = c
+		next, err := e.EvaluateCards(known)
+		if err != nil {
+			return nil, err
+		}
+		if next.Rank > current.Rank && next.Rank > BoardRank(known[2:]) {
+			outs = append(outs, c)
+		}
+	}
+	return outs, nil
+}
+
+// BoardRank 返回只用公共牌组成的牌型（不足5张时只看对子、三条和四条），用于判断牌型是否用到了底牌
+func BoardRank(board []card.Card) evaluator.HandRank {
+	if len(board) >= 5 {
+		if eval, err := evaluator.NewFastEvaluator().EvaluateCards(board); err == nil {
+			return eval.Rank
+		}
+	}
+	counts := make(map[card.Rank]int, len(board))
+	pairs, trips, quads := 0, 0, 0
+	for _, c := range board {
+		counts[c.Rank]++
+		switch counts[c.Rank] {
+		case 2:
+			pairs++
+		case 3:
+			pairs--
+			trips++
+		case 4:
+			trips--
+			quads++
+		}
+	}
+	switch {
+	case quads > 0:
+		return evaluator.RankFourOfAKind
+	case trips > 0:
+		return evaluator.RankThreeOfAKind
+	case pairs >= 2:
+		return evaluator.RankTwoPair
+	case pairs == 1:
+		return evaluator.RankOnePair
+	}
+	return evaluator.RankHighCard
+}
@@ -0,0 +1,46 @@
+package equity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOuts(t *testing.T) {
+	tests := []struct {
+		name  string
+		hole  string
+		board string
+		want  int
+	}{
+		// 同花听牌：9 张红桃，另外 3 张 A 和 3 张 K 成对
+		{"flush draw with overcards", "AhKh", "2h 7h Jd", 15},
+		// 两头顺子听牌：4 张 9 和 4 张 4 成顺，另外 6 张成对
+		{"open-ended straight draw", "6c5d", "7h 8s Kd", 14},
+		// 暗三条：剩下的 1 张同点数成四条，公共牌的 3 个点数各 3 张成葫芦
+		{"set on the turn", "7c7d", "7h Ks 2d 9c", 10},
+		// 公共牌成对不算补牌：两张高牌只能靠配对
+		{"overcards", "AcKd", "2h 7s 9d", 6},
+	}
+
+	for _, tt := range tests {
+		hole := parseHands(t, tt.hole)[0]
+		outs, err := Outs(hole, parseCards(t, tt.board))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if len(outs) != tt.want {
+			t.Errorf("%s: expected %d outs, got %d %v", tt.name, tt.want, len(outs), outs)
+		}
+	}
+
+	if _, err := Outs(parseHands(t, "AcKd")[0], nil); !errors.Is(err, ErrBoardTooLong) {
+		t.Errorf("expected an error before the flop, got %v", err)
+	}
+}
+
+func TestRandomRange(t *testing.T) {
+	if n := RandomRange().Len(); n != 1326 {
+		t.Errorf("expected 1326 combos, got %d", n)
+	}
+}
@@ -0,0 +1,417 @@
+package equity
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/evaluator"
+)
+
+// ==================== 手牌范围 ====================
+//
+// 范围写法（逗号或空格分隔，不区分大小写）：
+//   - QQ、AKs、AKo、AK：对子 6 种、同花 4 种、非同花 12 种、不限 16 种组合
+//   - QQ+：QQ、KK、AA
+//   - KTo+：踢脚递增到比高牌小一级（KTo、KJo、KQo）
+//   - 76s+：相邻的两张牌一起递增（76s、87s … AKs）
+//   - 99-66、A5s-A2s、T9s-65s：对子区间、同一高牌的踢脚区间、相同间隔的区间
+//   - AhKh：指定的一个组合
+
+// 范围错误
+var (
+	ErrInvalidRange = errors.New("无效的范围")
+	ErrEmptyRange   = errors.New("范围内没有可用的组合")
+	ErrNoValidDeal  = errors.New("各玩家的范围没有互不冲突的组合")
+)
+
+// rankChars 点数字符（从 2 到 A）
+const rankChars = "23456789TJQKA"
+
+// Range 手牌范围（不重复的底牌组合）
+type Range struct {
+	text   string
+	combos [][2]card.Card
+}
+
+// NewRange 由指定的底牌组合创建范围（重复的组合只保留一个）
+func NewRange(combos ...[2]card.Card) *Range {
+	r := &Range{}
+	seen := make(map[[2]card.Card]bool)
+	texts := make([]string, 0, len(combos))
+	for _, c := range combos {
+		c = normalizeCombo(c)
+		if !seen[c] {
+			seen[c] = true
+			r.combos = append(r.combos, c)
+			texts = append(texts, c[0].ShortString()+c[1].ShortString())
+		}
+	}
+	r.text = strings.Join(texts, ", ")
+	return r
+}
+
+// RandomRange 返回包含全部 1326 种组合的范围（随机手牌）
+func RandomRange() *Range {
+	deck := card.NewDeck().Cards()
+	r := &Range{text: "random", combos: make([][2]card.Card, 0, 1326)}
+	for i := range deck {
+		for j := i + 1; j < len(deck); j++ {
+			r.combos = append(r.combos, normalizeCombo([2]card.Card{deck[i], deck[j]}))
+		}
+	}
+	return r
+}
+
+// ParseRange 解析范围写法，如 "AKs, QQ+, A5s-A2s, 76s+, KTo+"
+func ParseRange(s string) (*Range, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: 范围为空", ErrInvalidRange)
+	}
+
+	var combos [][2]card.Card
+	for _, f := range fields {
+		cs, err := parseRangeToken(f)
+		if err != nil {
+			return nil, err
+		}
+		combos = append(combos, cs...)
+	}
+	r := NewRange(combos...)
+	r.text = strings.Join(fields, ", ")
+	return r, nil
+}
+
+// String 返回范围的写法
+func (r *Range) String() string {
+	return r.text
+}
+
+// Len 返回范围内的组合数
+func (r *Range) Len() int {
+	return len(r.combos)
+}
+
+// Combos 返回范围内的所有组合
+func (r *Range) Combos() [][2]card.Card {
+	return r.combos
+}
+
+// Available 返回不包含已知牌（公共牌、死牌或其他玩家的底牌）的组合，即去除牌的影响后剩下的组合
+func (r *Range) Available(known ...card.Card) [][2]card.Card {
+	blocked := make(map[card.Card]bool, len(known))
+	for _, c := range known {
+		blocked[c] = true
+	}
+	out := make([][2]card.Card, 0, len(r.combos))
+	for _, c := range r.combos {
+		if !blocked[c[0]] && !blocked[c[1]] {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// normalizeCombo 大牌在前（点数相同时按花色），使同一组合只有一种写法
+func normalizeCombo(c [2]card.Card) [2]card.Card {
+	if c[1].Rank > c[0].Rank || (c[1].Rank == c[0].Rank && c[1].Suit > c[0].Suit) {
+		c[0], c[1] = c[1], c[0]
+	}
+	return c
+}
+
+// ==================== 范围解析 ====================
+
+// handClass 不区分花色的起手牌类别，如 AKs
+type handClass struct {
+	high, low card.Rank
+	suited    byte // 's' 同花，'o' 非同花，0 不限
+}
+
+// parseRangeToken 解析范围中的一项
+func parseRangeToken(token string) ([][2]card.Card, error) {
+	bad := fmt.Errorf("%w: %q", ErrInvalidRange, token)
+
+	// 指定的组合，如 AhKh
+	if len(token) == 4 {
+		c1, err1 := card.ParseCard(token[:2])
+		c2, err2 := card.ParseCard(token[2:])
+		if err1 == nil && err2 == nil {
+			if c1 == c2 {
+				return nil, bad
+			}
+			return [][2]card.Card{{c1, c2}}, nil
+		}
+	}
+
+	upper := strings.ToUpper(token)
+	var classes []handClass
+	switch {
+	case strings.Contains(upper, "-"):
+		parts := strings.Split(upper, "-")
+		if len(parts) != 2 {
+			return nil, bad
+		}
+		from, ok1 := parseHandClass(parts[0])
+		to, ok2 := parseHandClass(parts[1])
+		if !ok1 || !ok2 {
+			return nil, bad
+		}
+		var ok bool
+		if classes, ok = classSpan(from, to); !ok {
+			return nil, bad
+		}
+
+	case strings.HasSuffix(upper, "+"):
+		from, ok := parseHandClass(strings.TrimSuffix(upper, "+"))
+		if !ok {
+			return nil, bad
+		}
+		classes = classPlus(from)
+
+	default:
+		class, ok := parseHandClass(upper)
+		if !ok {
+			return nil, bad
+		}
+		classes = []handClass{class}
+	}
+
+	var combos [][2]card.Card
+	for _, c := range classes {
+		combos = append(combos, c.combos()...)
+	}
+	return combos, nil
+}
+
+// parseHandClass 解析起手牌类别（已转为大写），如 AKS、QQ、T9
+func parseHandClass(s string) (handClass, bool) {
+	if len(s) < 2 || len(s) > 3 {
+		return handClass{}, false
+	}
+	i := strings.IndexByte(rankChars, s[0])
+	j := strings.IndexByte(rankChars, s[1])
+	if i < 0 || j < 0 {
+		return handClass{}, false
+	}
+	c := handClass{high: card.Two + card.Rank(i), low: card.Two + card.Rank(j)}
+	if c.low > c.high {
+		c.high, c.low = c.low, c.high
+	}
+	if len(s) == 3 {
+		switch s[2] {
+		case 'S':
+			c.suited = 's'
+		case 'O':
+			c.suited = 'o'
+		default:
+			return handClass{}, false
+		}
+		// 对子没有同花/非同花之分
+		if c.high == c.low {
+			return handClass{}, false
+		}
+	}
+	return c, true
+}
+
+// classPlus 展开 "+"：对子向上到 AA；相邻的两张一起向上到 AK；否则踢脚向上到比高牌小一级
+func classPlus(from handClass) []handClass {
+	var out []handClass
+	switch {
+	case from.high == from.low:
+		for r := from.high; r <= card.Ace; r++ {
+			out = append(out, handClass{high: r, low: r})
+		}
+	case from.high-from.low == 1:
+		for r := from.high; r <= card.Ace; r++ {
+			out = append(out, handClass{high: r, low: r - 1, suited: from.suited})
+		}
+	default:
+		for k := from.low; k < from.high; k++ {
+			out = append(out, handClass{high: from.high, low: k, suited: from.suited})
+		}
+	}
+	return out
+}
+
+// classSpan 展开区间：对子区间、同一高牌的踢脚区间或相同间隔的区间（两端顺序不限）
+func classSpan(a, b handClass) ([]handClass, bool) {
+	if a.suited != b.suited {
+		return nil, false
+	}
+	if a.high < b.high || (a.high == b.high && a.low < b.low) {
+		a, b = b, a
+	}
+	var out []handClass
+	switch {
+	case a.high == a.low && b.high == b.low:
+		for r := b.high; r <= a.high; r++ {
+			out = append(out, handClass{high: r, low: r})
+		}
+	case a.high == a.low || b.high == b.low:
+		return nil, false
+	case a.high == b.high:
+		for k := b.low; k <= a.low; k++ {
+			out = append(out, handClass{high: a.high, low: k, suited: a.suited})
+		}
+	case a.high-a.low == b.high-b.low:
+		gap := a.high - a.low
+		for r := b.high; r <= a.high; r++ {
+			out = append(out, handClass{high: r, low: r - gap, suited: a.suited})
+		}
+	default:
+		return nil, false
+	}
+	return out, true
+}
+
+// combos 返回类别中的所有具体组合
+func (c handClass) combos() [][2]card.Card {
+	var out [][2]card.Card
+	for s1 := card.Clubs; s1 <= card.Spades; s1++ {
+		for s2 := card.Clubs; s2 <= card.Spades; s2++ {
+			switch {
+			case c.high == c.low && s2 <= s1:
+				continue
+			case c.suited == 's' && s1 != s2:
+				continue
+			case c.suited == 'o' && s1 == s2:
+				continue
+			}
+			out = append(out, [2]card.Card{card.NewCard(s1, c.high), card.NewCard(s2, c.low)})
+		}
+	}
+	return out
+}
+
+// ==================== 范围对范围 ====================
+
+// CalculateRanges 计算各玩家范围的胜率和权益（固定的底牌可用 NewRange 或 "AhKh" 写成只有一个组合的范围）
+// 与公共牌、死牌冲突的组合先去掉；各玩家的组合互不冲突的所有发牌情况等概率
+func CalculateRanges(ranges []*Range, board, dead []card.Card, opts Options) (*Result, error) {
+	if len(ranges) < 2 {
+		return nil, fmt.Errorf("%w: 只有%d个范围", ErrTooFewHands, len(ranges))
+	}
+	c, err := newCalc(len(ranges), board, dead)
+	if err != nil {
+		return nil, err
+	}
+
+	deals := 1.0
+	c.ranges = make([][][2]evaluator.CardCode, len(ranges))
+	for i, r := range ranges {
+		for _, combo := range r.combos {
+			h := [2]evaluator.CardCode{evaluator.EncodeCard(combo[0]), evaluator.EncodeCard(combo[1])}
+			if c.dealt&holeMask(h) == 0 {
+				c.ranges[i] = append(c.ranges[i], h)
+			}
+		}
+		if len(c.ranges[i]) == 0 {
+			return nil, fmt.Errorf("%w: 第%d个范围 %s", ErrEmptyRange, i+1, r)
+		}
+		deals *= float64(len(c.ranges[i]))
+	}
+	if !c.hasDeal(0, 0) {
+		return nil, ErrNoValidDeal
+	}
+
+	z, err := opts.normalize()
+	if err != nil {
+		return nil, err
+	}
+	// 按组合数的乘积估计规模（冲突的组合会被跳过，实际更少）
+	evals := deals * binomial(len(c.base)-2*len(ranges), c.missing) * float64(len(ranges))
+	if opts.ExactLimit > 0 && evals <= float64(opts.ExactLimit) {
+		return c.exact(opts.Workers).result(true, z), nil
+	}
+	return c.monteCarlo(opts, z).result(false, z), nil
+}
+
+// hasDeal 检查从第 i 个范围起能否选出与 used 及彼此都不冲突的组合
+func (c *calc) hasDeal(i int, used uint64) bool {
+	if i == len(c.ranges) {
+		return true
+	}
+	for _, h := range c.ranges[i] {
+		if m := holeMask(h); used&m == 0 && c.hasDeal(i+1, used|m) {
+			return true
+		}
+	}
+	return false
+}
+
+// pickHoles 为每个玩家随机抽一个组合（有冲突时整组重抽，保证各种发牌等概率），并更新可发出的牌
+func (c *calc) pickHoles(rng *rand.Rand) {
+	var used uint64
+pick:
+	for {
+		used = 0
+		for i, r := range c.ranges {
+			h := r[rng.IntN(len(r))]
+			m := holeMask(h)
+			if used&m != 0 {
+				continue pick
+			}
+			used |= m
+			c.holes[i] = h
+		}
+		break
+	}
+	c.rest = appendRest(c.rest[:0], c.base, used)
+}
+
+// exactRanges 枚举各玩家互不冲突的组合以及所有可能的公共牌（按第一个玩家的组合分给各个 goroutine）
+func (c *calc) exactRanges(workers int) *tally {
+	total := newTally(len(c.holes))
+	firsts := make(chan int, len(c.ranges[0]))
+	for i := range c.ranges[0] {
+		firsts <- i
+	}
+	close(firsts)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wc := c.clone()
+			t := newTally(len(c.holes))
+			strengths := make([]evaluator.Strength, len(c.holes))
+			board := c.board
+
+			var pick func(i int, used uint64)
+			pick = func(i int, used uint64) {
+				if i == len(wc.ranges) {
+					wc.rest = appendRest(wc.rest[:0], wc.base, used)
+					wc.deal(&board, wc.known, 0, t, strengths)
+					return
+				}
+				for _, h := range wc.ranges[i] {
+					if m := holeMask(h); used&m == 0 {
+						wc.holes[i] = h
+						pick(i+1, used|m)
+					}
+				}
+			}
+			for first := range firsts {
+				h := wc.ranges[0][first]
+				wc.holes[0] = h
+				pick(1, holeMask(h))
+			}
+
+			mu.Lock()
+			total.add(t)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return total
+}
@@ -0,0 +1,168 @@
+package equity
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+)
+
+// mustRange 解析范围，失败时终止测试
+func mustRange(t *testing.T, s string) *Range {
+	t.Helper()
+	r, err := ParseRange(s)
+	if err != nil {
+		t.Fatalf("parse range %q: %v", s, err)
+	}
+	return r
+}
+
+// classNames 返回范围中不区分花色的类别（如 AKs、QQ），用于检查展开结果
+func classNames(r *Range) map[string]int {
+	names := make(map[string]int)
+	for _, c := range r.Combos() {
+		name := string(rankChars[c[0].Rank-card.Two]) + string(rankChars[c[1].Rank-card.Two])
+		switch {
+		case c[0].Rank == c[1].Rank:
+		case c[0].Suit == c[1].Suit:
+			name += "s"
+		default:
+			name += "o"
+		}
+		names[name]++
+	}
+	return names
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		text   string
+		combos int
+		want   []string // 展开后的类别
+	}{
+		{"AKs", 4, []string{"AKs"}},
+		{"ako", 12, []string{"AKo"}},
+		{"AK", 16, []string{"AKs", "AKo"}},
+		{"QQ+", 18, []string{"QQ", "KK", "AA"}},
+		{"A5s-A2s", 16, []string{"A5s", "A4s", "A3s", "A2s"}},
+		{"76s+", 32, []string{"76s", "87s", "98s", "T9s", "JTs", "QJs", "KQs", "AKs"}},
+		{"KTo+", 36, []string{"KTo", "KJo", "KQo"}},
+		{"99-66", 24, []string{"99", "88", "77", "66"}},
+		{"65s-T9s", 20, []string{"65s", "76s", "87s", "98s", "T9s"}},
+		{"AhKh", 1, []string{"AKs"}},
+		{"AKs, QQ+, A5s-A2s, 76s+, KTo+", 102, nil},
+		{"AK AKs", 16, nil},
+	}
+
+	for _, tt := range tests {
+		r, err := ParseRange(tt.text)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.text, err)
+			continue
+		}
+		if r.Len() != tt.combos {
+			t.Errorf("%q: expected %d combos, got %d", tt.text, tt.combos, r.Len())
+		}
+		if tt.want == nil {
+			continue
+		}
+		names := classNames(r)
+		if len(names) != len(tt.want) {
+			t.Errorf("%q: expected classes %v, got %v", tt.text, tt.want, names)
+		}
+		for _, w := range tt.want {
+			if names[w] == 0 {
+				t.Errorf("%q: missing %s in %v", tt.text, w, names)
+			}
+		}
+	}
+}
+
+func TestParseRange_Errors(t *testing.T) {
+	for _, text := range []string{"", "AX", "QQs", "AKs-KQo", "AKs-Q9s", "A5s-A2o", "99-A2s", "AhAh", "AKx", "A-K-Q"} {
+		if _, err := ParseRange(text); !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("%q: expected ErrInvalidRange, got %v", text, err)
+		}
+	}
+}
+
+func TestRange_Available(t *testing.T) {
+	// 公共牌上有一张 A：AA 从 6 种减为 3 种，AK 从 16 种减为 12 种
+	r := mustRange(t, "AA, AK")
+	board := parseCards(t, "As 7d 2c")
+	if n := len(r.Available(board...)); n != 15 {
+		t.Errorf("expected 15 combos after card removal, got %d", n)
+	}
+
+	// NewRange 去掉重复的组合（两张牌的顺序不影响）
+	ah, kh := parseCards(t, "Ah")[0], parseCards(t, "Kh")[0]
+	if n := NewRange([2]card.Card{ah, kh}, [2]card.Card{kh, ah}).Len(); n != 1 {
+		t.Errorf("expected 1 combo, got %d", n)
+	}
+}
+
+func TestCalculateRanges_MatchesCalculate(t *testing.T) {
+	// 只有一个组合的范围与固定底牌的结果相同
+	board := parseCards(t, "2h 7h Jd 3c")
+	want, err := Calculate(parseHands(t, "AhKh", "QsQc"), board, nil, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := CalculateRanges([]*Range{mustRange(t, "AhKh"), mustRange(t, "QsQc")}, board, nil, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.Exact || got.Samples != want.Samples || got.Hands[0] != want.Hands[0] {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestCalculateRanges_HandVsRange(t *testing.T) {
+	// 河牌：AA 对 {KK, 22}，公共牌上的 2 使 22 只剩 3 种组合（都成三条），KK 有 6 种
+	board := parseCards(t, "2c 7d 9h Js 4s")
+	r, err := CalculateRanges([]*Range{mustRange(t, "AhAd"), mustRange(t, "KK, 22")}, board, nil, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !r.Exact || r.Samples != 9 {
+		t.Fatalf("expected 9 exact deals, got exact=%v samples=%d", r.Exact, r.Samples)
+	}
+	if math.Abs(r.Hands[0].Equity-6.0/9) > 1e-9 {
+		t.Errorf("expected AA to win 6/9, got %+v", r.Hands[0])
+	}
+}
+
+func TestCalculateRanges_MonteCarlo(t *testing.T) {
+	// 翻牌前 AA 对 KK：范围内的组合都是等价的，结果应接近固定底牌的精确值（约 82%）
+	ranges := []*Range{mustRange(t, "AA"), mustRange(t, "KK")}
+	r, err := CalculateRanges(ranges, nil, nil, Options{Iterations: 100000, Seed: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Exact || r.Samples != 100000 {
+		t.Fatalf("expected 100000 Monte Carlo samples, got exact=%v samples=%d", r.Exact, r.Samples)
+	}
+	if e := r.Hands[0].Equity; e < 0.80 || e > 0.84 {
+		t.Errorf("expected AA to have about 82%% equity, got %.4f ± %.4f", e, r.Hands[0].Error)
+	}
+}
+
+func TestCalculateRanges_Errors(t *testing.T) {
+	board := parseCards(t, "As Ad 7c")
+	tests := []struct {
+		name   string
+		ranges []*Range
+		board  []card.Card
+		want   error
+	}{
+		{"one range", []*Range{mustRange(t, "AA")}, nil, ErrTooFewHands},
+		{"blocked by board", []*Range{mustRange(t, "AsAd"), mustRange(t, "KK")}, board, ErrEmptyRange},
+		{"no valid deal", []*Range{mustRange(t, "AA"), mustRange(t, "AA"), mustRange(t, "AA")}, board[2:], ErrNoValidDeal},
+	}
+	for _, tt := range tests {
+		if _, err := CalculateRanges(tt.ranges, tt.board, nil, Options{}); !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package evaluator
 
 import (
+	"errors"
+	"fmt"
 	"sort"
 
 	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
@@ -50,15 +52,105 @@ func (r HandRank) Symbol() string {
 	return "?"
 }
 
+// 评估错误
+var (
+	ErrTooFewCards   = errors.New("至少需要5张牌")
+	ErrTooManyCards  = errors.New("最多只能评估7张牌")
+	ErrInvalidCard   = errors.New("无效的牌")
+	ErrDuplicateCard = errors.New("重复的牌")
+)
+
+// Strength 手牌强度：高 4 位以上为牌型等级，低 20 位依次为 5 个比较点数（每个 4 位）
+// 不同手牌的强度可直接比较，数值越大牌越强，相等即平局
+type Strength uint32
+
+// strengthRankShift 牌型等级在 Strength 中的位置
+const strengthRankShift = 20
+
+// Rank 返回强度对应的牌型等级
+func (s Strength) Rank() HandRank {
+	return HandRank(s >> strengthRankShift)
+}
+
+// Compare 比较两个强度（返回 1 表示 s 更强，-1 表示 other 更强，0 表示平局）
+func (s Strength) Compare(other Strength) int {
+	switch {
+	case s > other:
+		return 1
+	case s < other:
+		return -1
+	}
+	return 0
+}
+
+// values 返回强度中的 5 个比较点数（没有用到的位置为 0）
+func (s Strength) values() [5]int {
+	var v [5]int
+	for i := range v {
+		v[i] = int(s>>(16-4*i)) & 0xF
+	}
+	return v
+}
+
+// makeStrength 由牌型等级和按比较顺序排列的点数生成强度
+func makeStrength(rank HandRank, values ...int) Strength {
+	s := Strength(rank) << strengthRankShift
+	for i, v := range values {
+		s |= Strength(v) << (16 - 4*i)
+	}
+	return s
+}
+
 // HandEvaluation 表示手牌评估结果
 type HandEvaluation struct {
-	Rank      HandRank      // 牌型等级
-	MainValue int           // 主要比较值（用于同牌型比较）
-	Kickers   []int         // 踢脚牌（用于进一步比较）
-	RawCards  []card.Card   // 参与评估的5张牌
+	Rank     HandRank    // 牌型等级
+	Strength Strength    // 手牌强度（可直接比较大小，相等即平局）
+	RawCards []card.Card // 构成最佳手牌的5张牌（按比较顺序排列）
+}
+
+// HandEvaluator 手牌评估器接口（Evaluator 和 FastEvaluator 的评估结果一致，可以互相替换）
+type HandEvaluator interface {
+	// EvaluateCards 从5到7张牌中找出最佳的5张牌组合
+	EvaluateCards(cards []card.Card) (HandEvaluation, error)
+	// Compare 比较两手牌（返回 1 表示 h1 赢，-1 表示 h2 赢，0 表示平局）
+	Compare(h1, h2 HandEvaluation) int
+}
+
+// EvaluateHand 评估底牌加上已发出的公共牌（公共牌中点数为 0 的牌表示尚未发出）
+func EvaluateHand(e HandEvaluator, holeCards [2]card.Card, communityCards [5]card.Card) (HandEvaluation, error) {
+	cards := make([]card.Card, 0, 7)
+	cards = append(cards, holeCards[:]...)
+	for _, c := range communityCards {
+		if c.Rank != 0 {
+			cards = append(cards, c)
+		}
+	}
+	return e.EvaluateCards(cards)
 }
 
-// Evaluator 是扑克手牌评估器
+// checkCards 检查牌数（5到7张）、牌面是否有效以及是否有重复的牌
+func checkCards(cards []card.Card) error {
+	if len(cards) < 5 {
+		return fmt.Errorf("%w: 只有%d张", ErrTooFewCards, len(cards))
+	}
+	if len(cards) > 7 {
+		return fmt.Errorf("%w: 有%d张", ErrTooManyCards, len(cards))
+	}
+	var seen uint64
+	for _, c := range cards {
+		if c.Rank < card.Two || c.Rank > card.Ace || c.Suit < card.Clubs || c.Suit > card.Spades {
+			return fmt.Errorf("%w: %v", ErrInvalidCard, c)
+		}
+		bit := uint64(1) << EncodeCard(c)
+		if seen&bit != 0 {
+			return fmt.Errorf("%w: %s", ErrDuplicateCard, c)
+		}
+		seen |= bit
+	}
+	return nil
+}
+
+// Evaluator 是扑克手牌评估器（逐一检查各牌型，便于理解和核对；性能敏感的场景使用 FastEvaluator）
 type Evaluator struct{}
 
 // NewEvaluator 创建一个新的评估器
@@ -66,16 +158,22 @@ func NewEvaluator() *Evaluator {
 	return &Evaluator{}
 }
 
-// Evaluate 评估一手牌（2张底牌 + 5张公共牌）
-func (e *Evaluator) Evaluate(holeCards [2]card.Card, communityCards [5]card.Card) HandEvaluation {
-	allCards := make([]card.Card, 0, 7)
-	allCards = append(allCards, holeCards[:]...)
-	allCards = append(allCards, communityCards[:]...)
-	return e.evaluate7Cards(allCards)
+// EvaluateCards 从5到7张牌中找出最佳的5张牌组合
+func (e *Evaluator) EvaluateCards(cards []card.Card) (HandEvaluation, error) {
+	if err := checkCards(cards); err != nil {
+		return HandEvaluation{}, err
+	}
+	sorted := append([]card.Card(nil), cards...)
+	s := e.evaluate(sorted)
+	return HandEvaluation{
+		Rank:     s.Rank(),
+		Strength: s,
+		RawCards: bestFive(cards, s),
+	}, nil
 }
 
-// evaluate7Cards 从7张牌中找到最佳的5张牌组合
-func (e *Evaluator) evaluate7Cards(cards []card.Card) HandEvaluation {
+// evaluate 返回牌的最佳强度（会将 cards 按点数从大到小排序）
+func (e *Evaluator) evaluate(cards []card.Card) Strength {
 	// 按花色分组
 	suitGroups := make(map[card.Suit][]card.Card)
 	// 统计每个点数出现的次数
@@ -92,59 +190,36 @@ func (e *Evaluator) evaluate7Cards(cards []card.Card) HandEvaluation {
 	})
 
 	// 从高到低检查各种牌型
-	if eval := e.checkRoyalFlush(suitGroups); eval.Rank > 0 {
-		return eval
+	if s := e.checkStraightFlush(suitGroups); s > 0 {
+		return s
 	}
-	if eval := e.checkStraightFlush(cards, suitGroups); eval.Rank > 0 {
-		return eval
+	if s := e.checkFourOfAKind(rankCounts, cards); s > 0 {
+		return s
 	}
-	if eval := e.checkFourOfAKind(rankCounts, cards); eval.Rank > 0 {
-		return eval
+	if s := e.checkFullHouse(rankCounts); s > 0 {
+		return s
 	}
-	if eval := e.checkFullHouse(rankCounts); eval.Rank > 0 {
-		return eval
+	if s := e.checkFlush(suitGroups); s > 0 {
+		return s
 	}
-	if eval := e.checkFlush(suitGroups); eval.Rank > 0 {
-		return eval
+	if s := e.checkStraight(cards); s > 0 {
+		return s
 	}
-	if eval := e.checkStraight(cards); eval.Rank > 0 {
-		return eval
+	if s := e.checkThreeOfAKind(rankCounts, cards); s > 0 {
+		return s
 	}
-	if eval := e.checkThreeOfAKind(rankCounts, cards); eval.Rank > 0 {
-		return eval
+	if s := e.checkTwoPair(rankCounts, cards); s > 0 {
+		return s
 	}
-	if eval := e.checkTwoPair(rankCounts, cards); eval.Rank > 0 {
-		return eval
-	}
-	if eval := e.checkOnePair(rankCounts, cards); eval.Rank > 0 {
-		return eval
+	if s := e.checkOnePair(rankCounts, cards); s > 0 {
+		return s
 	}
 
 	return e.checkHighCard(cards)
 }
 
-// checkRoyalFlush 检查皇家同花顺（A、K、Q、J、10 同花）
-func (e *Evaluator) checkRoyalFlush(suitGroups map[card.Suit][]card.Card) HandEvaluation {
-	for _, cards := range suitGroups {
-		if len(cards) >= 5 {
-			ranks := make(map[card.Rank]bool)
-			for _, c := range cards {
-				ranks[c.Rank] = true
-			}
-			if ranks[card.Ten] && ranks[card.Jack] && ranks[card.Queen] &&
-				ranks[card.King] && ranks[card.Ace] {
-				return HandEvaluation{
-					Rank:      RankRoyalFlush,
-					MainValue: 14, // A
-				}
-			}
-		}
-	}
-	return HandEvaluation{}
-}
-
-// checkStraightFlush 检查同花顺（5张同花色的顺子）
-func (e *Evaluator) checkStraightFlush(cards []card.Card, suitGroups map[card.Suit][]card.Card) HandEvaluation {
+// checkStraightFlush 检查同花顺（5张同花色的顺子，A 开头的为皇家同花顺）
+func (e *Evaluator) checkStraightFlush(suitGroups map[card.Suit][]card.Card) Strength {
 	for _, flushCards := range suitGroups {
 		if len(flushCards) >= 5 {
 			// 提取并排序点数
@@ -155,51 +230,38 @@ func (e *Evaluator) checkStraightFlush(cards []card.Card, suitGroups map[card.Su
 			sort.Slice(flushRanks, func(i, j int) bool {
 				return flushRanks[i] > flushRanks[j]
 			})
-			if eval := e.evaluateStraight(flushRanks); eval.Rank > 0 {
-				// 找到实际的同花顺牌
-				var straightCards []card.Card
-				for _, c := range cards {
-					if c.Suit == flushCards[0].Suit && containsRank(flushRanks, c.Rank) {
-						straightCards = append(straightCards, c)
-						if len(straightCards) == 5 {
-							break
-						}
-					}
+			// 同花 A2345（Ace 作为 1）
+			if flushRanks[0] == card.Ace {
+				flushRanks = append(flushRanks, 1)
+			}
+			if top := e.straightTop(flushRanks); top > 0 {
+				if top == card.Ace {
+					return makeStrength(RankRoyalFlush, int(top))
 				}
-				eval.RawCards = straightCards
-				eval.Rank = RankStraightFlush
-				return eval
+				return makeStrength(RankStraightFlush, int(top))
 			}
 		}
 	}
-	return HandEvaluation{}
+	return 0
 }
 
 // checkFourOfAKind 检查四条（四张相同点数）
-func (e *Evaluator) checkFourOfAKind(rankCounts map[card.Rank]int, sorted []card.Card) HandEvaluation {
+func (e *Evaluator) checkFourOfAKind(rankCounts map[card.Rank]int, sorted []card.Card) Strength {
 	for rank, count := range rankCounts {
 		if count == 4 {
-			kickers := make([]int, 0)
 			for _, c := range sorted {
 				if c.Rank != rank {
-					kickers = append(kickers, int(c.Rank))
-					if len(kickers) == 1 {
-						break
-					}
+					return makeStrength(RankFourOfAKind, int(rank), int(c.Rank))
 				}
 			}
-			return HandEvaluation{
-				Rank:      RankFourOfAKind,
-				MainValue: int(rank),
-				Kickers:   kickers,
-			}
+			return makeStrength(RankFourOfAKind, int(rank))
 		}
 	}
-	return HandEvaluation{}
+	return 0
 }
 
 // checkFullHouse 检查葫芦（三条加一对）
-func (e *Evaluator) checkFullHouse(rankCounts map[card.Rank]int) HandEvaluation {
+func (e *Evaluator) checkFullHouse(rankCounts map[card.Rank]int) Strength {
 	var threeRanks []card.Rank // 三条的点数列表
 	var pairRanks []card.Rank  // 对子的点数列表
 
@@ -216,59 +278,39 @@ func (e *Evaluator) checkFullHouse(rankCounts map[card.Rank]int) HandEvaluation
 		sort.Slice(threeRanks, func(i, j int) bool {
 			return threeRanks[i] > threeRanks[j]
 		})
-		three := threeRanks[0]
-		threeAsPair := threeRanks[1]
-
-		return HandEvaluation{
-			Rank:      RankFullHouse,
-			MainValue: int(three),
-			Kickers:   []int{int(threeAsPair)},
-		}
+		return makeStrength(RankFullHouse, int(threeRanks[0]), int(threeRanks[1]))
 	}
 
 	// 一组三条加上一组对子
 	if len(threeRanks) >= 1 && len(pairRanks) >= 1 {
-		three := threeRanks[0]
 		sort.Slice(pairRanks, func(i, j int) bool {
 			return pairRanks[i] > pairRanks[j]
 		})
-		pair := pairRanks[0]
-
-		return HandEvaluation{
-			Rank:      RankFullHouse,
-			MainValue: int(three),
-			Kickers:   []int{int(pair)},
-		}
+		return makeStrength(RankFullHouse, int(threeRanks[0]), int(pairRanks[0]))
 	}
 
-	return HandEvaluation{}
+	return 0
 }
 
 // checkFlush 检查同花（5张同花色）
-func (e *Evaluator) checkFlush(suitGroups map[card.Suit][]card.Card) HandEvaluation {
+func (e *Evaluator) checkFlush(suitGroups map[card.Suit][]card.Card) Strength {
 	for _, cards := range suitGroups {
 		if len(cards) >= 5 {
 			sort.Slice(cards, func(i, j int) bool {
 				return cards[i].Rank > cards[j].Rank
 			})
-			best5 := cards[:5]
-			kickers := make([]int, 5)
-			for i, c := range best5 {
-				kickers[i] = int(c.Rank)
-			}
-			return HandEvaluation{
-				Rank:      RankFlush,
-				MainValue: kickers[0],
-				Kickers:   kickers[1:],
-				RawCards:  best5,
+			values := make([]int, 5)
+			for i, c := range cards[:5] {
+				values[i] = int(c.Rank)
 			}
+			return makeStrength(RankFlush, values...)
 		}
 	}
-	return HandEvaluation{}
+	return 0
 }
 
 // checkStraight 检查顺子（5张连续点数）
-func (e *Evaluator) checkStraight(sorted []card.Card) HandEvaluation {
+func (e *Evaluator) checkStraight(sorted []card.Card) Strength {
 	// 去重
 	unique := make([]card.Rank, 0)
 	seen := make(map[card.Rank]bool)
@@ -279,28 +321,19 @@ func (e *Evaluator) checkStraight(sorted []card.Card) HandEvaluation {
 		}
 	}
 
-	// 检查 A2345 顺子（Ace 作为 1）
-	hasAce := false
-	for _, r := range unique {
-		if r == card.Ace {
-			hasAce = true
-			break
-		}
+	// 检查 A2345 顺子（Ace 作为 1，排在最后）
+	if unique[0] == card.Ace {
+		unique = append(unique, 1)
 	}
 
-	candidates := unique
-	if hasAce {
-		candidates = append(candidates, 1) // 将 Ace 视为 1
-		sort.Slice(candidates, func(i, j int) bool {
-			return candidates[i] > candidates[j]
-		})
+	if top := e.straightTop(unique); top > 0 {
+		return makeStrength(RankStraight, int(top))
 	}
-
-	return e.evaluateStraight(candidates)
+	return 0
 }
 
-// evaluateStraight 判断点数序列是否为顺子
-func (e *Evaluator) evaluateStraight(sortedRanks []card.Rank) HandEvaluation {
+// straightTop 返回从大到小排列的不重复点数中最大顺子的最大点数，没有顺子返回 0
+func (e *Evaluator) straightTop(sortedRanks []card.Rank) card.Rank {
 	for i := 0; i <= len(sortedRanks)-5; i++ {
 		straight := true
 		for j := 0; j < 4; j++ {
@@ -310,17 +343,14 @@ func (e *Evaluator) evaluateStraight(sortedRanks []card.Rank) HandEvaluation {
 			}
 		}
 		if straight {
-			return HandEvaluation{
-				Rank:      RankStraight,
-				MainValue: int(sortedRanks[i]),
-			}
+			return sortedRanks[i]
 		}
 	}
-	return HandEvaluation{}
+	return 0
 }
 
 // checkThreeOfAKind 检查三条（三张相同点数）
-func (e *Evaluator) checkThreeOfAKind(rankCounts map[card.Rank]int, sorted []card.Card) HandEvaluation {
+func (e *Evaluator) checkThreeOfAKind(rankCounts map[card.Rank]int, sorted []card.Card) Strength {
 	var threeRank card.Rank
 	for rank, count := range rankCounts {
 		if count == 3 && (threeRank == 0 || rank > threeRank) {
@@ -329,26 +359,22 @@ func (e *Evaluator) checkThreeOfAKind(rankCounts map[card.Rank]int, sorted []car
 	}
 
 	if threeRank > 0 {
-		kickers := make([]int, 0)
+		values := []int{int(threeRank)}
 		for _, c := range sorted {
 			if c.Rank != threeRank {
-				kickers = append(kickers, int(c.Rank))
-				if len(kickers) == 2 {
+				values = append(values, int(c.Rank))
+				if len(values) == 3 {
 					break
 				}
 			}
 		}
-		return HandEvaluation{
-			Rank:      RankThreeOfAKind,
-			MainValue: int(threeRank),
-			Kickers:   kickers,
-		}
+		return makeStrength(RankThreeOfAKind, values...)
 	}
-	return HandEvaluation{}
+	return 0
 }
 
 // checkTwoPair 检查两对
-func (e *Evaluator) checkTwoPair(rankCounts map[card.Rank]int, sorted []card.Card) HandEvaluation {
+func (e *Evaluator) checkTwoPair(rankCounts map[card.Rank]int, sorted []card.Card) Strength {
 	var pairs []card.Rank
 	for rank, count := range rankCounts {
 		if count == 2 {
@@ -361,25 +387,20 @@ func (e *Evaluator) checkTwoPair(rankCounts map[card.Rank]int, sorted []card.Car
 			return pairs[i] > pairs[j]
 		})
 
-		kicker := card.Two
+		values := []int{int(pairs[0]), int(pairs[1])}
 		for _, c := range sorted {
 			if c.Rank != pairs[0] && c.Rank != pairs[1] {
-				kicker = c.Rank
+				values = append(values, int(c.Rank))
 				break
 			}
 		}
-
-		return HandEvaluation{
-			Rank:      RankTwoPair,
-			MainValue: int(pairs[0]),
-			Kickers:   []int{int(pairs[1]), int(kicker)},
-		}
+		return makeStrength(RankTwoPair, values...)
 	}
-	return HandEvaluation{}
+	return 0
 }
 
 // checkOnePair 检查一对
-func (e *Evaluator) checkOnePair(rankCounts map[card.Rank]int, sorted []card.Card) HandEvaluation {
+func (e *Evaluator) checkOnePair(rankCounts map[card.Rank]int, sorted []card.Card) Strength {
 	var pairRank card.Rank
 	for rank, count := range rankCounts {
 		if count == 2 && (pairRank == 0 || rank > pairRank) {
@@ -388,68 +409,33 @@ func (e *Evaluator) checkOnePair(rankCounts map[card.Rank]int, sorted []card.Car
 	}
 
 	if pairRank > 0 {
-		kickers := make([]int, 0)
+		values := []int{int(pairRank)}
 		for _, c := range sorted {
 			if c.Rank != pairRank {
-				kickers = append(kickers, int(c.Rank))
-				if len(kickers) == 3 {
+				values = append(values, int(c.Rank))
+				if len(values) == 4 {
 					break
 				}
 			}
 		}
-		return HandEvaluation{
-			Rank:      RankOnePair,
-			MainValue: int(pairRank),
-			Kickers:   kickers,
-		}
+		return makeStrength(RankOnePair, values...)
 	}
-	return HandEvaluation{}
+	return 0
 }
 
 // checkHighCard 检查高牌（无任何组合时）
-func (e *Evaluator) checkHighCard(sorted []card.Card) HandEvaluation {
-	best5 := sorted
-	if len(best5) > 5 {
-		best5 = best5[:5]
-	}
-	kickers := make([]int, 0, 5)
-	for _, c := range best5 {
-		kickers = append(kickers, int(c.Rank))
-	}
-	return HandEvaluation{
-		Rank:      RankHighCard,
-		MainValue: kickers[0],
-		Kickers:   kickers[1:],
-		RawCards:  best5,
+func (e *Evaluator) checkHighCard(sorted []card.Card) Strength {
+	values := make([]int, 0, 5)
+	for _, c := range sorted[:5] {
+		values = append(values, int(c.Rank))
 	}
+	return makeStrength(RankHighCard, values...)
 }
 
 // Compare 比较两手牌
 // 返回 1 表示 h1 赢，-1 表示 h2 赢，0 表示平局
-// Rank 数值越大牌型越强（HighCard=1 < OnePair=2 < ... < RoyalFlush=10）
 func (e *Evaluator) Compare(h1, h2 HandEvaluation) int {
-	if h1.Rank != h2.Rank {
-		if h1.Rank > h2.Rank {
-			return 1 // 数字越大牌型越强
-		}
-		return -1
-	}
-	if h1.MainValue != h2.MainValue {
-		if h1.MainValue > h2.MainValue {
-			return 1
-		}
-		return -1
-	}
-	// 比较踢脚牌
-	for i := 0; i < len(h1.Kickers) && i < len(h2.Kickers); i++ {
-		if h1.Kickers[i] != h2.Kickers[i] {
-			if h1.Kickers[i] > h2.Kickers[i] {
-				return 1
-			}
-			return -1
-		}
-	}
-	return 0
+	return h1.Strength.Compare(h2.Strength)
 }
 
 // IsBetter 判断 h1 是否比 h2 强
@@ -462,12 +448,61 @@ func (e *Evaluator) IsTie(h1, h2 HandEvaluation) bool {
 	return e.Compare(h1, h2) == 0
 }
 
-// containsRank 辅助函数：检查点数列表是否包含指定点数
-func containsRank(ranks []card.Rank, target card.Rank) bool {
-	for _, r := range ranks {
-		if r == target {
-			return true
+// bestFive 按强度从牌中挑出构成最佳手牌的5张牌（按比较顺序排列）
+func bestFive(cards []card.Card, s Strength) []card.Card {
+	v := s.values()
+	rank := s.Rank()
+
+	// 每个比较点数需要的张数
+	var need []int
+	switch rank {
+	case RankStraight, RankStraightFlush, RankRoyalFlush:
+		top := v[0]
+		need = []int{1, 1, 1, 1, 1}
+		v = [5]int{top, top - 1, top - 2, top - 3, top - 4}
+		if top == 5 {
+			v[4] = int(card.Ace)
+		}
+	case RankFourOfAKind:
+		need = []int{4, 1}
+	case RankFullHouse:
+		need = []int{3, 2}
+	case RankThreeOfAKind:
+		need = []int{3, 1, 1}
+	case RankTwoPair:
+		need = []int{2, 2, 1}
+	case RankOnePair:
+		need = []int{2, 1, 1, 1}
+	default:
+		need = []int{1, 1, 1, 1, 1}
+	}
+
+	// 同花类牌型只能使用同花花色的牌
+	flushSuit := card.Suit(-1)
+	if rank == RankFlush || rank == RankStraightFlush || rank == RankRoyalFlush {
+		var suitCounts [4]int
+		for _, c := range cards {
+			suitCounts[c.Suit]++
+			if suitCounts[c.Suit] >= 5 {
+				flushSuit = c.Suit
+			}
+		}
+	}
+
+	best := make([]card.Card, 0, 5)
+	used := make([]bool, len(cards))
+	for i, n := range need {
+		for j, c := range cards {
+			if n == 0 {
+				break
+			}
+			if used[j] || int(c.Rank) != v[i] || (flushSuit >= 0 && c.Suit != flushSuit) {
+				continue
+			}
+			used[j] = true
+			best = append(best, c)
+			n--
 		}
 	}
-	return false
+	return best
 }
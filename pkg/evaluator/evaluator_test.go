@@ -1,338 +1,188 @@
 package evaluator
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
 )
 
-func TestEvaluator_RoyalFlush(t *testing.T) {
-	e := NewEvaluator()
-
-	// Royal Flush
-	hole := [2]card.Card{
-		{card.Spades, card.Ace},
-		{card.Spades, card.King},
-	}
-	community := [5]card.Card{
-		{card.Spades, card.Queen},
-		{card.Spades, card.Jack},
-		{card.Spades, card.Ten},
-		{card.Hearts, card.Two},
-		{card.Hearts, card.Three},
-	}
-
-	eval := e.Evaluate(hole, community)
-	if eval.Rank != RankRoyalFlush {
-		t.Errorf("expected Royal Flush, got %v", eval.Rank)
+// evaluators 返回所有 HandEvaluator 实现，测试对每个实现都运行一遍
+func evaluators() map[string]HandEvaluator {
+	return map[string]HandEvaluator{
+		"Evaluator":     NewEvaluator(),
+		"FastEvaluator": NewFastEvaluator(),
 	}
 }
 
-func TestEvaluator_StraightFlush(t *testing.T) {
-	e := NewEvaluator()
-
-	// Straight Flush 9-8-7-6-5 of hearts
-	hole := [2]card.Card{
-		{card.Hearts, card.Nine},
-		{card.Hearts, card.Eight},
-	}
-	community := [5]card.Card{
-		{card.Hearts, card.Seven},
-		{card.Hearts, card.Six},
-		{card.Hearts, card.Five},
-		{card.Diamonds, card.Ten},
-		{card.Clubs, card.King},
-	}
-
-	eval := e.Evaluate(hole, community)
-	if eval.Rank != RankStraightFlush {
-		t.Errorf("expected Straight Flush, got %v", eval.Rank)
-	}
-	if eval.MainValue != int(card.Nine) {
-		t.Errorf("expected 9 high, got %d", eval.MainValue)
-	}
+// parseCards 解析以空格分隔的牌，如 "As Ks Qs"
+func parseCards(t *testing.T, s string) []card.Card {
+	t.Helper()
+	var cards []card.Card
+	for _, f := range strings.Fields(s) {
+		c, err := card.ParseCard(f)
+		if err != nil {
+			t.Fatalf("parse %q: %v", f, err)
+		}
+		cards = append(cards, c)
+	}
+	return cards
 }
 
-func TestEvaluator_FourOfAKind(t *testing.T) {
-	e := NewEvaluator()
-
-	// Four of a kind Kings with Ace kicker
-	hole := [2]card.Card{
-		{card.Hearts, card.King},
-		{card.Diamonds, card.King},
-	}
-	community := [5]card.Card{
-		{card.Clubs, card.King},
-		{card.Spades, card.King},
-		{card.Hearts, card.Ace},
-		{card.Diamonds, card.Ten},
-		{card.Hearts, card.Five},
-	}
-
-	eval := e.Evaluate(hole, community)
-	if eval.Rank != RankFourOfAKind {
-		t.Errorf("expected Four of a Kind, got %v", eval.Rank)
-	}
-	if eval.MainValue != int(card.King) {
-		t.Errorf("expected Kings, got %d", eval.MainValue)
-	}
-}
-
-func TestEvaluator_FullHouse(t *testing.T) {
-	e := NewEvaluator()
-
-	// Full House: Queens over Kings
-	hole := [2]card.Card{
-		{card.Hearts, card.Queen},
-		{card.Diamonds, card.Queen},
-	}
-	community := [5]card.Card{
-		{card.Clubs, card.Queen},
-		{card.Spades, card.King},
-		{card.Hearts, card.King},
-		{card.Diamonds, card.Ten},
-		{card.Hearts, card.Five},
-	}
-
-	eval := e.Evaluate(hole, community)
-	if eval.Rank != RankFullHouse {
-		t.Errorf("expected Full House, got %v", eval.Rank)
+func TestHandEvaluator_EvaluateCards(t *testing.T) {
+	tests := []struct {
+		name  string
+		cards string
+		rank  HandRank
+		want  Strength
+		best  string // 最佳5张牌（按比较顺序）
+	}{
+		{"royal flush", "As Ks Qs Js Ts 2h 3h", RankRoyalFlush, makeStrength(RankRoyalFlush, 14), "As Ks Qs Js Ts"},
+		{"straight flush", "9h 8h 7h 6h 5h Td Kc", RankStraightFlush, makeStrength(RankStraightFlush, 9), "9h 8h 7h 6h 5h"},
+		{"straight flush over flush", "9h 8h 7h 6h 5h Ah Kc", RankStraightFlush, makeStrength(RankStraightFlush, 9), "9h 8h 7h 6h 5h"},
+		{"steel wheel", "Ah 2h 3h 4h 5h Kh 6c", RankStraightFlush, makeStrength(RankStraightFlush, 5), "5h 4h 3h 2h Ah"},
+		{"four of a kind", "Kh Kd Kc Ks Ah Td 5h", RankFourOfAKind, makeStrength(RankFourOfAKind, 13, 14), "Kh Kd Kc Ks Ah"},
+		{"full house", "Qh Qd Qc Ks Kh Td 5h", RankFullHouse, makeStrength(RankFullHouse, 12, 13), "Qh Qd Qc Ks Kh"},
+		{"full house from two trips", "Qh Qd Qc Ks Kh Kd 5h", RankFullHouse, makeStrength(RankFullHouse, 13, 12), "Ks Kh Kd Qh Qd"},
+		{"flush", "Ah Kh Jh 7h 5h 2d 3c", RankFlush, makeStrength(RankFlush, 14, 13, 11, 7, 5), "Ah Kh Jh 7h 5h"},
+		{"flush with six suited", "Ah Kh Jh 7h 5h 2h 3c", RankFlush, makeStrength(RankFlush, 14, 13, 11, 7, 5), "Ah Kh Jh 7h 5h"},
+		{"straight", "Th Jd Qc Ks Ah 2d 3c", RankStraight, makeStrength(RankStraight, 14), "Ah Ks Qc Jd Th"},
+		{"wheel", "Ah 2d 3c 4s 5h Kd Qc", RankStraight, makeStrength(RankStraight, 5), "5h 4s 3c 2d Ah"},
+		{"three of a kind", "7h 7d 7c Ks Qh Td 5h", RankThreeOfAKind, makeStrength(RankThreeOfAKind, 7, 13, 12), "7h 7d 7c Ks Qh"},
+		{"two pair", "Ah Ad Kc Ks Qh Td 5h", RankTwoPair, makeStrength(RankTwoPair, 14, 13, 12), "Ah Ad Kc Ks Qh"},
+		{"two pair from three pairs", "Ah Ad Kc Ks 5h 5d 2c", RankTwoPair, makeStrength(RankTwoPair, 14, 13, 5), "Ah Ad Kc Ks 5h"},
+		{"one pair", "Jh Jd Kc Qs 8h 5d 3h", RankOnePair, makeStrength(RankOnePair, 11, 13, 12, 8), "Jh Jd Kc Qs 8h"},
+		{"high card", "Ah Kd Qc Js 9h 5d 3h", RankHighCard, makeStrength(RankHighCard, 14, 13, 12, 11, 9), "Ah Kd Qc Js 9h"},
+		{"five cards", "Ah Ad Kc Ks Qh", RankTwoPair, makeStrength(RankTwoPair, 14, 13, 12), "Ah Ad Kc Ks Qh"},
+		{"six cards", "9h 8d 7c 6s 5h 2c", RankStraight, makeStrength(RankStraight, 9), "9h 8d 7c 6s 5h"},
+	}
+
+	for name, e := range evaluators() {
+		for _, tt := range tests {
+			eval, err := e.EvaluateCards(parseCards(t, tt.cards))
+			if err != nil {
+				t.Errorf("%s/%s: unexpected error: %v", name, tt.name, err)
+				continue
+			}
+			if eval.Rank != tt.rank || eval.Strength != tt.want {
+				t.Errorf("%s/%s: expected %s %#x, got %s %#x", name, tt.name, tt.rank, tt.want, eval.Rank, eval.Strength)
+			}
+			best := parseCards(t, tt.best)
+			if len(eval.RawCards) != 5 {
+				t.Errorf("%s/%s: expected 5 best cards, got %v", name, tt.name, eval.RawCards)
+				continue
+			}
+			for i := range best {
+				if eval.RawCards[i] != best[i] {
+					t.Errorf("%s/%s: expected best cards %v, got %v", name, tt.name, best, eval.RawCards)
+					break
+				}
+			}
+		}
 	}
 }
 
-func TestEvaluator_Flush(t *testing.T) {
+// TestEvaluator_SteelWheel 同花 A2345 是 5 高的同花顺（原先的 Evaluator 不把 A 当 1，只算成同花）
+func TestEvaluator_SteelWheel(t *testing.T) {
 	e := NewEvaluator()
-
-	// Flush in hearts (NOT a straight flush)
-	hole := [2]card.Card{
-		{card.Hearts, card.Ace},
-		{card.Hearts, card.King},
-	}
-	community := [5]card.Card{
-		{card.Hearts, card.Jack},
-		{card.Hearts, card.Seven},
-		{card.Hearts, card.Five},
-		{card.Diamonds, card.Two},
-		{card.Clubs, card.Three},
+	eval := func(cards string) HandEvaluation {
+		t.Helper()
+		h, err := e.EvaluateCards(parseCards(t, cards))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", cards, err)
+		}
+		return h
 	}
 
-	eval := e.Evaluate(hole, community)
-	if eval.Rank != RankFlush {
-		t.Errorf("expected Flush, got %v", eval.Rank)
+	wheel := eval("Ah 2h 3h 4h 5h Kh Qh")
+	if wheel.Rank != RankStraightFlush || wheel.Strength != makeStrength(RankStraightFlush, 5) {
+		t.Fatalf("expected a five-high straight flush, got %s %#x", wheel.Rank, wheel.Strength)
 	}
-}
-
-func TestEvaluator_Straight(t *testing.T) {
-	e := NewEvaluator()
-
-	// Straight 10-J-Q-K-A
-	hole := [2]card.Card{
-		{card.Hearts, card.Ten},
-		{card.Diamonds, card.Jack},
+	if quads := eval("Ah Ad Ac As 5h Kh Qh"); e.Compare(wheel, quads) != 1 {
+		t.Errorf("expected the steel wheel to beat %s", quads.Rank)
 	}
-	community := [5]card.Card{
-		{card.Clubs, card.Queen},
-		{card.Spades, card.King},
-		{card.Hearts, card.Ace},
-		{card.Diamonds, card.Two},
-		{card.Clubs, card.Three},
+	if six := eval("6h 2h 3h 4h 5h Kh Qh"); e.Compare(wheel, six) != -1 {
+		t.Errorf("expected the steel wheel to lose to a six-high straight flush")
 	}
 
-	eval := e.Evaluate(hole, community)
-	if eval.Rank != RankStraight {
-		t.Errorf("expected Straight, got %v", eval.Rank)
-	}
-	if eval.MainValue != int(card.Ace) {
-		t.Errorf("expected Ace high, got %d", eval.MainValue)
+	// 同花和 A2345 顺子不是同一花色时只是同花
+	if flush := eval("Ah 2h 3h 4h 5c 9h Kd"); flush.Rank != RankFlush {
+		t.Errorf("expected a flush when the wheel is not suited, got %s", flush.Rank)
 	}
 }
 
-func TestEvaluator_A2345Straight(t *testing.T) {
-	e := NewEvaluator()
-
-	// A-2-3-4-5 straight (wheel)
-	hole := [2]card.Card{
-		{card.Hearts, card.Ace},
-		{card.Diamonds, card.Two},
-	}
-	community := [5]card.Card{
-		{card.Clubs, card.Three},
-		{card.Spades, card.Four},
-		{card.Hearts, card.Five},
-		{card.Diamonds, card.King},
-		{card.Clubs, card.Queen},
-	}
-
-	eval := e.Evaluate(hole, community)
-	if eval.Rank != RankStraight {
-		t.Errorf("expected Straight, got %v", eval.Rank)
-	}
-	if eval.MainValue != int(card.Five) {
-		t.Errorf("expected 5 high straight, got %d", eval.MainValue)
+func TestHandEvaluator_Compare(t *testing.T) {
+	tests := []struct {
+		name   string
+		h1, h2 string
+		want   int
+	}{
+		{"pair of aces beats pair of kings", "Ah Ad Tc Qs Jh 8d 2c", "Kh Kd Tc Qs Jh 8d 2c", 1},
+		{"two pair beats one pair", "Th 2d Tc Qs Jh 8d 2c", "Kh Kd Tc Qs Jh 8d 2c", 1},
+		{"kicker decides", "Ah Kd Ac 7s 5h 3d 2c", "As Qd Ac 7s 5h 3d 2c", 1},
+		{"board plays", "Ah Kd Qc Js 9h 5d 3h", "Ac Ks Qc Js 9h 5d 3h", 0},
+		{"wheel loses to six-high straight", "Ah 2d 3c 4s 5h Kd Qc", "6h 2d 3c 4s 5h Kd Qc", -1},
+		{"flush beats straight", "Ah Kh Jh 7h 5h 2d 3c", "Th Jd Qc Ks Ah 2d 3c", 1},
+		{"higher trips make the better full house", "Qh Qd Qc Ks Kh Td 5h", "Jh Jd Jc As Ah Td 5h", 1},
+		{"fifth flush card decides", "Ah Kh Jh 7h 5h 2d 3c", "Ah Kh Jh 7h 4h 2d 3c", 1},
+	}
+
+	for name, e := range evaluators() {
+		for _, tt := range tests {
+			h1, err1 := e.EvaluateCards(parseCards(t, tt.h1))
+			h2, err2 := e.EvaluateCards(parseCards(t, tt.h2))
+			if err1 != nil || err2 != nil {
+				t.Fatalf("%s/%s: unexpected error: %v / %v", name, tt.name, err1, err2)
+			}
+			if got := e.Compare(h1, h2); got != tt.want {
+				t.Errorf("%s/%s: Compare = %d, want %d (%s %#x vs %s %#x)",
+					name, tt.name, got, tt.want, h1.Rank, h1.Strength, h2.Rank, h2.Strength)
+			}
+			if got := e.Compare(h2, h1); got != -tt.want {
+				t.Errorf("%s/%s: reversed Compare = %d, want %d", name, tt.name, got, -tt.want)
+			}
+		}
 	}
 }
 
-func TestEvaluator_ThreeOfAKind(t *testing.T) {
-	e := NewEvaluator()
-
-	// Three of a kind Sevens
-	hole := [2]card.Card{
-		{card.Hearts, card.Seven},
-		{card.Diamonds, card.Seven},
-	}
-	community := [5]card.Card{
-		{card.Clubs, card.Seven},
-		{card.Spades, card.King},
-		{card.Hearts, card.Queen},
-		{card.Diamonds, card.Ten},
-		{card.Hearts, card.Five},
-	}
-
-	eval := e.Evaluate(hole, community)
-	if eval.Rank != RankThreeOfAKind {
-		t.Errorf("expected Three of a Kind, got %v", eval.Rank)
+func TestHandEvaluator_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		cards []card.Card
+		want  error
+	}{
+		{"too few cards", parseCards(t, "Ah Kd Qc Js"), ErrTooFewCards},
+		{"too many cards", parseCards(t, "Ah Kd Qc Js 9h 5d 3h 2c"), ErrTooManyCards},
+		{"duplicate card", parseCards(t, "Ah Kd Qc Js Ah"), ErrDuplicateCard},
+		{"undealt card", append(parseCards(t, "Ah Kd Qc Js"), card.Card{}), ErrInvalidCard},
+		{"invalid suit", append(parseCards(t, "Ah Kd Qc Js"), card.NewCard(card.Suit(7), card.Ace)), ErrInvalidCard},
+	}
+
+	for name, e := range evaluators() {
+		for _, tt := range tests {
+			if _, err := e.EvaluateCards(tt.cards); !errors.Is(err, tt.want) {
+				t.Errorf("%s/%s: expected %v, got %v", name, tt.name, tt.want, err)
+			}
+		}
 	}
 }
 
-func TestEvaluator_TwoPair(t *testing.T) {
-	e := NewEvaluator()
-
-	// Two pair: Aces and Kings
-	hole := [2]card.Card{
-		{card.Hearts, card.Ace},
-		{card.Diamonds, card.Ace},
-	}
-	community := [5]card.Card{
-		{card.Clubs, card.King},
-		{card.Spades, card.King},
-		{card.Hearts, card.Queen},
-		{card.Diamonds, card.Ten},
-		{card.Hearts, card.Five},
-	}
-
-	eval := e.Evaluate(hole, community)
-	if eval.Rank != RankTwoPair {
-		t.Errorf("expected Two Pair, got %v", eval.Rank)
-	}
-}
-
-func TestEvaluator_OnePair(t *testing.T) {
-	e := NewEvaluator()
-
-	// One pair: Jacks (no straight possible)
-	hole := [2]card.Card{
-		{card.Hearts, card.Jack},
-		{card.Diamonds, card.Jack},
-	}
-	community := [5]card.Card{
-		{card.Clubs, card.King},
-		{card.Spades, card.Queen},
-		{card.Hearts, card.Eight},
-		{card.Diamonds, card.Five},
-		{card.Hearts, card.Three},
-	}
-
-	eval := e.Evaluate(hole, community)
-	if eval.Rank != RankOnePair {
-		t.Errorf("expected One Pair, got %v", eval.Rank)
-	}
-}
-
-func TestEvaluator_HighCard(t *testing.T) {
-	e := NewEvaluator()
-
-	// High card: Ace (no straight possible)
-	hole := [2]card.Card{
-		{card.Hearts, card.Ace},
-		{card.Diamonds, card.King},
-	}
-	community := [5]card.Card{
-		{card.Clubs, card.Queen},
-		{card.Spades, card.Jack},
-		{card.Hearts, card.Nine},
-		{card.Diamonds, card.Five},
-		{card.Hearts, card.Three},
-	}
-
-	eval := e.Evaluate(hole, community)
-	if eval.Rank != RankHighCard {
-		t.Errorf("expected High Card, got %v", eval.Rank)
-	}
-}
-
-func TestEvaluator_Compare(t *testing.T) {
-	e := NewEvaluator()
-
-	// 公共牌不包含 A 和 K，确保玩家只有各自的一对
-	community := [5]card.Card{
-		{card.Clubs, card.Ten},
-		{card.Spades, card.Queen},
-		{card.Hearts, card.Jack},
-		{card.Diamonds, card.Eight},
-		{card.Clubs, card.Two},
-	}
-
-	// 玩家1: 一对 A（最大的一对）
-	hole1 := [2]card.Card{
-		{card.Hearts, card.Ace},
-		{card.Diamonds, card.Ace},
-	}
-
-	// 玩家2: 一对 K
-	hole2 := [2]card.Card{
-		{card.Hearts, card.King},
-		{card.Diamonds, card.King},
-	}
-
-	eval1 := e.Evaluate(hole1, community)
-	eval2 := e.Evaluate(hole2, community)
-
-	// 一对 A 应该赢一对 K
-	cmp := e.Compare(eval1, eval2)
-	if cmp != 1 {
-		t.Errorf("一对A应该赢一对K, 实际结果: cmp=%d, eval1=%s(%d), eval2=%s(%d)",
-			cmp, eval1.Rank, eval1.MainValue, eval2.Rank, eval2.MainValue)
-	}
-
-	// 额外测试：两对 应该赢 一对
-	hole3 := [2]card.Card{
-		{card.Hearts, card.Ten},   // 和公共牌的 10♣ 组成一对 10
-		{card.Diamonds, card.Two}, // 和公共牌的 2♣ 组成一对 2
-	}
-	eval3 := e.Evaluate(hole3, community)
-
-	cmp2 := e.Compare(eval3, eval2)
-	if cmp2 != 1 {
-		t.Errorf("两对应该赢一对, 实际结果: cmp=%d, eval3=%s(%d), eval2=%s(%d)",
-			cmp2, eval3.Rank, eval3.MainValue, eval2.Rank, eval2.MainValue)
-	}
-}
-
-func TestEvaluator_Tie(t *testing.T) {
-	e := NewEvaluator()
-
-	// Both players have identical hands
-	hole1 := [2]card.Card{
-		{card.Hearts, card.Ace},
-		{card.Diamonds, card.King},
-	}
-	hole2 := [2]card.Card{
-		{card.Clubs, card.Ace},
-		{card.Spades, card.King},
-	}
-	community := [5]card.Card{
-		{card.Clubs, card.Queen},
-		{card.Spades, card.Jack},
-		{card.Hearts, card.Ten},
-		{card.Diamonds, card.Nine},
-		{card.Hearts, card.Five},
-	}
-
-	eval1 := e.Evaluate(hole1, community)
-	eval2 := e.Evaluate(hole2, community)
-
-	cmp := e.Compare(eval1, eval2)
-	if cmp != 0 {
-		t.Error("Players should tie with identical high cards")
+func TestEvaluateHand(t *testing.T) {
+	hole := [2]card.Card(parseCards(t, "Ah As"))
+
+	for name, e := range evaluators() {
+		// 只发了翻牌：评估已发出的5张牌
+		var board [5]card.Card
+		copy(board[:], parseCards(t, "Ac Kd Kc"))
+		eval, err := EvaluateHand(e, hole, board)
+		if err != nil || eval.Rank != RankFullHouse {
+			t.Errorf("%s: expected full house on the flop, got %s (%v)", name, eval.Rank, err)
+		}
+
+		// 翻牌前没有公共牌
+		if _, err := EvaluateHand(e, hole, [5]card.Card{}); !errors.Is(err, ErrTooFewCards) {
+			t.Errorf("%s: expected ErrTooFewCards before the flop, got %v", name, err)
+		}
 	}
 }
@@ -0,0 +1,265 @@
+package evaluator
+
+import (
+	"math/bits"
+	"sync"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+)
+
+// ==================== 快速评估器 ====================
+//
+// FastEvaluator 使用预先计算的查找表评估手牌：
+//   - 某个花色有 5 张及以上时，用该花色的 13 位点数掩码查同花表（同花时不可能组成葫芦或四条）
+//   - 否则把 13 个点数的张数看作一个五进制数，用完美哈希查非同花表（5/6/7 张牌各一张表）
+//
+// 热点路径（Strength7/StrengthOf）直接使用 CardCode，不分配内存，也不检查牌是否有效或重复
+
+// CardCode 紧凑的整数扑克牌编码：点数索引(0-12) * 4 + 花色(0-3)，取值 0-51
+type CardCode uint8
+
+// EncodeCard 将扑克牌编码为 CardCode（c 必须是有效的牌）
+func EncodeCard(c card.Card) CardCode {
+	return CardCode(int(c.Rank-card.Two)*4 + int(c.Suit))
+}
+
+// Card 将 CardCode 解码为扑克牌
+func (c CardCode) Card() card.Card {
+	return card.NewCard(card.Suit(c&3), card.Rank(c>>2)+card.Two)
+}
+
+// FastEvaluator 基于查找表的快速手牌评估器（实现 HandEvaluator，结果与 Evaluator 一致）
+type FastEvaluator struct{}
+
+// NewFastEvaluator 创建快速评估器（首次创建时生成查找表）
+func NewFastEvaluator() *FastEvaluator {
+	fastTablesOnce.Do(buildFastTables)
+	return &FastEvaluator{}
+}
+
+// EvaluateCards 从5到7张牌中找出最佳的5张牌组合
+func (e *FastEvaluator) EvaluateCards(cards []card.Card) (HandEvaluation, error) {
+	if err := checkCards(cards); err != nil {
+		return HandEvaluation{}, err
+	}
+	codes := make([]CardCode, len(cards))
+	for i, c := range cards {
+		codes[i] = EncodeCard(c)
+	}
+	s := e.StrengthOf(codes...)
+	return HandEvaluation{
+		Rank:     s.Rank(),
+		Strength: s,
+		RawCards: bestFive(cards, s),
+	}, nil
+}
+
+// Compare 比较两手牌（返回 1 表示 h1 赢，-1 表示 h2 赢，0 表示平局）
+func (e *FastEvaluator) Compare(h1, h2 HandEvaluation) int {
+	return h1.Strength.Compare(h2.Strength)
+}
+
+// Strength7 返回7张牌的最佳强度（热点路径，不分配内存）
+func (e *FastEvaluator) Strength7(cards [7]CardCode) Strength {
+	var masks [4]uint16
+	var counts [13]uint8
+	for _, c := range cards {
+		masks[c&3] |= 1 << (c >> 2)
+		counts[c>>2]++
+	}
+	for _, m := range masks {
+		if bits.OnesCount16(m) >= 5 {
+			return flushTable[m]
+		}
+	}
+	return noFlushTables[7][quinaryHash(&counts, 7)]
+}
+
+// StrengthOf 返回5到7张牌的最佳强度（牌数不在范围内时返回 0）
+func (e *FastEvaluator) StrengthOf(cards ...CardCode) Strength {
+	n := len(cards)
+	if n < 5 || n > 7 {
+		return 0
+	}
+	var masks [4]uint16
+	var counts [13]uint8
+	for _, c := range cards {
+		masks[c&3] |= 1 << (c >> 2)
+		counts[c>>2]++
+	}
+	for _, m := range masks {
+		if bits.OnesCount16(m) >= 5 {
+			return flushTable[m]
+		}
+	}
+	return noFlushTables[n][quinaryHash(&counts, n)]
+}
+
+// ==================== 查找表 ====================
+
+var (
+	fastTablesOnce sync.Once
+
+	// flushTable 同花表：以同一花色的点数掩码为索引（至少5位）
+	flushTable [1 << 13]Strength
+
+	// noFlushTables 非同花表：以点数张数的五进制完美哈希为索引，按牌数（5-7）分表
+	noFlushTables [8][]Strength
+
+	// quinaryWays[m][s] 长度为 m、每位 0-4、各位之和为 s 的五进制数的个数
+	quinaryWays [14][8]int
+
+	// quinaryOffset[i][q][k] 第 i 位取 q、剩余张数为 k 时哈希值的增量
+	quinaryOffset [13][5][8]int32
+)
+
+// buildFastTables 生成所有查找表
+func buildFastTables() {
+	// 五进制计数
+	quinaryWays[0][0] = 1
+	for m := 1; m <= 13; m++ {
+		for s := 0; s <= 7; s++ {
+			for d := 0; d <= 4 && d <= s; d++ {
+				quinaryWays[m][s] += quinaryWays[m-1][s-d]
+			}
+		}
+	}
+	// 第 i 位取 q 时，排在前面的是该位取 0..q-1 的所有组合
+	for i := 0; i < 13; i++ {
+		for q := 0; q <= 4; q++ {
+			for k := 0; k <= 7; k++ {
+				sum := 0
+				for d := 0; d < q && d <= k; d++ {
+					sum += quinaryWays[12-i][k-d]
+				}
+				quinaryOffset[i][q][k] = int32(sum)
+			}
+		}
+	}
+
+	// 同花表
+	for mask := 0; mask < len(flushTable); mask++ {
+		if bits.OnesCount16(uint16(mask)) >= 5 {
+			flushTable[mask] = flushStrength(uint16(mask))
+		}
+	}
+
+	// 非同花表：枚举所有张数组合
+	for n := 5; n <= 7; n++ {
+		table := make([]Strength, quinaryWays[13][n])
+		var counts [13]uint8
+		var fill func(i, left int)
+		fill = func(i, left int) {
+			if i == 13 {
+				if left == 0 {
+					table[quinaryHash(&counts, n)] = rankStrength(&counts)
+				}
+				return
+			}
+			for d := 0; d <= 4 && d <= left; d++ {
+				counts[i] = uint8(d)
+				fill(i+1, left-d)
+			}
+			counts[i] = 0
+		}
+		fill(0, n)
+		noFlushTables[n] = table
+	}
+}
+
+// quinaryHash 将点数张数（共 n 张）映射为 [0, quinaryWays[13][n]) 内的唯一索引
+func quinaryHash(counts *[13]uint8, n int) int {
+	hash := int32(0)
+	k := n
+	for i, q := range counts {
+		hash += quinaryOffset[i][q][k]
+		k -= int(q)
+	}
+	return int(hash)
+}
+
+// straightTop 返回点数掩码中最大顺子的最大点数（A2345 为 5），没有顺子返回 0
+func straightTop(mask uint16) int {
+	for top := 12; top >= 4; top-- {
+		if (mask>>(top-4))&0x1F == 0x1F {
+			return top + 2
+		}
+	}
+	// A2345：A 的位加上 2-5 的位
+	if mask&0x100F == 0x100F {
+		return 5
+	}
+	return 0
+}
+
+// flushStrength 同花（或同花顺）的强度
+func flushStrength(mask uint16) Strength {
+	if top := straightTop(mask); top > 0 {
+		if top == int(card.Ace) {
+			return makeStrength(RankRoyalFlush, top)
+		}
+		return makeStrength(RankStraightFlush, top)
+	}
+	return makeStrength(RankFlush, topRanks(mask, 5)...)
+}
+
+// topRanks 返回点数掩码中最大的 n 个点数（从大到小）
+func topRanks(mask uint16, n int) []int {
+	values := make([]int, 0, n)
+	for i := 12; i >= 0 && len(values) < n; i-- {
+		if mask&(1<<i) != 0 {
+			values = append(values, i+2)
+		}
+	}
+	return values
+}
+
+// rankStrength 不考虑同花时，由点数张数得出的最佳强度
+func rankStrength(counts *[13]uint8) Strength {
+	var present uint16
+	var quads, trips, pairs []int
+	for i := 12; i >= 0; i-- {
+		c := counts[i]
+		if c > 0 {
+			present |= 1 << i
+		}
+		switch {
+		case c == 4:
+			quads = append(quads, i)
+		case c == 3:
+			trips = append(trips, i)
+		case c == 2:
+			pairs = append(pairs, i)
+		}
+	}
+
+	// kickers 返回除 used 之外最大的 n 个点数
+	kickers := func(n int, used ...int) []int {
+		mask := present
+		for _, u := range used {
+			mask &^= 1 << u
+		}
+		return topRanks(mask, n)
+	}
+
+	switch {
+	case len(quads) > 0:
+		return makeStrength(RankFourOfAKind, append([]int{quads[0] + 2}, kickers(1, quads[0])...)...)
+	case len(trips) >= 2:
+		return makeStrength(RankFullHouse, trips[0]+2, trips[1]+2)
+	case len(trips) == 1 && len(pairs) > 0:
+		return makeStrength(RankFullHouse, trips[0]+2, pairs[0]+2)
+	}
+	if top := straightTop(present); top > 0 {
+		return makeStrength(RankStraight, top)
+	}
+	switch {
+	case len(trips) == 1:
+		return makeStrength(RankThreeOfAKind, append([]int{trips[0] + 2}, kickers(2, trips[0])...)...)
+	case len(pairs) >= 2:
+		return makeStrength(RankTwoPair, append([]int{pairs[0] + 2, pairs[1] + 2}, kickers(1, pairs[0], pairs[1])...)...)
+	case len(pairs) == 1:
+		return makeStrength(RankOnePair, append([]int{pairs[0] + 2}, kickers(3, pairs[0])...)...)
+	}
+	return makeStrength(RankHighCard, topRanks(present, 5)...)
+}
@@ -0,0 +1,214 @@
+package evaluator
+
+import (
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+)
+
+// allCodes 返回52张牌的编码
+func allCodes() []CardCode {
+	codes := make([]CardCode, 52)
+	for i := range codes {
+		codes[i] = CardCode(i)
+	}
+	return codes
+}
+
+// randomHand 洗牌后取出前7张牌
+func randomHand(r *rand.Rand, deck []card.Card) []card.Card {
+	r.Shuffle(len(deck), func(i, j int) { deck[i], deck[j] = deck[j], deck[i] })
+	return append([]card.Card(nil), deck[:7]...)
+}
+
+func TestEncodeCard(t *testing.T) {
+	seen := make(map[CardCode]bool)
+	for _, c := range card.NewDeck().Cards() {
+		code := EncodeCard(c)
+		if code > 51 || seen[code] {
+			t.Fatalf("Invalid or duplicate code %d for %s", code, c)
+		}
+		seen[code] = true
+		if code.Card() != c {
+			t.Errorf("Expected %s to round-trip, got %s", c, code.Card())
+		}
+	}
+}
+
+// TestFastEvaluator_All5CardHands 遍历全部 2,598,960 种5张牌组合，检查各牌型的数量
+func TestFastEvaluator_All5CardHands(t *testing.T) {
+	e := NewFastEvaluator()
+	codes := allCodes()
+	counts := make(map[HandRank]int)
+	for a := 0; a < 52; a++ {
+		for b := a + 1; b < 52; b++ {
+			for c := b + 1; c < 52; c++ {
+				for d := c + 1; d < 52; d++ {
+					for f := d + 1; f < 52; f++ {
+						counts[e.StrengthOf(codes[a], codes[b], codes[c], codes[d], codes[f]).Rank()]++
+					}
+				}
+			}
+		}
+	}
+
+	want := map[HandRank]int{
+		RankRoyalFlush:    4,
+		RankStraightFlush: 36,
+		RankFourOfAKind:   624,
+		RankFullHouse:     3744,
+		RankFlush:         5108,
+		RankStraight:      10200,
+		RankThreeOfAKind:  54912,
+		RankTwoPair:       123552,
+		RankOnePair:       1098240,
+		RankHighCard:      1302540,
+	}
+	for rank, n := range want {
+		if counts[rank] != n {
+			t.Errorf("%s: expected %d hands, got %d", rank, n, counts[rank])
+		}
+	}
+}
+
+// TestFastEvaluator_All7CardHands 遍历全部 133,784,560 种7张牌组合，检查各牌型的数量
+// 耗时较长，设置环境变量 EVALUATOR_EXHAUSTIVE=1 时才运行
+func TestFastEvaluator_All7CardHands(t *testing.T) {
+	if os.Getenv("EVALUATOR_EXHAUSTIVE") == "" {
+		t.Skip("set EVALUATOR_EXHAUSTIVE=1 to enumerate all 7-card hands")
+	}
+
+	e := NewFastEvaluator()
+	var counts [RankRoyalFlush + 1]int
+	var hand [7]CardCode
+	for a := 0; a < 52; a++ {
+		hand[0] = CardCode(a)
+		for b := a + 1; b < 52; b++ {
+			hand[1] = CardCode(b)
+			for c := b + 1; c < 52; c++ {
+				hand[2] = CardCode(c)
+				for d := c + 1; d < 52; d++ {
+					hand[3] = CardCode(d)
+					for f := d + 1; f < 52; f++ {
+						hand[4] = CardCode(f)
+						for g := f + 1; g < 52; g++ {
+							hand[5] = CardCode(g)
+							for h := g + 1; h < 52; h++ {
+								hand[6] = CardCode(h)
+								counts[e.Strength7(hand).Rank()]++
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	want := map[HandRank]int{
+		RankRoyalFlush:    4324,
+		RankStraightFlush: 37260,
+		RankFourOfAKind:   224848,
+		RankFullHouse:     3473184,
+		RankFlush:         4047644,
+		RankStraight:      6180020,
+		RankThreeOfAKind:  6461620,
+		RankTwoPair:       31433400,
+		RankOnePair:       58627800,
+		RankHighCard:      23294460,
+	}
+	for rank, n := range want {
+		if counts[rank] != n {
+			t.Errorf("%s: expected %d hands, got %d", rank, n, counts[rank])
+		}
+	}
+}
+
+// TestFastEvaluator_MatchesEvaluator 随机抽样，与 Evaluator 的强度、最佳5张牌和胜负比较结果逐一核对
+func TestFastEvaluator_MatchesEvaluator(t *testing.T) {
+	samples := 200000
+	if testing.Short() {
+		samples = 20000
+	}
+
+	slow := NewEvaluator()
+	fast := NewFastEvaluator()
+	r := rand.New(rand.NewSource(1))
+	deck := card.NewDeck().Cards()
+
+	var prevSlow, prevFast HandEvaluation
+	for i := 0; i < samples; i++ {
+		cards := randomHand(r, deck)
+		se, err1 := slow.EvaluateCards(cards)
+		fe, err2 := fast.EvaluateCards(cards)
+		if err1 != nil || err2 != nil {
+			t.Fatalf("%v: unexpected error: %v / %v", cards, err1, err2)
+		}
+
+		var codes [7]CardCode
+		for j, c := range cards {
+			codes[j] = EncodeCard(c)
+		}
+		strength := fast.Strength7(codes)
+
+		if se.Strength != fe.Strength || strength != se.Strength {
+			t.Fatalf("%v: Evaluator says %s %#x, FastEvaluator says %s %#x / %#x",
+				cards, se.Rank, se.Strength, fe.Rank, fe.Strength, strength)
+		}
+		for j := range se.RawCards {
+			if se.RawCards[j] != fe.RawCards[j] {
+				t.Fatalf("%v: best cards differ: %v / %v", cards, se.RawCards, fe.RawCards)
+			}
+		}
+
+		if i > 0 {
+			want := slow.Compare(se, prevSlow)
+			if got := fast.Compare(fe, prevFast); got != want {
+				t.Fatalf("%v: Compare = %d, want %d", cards, got, want)
+			}
+		}
+		prevSlow, prevFast = se, fe
+	}
+}
+
+func BenchmarkEvaluator_EvaluateCards(b *testing.B) {
+	e := NewEvaluator()
+	r := rand.New(rand.NewSource(1))
+	deck := card.NewDeck().Cards()
+	cards := randomHand(r, deck)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		e.EvaluateCards(cards)
+	}
+}
+
+func BenchmarkFastEvaluator_EvaluateCards(b *testing.B) {
+	e := NewFastEvaluator()
+	r := rand.New(rand.NewSource(1))
+	deck := card.NewDeck().Cards()
+	cards := randomHand(r, deck)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		e.EvaluateCards(cards)
+	}
+}
+
+func BenchmarkFastEvaluator_Strength7(b *testing.B) {
+	e := NewFastEvaluator()
+	r := rand.New(rand.NewSource(1))
+	hands := make([][7]CardCode, 1024)
+	codes := allCodes()
+	for i := range hands {
+		r.Shuffle(len(codes), func(x, y int) { codes[x], codes[y] = codes[y], codes[x] })
+		copy(hands[i][:], codes[:7])
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.Strength7(hands[i&1023])
+	}
+}
@@ -0,0 +1,157 @@
+// Package fair 实现可验证公平（provably fair）的洗牌
+//
+// 每局开始前服务器生成 32 字节的随机种子，只公布种子的 SHA-256 摘要（承诺）；
+// 本局结束后公布种子，玩家可以自己验证：
+//
+//  1. SHA-256(种子) 等于开局时公布的承诺；
+//  2. 用种子按下面的算法洗牌得到的牌序与实际发出的牌一致。
+//
+// 洗牌算法：从按花色（♣♦♥♠）和点数（2…A）排列的新牌开始做 Fisher-Yates 洗牌，
+// i 从 51 递减到 1，每次取 j = Intn(i+1) 并交换第 i 和第 j 张。
+// Intn 使用的随机数流为 SHA-256(种子 ‖ 计数器) 依次拼接（计数器为 8 字节大端序，从 0 开始），
+// 每次读取 8 字节作为大端序 uint64，落在 n 的整数倍之外时丢弃重取（拒绝采样，保证均匀）。
+package fair
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+)
+
+// SeedSize 种子字节数
+const SeedSize = 32
+
+// ==================== 错误定义 ====================
+var (
+	ErrInvalidSeed        = errors.New("无效的种子")
+	ErrCommitmentMismatch = errors.New("种子与承诺不符")
+	ErrDealMismatch       = errors.New("发出的牌与种子生成的牌序不符")
+)
+
+// Shuffle 一局的可验证洗牌
+type Shuffle struct {
+	seed []byte
+}
+
+// NewShuffle 使用 crypto/rand 生成新的种子
+func NewShuffle() (*Shuffle, error) {
+	seed := make([]byte, SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+	return &Shuffle{seed: seed}, nil
+}
+
+// ParseSeed 从十六进制种子恢复洗牌
+func ParseSeed(seedHex string) (*Shuffle, error) {
+	seed, err := hex.DecodeString(seedHex)
+	if err != nil || len(seed) == 0 {
+		return nil, ErrInvalidSeed
+	}
+	return &Shuffle{seed: seed}, nil
+}
+
+// Commitment 返回开局前公布的承诺（种子 SHA-256 的十六进制）
+func (s *Shuffle) Commitment() string {
+	sum := sha256.Sum256(s.seed)
+	return hex.EncodeToString(sum[:])
+}
+
+// Seed 返回十六进制种子（本局结束后才能公布）
+func (s *Shuffle) Seed() string {
+	return hex.EncodeToString(s.seed)
+}
+
+// Source 返回由种子确定的随机数来源
+func (s *Shuffle) Source() card.RandomSource {
+	return &stream{seed: s.seed}
+}
+
+// Deck 返回用种子洗好的牌
+func (s *Shuffle) Deck() *card.Deck {
+	deck := card.NewDeck()
+	deck.ShuffleWith(s.Source())
+	return deck
+}
+
+// Verify 验证种子与承诺相符，返回种子生成的完整牌序
+func Verify(commitment, seedHex string) ([]card.Card, error) {
+	s, err := ParseSeed(seedHex)
+	if err != nil {
+		return nil, err
+	}
+	if s.Commitment() != commitment {
+		return nil, ErrCommitmentMismatch
+	}
+	return s.Deck().Cards(), nil
+}
+
+// CheckDeal 检查自己的底牌和公共牌是否按发牌顺序出现在牌序中：
+// 第一张牌烧掉后依次发底牌（每人连续两张），之后翻牌、转牌、河牌前各烧一张
+func CheckDeal(deck []card.Card, hole [2]card.Card, board []card.Card) error {
+	pos := make(map[card.Card]int, len(deck))
+	for i, c := range deck {
+		pos[c] = i
+	}
+
+	flop := len(deck)
+	if len(board) > 0 {
+		flop = pos[board[0]]
+		// 翻牌连续三张，转牌和河牌前各烧一张
+		offsets := []int{0, 1, 2, 4, 6}
+		for i, c := range board {
+			if i >= len(offsets) || pos[c] != flop+offsets[i] {
+				return fmt.Errorf("%w: 公共牌 %s", ErrDealMismatch, c)
+			}
+		}
+	}
+
+	if hole[0].Rank != 0 {
+		i := pos[hole[0]]
+		if i%2 != 1 || pos[hole[1]] != i+1 || i+1 >= flop {
+			return fmt.Errorf("%w: 底牌 %s %s", ErrDealMismatch, hole[0], hole[1])
+		}
+	}
+	return nil
+}
+
+// ==================== 随机数流 ====================
+
+// stream 由种子确定的随机数流：SHA-256(种子 ‖ 计数器) 依次拼接
+type stream struct {
+	seed    []byte
+	counter uint64
+	buf     []byte
+}
+
+// Intn 返回 [0, n) 内均匀分布的随机数
+func (s *stream) Intn(n int) int {
+	bound := uint64(n)
+	limit := ^uint64(0) - ^uint64(0)%bound
+	for {
+		v := s.next()
+		if v < limit {
+			return int(v % bound)
+		}
+	}
+}
+
+// next 读取下一个 uint64
+func (s *stream) next() uint64 {
+	if len(s.buf) < 8 {
+		block := make([]byte, len(s.seed)+8)
+		copy(block, s.seed)
+		binary.BigEndian.PutUint64(block[len(s.seed):], s.counter)
+		s.counter++
+		sum := sha256.Sum256(block)
+		s.buf = append(s.buf, sum[:]...)
+	}
+	v := binary.BigEndian.Uint64(s.buf[:8])
+	s.buf = s.buf[8:]
+	return v
+}
@@ -0,0 +1,82 @@
+package fair
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+)
+
+func TestShuffle_KnownVector(t *testing.T) {
+	// 全零种子的固定结果，第三方验证工具可以用它核对算法实现
+	s, err := ParseSeed(strings.Repeat("00", SeedSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Commitment(); got != "66687aadf862bd776c8fc18b8e9f8e20089714856ee233b3902a591d0d5f2925" {
+		t.Errorf("unexpected commitment %s", got)
+	}
+
+	var top []string
+	for _, c := range s.Deck().Cards()[:8] {
+		top = append(top, c.ShortString())
+	}
+	if got := strings.Join(top, " "); got != "Js As 9c 9s Kd 4d Ac 2h" {
+		t.Errorf("unexpected deck order %s", got)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	s, err := NewShuffle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Seed()) != SeedSize*2 {
+		t.Errorf("expected %d hex chars, got %d", SeedSize*2, len(s.Seed()))
+	}
+
+	deck, err := Verify(s.Commitment(), s.Seed())
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if _, err := card.NewDeckFromCards(deck); err != nil {
+		t.Errorf("derived deck invalid: %v", err)
+	}
+	for i, c := range s.Deck().Cards() {
+		if deck[i] != c {
+			t.Fatalf("card %d: expected %v, got %v", i, c, deck[i])
+		}
+	}
+
+	other, _ := NewShuffle()
+	if _, err := Verify(s.Commitment(), other.Seed()); !errors.Is(err, ErrCommitmentMismatch) {
+		t.Errorf("expected ErrCommitmentMismatch, got %v", err)
+	}
+	if _, err := Verify(s.Commitment(), "not-hex"); !errors.Is(err, ErrInvalidSeed) {
+		t.Errorf("expected ErrInvalidSeed, got %v", err)
+	}
+}
+
+func TestCheckDeal(t *testing.T) {
+	s, _ := NewShuffle()
+	deck := s.Deck().Cards()
+
+	// 三人桌：烧1张，底牌 1-6，烧7，翻牌 8-10，烧11，转牌 12，烧13，河牌 14
+	hole := [2]card.Card{deck[3], deck[4]}
+	board := []card.Card{deck[8], deck[9], deck[10], deck[12], deck[14]}
+	if err := CheckDeal(deck, hole, board); err != nil {
+		t.Errorf("valid deal rejected: %v", err)
+	}
+	if err := CheckDeal(deck, [2]card.Card{}, board[:3]); err != nil {
+		t.Errorf("flop only, no hole cards: %v", err)
+	}
+
+	if err := CheckDeal(deck, [2]card.Card{deck[2], deck[3]}, board); !errors.Is(err, ErrDealMismatch) {
+		t.Errorf("misaligned hole cards: expected ErrDealMismatch, got %v", err)
+	}
+	swapped := []card.Card{deck[8], deck[9], deck[10], deck[13], deck[14]}
+	if err := CheckDeal(deck, hole, swapped); !errors.Is(err, ErrDealMismatch) {
+		t.Errorf("wrong turn: expected ErrDealMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package game
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sort"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/equity"
+)
+
+// ==================== 全下期望值 ====================
+
+// 全下权益的计算量（在引擎锁内计算，必须很快）：翻牌和转牌圈全下精确枚举，
+// 翻牌前全下用固定次数、由手牌决定种子的模拟，保证同一副牌重放时权益和期望值相同
+const (
+	allInExactLimit = 20000 // 精确枚举的最大评估次数（翻牌圈全下最多约 9000 次）
+	allInIterations = 20000 // 翻牌前全下的模拟次数
+)
+
+// allInEV 河牌前全员全下时各玩家的权益和期望赢得的筹码（按玩家索引）
+type allInEV struct {
+	stage  Stage           // 全下时所在的阶段
+	equity map[int]float64 // 对所有未弃牌玩家的权益
+	evWon  map[int]float64 // 按权益期望赢得的筹码（各层底池金额 × 该层的权益之和）
+}
+
+// computeAllInEV 在发出剩余公共牌之前计算各玩家的权益和期望值
+// 底池按每人本局投入的筹码分层（与边池相同），每层只在有资格的玩家之间按权益分配
+func (e *GameEngine) computeAllInEV() *allInEV {
+	var contenders []int
+	invested := make(map[int]int, len(e.state.Players))
+	for i, p := range e.state.Players {
+		if p.HoleCards[0].Rank == 0 {
+			continue
+		}
+		invested[i] = e.handStartChips[p.ID] - p.Chips
+		if p.Status == models.PlayerStatusActive || p.Status == models.PlayerStatusAllIn {
+			contenders = append(contenders, i)
+		}
+	}
+	if len(contenders) < 2 {
+		return nil
+	}
+
+	var board []card.Card
+	for _, c := range e.state.CommunityCards {
+		if c.Rank != 0 {
+			board = append(board, c)
+		}
+	}
+
+	// equityOf 计算一组玩家之间的权益（同一组玩家只计算一次）
+	cache := make(map[string][]float64)
+	equityOf := func(players []int) []float64 {
+		key := fmt.Sprint(players)
+		if eq, ok := cache[key]; ok {
+			return eq
+		}
+		hands := make([][2]card.Card, len(players))
+		for i, idx := range players {
+			hands[i] = e.state.Players[idx].HoleCards
+		}
+		eq := make([]float64, len(players))
+		if len(players) == 1 {
+			eq[0] = 1
+		} else if r, err := equity.Calculate(hands, board, nil, equity.Options{
+			Iterations: allInIterations,
+			Workers:    1,
+			ExactLimit: allInExactLimit,
+			Seed:       allInSeed(hands, board),
+		}); err == nil {
+			for i, h := range r.Hands {
+				eq[i] = h.Equity
+			}
+		} else {
+			log.Printf("[引擎] 计算全下权益失败 | 错误=%v", err)
+			return nil
+		}
+		cache[key] = eq
+		return eq
+	}
+
+	ev := &allInEV{
+		stage:  e.state.Stage,
+		equity: make(map[int]float64, len(contenders)),
+		evWon:  make(map[int]float64, len(contenders)),
+	}
+	all := equityOf(contenders)
+	if all == nil {
+		return nil
+	}
+	for i, idx := range contenders {
+		ev.equity[idx] = all[i]
+	}
+
+	// 按投入金额从小到大分层
+	levels := make([]int, 0, len(invested))
+	for _, amount := range invested {
+		levels = append(levels, amount)
+	}
+	sort.Ints(levels)
+	prev := 0
+	for _, level := range levels {
+		if level <= prev {
+			continue
+		}
+		amount := 0
+		for _, inv := range invested {
+			amount += min(inv, level) - min(inv, prev)
+		}
+		var eligible []int
+		for _, idx := range contenders {
+			if invested[idx] >= level {
+				eligible = append(eligible, idx)
+			}
+		}
+		prev = level
+		if len(eligible) == 0 {
+			continue
+		}
+		eq := equityOf(eligible)
+		if eq == nil {
+			return nil
+		}
+		for i, idx := range eligible {
+			ev.evWon[idx] += float64(amount) * eq[i]
+		}
+	}
+
+	for _, idx := range contenders {
+		log.Printf("[引擎] 全下权益 | %s | 权益=%.1f%% | 期望赢得=%.1f",
+			e.state.Players[idx].Name, ev.equity[idx]*100, ev.evWon[idx])
+	}
+	return ev
+}
+
+// allInSeed 由底牌和公共牌得到模拟的随机种子（相同的牌得到相同的种子）
+func allInSeed(hands [][2]card.Card, board []card.Card) uint64 {
+	h := fnv.New64a()
+	for _, hole := range hands {
+		fmt.Fprint(h, hole[0], hole[1], "|")
+	}
+	for _, c := range board {
+		fmt.Fprint(h, c)
+	}
+	return h.Sum64() | 1
+}
+
+// handAllInEV 返回玩家在该手牌中的全下期望盈亏（河牌前全员全下的手牌按全下时的期望值，其余按实际结果）
+func handAllInEV(hand *HandHistory, hp *HistoryPlayer) float64 {
+	net := float64(hp.FinalChips - hp.StartChips)
+	if hand.AllInStage == StageWaiting {
+		return net
+	}
+	return net - float64(hp.WonChips) + hp.EVWonChips
+}
@@ -4,7 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"math/rand"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -12,19 +12,59 @@ import (
 	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
 	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
 	"github.com/wilenwang/just_play/Texas-Holdem/pkg/evaluator"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/fair"
 )
 
 // GameEngine 管理扑克游戏的状态和逻辑
 type GameEngine struct {
 	state     *GameState       // 游戏状态
 	config    *Config         // 游戏配置
-	evaluator *evaluator.Evaluator // 手牌评估器
+	evaluator evaluator.HandEvaluator // 手牌评估器
 	deck      *card.Deck      // 牌组
-	rand      *rand.Rand      // 随机数生成器
+	source    card.RandomSource // 洗牌使用的随机数来源
 	mutex     sync.RWMutex    // 读写锁
 
+	// 下一局生效的盲注（手牌进行中修改盲注时暂存）
+	nextBlinds *blindLevel
+
+	// 累计发放到牌桌的筹码（入座发放、房主调整、离开收回），用于筹码守恒校验
+	chipsIssued int
+
+	// 手牌历史记录（为 nil 时不记录）
+	history *HistoryManager
+
+	// 玩家统计（为 nil 时不统计，需要同时设置手牌历史）
+	stats *StatsManager
+
+	// 本局开始时（扣除前注和盲注之前）各玩家的筹码，用于计算投入的筹码
+	handStartChips map[string]int
+
+	// 本局河牌前全员全下时的权益和期望值（没有全下时为 nil）
+	allIn *allInEV
+
+	// 不计算全下权益（批量模拟时关闭以节省时间）
+	allInEVDisabled bool
+
+	// 下一局指定的牌序和庄家座位（用于复现手牌，使用一次后清除）
+	nextDeck   *card.Deck
+	nextButton int
+
+	// 可验证公平洗牌：开局公布种子摘要，本局结束后公布种子
+	provablyFair bool
+	fairShuffle  *fair.Shuffle
+
 	// 状态变化回调
 	onStateChange func(state *GameState)
+
+	// 手牌结束回调（参数为完整的手牌记录，需要设置手牌历史）
+	onHandEnd func(hand HandHistory)
+}
+
+// blindLevel 盲注级别
+type blindLevel struct {
+	smallBlind int // 小盲注
+	bigBlind   int // 大盲注
+	ante       int // 前注
 }
 
 // Config 保存游戏配置
@@ -36,6 +76,8 @@ type Config struct {
 	Ante           int // 前注金额（可选）
 	StartingChips  int // 初始筹码
 	ActionTimeout  int // 动作超时时间
+	SitOutOrbits   int // 离座超过 N 圈后自动移出牌桌（0 表示不限制）
+	MaxTimeouts    int // 连续超时 N 次后自动离座（0 表示不限制）
 }
 
 // GameState 表示当前的游戏状态
@@ -51,6 +93,8 @@ type GameState struct {
 	Players        []*models.Player    // 所有玩家
 	Actions        []models.PlayerAction // 动作记录
 	LastShowdown   *ShowdownResult     // 最近一局的结算结果
+	FairHash       string              // 可验证公平洗牌：本局种子的 SHA-256（开局时公布）
+	FairSeed       string              // 可验证公平洗牌：本局种子（本局结束后公布）
 }
 
 // Stage 表示当前的下注阶段
@@ -79,6 +123,11 @@ func (s Stage) String() string {
 	return "未知"
 }
 
+// IsBetting 是否处于下注阶段（翻牌前/翻牌/转牌/河牌，即手牌进行中）
+func (s Stage) IsBetting() bool {
+	return s >= StagePreFlop && s <= StageRiver
+}
+
 // SidePot 表示边池
 // 边池按照贡献金额从小到大排列，MainPot 是最后一个（最大的）边池
 type SidePot struct {
@@ -91,6 +140,7 @@ type ShowdownResult struct {
 	Players  []PlayerResult // 每位参与摊牌的玩家结果
 	TotalPot int            // 本局总底池
 	IsEarlyEnd bool         // 是否提前结束（其他人全弃牌）
+	AllInStage Stage        // 河牌前全员全下时所在的阶段（没有全下时为 StageWaiting）
 }
 
 // PlayerResult 单个玩家的结算结果
@@ -106,6 +156,8 @@ type PlayerResult struct {
 	IsFolded   bool                   // 是否已弃牌
 	ChipsBefore int                   // 结算前筹码
 	ChipsAfter  int                   // 结算后筹码
+	AllInEquity float64               // 河牌前全员全下时的权益（没有全下时为 0）
+	EVWonAmount float64               // 按全下时的权益期望赢得的筹码（没有全下时等于 WonAmount）
 }
 
 // NewEngine 创建新的游戏引擎
@@ -126,8 +178,9 @@ func NewEngine(config *Config) *GameEngine {
 			SidePots: make([]SidePot, 0),
 		},
 		config:    config,
-		evaluator: evaluator.NewEvaluator(),
-		rand:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		evaluator: evaluator.NewFastEvaluator(),
+		source:    card.NewCryptoSource(),
+		nextButton: -1,
 	}
 
 	return engine
@@ -140,6 +193,41 @@ func (e *GameEngine) SetOnStateChange(fn func(state *GameState)) {
 	e.onStateChange = fn
 }
 
+// SetOnHandEnd 设置手牌结束回调（在引擎锁内调用，回调中不能再调用引擎方法）
+func (e *GameEngine) SetOnHandEnd(fn func(hand HandHistory)) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.onHandEnd = fn
+}
+
+// SetHistory 设置手牌历史记录器，之后每一手牌都会被完整记录
+func (e *GameEngine) SetHistory(h *HistoryManager) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.history = h
+}
+
+// History 返回手牌历史记录器（未设置时为 nil）
+func (e *GameEngine) History() *HistoryManager {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.history
+}
+
+// SetStats 设置玩家统计，之后每一手牌结束时根据手牌历史累计统计
+func (e *GameEngine) SetStats(s *StatsManager) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.stats = s
+}
+
+// Stats 返回玩家统计（未设置时为 nil）
+func (e *GameEngine) Stats() *StatsManager {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.stats
+}
+
 // GetState 获取当前游戏状态（线程安全）
 func (e *GameEngine) GetState() *GameState {
 	e.mutex.RLock()
@@ -192,6 +280,7 @@ func (e *GameEngine) AddPlayer(id, name string, seat int) (*models.Player, error
 	}
 
 	e.state.Players = append(e.state.Players, player)
+	e.chipsIssued += player.Chips
 	e.notifyStateChange()
 
 	return player, nil
@@ -204,10 +293,16 @@ func (e *GameEngine) RemovePlayer(id string) error {
 
 	for i, p := range e.state.Players {
 		if p.ID == id {
-			if e.state.Stage != StageWaiting && p.Status == models.PlayerStatusActive {
-				p.Status = models.PlayerStatusFolded
+			if e.state.Stage.IsBetting() {
+				// 手牌进行中不能直接移除（会打乱行动顺序和底池归属），本局结束后再移除
+				if p.Status == models.PlayerStatusActive {
+					p.Status = models.PlayerStatusFolded
+				}
+				p.LeavePending = true
 			} else {
 				e.state.Players = append(e.state.Players[:i], e.state.Players[i+1:]...)
+				e.chipsIssued -= p.Chips
+				e.syncDealerButton()
 			}
 			e.notifyStateChange()
 			return nil
@@ -216,6 +311,280 @@ func (e *GameEngine) RemovePlayer(id string) error {
 	return ErrPlayerNotFound
 }
 
+// ApplyPendingRemovals 移除手牌进行中申请离开的玩家，返回被移除的玩家（只能在两局之间调用）
+func (e *GameEngine) ApplyPendingRemovals() []*models.Player {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.state.Stage.IsBetting() {
+		return nil
+	}
+
+	removed := e.removePendingPlayers()
+	if len(removed) > 0 {
+		e.notifyStateChange()
+	}
+	return removed
+}
+
+// AdjustChips 调整玩家筹码（delta 为正表示增加，为负表示扣除），只能在两局之间调用
+func (e *GameEngine) AdjustChips(playerID string, delta int) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.state.Stage.IsBetting() {
+		return ErrHandInProgress
+	}
+
+	p := e.getPlayerByID(playerID)
+	if p == nil {
+		return ErrPlayerNotFound
+	}
+	if p.Chips+delta < 0 {
+		return ErrInvalidChips
+	}
+
+	p.Chips += delta
+	e.chipsIssued += delta
+	log.Printf("[引擎] 调整筹码 | 玩家=%s | 变化=%+d | 当前筹码=%d", p.Name, delta, p.Chips)
+
+	e.notifyStateChange()
+	return nil
+}
+
+// SetBlinds 修改盲注和前注，手牌进行中修改时从下一局开始生效
+func (e *GameEngine) SetBlinds(smallBlind, bigBlind, ante int) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if smallBlind <= 0 || bigBlind < smallBlind || ante < 0 {
+		return ErrInvalidBlinds
+	}
+
+	level := &blindLevel{smallBlind: smallBlind, bigBlind: bigBlind, ante: ante}
+	if e.state.Stage.IsBetting() {
+		e.nextBlinds = level
+		log.Printf("[引擎] 盲注将在下一局调整为 %d/%d (前注 %d)", smallBlind, bigBlind, ante)
+		return nil
+	}
+
+	e.applyBlinds(level)
+	return nil
+}
+
+// SetRandomSource 设置洗牌使用的随机数来源（默认 crypto/rand，测试中可使用固定种子）
+func (e *GameEngine) SetRandomSource(src card.RandomSource) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.source = src
+}
+
+// newFairShuffle 生成可验证洗牌的种子（测试中替换以模拟失败）
+var newFairShuffle = fair.NewShuffle
+
+// SetProvablyFair 开启或关闭可验证公平洗牌，开启后每局用新种子洗牌，
+// 开局时在 FairHash 公布种子摘要，本局结束后在 FairSeed 公布种子
+func (e *GameEngine) SetProvablyFair(enabled bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.provablyFair = enabled
+}
+
+// SetAllInEV 开启或关闭河牌前全员全下时的权益计算（默认开启；关闭后结算结果中的权益为 0，期望值等于实际输赢）
+func (e *GameEngine) SetAllInEV(enabled bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.allInEVDisabled = !enabled
+}
+
+// SetNextDeck 指定下一局使用的牌序（必须是不重复的52张牌，从第一张开始发）
+func (e *GameEngine) SetNextDeck(cards []card.Card) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	deck, err := card.NewDeckFromCards(cards)
+	if err != nil {
+		return err
+	}
+	e.nextDeck = deck
+	return nil
+}
+
+// SetNextButton 指定下一局的庄家座位（不再按顺序轮转）
+func (e *GameEngine) SetNextButton(seat int) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if seat < 0 || seat >= e.config.MaxPlayers {
+		return ErrInvalidSeat
+	}
+	e.nextButton = seat
+	return nil
+}
+
+// ChipBalance 返回牌桌上的筹码总量（玩家筹码 + 底池 + 边池）和累计发放到牌桌的筹码
+// 筹码守恒时两者相等
+func (e *GameEngine) ChipBalance() (inPlay, issued int) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	inPlay = e.state.Pot
+	for _, p := range e.state.Players {
+		inPlay += p.Chips
+	}
+	for _, pot := range e.state.SidePots {
+		inPlay += pot.Amount
+	}
+	return inPlay, e.chipsIssued
+}
+
+// ChangeSeat 玩家换到指定空座位（只能在两局之间调用）
+func (e *GameEngine) ChangeSeat(playerID string, seat int) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.state.Stage.IsBetting() {
+		return ErrHandInProgress
+	}
+
+	p := e.getPlayerByID(playerID)
+	if p == nil {
+		return ErrPlayerNotFound
+	}
+	if seat < 0 || seat >= e.config.MaxPlayers {
+		return ErrInvalidSeat
+	}
+	if p.Seat == seat {
+		return nil
+	}
+	for _, other := range e.state.Players {
+		if other.Seat == seat {
+			return ErrSeatOccupied
+		}
+	}
+
+	log.Printf("[引擎] 换座 | 玩家=%s | 座位%d → 座位%d", p.Name, p.Seat, seat)
+	p.Seat = seat
+	e.sortPlayersBySeat()
+
+	e.notifyStateChange()
+	return nil
+}
+
+// FreeSeats 返回当前空闲的座位号（升序）
+func (e *GameEngine) FreeSeats() []int {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	used := make(map[int]bool, len(e.state.Players))
+	for _, p := range e.state.Players {
+		used[p.Seat] = true
+	}
+
+	seats := make([]int, 0, e.config.MaxPlayers)
+	for i := 0; i < e.config.MaxPlayers; i++ {
+		if !used[i] {
+			seats = append(seats, i)
+		}
+	}
+	return seats
+}
+
+// SitOut 玩家申请暂时离座
+// 手牌进行中且已发到底牌的玩家会在本局结束后离座，其余情况立即生效
+func (e *GameEngine) SitOut(playerID string) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	p := e.getPlayerByID(playerID)
+	if p == nil {
+		return ErrPlayerNotFound
+	}
+	if p.Status == models.PlayerStatusSittingOut || p.SitOutPending {
+		return nil
+	}
+
+	if e.state.Stage.IsBetting() && p.HasHoleCards() {
+		p.SitOutPending = true
+		log.Printf("[引擎] 离座申请 | 玩家=%s | 本局结束后生效", p.Name)
+	} else {
+		p.Status = models.PlayerStatusSittingOut
+		p.SitOutHands = 0
+		log.Printf("[引擎] 离座 | 玩家=%s", p.Name)
+	}
+
+	e.notifyStateChange()
+	return nil
+}
+
+// SitIn 玩家回到座位，从下一局开始参与发牌
+func (e *GameEngine) SitIn(playerID string) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	p := e.getPlayerByID(playerID)
+	if p == nil {
+		return ErrPlayerNotFound
+	}
+
+	p.SitOutPending = false
+	p.SitOutHands = 0
+	if p.Status == models.PlayerStatusSittingOut {
+		// 手牌进行中回座的玩家先标记为弃牌，等待下一局
+		if e.state.Stage.IsBetting() {
+			p.Status = models.PlayerStatusFolded
+		} else {
+			p.Status = models.PlayerStatusActive
+		}
+		log.Printf("[引擎] 回座 | 玩家=%s", p.Name)
+	}
+
+	e.notifyStateChange()
+	return nil
+}
+
+// RemoveIdlePlayers 移除离座超过配置圈数的玩家，返回被移除的玩家
+// 一圈按当前未离座的玩家数计算，只能在两局之间调用
+func (e *GameEngine) RemoveIdlePlayers() []*models.Player {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.config.SitOutOrbits <= 0 || e.state.Stage.IsBetting() {
+		return nil
+	}
+
+	seated := 0
+	for _, p := range e.state.Players {
+		if p.Status != models.PlayerStatusSittingOut {
+			seated++
+		}
+	}
+	if seated < 1 {
+		seated = 1
+	}
+	limit := e.config.SitOutOrbits * seated
+
+	var removed []*models.Player
+	kept := make([]*models.Player, 0, len(e.state.Players))
+	for _, p := range e.state.Players {
+		if p.Status == models.PlayerStatusSittingOut && p.SitOutHands >= limit {
+			log.Printf("[引擎] 离座超时移出 | 玩家=%s | 错过手数=%d | 上限=%d", p.Name, p.SitOutHands, limit)
+			e.chipsIssued -= p.Chips
+			playerCopy := *p
+			removed = append(removed, &playerCopy)
+			continue
+		}
+		kept = append(kept, p)
+	}
+
+	if len(removed) > 0 {
+		e.state.Players = kept
+		e.syncDealerButton()
+		e.notifyStateChange()
+	}
+	return removed
+}
+
 // StartHand 开始新的一局
 func (e *GameEngine) StartHand() error {
 	e.mutex.Lock()
@@ -224,16 +593,45 @@ func (e *GameEngine) StartHand() error {
 	log.Printf("[引擎] StartHand 开始 | 当前阶段=%s", e.state.Stage)
 
 	// 允许从等待、摊牌、局结束状态开始新的一局
-	if e.state.Stage != StageWaiting && e.state.Stage != StageShowdown && e.state.Stage != StageEnd {
+	if e.state.Stage.IsBetting() {
 		log.Printf("[引擎] StartHand 拒绝 | 当前阶段=%s 不允许开始新局", e.state.Stage)
 		return ErrHandInProgress
 	}
 
+	// 可验证洗牌先生成种子，失败时直接返回，不改动任何状态
+	var shuffle *fair.Shuffle
+	if e.nextDeck == nil && e.provablyFair {
+		var err error
+		if shuffle, err = newFairShuffle(); err != nil {
+			log.Printf("[引擎] StartHand 失败 | 生成洗牌种子失败: %v", err)
+			return err
+		}
+	}
+
+	// 应用暂存的盲注调整
+	if e.nextBlinds != nil {
+		e.applyBlinds(e.nextBlinds)
+		e.nextBlinds = nil
+	}
+
+	// 移除上局申请离开的玩家，并按座位号整理行动顺序
+	e.removePendingPlayers()
+	e.sortPlayersBySeat()
+
 	// 先重置玩家状态（必须在检查活跃玩家数之前，否则上局弃牌/全下的玩家会被误判为不活跃）
 	for _, p := range e.state.Players {
 		p.HoleCards = [2]card.Card{}
 		p.CurrentBet = 0
 		p.HasActed = false
+		if p.SitOutPending {
+			p.SitOutPending = false
+			p.Status = models.PlayerStatusSittingOut
+			p.SitOutHands = 0
+		}
+		if p.Status == models.PlayerStatusSittingOut {
+			// 离座玩家不参与发牌、按钮和盲注轮转
+			continue
+		}
 		if p.Chips > 0 {
 			p.Status = models.PlayerStatusActive
 		} else {
@@ -251,6 +649,13 @@ func (e *GameEngine) StartHand() error {
 		return ErrNotEnoughPlayers
 	}
 
+	// 记录离座玩家错过的手数
+	for _, p := range e.state.Players {
+		if p.Status == models.PlayerStatusSittingOut {
+			p.SitOutHands++
+		}
+	}
+
 	// 准备新局
 	e.state.Stage = StagePreFlop
 	e.state.CommunityCards = [5]card.Card{}
@@ -258,15 +663,44 @@ func (e *GameEngine) StartHand() error {
 	e.state.Pot = 0
 	e.state.SidePots = make([]SidePot, 0)
 
-	// 洗牌
-	e.deck = card.NewDeck()
-	e.deck.Shuffle()
-	log.Printf("[引擎] 洗牌完成")
-
-	// 轮转庄家按钮
-	e.rotateDealerButton()
+	// 洗牌（指定了牌序时直接使用）
+	e.fairShuffle = nil
+	e.state.FairHash = ""
+	e.state.FairSeed = ""
+	switch {
+	case e.nextDeck != nil:
+		e.deck = e.nextDeck
+		e.nextDeck = nil
+		log.Printf("[引擎] 使用指定牌序")
+	case shuffle != nil:
+		e.fairShuffle = shuffle
+		e.deck = shuffle.Deck()
+		e.state.FairHash = shuffle.Commitment()
+		log.Printf("[引擎] 可验证洗牌完成 | 承诺=%s", e.state.FairHash)
+	default:
+		e.deck = card.NewDeck()
+		e.deck.ShuffleWith(e.source)
+		log.Printf("[引擎] 洗牌完成")
+	}
+	deckOrder := append([]card.Card(nil), e.deck.Cards()...)
+
+	// 轮转庄家按钮（指定了庄家座位时直接放到该座位）
+	if e.nextButton >= 0 {
+		e.placeDealerButton(e.nextButton)
+		e.nextButton = -1
+	} else {
+		e.rotateDealerButton()
+	}
 	log.Printf("[引擎] 庄家按钮 → 座位%d (%s)", e.state.Players[e.state.DealerButton].Seat, e.state.Players[e.state.DealerButton].Name)
 
+	// 记录开局筹码（用于手牌历史）
+	startChips := make(map[string]int, len(e.state.Players))
+	for _, p := range e.state.Players {
+		startChips[p.ID] = p.Chips
+	}
+	e.handStartChips = startChips
+	e.allIn = nil
+
 	// 扣除前注（如果有配置）
 	e.collectAnte()
 
@@ -281,6 +715,8 @@ func (e *GameEngine) StartHand() error {
 		}
 	}
 
+	e.recordHandStart(startChips, deckOrder)
+
 	// 设置翻牌前第一个行动玩家（大盲之后的玩家，2人局为小盲/庄家）
 	e.state.CurrentPlayer = e.findFirstToActPreflop()
 	log.Printf("[引擎] 翻牌前第一个行动 → %s(idx=%d)", e.state.Players[e.state.CurrentPlayer].Name, e.state.CurrentPlayer)
@@ -297,8 +733,7 @@ func (e *GameEngine) PlayerAction(playerID string, action models.ActionType, amo
 	defer e.mutex.Unlock()
 
 	// 检查游戏是否处于下注阶段（只有翻牌前、翻牌、转牌、河牌可以行动）
-	if e.state.Stage != StagePreFlop && e.state.Stage != StageFlop &&
-		e.state.Stage != StageTurn && e.state.Stage != StageRiver {
+	if !e.state.Stage.IsBetting() {
 		log.Printf("[引擎] PlayerAction 拒绝 | 当前阶段=%s 不是下注阶段", e.state.Stage)
 		return ErrNotYourTurn
 	}
@@ -324,6 +759,7 @@ func (e *GameEngine) PlayerAction(playerID string, action models.ActionType, amo
 
 	// 记录加注/全下前的最高下注，用于判断是否需要重置其他玩家的行动状态
 	prevBet := e.state.CurrentBet
+	chipsBefore := player.Chips
 
 	// 执行动作
 	switch action {
@@ -379,6 +815,9 @@ func (e *GameEngine) PlayerAction(playerID string, action models.ActionType, amo
 		Action:   action,
 		Amount:   player.CurrentBet,
 	})
+	if e.history != nil {
+		e.history.RecordAction(playerID, player.Name, e.state.Stage, action, chipsBefore-player.Chips, player.CurrentBet)
+	}
 
 	// 检查是否只剩一名未弃牌玩家（提前结束判定：所有其他人都弃牌了）
 	if e.checkEarlyFinish() {
@@ -405,6 +844,55 @@ func (e *GameEngine) PlayerAction(playerID string, action models.ActionType, amo
 
 // ==================== 私有方法 ====================
 
+// applyBlinds 将盲注级别写入配置
+func (e *GameEngine) applyBlinds(level *blindLevel) {
+	e.config.SmallBlind = level.smallBlind
+	e.config.BigBlind = level.bigBlind
+	e.config.Ante = level.ante
+	log.Printf("[引擎] 盲注调整为 %d/%d (前注 %d)", level.smallBlind, level.bigBlind, level.ante)
+}
+
+// removePendingPlayers 移除标记为离开的玩家，返回被移除玩家的副本
+func (e *GameEngine) removePendingPlayers() []*models.Player {
+	var removed []*models.Player
+	kept := make([]*models.Player, 0, len(e.state.Players))
+	for _, p := range e.state.Players {
+		if p.LeavePending {
+			log.Printf("[引擎] 移除离开的玩家 | 玩家=%s", p.Name)
+			e.chipsIssued -= p.Chips
+			playerCopy := *p
+			removed = append(removed, &playerCopy)
+			continue
+		}
+		kept = append(kept, p)
+	}
+
+	if len(removed) > 0 {
+		e.state.Players = kept
+		e.syncDealerButton()
+	}
+	return removed
+}
+
+// sortPlayersBySeat 按座位号排序玩家（行动顺序与座位一致），并同步庄家按钮索引
+func (e *GameEngine) sortPlayersBySeat() {
+	sort.SliceStable(e.state.Players, func(i, j int) bool {
+		return e.state.Players[i].Seat < e.state.Players[j].Seat
+	})
+	e.syncDealerButton()
+}
+
+// syncDealerButton 玩家列表变化后，根据 IsDealer 标记重新定位庄家按钮索引
+func (e *GameEngine) syncDealerButton() {
+	for i, p := range e.state.Players {
+		if p.IsDealer {
+			e.state.DealerButton = i
+			return
+		}
+	}
+	e.state.DealerButton = 0
+}
+
 // checkEarlyFinish 检查是否只剩一名未弃牌玩家，可以提前结束
 // 未弃牌 = Active + AllIn，只有当仅剩1人时才提前结束（其他人全弃牌了）
 func (e *GameEngine) checkEarlyFinish() bool {
@@ -472,6 +960,12 @@ func (e *GameEngine) collectSidePots() {
 		}
 	}
 
+	// 之前各轮（还没有人全下时）留在底池里的筹码，并入本轮的主池
+	carry := e.state.Pot
+	for _, p := range e.state.Players {
+		carry -= p.CurrentBet
+	}
+
 	if len(bettingPlayers) == 0 {
 		return
 	}
@@ -498,8 +992,9 @@ func (e *GameEngine) collectSidePots() {
 		}
 	}
 
-	// 主池金额 = minBet * 有资格获得的玩家数
-	mainPotAmount := minBet * len(eligibleForMain)
+	// 主池金额 = minBet * 有资格获得的玩家数 + 之前各轮的底池
+	// （未弃牌的玩家本轮都有下注，都在主池的资格名单中）
+	mainPotAmount := minBet*len(eligibleForMain) + carry
 	if mainPotAmount > 0 {
 		e.state.SidePots = append(e.state.SidePots, SidePot{
 			Amount:          mainPotAmount,
@@ -597,6 +1092,27 @@ func (e *GameEngine) rotateDealerButton() {
 	}
 }
 
+// placeDealerButton 将庄家按钮放到指定座位（该座位没有活跃玩家时按顺序轮转）
+func (e *GameEngine) placeDealerButton(seat int) {
+	target := -1
+	for i, p := range e.state.Players {
+		if p.Seat == seat && p.Status == models.PlayerStatusActive {
+			target = i
+			break
+		}
+	}
+	if target < 0 {
+		log.Printf("[引擎] 指定的庄家座位%d没有活跃玩家，按顺序轮转", seat)
+		e.rotateDealerButton()
+		return
+	}
+
+	for i, p := range e.state.Players {
+		p.IsDealer = i == target
+	}
+	e.state.DealerButton = target
+}
+
 // collectBlinds 扣除盲注（会在前注之后执行）
 // 2人局特殊规则：庄家=小盲，非庄家=大盲
 // 3人及以上：庄家下一位=小盲，再下一位=大盲
@@ -807,6 +1323,9 @@ func (e *GameEngine) advanceBettingRound() {
 // dealRemainingAndShowdown 全员全下时，发完剩余公共牌并直接摊牌
 func (e *GameEngine) dealRemainingAndShowdown() {
 	log.Printf("[引擎] dealRemainingAndShowdown | 从阶段=%s 快进到摊牌", e.state.Stage)
+	if e.state.Stage < StageRiver && !e.allInEVDisabled {
+		e.allIn = e.computeAllInEV()
+	}
 	switch e.state.Stage {
 	case StagePreFlop:
 		// 发翻牌（3张）+ 转牌 + 河牌
@@ -906,6 +1425,11 @@ func (e *GameEngine) determineWinners() {
 	totalPot := e.state.Pot
 	log.Printf("[引擎] ====== 开始结算 | 底池=%d | 边池数=%d ======", totalPot, len(e.state.SidePots))
 
+	// 本局结束，公布可验证洗牌的种子
+	if e.fairShuffle != nil {
+		e.state.FairSeed = e.fairShuffle.Seed()
+	}
+
 	// 打印公共牌
 	var ccards []string
 	for _, c := range e.state.CommunityCards {
@@ -920,6 +1444,9 @@ func (e *GameEngine) determineWinners() {
 		TotalPot:   totalPot,
 		IsEarlyEnd: false,
 	}
+	if e.allIn != nil {
+		result.AllInStage = e.allIn.stage
+	}
 	chipsBefore := make(map[int]int)
 	for i, p := range e.state.Players {
 		chipsBefore[i] = p.Chips
@@ -941,28 +1468,114 @@ func (e *GameEngine) determineWinners() {
 			PlayerIdx:   i,
 			PlayerName:  p.Name,
 			HoleCards:   p.HoleCards,
-			IsFolded:    p.Status == models.PlayerStatusFolded,
+			IsFolded:    p.Status == models.PlayerStatusFolded || p.Status == models.PlayerStatusSittingOut,
 			ChipsBefore: chipsBefore[i],
 			ChipsAfter:  p.Chips,
 			WonAmount:   p.Chips - chipsBefore[i],
 			IsWinner:    p.Chips > chipsBefore[i],
 		}
+		pr.EVWonAmount = float64(pr.WonAmount)
+		if e.allIn != nil {
+			pr.AllInEquity = e.allIn.equity[i]
+			pr.EVWonAmount = e.allIn.evWon[i]
+		}
 
 		// 对未弃牌的玩家评估牌型
 		if !pr.IsFolded && p.HoleCards[0].Rank != 0 && len(ccards) > 0 {
-			eval := e.evaluator.Evaluate(p.HoleCards, e.state.CommunityCards)
-			pr.HandRank = eval.Rank
-			pr.HandName = eval.Rank.String()
-			pr.BestCards = eval.RawCards
+			if eval, err := evaluator.EvaluateHand(e.evaluator, p.HoleCards, e.state.CommunityCards); err == nil {
+				pr.HandRank = eval.Rank
+				pr.HandName = eval.Rank.String()
+				pr.BestCards = eval.RawCards
+			}
 		}
 
 		result.Players = append(result.Players, pr)
 	}
 
 	e.state.LastShowdown = result
+	e.recordHandEnd(result)
 	log.Printf("[引擎] ====== 结算完成 ======")
 }
 
+// recordHandStart 发牌后记录新一手牌的玩家、底牌和强制下注
+func (e *GameEngine) recordHandStart(startChips map[string]int, deck []card.Card) {
+	if e.history == nil {
+		return
+	}
+
+	h := e.history
+	h.StartHand(h.NextHandID(), e.state.ID)
+	h.SetTableInfo(e.config.SmallBlind, e.config.BigBlind, e.config.Ante, e.state.Players[e.state.DealerButton].Seat)
+	h.SetDeck(deck)
+	if e.fairShuffle != nil {
+		h.SetFairShuffle(e.fairShuffle.Commitment(), e.fairShuffle.Seed())
+	}
+
+	for _, p := range e.state.Players {
+		if p.HoleCards[0].Rank == 0 {
+			// 未发牌的玩家（离座、无筹码）不参与本局
+			continue
+		}
+		chips := startChips[p.ID]
+		posted := chips - p.Chips
+		ante := 0
+		if e.config.Ante > 0 {
+			ante = min(chips, e.config.Ante)
+		}
+
+		h.AddPlayer(p.ID, p.Name, p.Seat, chips)
+		h.SetHoleCards(p.ID, p.HoleCards)
+		h.RecordPosts(p.ID, ante, posted-ante)
+	}
+}
+
+// recordHandEnd 结算后记录摊牌牌型、获胜者和最终筹码
+func (e *GameEngine) recordHandEnd(result *ShowdownResult) {
+	if e.history == nil {
+		return
+	}
+
+	// 只剩一名未弃牌玩家时不亮牌
+	contenders := 0
+	for _, pr := range result.Players {
+		if !pr.IsFolded {
+			contenders++
+		}
+	}
+
+	// 底池按实际分配的筹码计算（存在边池时 TotalPot 可能已被清零）
+	pot := 0
+	var winners []WinnerInfo
+	for _, pr := range result.Players {
+		p := e.state.Players[pr.PlayerIdx]
+		if contenders > 1 && !pr.IsFolded && pr.HandName != "" {
+			e.history.RecordShowdown(p.ID, p.Name, pr.HandRank, pr.HandName, pr.BestCards)
+		}
+		e.history.SetPlayerResult(p.ID, pr.ChipsAfter, pr.WonAmount)
+		if result.AllInStage != StageWaiting {
+			e.history.SetPlayerAllInEV(p.ID, pr.AllInEquity, pr.EVWonAmount)
+		}
+		if pr.IsWinner {
+			winners = append(winners, WinnerInfo{PlayerID: p.ID, PlayerName: p.Name, Amount: pr.WonAmount})
+			pot += pr.WonAmount
+		}
+	}
+
+	if result.AllInStage != StageWaiting {
+		e.history.SetAllInStage(result.AllInStage)
+	}
+	hand, ok := e.history.EndHand(e.state.CommunityCards, pot, winners)
+	if !ok {
+		return
+	}
+	if e.stats != nil {
+		e.stats.RecordHand(hand)
+	}
+	if e.onHandEnd != nil {
+		e.onHandEnd(hand)
+	}
+}
+
 // determineWinnersStandard 标准结算逻辑（无边池）
 func (e *GameEngine) determineWinnersStandard() {
 	var bestEval evaluator.HandEvaluation
@@ -971,9 +1584,13 @@ func (e *GameEngine) determineWinnersStandard() {
 
 	for i, p := range e.state.Players {
 		if p.Status == models.PlayerStatusActive || p.Status == models.PlayerStatusAllIn {
-			eval := e.evaluator.Evaluate(p.HoleCards, e.state.CommunityCards)
-			log.Printf("[引擎] 评估手牌 | %s | 底牌=[%s %s] | 牌型=%s | 主值=%d",
-				p.Name, p.HoleCards[0], p.HoleCards[1], eval.Rank, eval.MainValue)
+			// 其他人都弃牌时公共牌可能没有发完，无法评估的手牌按最弱处理（唯一的玩家直接获胜）
+			eval, err := evaluator.EvaluateHand(e.evaluator, p.HoleCards, e.state.CommunityCards)
+			if err != nil {
+				log.Printf("[引擎] 无法评估手牌 | %s | 原因=%v", p.Name, err)
+			}
+			log.Printf("[引擎] 评估手牌 | %s | 底牌=[%s %s] | 牌型=%s | 强度=%#x",
+				p.Name, p.HoleCards[0], p.HoleCards[1], eval.Rank, uint32(eval.Strength))
 			if bestPlayerIdx < 0 {
 				bestEval = eval
 				bestPlayerIdx = i
@@ -1027,7 +1644,11 @@ func (e *GameEngine) determineWinnersWithSidePots() {
 
 	for i, p := range e.state.Players {
 		if p.Status == models.PlayerStatusActive || p.Status == models.PlayerStatusAllIn {
-			eval := e.evaluator.Evaluate(p.HoleCards, e.state.CommunityCards)
+			// 无法评估的手牌按最弱处理，见 determineWinnersStandard
+			eval, err := evaluator.EvaluateHand(e.evaluator, p.HoleCards, e.state.CommunityCards)
+			if err != nil {
+				log.Printf("[引擎] 无法评估手牌 | %s | 原因=%v", p.Name, err)
+			}
 			qualifiedPlayers[i] = eval
 		}
 	}
@@ -1157,4 +1778,6 @@ var (
 	ErrNotEnoughChips   = errors.New("筹码不足")
 	ErrInvalidAction    = errors.New("无效动作")
 	ErrPlayerNotFound   = errors.New("玩家不存在")
+	ErrInvalidChips     = errors.New("无效筹码数")
+	ErrInvalidBlinds    = errors.New("无效盲注")
 )
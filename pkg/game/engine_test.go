@@ -1,10 +1,13 @@
 package game
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
 	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/fair"
 )
 
 // ==================== 游戏配置测试 ====================
@@ -1069,6 +1072,15 @@ func TestStage_String(t *testing.T) {
 	}
 }
 
+func TestStage_IsBetting(t *testing.T) {
+	for stage := Stage(-1); stage <= StageEnd+1; stage++ {
+		want := stage == StagePreFlop || stage == StageFlop || stage == StageTurn || stage == StageRiver
+		if got := stage.IsBetting(); got != want {
+			t.Errorf("Stage %d: expected IsBetting=%v, got %v", stage, want, got)
+		}
+	}
+}
+
 func TestStage_Unknown(t *testing.T) {
 	stage := Stage(100)
 	if stage.String() != "未知" {
@@ -1424,6 +1436,654 @@ func TestIntegration_DealerButtonRotation(t *testing.T) {
 	}
 }
 
+// ==================== 离座测试 ====================
+
+func TestSitOut_SkippedInNextHand(t *testing.T) {
+	engine := NewEngine(&Config{
+		MinPlayers:    2,
+		MaxPlayers:    4,
+		SmallBlind:    10,
+		BigBlind:      20,
+		StartingChips: 1000,
+	})
+
+	engine.AddPlayer("p1", "Alice", 0)
+	engine.AddPlayer("p2", "Bob", 1)
+	engine.AddPlayer("p3", "Charlie", 2)
+
+	if err := engine.SitOut("p3"); err != nil {
+		t.Fatalf("SitOut failed: %v", err)
+	}
+	if err := engine.StartHand(); err != nil {
+		t.Fatalf("StartHand failed: %v", err)
+	}
+
+	p3 := engine.getPlayerByID("p3")
+	if p3.Status != models.PlayerStatusSittingOut {
+		t.Errorf("expected p3 sitting out, got %s", p3.Status)
+	}
+	if p3.HasHoleCards() {
+		t.Error("sitting out player should not be dealt hole cards")
+	}
+	if p3.CurrentBet != 0 || p3.Chips != 1000 {
+		t.Errorf("sitting out player should not post blinds, bet=%d chips=%d", p3.CurrentBet, p3.Chips)
+	}
+	if p3.SitOutHands != 1 {
+		t.Errorf("expected SitOutHands=1, got %d", p3.SitOutHands)
+	}
+}
+
+func TestSitOut_PendingDuringHand(t *testing.T) {
+	engine := NewEngine(&Config{
+		MinPlayers:    2,
+		MaxPlayers:    4,
+		SmallBlind:    10,
+		BigBlind:      20,
+		StartingChips: 1000,
+	})
+
+	engine.AddPlayer("p1", "Alice", 0)
+	engine.AddPlayer("p2", "Bob", 1)
+	engine.AddPlayer("p3", "Charlie", 2)
+	engine.StartHand()
+
+	if err := engine.SitOut("p1"); err != nil {
+		t.Fatalf("SitOut failed: %v", err)
+	}
+	p1 := engine.getPlayerByID("p1")
+	if !p1.SitOutPending {
+		t.Error("expected SitOutPending during hand")
+	}
+	if p1.Status == models.PlayerStatusSittingOut {
+		t.Error("player in hand should not sit out immediately")
+	}
+
+	// 结束本局：依次弃牌直到只剩一人
+	for i := 0; i < 2; i++ {
+		state := engine.GetState()
+		engine.PlayerAction(state.Players[state.CurrentPlayer].ID, models.ActionFold, 0)
+	}
+
+	engine.StartHand()
+	p1 = engine.getPlayerByID("p1")
+	if p1.Status != models.PlayerStatusSittingOut || p1.SitOutPending {
+		t.Errorf("expected p1 sitting out after hand, status=%s pending=%v", p1.Status, p1.SitOutPending)
+	}
+}
+
+func TestSitIn_ReturnsToActive(t *testing.T) {
+	engine := NewEngine(&Config{
+		MinPlayers:    2,
+		MaxPlayers:    4,
+		SmallBlind:    10,
+		BigBlind:      20,
+		StartingChips: 1000,
+	})
+
+	engine.AddPlayer("p1", "Alice", 0)
+	engine.AddPlayer("p2", "Bob", 1)
+
+	engine.SitOut("p2")
+	if err := engine.StartHand(); err != ErrNotEnoughPlayers {
+		t.Errorf("expected ErrNotEnoughPlayers with one seated player, got %v", err)
+	}
+
+	if err := engine.SitIn("p2"); err != nil {
+		t.Fatalf("SitIn failed: %v", err)
+	}
+	if p2 := engine.getPlayerByID("p2"); p2.Status != models.PlayerStatusActive {
+		t.Errorf("expected p2 active, got %s", p2.Status)
+	}
+	if err := engine.StartHand(); err != nil {
+		t.Errorf("StartHand failed after sit in: %v", err)
+	}
+
+	if err := engine.SitIn("nonexistent"); err != ErrPlayerNotFound {
+		t.Errorf("expected ErrPlayerNotFound, got %v", err)
+	}
+}
+
+func TestRemoveIdlePlayers(t *testing.T) {
+	engine := NewEngine(&Config{
+		MinPlayers:    2,
+		MaxPlayers:    4,
+		SmallBlind:    10,
+		BigBlind:      20,
+		StartingChips: 1000,
+		SitOutOrbits:  1,
+	})
+
+	engine.AddPlayer("p1", "Alice", 0)
+	engine.AddPlayer("p2", "Bob", 1)
+	engine.AddPlayer("p3", "Charlie", 2)
+	engine.SitOut("p3")
+
+	// 一圈 = 2 个在座玩家的手数
+	for i := 0; i < 2; i++ {
+		if removed := engine.RemoveIdlePlayers(); len(removed) != 0 {
+			t.Fatalf("hand %d: expected no removal, got %d", i, len(removed))
+		}
+		if err := engine.StartHand(); err != nil {
+			t.Fatalf("StartHand failed: %v", err)
+		}
+		state := engine.GetState()
+		engine.PlayerAction(state.Players[state.CurrentPlayer].ID, models.ActionFold, 0)
+	}
+
+	removed := engine.RemoveIdlePlayers()
+	if len(removed) != 1 || removed[0].ID != "p3" {
+		t.Fatalf("expected p3 removed, got %v", removed)
+	}
+	if engine.getPlayerByID("p3") != nil {
+		t.Error("p3 should no longer be at the table")
+	}
+}
+
+// ==================== 换座与离开测试 ====================
+
+func TestChangeSeat_BetweenHands(t *testing.T) {
+	engine := NewEngine(&Config{
+		MinPlayers:    2,
+		MaxPlayers:    6,
+		SmallBlind:    10,
+		BigBlind:      20,
+		StartingChips: 1000,
+	})
+
+	engine.AddPlayer("p1", "Alice", 0)
+	engine.AddPlayer("p2", "Bob", 1)
+	engine.AddPlayer("p3", "Charlie", 2)
+
+	if err := engine.ChangeSeat("p1", 4); err != nil {
+		t.Fatalf("ChangeSeat failed: %v", err)
+	}
+
+	// 行动顺序按座位号排列
+	state := engine.GetState()
+	expected := []string{"p2", "p3", "p1"}
+	for i, id := range expected {
+		if state.Players[i].ID != id {
+			t.Errorf("position %d: expected %s, got %s", i, id, state.Players[i].ID)
+		}
+	}
+
+	if err := engine.ChangeSeat("p2", 2); err != ErrSeatOccupied {
+		t.Errorf("expected ErrSeatOccupied, got %v", err)
+	}
+	if err := engine.ChangeSeat("p2", 6); err != ErrInvalidSeat {
+		t.Errorf("expected ErrInvalidSeat, got %v", err)
+	}
+
+	engine.StartHand()
+	if err := engine.ChangeSeat("p2", 5); err != ErrHandInProgress {
+		t.Errorf("expected ErrHandInProgress during hand, got %v", err)
+	}
+}
+
+func TestChangeSeat_KeepsDealerButton(t *testing.T) {
+	engine := NewEngine(&Config{
+		MinPlayers:    2,
+		MaxPlayers:    6,
+		SmallBlind:    10,
+		BigBlind:      20,
+		StartingChips: 1000,
+	})
+
+	engine.AddPlayer("p1", "Alice", 0)
+	engine.AddPlayer("p2", "Bob", 1)
+	engine.AddPlayer("p3", "Charlie", 2)
+
+	engine.StartHand()
+	state := engine.GetState()
+	dealerID := state.Players[state.DealerButton].ID
+	for state.Stage != StageEnd && state.Stage != StageShowdown {
+		engine.PlayerAction(state.Players[state.CurrentPlayer].ID, models.ActionFold, 0)
+		state = engine.GetState()
+	}
+
+	if err := engine.ChangeSeat(dealerID, 5); err != nil {
+		t.Fatalf("ChangeSeat failed: %v", err)
+	}
+	state = engine.GetState()
+	if state.Players[state.DealerButton].ID != dealerID {
+		t.Errorf("dealer button should follow %s, got %s", dealerID, state.Players[state.DealerButton].ID)
+	}
+}
+
+func TestRemovePlayer_PendingUntilHandEnds(t *testing.T) {
+	engine := NewEngine(&Config{
+		MinPlayers:    2,
+		MaxPlayers:    4,
+		SmallBlind:    10,
+		BigBlind:      20,
+		StartingChips: 1000,
+	})
+
+	engine.AddPlayer("p1", "Alice", 0)
+	engine.AddPlayer("p2", "Bob", 1)
+	engine.AddPlayer("p3", "Charlie", 2)
+	engine.StartHand()
+
+	engine.RemovePlayer("p3")
+	if removed := engine.ApplyPendingRemovals(); removed != nil {
+		t.Errorf("should not remove players during a hand, got %v", removed)
+	}
+	if len(engine.GetState().Players) != 3 {
+		t.Fatal("player should stay at the table until the hand ends")
+	}
+
+	for i := 0; i < 2; i++ {
+		state := engine.GetState()
+		if state.Stage == StageEnd || state.Stage == StageShowdown {
+			break
+		}
+		engine.PlayerAction(state.Players[state.CurrentPlayer].ID, models.ActionFold, 0)
+	}
+
+	removed := engine.ApplyPendingRemovals()
+	if len(removed) != 1 || removed[0].ID != "p3" {
+		t.Fatalf("expected p3 removed after hand, got %v", removed)
+	}
+	if seats := engine.FreeSeats(); len(seats) != 2 || seats[0] != 2 {
+		t.Errorf("expected free seats [2 3], got %v", seats)
+	}
+}
+
+// ==================== 房主管理测试 ====================
+
+func TestAdjustChips(t *testing.T) {
+	engine := NewEngine(&Config{
+		MinPlayers:    2,
+		MaxPlayers:    6,
+		SmallBlind:    10,
+		BigBlind:      20,
+		StartingChips: 1000,
+	})
+
+	engine.AddPlayer("p1", "Alice", 0)
+	engine.AddPlayer("p2", "Bob", 1)
+
+	if err := engine.AdjustChips("p1", 500); err != nil {
+		t.Fatalf("AdjustChips failed: %v", err)
+	}
+	if err := engine.AdjustChips("p2", -300); err != nil {
+		t.Fatalf("AdjustChips failed: %v", err)
+	}
+	if chips := engine.getPlayerByID("p1").Chips; chips != 1500 {
+		t.Errorf("expected 1500 chips, got %d", chips)
+	}
+	if chips := engine.getPlayerByID("p2").Chips; chips != 700 {
+		t.Errorf("expected 700 chips, got %d", chips)
+	}
+
+	if err := engine.AdjustChips("p2", -701); err != ErrInvalidChips {
+		t.Errorf("expected ErrInvalidChips, got %v", err)
+	}
+	if err := engine.AdjustChips("nobody", 100); err != ErrPlayerNotFound {
+		t.Errorf("expected ErrPlayerNotFound, got %v", err)
+	}
+
+	engine.StartHand()
+	if err := engine.AdjustChips("p1", 100); err != ErrHandInProgress {
+		t.Errorf("expected ErrHandInProgress during hand, got %v", err)
+	}
+}
+
+func TestSetBlinds(t *testing.T) {
+	engine := NewEngine(&Config{
+		MinPlayers:    2,
+		MaxPlayers:    6,
+		SmallBlind:    10,
+		BigBlind:      20,
+		StartingChips: 1000,
+	})
+
+	engine.AddPlayer("p1", "Alice", 0)
+	engine.AddPlayer("p2", "Bob", 1)
+
+	// 两局之间立即生效
+	if err := engine.SetBlinds(20, 40, 5); err != nil {
+		t.Fatalf("SetBlinds failed: %v", err)
+	}
+	if engine.config.SmallBlind != 20 || engine.config.BigBlind != 40 || engine.config.Ante != 5 {
+		t.Errorf("expected blinds 20/40 ante 5, got %d/%d ante %d",
+			engine.config.SmallBlind, engine.config.BigBlind, engine.config.Ante)
+	}
+
+	// 非法盲注
+	for _, c := range [][3]int{{0, 20, 0}, {20, 10, 0}, {10, 20, -1}} {
+		if err := engine.SetBlinds(c[0], c[1], c[2]); err != ErrInvalidBlinds {
+			t.Errorf("SetBlinds(%d, %d, %d): expected ErrInvalidBlinds, got %v", c[0], c[1], c[2], err)
+		}
+	}
+
+	// 手牌进行中修改，下一局才生效
+	engine.StartHand()
+	if err := engine.SetBlinds(50, 100, 0); err != nil {
+		t.Fatalf("SetBlinds during hand failed: %v", err)
+	}
+	if engine.config.BigBlind != 40 {
+		t.Errorf("blinds should not change mid-hand, got big blind %d", engine.config.BigBlind)
+	}
+
+	engine.state.Stage = StageShowdown
+	engine.StartHand()
+	if engine.config.SmallBlind != 50 || engine.config.BigBlind != 100 || engine.config.Ante != 0 {
+		t.Errorf("expected blinds 50/100 ante 0 at next hand, got %d/%d ante %d",
+			engine.config.SmallBlind, engine.config.BigBlind, engine.config.Ante)
+	}
+}
+
+// ==================== 筹码守恒测试 ====================
+
+func TestChipBalance(t *testing.T) {
+	engine := NewEngine(&Config{
+		MinPlayers:    2,
+		MaxPlayers:    6,
+		SmallBlind:    10,
+		BigBlind:      20,
+		StartingChips: 1000,
+	})
+
+	engine.AddPlayer("p1", "Alice", 0)
+	engine.AddPlayer("p2", "Bob", 1)
+	engine.AddPlayer("p3", "Charlie", 2)
+
+	check := func(stage string, wantIssued int) {
+		t.Helper()
+		inPlay, issued := engine.ChipBalance()
+		if issued != wantIssued {
+			t.Errorf("%s: expected %d chips issued, got %d", stage, wantIssued, issued)
+		}
+		if inPlay != issued {
+			t.Errorf("%s: chips in play %d != issued %d", stage, inPlay, issued)
+		}
+	}
+
+	check("after join", 3000)
+
+	engine.AdjustChips("p1", 500)
+	check("after adjust", 3500)
+
+	engine.StartHand()
+	check("after blinds", 3500)
+
+	// 离开的玩家本局结束后收回筹码
+	engine.RemovePlayer("p3")
+	check("pending removal", 3500)
+
+	engine.state.Stage = StageShowdown
+	removed := engine.ApplyPendingRemovals()
+	if len(removed) != 1 {
+		t.Fatalf("expected 1 removed player, got %d", len(removed))
+	}
+	check("after removal", 3500-removed[0].Chips)
+}
+
+func TestChipBalance_AllInAfterEarlierStreet(t *testing.T) {
+	// 翻牌前没有人全下（底池没有分边池），翻牌圈有人全下时，翻牌前的底池也要并入主池
+	engine := NewEngine(&Config{
+		MinPlayers:    2,
+		MaxPlayers:    6,
+		SmallBlind:    10,
+		BigBlind:      20,
+		StartingChips: 1000,
+	})
+	engine.AddPlayer("p1", "Alice", 0)
+	engine.AddPlayer("p2", "Bob", 1)
+	engine.AddPlayer("p3", "Charlie", 2)
+	engine.AdjustChips("p3", -700)
+
+	if err := engine.StartHand(); err != nil {
+		t.Fatalf("StartHand failed: %v", err)
+	}
+	for i := 0; ; i++ {
+		state := engine.GetState()
+		if state.Stage == StageShowdown || i > 20 {
+			break
+		}
+		p := state.Players[state.CurrentPlayer]
+		action := models.ActionCall
+		switch {
+		case state.Stage == StageFlop && p.ID == "p3":
+			action = models.ActionAllIn
+		case state.CurrentBet == p.CurrentBet:
+			action = models.ActionCheck
+		}
+		if err := engine.PlayerAction(p.ID, action, 0); err != nil {
+			t.Fatalf("%s %s failed: %v", p.Name, action, err)
+		}
+	}
+
+	state := engine.GetState()
+	if state.Stage != StageShowdown {
+		t.Fatalf("expected showdown, got %s", state.Stage)
+	}
+	if state.Pot != 0 {
+		t.Errorf("expected the pot to be settled, got %d", state.Pot)
+	}
+	if inPlay, issued := engine.ChipBalance(); inPlay != issued {
+		t.Errorf("chips in play %d != issued %d", inPlay, issued)
+	}
+}
+
+// ==================== 手牌历史测试 ====================
+
+func TestHistory_RecordsLiveHands(t *testing.T) {
+	engine := NewEngine(&Config{
+		MinPlayers:    2,
+		MaxPlayers:    6,
+		SmallBlind:    10,
+		BigBlind:      20,
+		StartingChips: 1000,
+	})
+	history, _ := NewHistoryManager("")
+	engine.SetHistory(history)
+
+	engine.AddPlayer("p1", "Alice", 0)
+	engine.AddPlayer("p2", "Bob", 1)
+	engine.AddPlayer("p3", "Charlie", 2)
+
+	// 第一局：翻牌前行动者加注，其余玩家弃牌
+	if err := engine.StartHand(); err != nil {
+		t.Fatalf("StartHand failed: %v", err)
+	}
+	state := engine.GetState()
+	raiser := state.Players[state.CurrentPlayer]
+	if err := engine.PlayerAction(raiser.ID, models.ActionRaise, 60); err != nil {
+		t.Fatalf("raise failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		state = engine.GetState()
+		if err := engine.PlayerAction(state.Players[state.CurrentPlayer].ID, models.ActionFold, 0); err != nil {
+			t.Fatalf("fold failed: %v", err)
+		}
+	}
+
+	if history.GetHandCount() != 1 {
+		t.Fatalf("expected 1 recorded hand, got %d", history.GetHandCount())
+	}
+	hand, ok := history.GetHand(1)
+	if !ok {
+		t.Fatal("hand #1 not found")
+	}
+
+	if hand.EndTime.IsZero() || hand.EndTime.Before(hand.Timestamp) {
+		t.Errorf("expected end time after start time, got %v / %v", hand.Timestamp, hand.EndTime)
+	}
+	if hand.SmallBlind != 10 || hand.BigBlind != 20 {
+		t.Errorf("expected blinds 10/20, got %d/%d", hand.SmallBlind, hand.BigBlind)
+	}
+	if len(hand.Players) != 3 {
+		t.Fatalf("expected 3 players, got %d", len(hand.Players))
+	}
+
+	posted := 0
+	for _, p := range hand.Players {
+		if p.StartChips != 1000 {
+			t.Errorf("%s: expected start chips 1000, got %d", p.Name, p.StartChips)
+		}
+		if p.HoleCards[0].Rank == 0 {
+			t.Errorf("%s: hole cards not recorded", p.Name)
+		}
+		posted += p.PostedBlind
+	}
+	if posted != 30 {
+		t.Errorf("expected 30 posted in blinds, got %d", posted)
+	}
+
+	if len(hand.Actions) != 3 {
+		t.Fatalf("expected 3 actions, got %d", len(hand.Actions))
+	}
+	first := hand.Actions[0]
+	if first.PlayerID != raiser.ID || first.Action != models.ActionRaise || first.BetTo != 60 {
+		t.Errorf("unexpected first action: %+v", first)
+	}
+	if first.Round != StagePreFlop {
+		t.Errorf("expected preflop action, got %v", first.Round)
+	}
+
+	// 没有摊牌：只有获胜者，不记录牌型
+	if len(hand.Showdown) != 0 {
+		t.Errorf("expected no showdown entries, got %d", len(hand.Showdown))
+	}
+	if len(hand.Winners) != 1 || hand.Winners[0].PlayerID != raiser.ID {
+		t.Fatalf("expected %s to win, got %+v", raiser.Name, hand.Winners)
+	}
+	if hand.Pot != posted+first.Amount || hand.Winners[0].Amount != hand.Pot {
+		t.Errorf("expected pot %d, got pot %d / winner amount %d", posted+first.Amount, hand.Pot, hand.Winners[0].Amount)
+	}
+	for _, p := range hand.Players {
+		if p.ID == raiser.ID {
+			if !p.IsWinner {
+				t.Errorf("%s should be marked as winner", p.Name)
+			}
+			continue
+		}
+		if p.FinalChips != p.StartChips-p.PostedBlind {
+			t.Errorf("%s: expected final chips %d, got %d", p.Name, p.StartChips-p.PostedBlind, p.FinalChips)
+		}
+	}
+
+	// 第二局的编号接续第一局
+	engine.StartHand()
+	state = engine.GetState()
+	engine.PlayerAction(state.Players[state.CurrentPlayer].ID, models.ActionFold, 0)
+	state = engine.GetState()
+	engine.PlayerAction(state.Players[state.CurrentPlayer].ID, models.ActionFold, 0)
+
+	recent := history.GetRecentHands(10)
+	if len(recent) != 2 || recent[1].HandID != 2 {
+		t.Errorf("expected hands #1 and #2, got %d hands", len(recent))
+	}
+}
+
+// ==================== 洗牌测试 ====================
+
+// newShuffleTestEngine 创建三人桌引擎
+func newShuffleTestEngine() *GameEngine {
+	engine := NewEngine(&Config{MinPlayers: 2, MaxPlayers: 6, SmallBlind: 10, BigBlind: 20, StartingChips: 1000})
+	engine.AddPlayer("p1", "Alice", 0)
+	engine.AddPlayer("p2", "Bob", 1)
+	engine.AddPlayer("p3", "Charlie", 2)
+	return engine
+}
+
+func TestEngine_SeededRandomSource(t *testing.T) {
+	deal := func() [][2]card.Card {
+		engine := newShuffleTestEngine()
+		engine.SetRandomSource(card.NewSeededSource(7))
+		engine.StartHand()
+		var hands [][2]card.Card
+		for _, p := range engine.GetState().Players {
+			hands = append(hands, p.HoleCards)
+		}
+		return hands
+	}
+
+	first, second := deal(), deal()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("player %d: same seed dealt %v and %v", i, first[i], second[i])
+		}
+	}
+}
+
+func TestEngine_ProvablyFair(t *testing.T) {
+	engine := newShuffleTestEngine()
+	engine.SetProvablyFair(true)
+	if err := engine.StartHand(); err != nil {
+		t.Fatalf("StartHand failed: %v", err)
+	}
+
+	state := engine.GetState()
+	if len(state.FairHash) != 64 || state.FairSeed != "" {
+		t.Fatalf("expected commitment only before the hand ends, got hash=%q seed=%q", state.FairHash, state.FairSeed)
+	}
+	commitment := state.FairHash
+	holes := make(map[string][2]card.Card)
+	for _, p := range state.Players {
+		holes[p.ID] = p.HoleCards
+	}
+
+	// 所有人弃牌到大盲
+	for i := 0; i < 2; i++ {
+		state = engine.GetState()
+		engine.PlayerAction(state.Players[state.CurrentPlayer].ID, models.ActionFold, 0)
+	}
+
+	state = engine.GetState()
+	if state.FairHash != commitment || state.FairSeed == "" {
+		t.Fatalf("expected seed to be revealed after the hand, got hash=%q seed=%q", state.FairHash, state.FairSeed)
+	}
+	deck, err := fair.Verify(commitment, state.FairSeed)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	for id, hole := range holes {
+		if err := fair.CheckDeal(deck, hole, nil); err != nil {
+			t.Errorf("%s: %v", id, err)
+		}
+	}
+
+	// 下一局使用新的种子
+	engine.StartHand()
+	if next := engine.GetState(); next.FairHash == commitment || next.FairSeed != "" {
+		t.Errorf("expected a fresh commitment for the next hand, got hash=%q seed=%q", next.FairHash, next.FairSeed)
+	}
+}
+
+func TestEngine_ProvablyFairShuffleError(t *testing.T) {
+	engine := newShuffleTestEngine()
+	engine.SetProvablyFair(true)
+	before := engine.GetState()
+
+	// 生成种子失败时不改动状态，之后可以正常开局
+	newFairShuffle = func() (*fair.Shuffle, error) { return nil, errors.New("entropy unavailable") }
+	err := engine.StartHand()
+	newFairShuffle = fair.NewShuffle
+	if err == nil {
+		t.Fatal("expected StartHand to fail when the shuffle cannot be created")
+	}
+
+	state := engine.GetState()
+	if state.Stage != StageWaiting || state.Pot != 0 {
+		t.Fatalf("expected an untouched waiting table, got stage=%s pot=%d", state.Stage, state.Pot)
+	}
+	for i, p := range state.Players {
+		if p.Chips != before.Players[i].Chips || p.SitOutHands != before.Players[i].SitOutHands {
+			t.Errorf("player %s changed after a failed start: %+v", p.Name, p)
+		}
+	}
+
+	if err := engine.StartHand(); err != nil {
+		t.Fatalf("StartHand after a shuffle error failed: %v", err)
+	}
+	if engine.GetState().Stage != StagePreFlop {
+		t.Errorf("expected preflop after retrying, got %s", engine.GetState().Stage)
+	}
+}
+
 // ==================== 性能测试 ====================
 
 func BenchmarkDetermineWinners(b *testing.B) {
@@ -1463,3 +2123,140 @@ func BenchmarkDetermineWinners(b *testing.B) {
 		engine.determineWinners()
 	}
 }
+
+// ==================== 全下期望值测试 ====================
+
+func TestAllInEV_Preflop(t *testing.T) {
+	engine := NewEngine(&Config{MinPlayers: 2, MaxPlayers: 6, SmallBlind: 10, BigBlind: 20, StartingChips: 1000})
+	history, _ := NewHistoryManager("")
+	stats := NewStatsManager()
+	engine.SetHistory(history)
+	engine.SetStats(stats)
+	engine.AddPlayer("p1", "Alice", 0)
+	engine.AddPlayer("p2", "Bob", 1)
+	if err := engine.StartHand(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 翻牌前全下并跟注
+	for i := 0; i < 2; i++ {
+		state := engine.GetState()
+		if err := engine.PlayerAction(state.Players[state.CurrentPlayer].ID, models.ActionAllIn, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sd := engine.GetState().LastShowdown
+	if sd == nil || sd.AllInStage != StagePreFlop {
+		t.Fatalf("expected a preflop all-in showdown, got %+v", sd)
+	}
+	var equitySum, evSum float64
+	for _, pr := range sd.Players {
+		equitySum += pr.AllInEquity
+		evSum += pr.EVWonAmount
+		// 按权益期望赢得的筹码 = 底池 × 权益
+		if diff := pr.EVWonAmount - 2000*pr.AllInEquity; diff > 1e-6 || diff < -1e-6 {
+			t.Errorf("%s: expected EV %.1f for equity %.4f, got %.1f", pr.PlayerName, 2000*pr.AllInEquity, pr.AllInEquity, pr.EVWonAmount)
+		}
+	}
+	if equitySum < 0.999 || equitySum > 1.001 || evSum < 1999 || evSum > 2001 {
+		t.Errorf("expected equities to sum to 1 and EV to the pot, got %v and %v", equitySum, evSum)
+	}
+
+	hands := history.GetRecentHands(1)
+	if len(hands) != 1 || hands[0].AllInStage != StagePreFlop {
+		t.Fatalf("expected the all-in stage in hand history, got %+v", hands)
+	}
+	var evNet float64
+	for _, hp := range hands[0].Players {
+		if hp.AllInEquity == 0 && hp.EVWonChips == 0 {
+			t.Errorf("%s: expected all-in EV in hand history", hp.Name)
+		}
+		st := stats.GetStats(hp.Name)
+		if st.AllInHands != 1 {
+			t.Errorf("%s: expected 1 all-in hand, got %d", hp.Name, st.AllInHands)
+		}
+		evNet += st.AllInEVNet
+	}
+	if evNet < -1 || evNet > 1 {
+		t.Errorf("expected EV-adjusted winnings to sum to zero, got %v", evNet)
+	}
+}
+
+func TestAllInEV_SidePots(t *testing.T) {
+	engine := NewEngine(&Config{MinPlayers: 2, MaxPlayers: 6, SmallBlind: 10, BigBlind: 20, StartingChips: 1000})
+	engine.AddPlayer("p1", "Alice", 0)
+	engine.AddPlayer("p2", "Bob", 1)
+	engine.AddPlayer("p3", "Charlie", 2)
+	if err := engine.StartHand(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 筹码 300/1000/1000 的三人翻牌前全下：主池 900 三人争夺，边池 1400 只在两名深筹码玩家之间分配
+	engine.handStartChips = map[string]int{"p1": 300, "p2": 1000, "p3": 1000}
+	short := -1
+	for i, p := range engine.state.Players {
+		p.Chips = 0
+		p.Status = models.PlayerStatusAllIn
+		if p.ID == "p1" {
+			short = i
+		}
+	}
+
+	ev := engine.computeAllInEV()
+	if ev == nil {
+		t.Fatal("expected all-in EV")
+	}
+	// 短筹码玩家只能赢主池
+	if got, want := ev.evWon[short], 900*ev.equity[short]; got < want-1e-6 || got > want+1e-6 {
+		t.Errorf("expected short stack EV %.1f, got %.1f", want, got)
+	}
+	total := 0.0
+	for _, v := range ev.evWon {
+		total += v
+	}
+	if total < 2299 || total > 2301 {
+		t.Errorf("expected EV to sum to the pot 2300, got %.1f", total)
+	}
+}
+
+func TestAllInEV_Reproducible(t *testing.T) {
+	// 同一副牌重放时全下权益和期望值相同（单挑和多人翻牌前全下）
+	deck := card.NewDeck()
+	deck.ShuffleWith(card.NewSeededSource(11))
+	cards := deck.Cards()
+
+	play := func(players int) []PlayerResult {
+		engine := NewEngine(&Config{MinPlayers: 2, MaxPlayers: 6, SmallBlind: 10, BigBlind: 20, StartingChips: 1000})
+		for i := 0; i < players; i++ {
+			engine.AddPlayer(fmt.Sprintf("p%d", i+1), fmt.Sprintf("P%d", i+1), i)
+		}
+		if err := engine.SetNextDeck(cards); err != nil {
+			t.Fatal(err)
+		}
+		if err := engine.StartHand(); err != nil {
+			t.Fatal(err)
+		}
+		for engine.GetState().Stage == StagePreFlop {
+			state := engine.GetState()
+			if err := engine.PlayerAction(state.Players[state.CurrentPlayer].ID, models.ActionAllIn, 0); err != nil {
+				t.Fatal(err)
+			}
+		}
+		sd := engine.GetState().LastShowdown
+		if sd == nil || sd.AllInStage != StagePreFlop {
+			t.Fatalf("expected a preflop all-in showdown, got %+v", sd)
+		}
+		return sd.Players
+	}
+
+	for _, players := range []int{2, 4} {
+		first, second := play(players), play(players)
+		for i := range first {
+			if first[i].AllInEquity != second[i].AllInEquity || first[i].EVWonAmount != second[i].EVWonAmount {
+				t.Errorf("%d players, %s: expected the same EV on replay, got %v/%v and %v/%v", players, first[i].PlayerName,
+					first[i].AllInEquity, first[i].EVWonAmount, second[i].AllInEquity, second[i].EVWonAmount)
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package game
+
+// ==================== 资金曲线 ====================
+
+// GraphPoint 资金曲线上的一个点（对应玩家参与的一手牌，盈亏均为累计值）
+type GraphPoint struct {
+	HandID      int     `json:"hand_id"`      // 手牌编号
+	Stack       int     `json:"stack"`        // 本手结束后的筹码
+	Net         int     `json:"net"`          // 累计净盈亏
+	AllInEV     float64 `json:"all_in_ev"`    // 累计全下期望盈亏（全下的手牌按期望值，其余按实际结果）
+	AllInHands  int     `json:"all_in_hands"` // 累计河牌前全员全下的手数（为 0 时全下期望盈亏与净盈亏相同）
+	Showdown    int     `json:"showdown"`     // 累计摊牌盈亏
+	NonShowdown int     `json:"non_showdown"` // 累计非摊牌盈亏
+}
+
+// BuildSessionGraph 按时间顺序由手牌历史生成玩家的资金曲线（按名称匹配玩家，未参与的手牌跳过）
+func BuildSessionGraph(hands []HandHistory, name string) []GraphPoint {
+	points := make([]GraphPoint, 0, len(hands))
+	var cur GraphPoint
+	for i := range hands {
+		hand := &hands[i]
+		hp := findHistoryPlayer(hand, name)
+		if hp == nil {
+			continue
+		}
+
+		net := hp.FinalChips - hp.StartChips
+		cur.HandID = hand.HandID
+		cur.Stack = hp.FinalChips
+		cur.Net += net
+		cur.AllInEV += handAllInEV(hand, hp)
+		if hand.AllInStage != StageWaiting && wentToShowdown(hand, hp.ID) {
+			cur.AllInHands++
+		}
+		if wentToShowdown(hand, hp.ID) {
+			cur.Showdown += net
+		} else {
+			cur.NonShowdown += net
+		}
+		points = append(points, cur)
+	}
+	return points
+}
+
+// findHistoryPlayer 按名称查找手牌中的玩家
+func findHistoryPlayer(hand *HandHistory, name string) *HistoryPlayer {
+	for i := range hand.Players {
+		if hand.Players[i].Name == name {
+			return &hand.Players[i]
+		}
+	}
+	return nil
+}
+
+// wentToShowdown 判断玩家是否在该手牌中摊牌
+func wentToShowdown(hand *HandHistory, playerID string) bool {
+	for _, sd := range hand.Showdown {
+		if sd.PlayerID == playerID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package game
+
+import "testing"
+
+func TestBuildSessionGraph(t *testing.T) {
+	// 新连接后 ID 会变化，曲线按名称匹配玩家
+	hands := []HandHistory{
+		hudTestHand([4]string{"a1", "b1", "c1", "d1"}),
+		{
+			HandID: 2,
+			Players: []HistoryPlayer{
+				{ID: "a2", Name: "Alice", StartChips: 320, FinalChips: 400},
+				{ID: "b2", Name: "Bob", StartChips: 990, FinalChips: 910},
+			},
+		},
+		{
+			HandID: 3,
+			Players: []HistoryPlayer{
+				{ID: "b2", Name: "Bob", StartChips: 910, FinalChips: 890},
+				{ID: "c2", Name: "Carol", StartChips: 980, FinalChips: 1000},
+			},
+		},
+		{
+			// 翻牌圈全下，Alice 有 70% 权益并赢下 800 的底池
+			HandID:     4,
+			AllInStage: StageFlop,
+			Players: []HistoryPlayer{
+				{ID: "a2", Name: "Alice", StartChips: 400, FinalChips: 800, WonChips: 800, AllInEquity: 0.7, EVWonChips: 560},
+				{ID: "b2", Name: "Bob", StartChips: 890, FinalChips: 490, AllInEquity: 0.3, EVWonChips: 240},
+			},
+			Showdown: []ShowdownInfo{{PlayerID: "a2"}, {PlayerID: "b2"}},
+		},
+	}
+
+	points := BuildSessionGraph(hands, "Alice")
+	if len(points) != 3 {
+		t.Fatalf("Expected 3 points (hand 3 skipped), got %d", len(points))
+	}
+
+	want := []GraphPoint{
+		{HandID: 1, Stack: 320, Net: -680, AllInEV: -680, Showdown: -680},
+		{HandID: 2, Stack: 400, Net: -600, AllInEV: -600, Showdown: -680, NonShowdown: 80},
+		{HandID: 4, Stack: 800, Net: -200, AllInEV: -440, AllInHands: 1, Showdown: -280, NonShowdown: 80},
+	}
+	for i, p := range points {
+		if p != want[i] {
+			t.Errorf("Point %d: expected %+v, got %+v", i, want[i], p)
+		}
+		if p.Showdown+p.NonShowdown != p.Net {
+			t.Errorf("Point %d: showdown + non-showdown should equal net", i)
+		}
+	}
+
+	if len(BuildSessionGraph(hands, "Nobody")) != 0 {
+		t.Error("Expected no points for a player who never played")
+	}
+}
@@ -1,10 +1,11 @@
 package game
 
 import (
-	"encoding/json"
 	"fmt"
+	"log"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
@@ -16,13 +17,22 @@ import (
 type HandHistory struct {
 	HandID         int              `json:"hand_id"`          // 手牌编号
 	Timestamp      time.Time        `json:"timestamp"`        // 时间戳
+	EndTime        time.Time        `json:"end_time"`         // 结束时间
 	GameID         string           `json:"game_id"`         // 游戏ID
+	SmallBlind     int              `json:"small_blind"`     // 小盲注
+	BigBlind       int              `json:"big_blind"`       // 大盲注
+	Ante           int              `json:"ante"`            // 前注
+	DealerSeat     int              `json:"dealer_seat"`     // 庄家座位号
 	Players        []HistoryPlayer  `json:"players"`         // 参与的玩家
 	CommunityCards [5]card.Card    `json:"community_cards"` // 公共牌
 	Actions        []HistoryAction  `json:"actions"`         // 行动记录
 	Showdown       []ShowdownInfo  `json:"showdown"`        // 摊牌信息
 	Pot            int              `json:"pot"`             // 底池金额
 	Winners        []WinnerInfo    `json:"winners"`         // 获胜者信息
+	Deck           []card.Card      `json:"deck,omitempty"`  // 开局时的完整牌序（用于复现手牌）
+	FairHash       string           `json:"fair_hash,omitempty"` // 可验证公平洗牌的种子摘要
+	FairSeed       string           `json:"fair_seed,omitempty"` // 可验证公平洗牌的种子
+	AllInStage     Stage            `json:"all_in_stage,omitempty"` // 河牌前全员全下时所在的阶段（没有全下时为 0）
 }
 
 // HistoryPlayer 表示历史记录中的玩家信息
@@ -31,9 +41,14 @@ type HistoryPlayer struct {
 	Name       string       `json:"name"`        // 玩家名称
 	Seat       int          `json:"seat"`        // 座位号
 	HoleCards  [2]card.Card `json:"hole_cards"`  // 底牌
+	StartChips int          `json:"start_chips"` // 开局筹码（扣除前注和盲注之前）
+	PostedAnte int          `json:"posted_ante"` // 支付的前注
+	PostedBlind int         `json:"posted_blind"` // 支付的盲注
 	FinalChips int          `json:"final_chips"` // 最终筹码
 	WonChips   int          `json:"won_chips"`   // 赢得筹码
 	IsWinner   bool         `json:"is_winner"`   // 是否获胜
+	AllInEquity float64     `json:"all_in_equity,omitempty"` // 河牌前全员全下时的权益
+	EVWonChips  float64     `json:"ev_won_chips,omitempty"`  // 按全下时的权益期望赢得的筹码
 }
 
 // HistoryAction 表示历史记录中的行动
@@ -42,7 +57,8 @@ type HistoryAction struct {
 	PlayerName string           `json:"player_name"` // 玩家名称
 	Round      Stage           `json:"round"`       // 所在阶段
 	Action     models.ActionType `json:"action"`     // 执行的行动
-	Amount     int              `json:"amount"`      // 下注金额
+	Amount     int              `json:"amount"`      // 本次投入的筹码
+	BetTo      int              `json:"bet_to"`      // 行动后本轮累计下注
 	Timestamp  time.Time        `json:"timestamp"`   // 时间戳
 }
 
@@ -64,40 +80,164 @@ type WinnerInfo struct {
 
 // HistoryManager 管理手牌历史记录
 type HistoryManager struct {
-	hands      []HandHistory      // 所有手牌历史
+	hands      []HandHistory      // 内存中的手牌历史（持久化时只缓存最近 CacheSize 手）
 	currentHand *HandHistory      // 当前正在进行的牌局
-	file       *os.File           // 文件句柄（用于保存）
+	store      *historyStore      // 持久化存储（为 nil 时只保存在内存中）
 	filename   string             // 保存文件名
+	cacheSize  int                // 持久化时内存缓存的手数
+	nextID     int                // 下一手牌的编号（与存储索引分开计数，保存失败的手牌也不会重复编号）
 	mu         chan struct{}      // 互斥锁（使用chan模拟）
+
+	// 持久化时由写入协程按顺序追加手牌，EndHand 不在调用方（引擎锁内）等待 fsync
+	storeMu    sync.Mutex         // 保护 store（写入协程追加时只持有该锁）
+	writes     chan historyWrite  // 待写入的手牌和刷新请求
+	writerDone chan struct{}      // 写入协程退出时关闭
+	closeMu    sync.RWMutex       // 保护 closed 和关闭 writes
+	closed     bool               // 是否已关闭
+}
+
+// historyWrite 写入协程的一项任务：追加一手牌，或者（hand 为 nil 时）在之前的任务完成后关闭 done
+type historyWrite struct {
+	hand *HandHistory
+	done chan struct{}
 }
 
-// NewHistoryManager 创建历史记录管理器
+// NewHistoryManager 创建历史记录管理器（filename 为空时只保存在内存中）
 func NewHistoryManager(filename string) (*HistoryManager, error) {
+	return NewHistoryManagerWithOptions(filename, DefaultHistoryOptions())
+}
+
+// NewHistoryManagerWithOptions 使用指定的存储选项创建历史记录管理器
+// 手牌以 JSON Lines 格式追加写入 filename 同目录下按日期和大小切分的文件，
+// 旧版的 JSON 数组文件会被导入后重命名为 .bak
+func NewHistoryManagerWithOptions(filename string, opts HistoryOptions) (*HistoryManager, error) {
 	h := &HistoryManager{
-		hands:    make([]HandHistory, 0),
-		filename: filename,
-		mu:       make(chan struct{}, 1),
-	}
-
-	// 如果指定了文件名，尝试加载历史记录
-	if filename != "" {
-		if file, err := os.Open(filename); err == nil {
-			defer file.Close()
-			decoder := json.NewDecoder(file)
-			if err := decoder.Decode(&h.hands); err == nil {
-				// 加载成功
+		hands:     make([]HandHistory, 0),
+		filename:  filename,
+		cacheSize: opts.CacheSize,
+		nextID:    1,
+		mu:        make(chan struct{}, 1),
+	}
+	if filename == "" {
+		return h, nil
+	}
+
+	store, err := openHistoryStore(filename, opts)
+	if err != nil {
+		return nil, err
+	}
+	h.store = store
+
+	// 导入旧版历史文件
+	if legacy := loadLegacyHistory(filename); legacy != nil && len(store.index) == 0 {
+		for i := range legacy {
+			if err := store.append(&legacy[i]); err != nil {
+				store.closeFiles()
+				return nil, err
 			}
 		}
+		if err := os.Rename(filename, filename+".bak"); err != nil {
+			log.Printf("[历史] 旧版历史文件重命名失败 | 文件=%s | 错误=%v", filename, err)
+		}
+		log.Printf("[历史] 导入旧版历史 | 文件=%s | 手数=%d", filename, len(legacy))
+	}
+
+	// 缓存最近的手牌
+	from := len(store.index) - h.cacheSize
+	if from < 0 || h.cacheSize <= 0 {
+		from = 0
 	}
+	h.hands = store.readRange(from, len(store.index))
+	if n := len(store.index); n > 0 {
+		h.nextID = store.index[n-1].HandID + 1
+	}
+
+	h.writes = make(chan historyWrite, 256)
+	h.writerDone = make(chan struct{})
+	go h.writeLoop()
 
 	return h, nil
 }
 
+// Close 等待排队的手牌写完后关闭历史文件
+func (h *HistoryManager) Close() error {
+	if h.store == nil {
+		return nil
+	}
+
+	h.closeMu.Lock()
+	if !h.closed {
+		h.closed = true
+		close(h.writes)
+	}
+	h.closeMu.Unlock()
+	<-h.writerDone
+
+	h.storeMu.Lock()
+	defer h.storeMu.Unlock()
+	return h.store.closeFiles()
+}
+
+// writeLoop 写入协程：按顺序追加手牌并 fsync，保存失败的手牌从缓存中移除，保证缓存与文件一致
+func (h *HistoryManager) writeLoop() {
+	defer close(h.writerDone)
+	for w := range h.writes {
+		if w.hand == nil {
+			close(w.done)
+			continue
+		}
+
+		h.storeMu.Lock()
+		err := h.store.append(w.hand)
+		h.storeMu.Unlock()
+		if err != nil {
+			log.Printf("[历史] 保存失败 | 手牌=#%d | 错误=%v", w.hand.HandID, err)
+			h.uncacheHand(w.hand.HandID)
+		}
+	}
+}
+
+// enqueue 把任务交给写入协程（已关闭时返回 false）
+func (h *HistoryManager) enqueue(w historyWrite) bool {
+	h.closeMu.RLock()
+	defer h.closeMu.RUnlock()
+	if h.closed {
+		return false
+	}
+	h.writes <- w
+	return true
+}
+
+// flush 等待之前排队的手牌都写完（从文件读取前调用，调用方不能持有 h.mu）
+func (h *HistoryManager) flush() {
+	if h.store == nil {
+		return
+	}
+	done := make(chan struct{})
+	if h.enqueue(historyWrite{done: done}) {
+		<-done
+	}
+}
+
+// uncacheHand 从缓存中移除保存失败的手牌
+func (h *HistoryManager) uncacheHand(handID int) {
+	h.mu <- struct{}{}
+	defer func() { <-h.mu }()
+
+	for i := range h.hands {
+		if h.hands[i].HandID == handID {
+			h.hands = append(h.hands[:i], h.hands[i+1:]...)
+			return
+		}
+	}
+}
+
 // StartHand 开始记录新的一手牌
 func (h *HistoryManager) StartHand(handID int, gameID string) {
 	h.mu <- struct{}{}
 	defer func() { <-h.mu }()
 
+	h.nextID = max(h.nextID, handID+1)
 	h.currentHand = &HandHistory{
 		HandID:    handID,
 		Timestamp: time.Now(),
@@ -108,8 +248,51 @@ func (h *HistoryManager) StartHand(handID int, gameID string) {
 	}
 }
 
-// AddPlayer 添加玩家到当前手牌记录
+// SetTableInfo 记录当前手牌的盲注、前注和庄家座位
+func (h *HistoryManager) SetTableInfo(smallBlind, bigBlind, ante, dealerSeat int) {
+	h.mu <- struct{}{}
+	defer func() { <-h.mu }()
+
+	if h.currentHand == nil {
+		return
+	}
+
+	h.currentHand.SmallBlind = smallBlind
+	h.currentHand.BigBlind = bigBlind
+	h.currentHand.Ante = ante
+	h.currentHand.DealerSeat = dealerSeat
+}
+
+// SetDeck 记录当前手牌开局时的完整牌序
+func (h *HistoryManager) SetDeck(deck []card.Card) {
+	h.mu <- struct{}{}
+	defer func() { <-h.mu }()
+
+	if h.currentHand == nil {
+		return
+	}
+
+	h.currentHand.Deck = deck
+}
+
+// SetFairShuffle 记录当前手牌可验证公平洗牌的种子摘要和种子
+func (h *HistoryManager) SetFairShuffle(hash, seed string) {
+	h.mu <- struct{}{}
+	defer func() { <-h.mu }()
+
+	if h.currentHand == nil {
+		return
+	}
+
+	h.currentHand.FairHash = hash
+	h.currentHand.FairSeed = seed
+}
+
+// AddPlayer 添加玩家到当前手牌记录（chips 为开局筹码）
 func (h *HistoryManager) AddPlayer(id, name string, seat int, chips int) {
+	h.mu <- struct{}{}
+	defer func() { <-h.mu }()
+
 	if h.currentHand == nil {
 		return
 	}
@@ -118,12 +301,76 @@ func (h *HistoryManager) AddPlayer(id, name string, seat int, chips int) {
 		ID:         id,
 		Name:       name,
 		Seat:       seat,
+		StartChips: chips,
 		FinalChips: chips,
 	})
 }
 
-// RecordAction 记录玩家行动
-func (h *HistoryManager) RecordAction(playerID, playerName string, round Stage, action models.ActionType, amount int) {
+// SetHoleCards 记录玩家底牌
+func (h *HistoryManager) SetHoleCards(playerID string, cards [2]card.Card) {
+	h.updatePlayer(playerID, func(p *HistoryPlayer) {
+		p.HoleCards = cards
+	})
+}
+
+// RecordPosts 记录玩家支付的前注和盲注
+func (h *HistoryManager) RecordPosts(playerID string, ante, blind int) {
+	h.updatePlayer(playerID, func(p *HistoryPlayer) {
+		p.PostedAnte = ante
+		p.PostedBlind = blind
+	})
+}
+
+// SetPlayerResult 记录玩家本局结束后的筹码和赢得的筹码
+func (h *HistoryManager) SetPlayerResult(playerID string, finalChips, wonChips int) {
+	h.updatePlayer(playerID, func(p *HistoryPlayer) {
+		p.FinalChips = finalChips
+		p.WonChips = wonChips
+	})
+}
+
+// SetPlayerAllInEV 记录玩家在河牌前全员全下时的权益和期望赢得的筹码
+func (h *HistoryManager) SetPlayerAllInEV(playerID string, equity, evWon float64) {
+	h.updatePlayer(playerID, func(p *HistoryPlayer) {
+		p.AllInEquity = equity
+		p.EVWonChips = evWon
+	})
+}
+
+// SetAllInStage 记录本手牌在哪个阶段全员全下
+func (h *HistoryManager) SetAllInStage(stage Stage) {
+	h.mu <- struct{}{}
+	defer func() { <-h.mu }()
+
+	if h.currentHand == nil {
+		return
+	}
+
+	h.currentHand.AllInStage = stage
+}
+
+// updatePlayer 修改当前手牌中的玩家记录
+func (h *HistoryManager) updatePlayer(playerID string, fn func(p *HistoryPlayer)) {
+	h.mu <- struct{}{}
+	defer func() { <-h.mu }()
+
+	if h.currentHand == nil {
+		return
+	}
+
+	for i := range h.currentHand.Players {
+		if h.currentHand.Players[i].ID == playerID {
+			fn(&h.currentHand.Players[i])
+			return
+		}
+	}
+}
+
+// RecordAction 记录玩家行动（amount 为本次投入的筹码，betTo 为行动后本轮累计下注）
+func (h *HistoryManager) RecordAction(playerID, playerName string, round Stage, action models.ActionType, amount, betTo int) {
+	h.mu <- struct{}{}
+	defer func() { <-h.mu }()
+
 	if h.currentHand == nil {
 		return
 	}
@@ -134,12 +381,16 @@ func (h *HistoryManager) RecordAction(playerID, playerName string, round Stage,
 		Round:      round,
 		Action:     action,
 		Amount:     amount,
+		BetTo:      betTo,
 		Timestamp:  time.Now(),
 	})
 }
 
 // RecordShowdown 记录摊牌信息
 func (h *HistoryManager) RecordShowdown(playerID, playerName string, handRank evaluator.HandRank, handName string, bestCards []card.Card) {
+	h.mu <- struct{}{}
+	defer func() { <-h.mu }()
+
 	if h.currentHand == nil {
 		return
 	}
@@ -153,15 +404,32 @@ func (h *HistoryManager) RecordShowdown(playerID, playerName string, handRank ev
 	})
 }
 
-// EndHand 结束当前手牌记录
-func (h *HistoryManager) EndHand(community [5]card.Card, pot int, winners []WinnerInfo) {
+// EndHand 结束当前手牌记录，返回完成的手牌（没有正在记录的手牌时返回 false）
+// 手牌立即进入缓存，写入文件由写入协程完成（不在调用方等待 fsync）；保存失败的手牌会从缓存中移除
+func (h *HistoryManager) EndHand(community [5]card.Card, pot int, winners []WinnerInfo) (HandHistory, bool) {
+	hand, ok := h.finishHand(community, pot, winners)
+	if ok && h.store != nil && !h.enqueue(historyWrite{hand: &hand}) {
+		log.Printf("[历史] 历史已关闭，未保存 | 手牌=#%d", hand.HandID)
+		h.uncacheHand(hand.HandID)
+	}
+	return hand, ok
+}
+
+// finishHand 完成当前手牌记录并加入缓存
+func (h *HistoryManager) finishHand(community [5]card.Card, pot int, winners []WinnerInfo) (HandHistory, bool) {
+	h.mu <- struct{}{}
+	defer func() { <-h.mu }()
+
 	if h.currentHand == nil {
-		return
+		return HandHistory{}, false
 	}
+	hand := h.currentHand
+	h.currentHand = nil
 
-	h.currentHand.CommunityCards = community
-	h.currentHand.Pot = pot
-	h.currentHand.Winners = winners
+	hand.EndTime = time.Now()
+	hand.CommunityCards = community
+	hand.Pot = pot
+	hand.Winners = winners
 
 	// 标记获胜者
 	winnerSet := make(map[string]bool)
@@ -169,46 +437,72 @@ func (h *HistoryManager) EndHand(community [5]card.Card, pot int, winners []Winn
 		winnerSet[w.PlayerID] = true
 	}
 
-	for i := range h.currentHand.Players {
-		if winnerSet[h.currentHand.Players[i].ID] {
-			h.currentHand.Players[i].IsWinner = true
+	for i := range hand.Players {
+		if winnerSet[hand.Players[i].ID] {
+			hand.Players[i].IsWinner = true
 		}
 	}
 
-	h.hands = append(h.hands, *h.currentHand)
-	h.currentHand = nil
+	h.cacheHand(*hand)
+	return *hand, true
+}
 
-	// 保存到文件
-	if h.filename != "" {
-		h.saveToFile()
+// cacheHand 把手牌加入内存缓存（持久化时只保留最近 cacheSize 手，调用方需持有锁）
+func (h *HistoryManager) cacheHand(hand HandHistory) {
+	h.hands = append(h.hands, hand)
+	if h.store != nil && h.cacheSize > 0 && len(h.hands) > h.cacheSize {
+		h.hands = append(h.hands[:0:0], h.hands[len(h.hands)-h.cacheSize:]...)
 	}
 }
 
-// saveToFile 保存历史记录到文件
-func (h *HistoryManager) saveToFile() {
-	if h.filename == "" {
-		return
+// AddHand 添加一手已完成的牌（例如从外部导入），HandID 为 0 时自动编号
+func (h *HistoryManager) AddHand(hand HandHistory) (int, error) {
+	if hand.HandID == 0 {
+		hand.HandID = h.NextHandID()
 	}
+	h.flush()
 
-	file, err := os.OpenFile(h.filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
-	if err != nil {
-		return
+	h.mu <- struct{}{}
+	defer func() { <-h.mu }()
+
+	if h.store != nil {
+		h.storeMu.Lock()
+		err := h.store.append(&hand)
+		h.storeMu.Unlock()
+		if err != nil {
+			return 0, err
+		}
 	}
-	defer file.Close()
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	encoder.Encode(h.hands)
+	h.nextID = max(h.nextID, hand.HandID+1)
+	h.cacheHand(hand)
+	return hand.HandID, nil
 }
 
 // GetRecentHands 获取最近N手牌历史
 func (h *HistoryManager) GetRecentHands(n int) []HandHistory {
-	h.mu <- struct{}{}
-	defer func() { <-h.mu }()
-
 	if n <= 0 {
 		n = 10
 	}
+
+	// 超出内存缓存的部分从文件读取（先等排队的手牌写完）
+	h.mu <- struct{}{}
+	fromStore := h.store != nil && n > len(h.hands)
+	<-h.mu
+	if fromStore {
+		h.flush()
+		h.storeMu.Lock()
+		defer h.storeMu.Unlock()
+		total := len(h.store.index)
+		if n > total {
+			n = total
+		}
+		return h.store.readRange(total-n, total)
+	}
+
+	h.mu <- struct{}{}
+	defer func() { <-h.mu }()
+
 	if n > len(h.hands) {
 		n = len(h.hands)
 	}
@@ -218,8 +512,52 @@ func (h *HistoryManager) GetRecentHands(n int) []HandHistory {
 	return result
 }
 
-// GetAllHands 获取所有手牌历史
+// GetHand 按手牌编号查找历史记录
+func (h *HistoryManager) GetHand(handID int) (HandHistory, bool) {
+	h.mu <- struct{}{}
+	for i := len(h.hands) - 1; i >= 0; i-- {
+		if h.hands[i].HandID == handID {
+			hand := h.hands[i]
+			<-h.mu
+			return hand, true
+		}
+	}
+	<-h.mu
+
+	// 不在缓存中时按索引从文件读取
+	if h.store != nil {
+		h.flush()
+		h.storeMu.Lock()
+		defer h.storeMu.Unlock()
+		if i, ok := h.store.byID[handID]; ok {
+			hand, err := h.store.read(h.store.index[i])
+			if err != nil {
+				log.Printf("[历史] 读取失败 | 手牌=#%d | 错误=%v", handID, err)
+				return HandHistory{}, false
+			}
+			return hand, true
+		}
+	}
+	return HandHistory{}, false
+}
+
+// NextHandID 返回下一手牌的编号（接续已加载的历史记录）
+func (h *HistoryManager) NextHandID() int {
+	h.mu <- struct{}{}
+	defer func() { <-h.mu }()
+
+	return h.nextID
+}
+
+// GetAllHands 获取所有手牌历史（持久化时从文件读取）
 func (h *HistoryManager) GetAllHands() []HandHistory {
+	if h.store != nil {
+		h.flush()
+		h.storeMu.Lock()
+		defer h.storeMu.Unlock()
+		return h.store.readRange(0, len(h.store.index))
+	}
+
 	h.mu <- struct{}{}
 	defer func() { <-h.mu }()
 
@@ -230,9 +568,15 @@ func (h *HistoryManager) GetAllHands() []HandHistory {
 
 // GetHandCount 获取历史手牌总数
 func (h *HistoryManager) GetHandCount() int {
+	if h.store != nil {
+		h.flush()
+		h.storeMu.Lock()
+		defer h.storeMu.Unlock()
+		return len(h.store.index)
+	}
+
 	h.mu <- struct{}{}
 	defer func() { <-h.mu }()
-
 	return len(h.hands)
 }
 
@@ -0,0 +1,533 @@
+package game
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ==================== 手牌历史持久化 ====================
+
+// 手牌历史以 JSON Lines 格式追加写入分段文件，每个分段文件旁有一个 .idx 索引文件：
+//
+//	hands-20261018-001.jsonl      每行一手牌（HandHistory 的 JSON）
+//	hands-20261018-001.jsonl.idx  每行 "手牌编号 偏移 长度"
+//
+// 每手牌写入后 fsync，进程崩溃最多丢失正在写入的最后一行；加载时会截掉不完整的尾行，
+// 并根据数据文件补全缺失的索引。
+
+// ==================== 错误定义 ====================
+var (
+	ErrHistoryBroken = errors.New("手牌历史存储已损坏，停止写入")
+)
+
+// HistoryOptions 手牌历史存储选项
+type HistoryOptions struct {
+	MaxFileSize int64 // 单个分段文件的最大字节数，超过后切换到新文件（0 表示不按大小切分）
+	RotateDaily bool  // 是否按日期切分文件
+	CacheSize   int   // 内存中缓存的最近手数（更早的手牌按索引从文件读取）
+}
+
+// DefaultHistoryOptions 返回默认的手牌历史存储选项
+func DefaultHistoryOptions() HistoryOptions {
+	return HistoryOptions{
+		MaxFileSize: 64 << 20,
+		RotateDaily: true,
+		CacheSize:   1000,
+	}
+}
+
+// historyIndexEntry 索引条目：手牌在分段文件中的位置
+type historyIndexEntry struct {
+	HandID  int
+	Segment int   // 分段序号（historyStore.segments 的下标）
+	Offset  int64 // 记录起始偏移
+	Length  int   // 记录长度（含换行符）
+}
+
+// historySegment 一个分段数据文件
+type historySegment struct {
+	path string
+	date string // 日期 YYYYMMDD
+	seq  int    // 同一天内的序号
+	size int64  // 当前文件大小
+}
+
+// historyStore 追加写入的手牌历史存储
+type historyStore struct {
+	prefix   string // 分段文件路径前缀（不含日期和扩展名）
+	opts     HistoryOptions
+	segments []historySegment
+	index    []historyIndexEntry // 按写入顺序排列
+	byID     map[int]int         // 手牌编号 → index 下标
+
+	file    historyFile // 当前分段数据文件
+	idxFile *os.File    // 当前分段索引文件
+	broken  error       // 写入失败且无法截回时的原因（之后拒绝追加，避免索引偏移错位）
+}
+
+// historyFile 分段数据文件需要的操作（测试中可替换以模拟写入失败）
+type historyFile interface {
+	io.WriteCloser
+	Sync() error
+	Truncate(size int64) error
+}
+
+// historyExt 分段数据文件扩展名
+const historyExt = ".jsonl"
+
+// openHistoryStore 打开（或创建）手牌历史存储，加载所有分段的索引
+func openHistoryStore(filename string, opts HistoryOptions) (*historyStore, error) {
+	prefix := strings.TrimSuffix(filename, filepath.Ext(filename))
+	if dir := filepath.Dir(prefix); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	s := &historyStore{
+		prefix: prefix,
+		opts:   opts,
+		byID:   make(map[int]int),
+	}
+
+	segments, err := s.listSegments()
+	if err != nil {
+		return nil, err
+	}
+	for _, seg := range segments {
+		if err := s.loadSegment(seg); err != nil {
+			return nil, fmt.Errorf("load %s: %w", seg.path, err)
+		}
+	}
+
+	return s, nil
+}
+
+// listSegments 查找所有分段文件，按日期和序号排序
+func (s *historyStore) listSegments() ([]historySegment, error) {
+	paths, err := filepath.Glob(s.prefix + "-*" + historyExt)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []historySegment
+	for _, path := range paths {
+		if seg, ok := s.parseSegmentName(path); ok {
+			segments = append(segments, seg)
+		}
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		if segments[i].date != segments[j].date {
+			return segments[i].date < segments[j].date
+		}
+		return segments[i].seq < segments[j].seq
+	})
+	return segments, nil
+}
+
+// parseSegmentName 从文件名解析分段日期和序号
+func (s *historyStore) parseSegmentName(path string) (historySegment, bool) {
+	name := strings.TrimSuffix(strings.TrimPrefix(path, s.prefix+"-"), historyExt)
+	parts := strings.Split(name, "-")
+	if len(parts) != 2 || len(parts[0]) != 8 {
+		return historySegment{}, false
+	}
+	if _, err := time.Parse("20060102", parts[0]); err != nil {
+		return historySegment{}, false
+	}
+	seq, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return historySegment{}, false
+	}
+	return historySegment{path: path, date: parts[0], seq: seq}, true
+}
+
+// segmentPath 生成分段文件路径
+func (s *historyStore) segmentPath(date string, seq int) string {
+	return fmt.Sprintf("%s-%s-%03d%s", s.prefix, date, seq, historyExt)
+}
+
+// loadSegment 加载一个分段：读取索引，补全未索引的记录，截掉不完整的尾行
+func (s *historyStore) loadSegment(seg historySegment) error {
+	info, err := os.Stat(seg.path)
+	if err != nil {
+		return err
+	}
+	seg.size = info.Size()
+	segIdx := len(s.segments)
+
+	// 读取索引中有效的前缀部分
+	entries, indexed, clean := s.readIndex(seg, segIdx)
+
+	// 扫描索引之后的记录（写入数据后、写入索引前崩溃）
+	scanned, end, err := s.scanSegment(seg, segIdx, indexed)
+	if err != nil {
+		return err
+	}
+	if end < seg.size {
+		log.Printf("[历史] 截掉不完整的记录 | 文件=%s | 偏移=%d | 丢弃=%d字节", seg.path, end, seg.size-end)
+		if err := os.Truncate(seg.path, end); err != nil {
+			return err
+		}
+		seg.size = end
+	}
+	if len(scanned) > 0 || !clean {
+		log.Printf("[历史] 重建索引 | 文件=%s | 补充=%d手", seg.path, len(scanned))
+		entries = append(entries, scanned...)
+		if err := s.writeIndex(seg, entries); err != nil {
+			return err
+		}
+	}
+
+	s.segments = append(s.segments, seg)
+	for _, e := range entries {
+		s.byID[e.HandID] = len(s.index)
+		s.index = append(s.index, e)
+	}
+	return nil
+}
+
+// readIndex 读取分段索引，返回有效条目、已索引到的偏移，以及索引文件是否完好
+// 索引损坏或与数据文件不一致时，只保留之前完好的部分
+func (s *historyStore) readIndex(seg historySegment, segIdx int) ([]historyIndexEntry, int64, bool) {
+	data, err := os.ReadFile(seg.path + ".idx")
+	if err != nil {
+		return nil, 0, seg.size == 0
+	}
+
+	var entries []historyIndexEntry
+	var end int64
+	lines := strings.SplitAfter(string(data), "\n")
+	for _, line := range lines {
+		var e historyIndexEntry
+		if !strings.HasSuffix(line, "\n") {
+			break
+		}
+		if _, err := fmt.Sscanf(line, "%d %d %d\n", &e.HandID, &e.Offset, &e.Length); err != nil {
+			break
+		}
+		if e.Offset < end || e.Length <= 0 || e.Offset+int64(e.Length) > seg.size {
+			break
+		}
+		e.Segment = segIdx
+		entries = append(entries, e)
+		end = e.Offset + int64(e.Length)
+	}
+
+	// SplitAfter 在以换行结尾时会多出一个空串
+	clean := len(entries) == len(lines)-1 && lines[len(lines)-1] == ""
+	return entries, end, clean
+}
+
+// scanSegment 从 offset 开始扫描分段中的完整记录，返回新条目和最后一条完整记录的结束位置
+func (s *historyStore) scanSegment(seg historySegment, segIdx int, offset int64) ([]historyIndexEntry, int64, error) {
+	if offset >= seg.size {
+		return nil, offset, nil
+	}
+
+	file, err := os.Open(seg.path)
+	if err != nil {
+		return nil, offset, err
+	}
+	defer file.Close()
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, err
+	}
+
+	var entries []historyIndexEntry
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// 没有换行符结尾的是写入中断的记录
+			return entries, offset, nil
+		}
+
+		var hand struct {
+			HandID int `json:"hand_id"`
+		}
+		if jsonErr := json.Unmarshal(bytes.TrimSpace(line), &hand); jsonErr != nil {
+			log.Printf("[历史] 跳过损坏的记录 | 文件=%s | 偏移=%d | 错误=%v", seg.path, offset, jsonErr)
+		} else {
+			entries = append(entries, historyIndexEntry{
+				HandID:  hand.HandID,
+				Segment: segIdx,
+				Offset:  offset,
+				Length:  len(line),
+			})
+		}
+		offset += int64(len(line))
+	}
+}
+
+// writeIndex 重写分段索引文件
+func (s *historyStore) writeIndex(seg historySegment, entries []historyIndexEntry) error {
+	var b strings.Builder
+	for _, e := range entries {
+		fmt.Fprintf(&b, "%d %d %d\n", e.HandID, e.Offset, e.Length)
+	}
+	return os.WriteFile(seg.path+".idx", []byte(b.String()), 0644)
+}
+
+// append 追加一手牌记录并 fsync，必要时先切换分段
+func (s *historyStore) append(hand *HandHistory) error {
+	if s.broken != nil {
+		return fmt.Errorf("%w: %v", ErrHistoryBroken, s.broken)
+	}
+	data, err := json.Marshal(hand)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if err := s.rotateIfNeeded(hand.Timestamp, int64(len(data))); err != nil {
+		return err
+	}
+
+	segIdx := len(s.segments) - 1
+	seg := &s.segments[segIdx]
+	if _, err := s.file.Write(data); err != nil {
+		return s.rollback(seg, err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return s.rollback(seg, err)
+	}
+
+	entry := historyIndexEntry{HandID: hand.HandID, Segment: segIdx, Offset: seg.size, Length: len(data)}
+	seg.size += int64(len(data))
+	s.byID[entry.HandID] = len(s.index)
+	s.index = append(s.index, entry)
+
+	// 索引可以从数据文件重建，不需要 fsync
+	_, err = fmt.Fprintf(s.idxFile, "%d %d %d\n", entry.HandID, entry.Offset, entry.Length)
+	return err
+}
+
+// rollback 写入或 fsync 失败后把数据文件截回写入前的大小，保证后续记录的偏移正确；
+// 截断也失败时标记存储损坏，之后的追加都返回 ErrHistoryBroken
+func (s *historyStore) rollback(seg *historySegment, cause error) error {
+	if err := s.file.Truncate(seg.size); err != nil {
+		s.broken = fmt.Errorf("写入 %s 失败后无法截回: %v（写入错误: %v）", seg.path, err, cause)
+		log.Printf("[历史] 存储已损坏，停止写入 | 文件=%s | 错误=%v", seg.path, s.broken)
+		return fmt.Errorf("%w: %v", ErrHistoryBroken, s.broken)
+	}
+	return cause
+}
+
+// rotateIfNeeded 当前没有打开的分段、日期变化或文件超过大小限制时切换到新分段
+func (s *historyStore) rotateIfNeeded(t time.Time, size int64) error {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	date := t.Format("20060102")
+
+	if len(s.segments) > 0 {
+		last := s.segments[len(s.segments)-1]
+		sameDay := !s.opts.RotateDaily || last.date == date
+		fits := s.opts.MaxFileSize <= 0 || last.size == 0 || last.size+size <= s.opts.MaxFileSize
+		if sameDay && fits {
+			if s.file == nil {
+				return s.openSegment(len(s.segments) - 1)
+			}
+			return nil
+		}
+	}
+
+	// 新分段：同一天内序号递增
+	seq := 1
+	for _, seg := range s.segments {
+		if seg.date == date && seg.seq >= seq {
+			seq = seg.seq + 1
+		}
+	}
+	if !s.opts.RotateDaily && len(s.segments) > 0 {
+		date = s.segments[len(s.segments)-1].date
+		seq = s.segments[len(s.segments)-1].seq + 1
+	}
+
+	s.closeFiles()
+	s.segments = append(s.segments, historySegment{path: s.segmentPath(date, seq), date: date, seq: seq})
+	log.Printf("[历史] 新建分段 | 文件=%s", s.segments[len(s.segments)-1].path)
+	return s.openSegment(len(s.segments) - 1)
+}
+
+// openSegment 以追加方式打开分段数据文件和索引文件
+func (s *historyStore) openSegment(segIdx int) error {
+	seg := s.segments[segIdx]
+
+	file, err := os.OpenFile(seg.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	idxFile, err := os.OpenFile(seg.path+".idx", os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	s.file = file
+	s.idxFile = idxFile
+	return nil
+}
+
+// read 读取索引条目对应的手牌
+func (s *historyStore) read(entry historyIndexEntry) (HandHistory, error) {
+	var hand HandHistory
+
+	file, err := os.Open(s.segments[entry.Segment].path)
+	if err != nil {
+		return hand, err
+	}
+	defer file.Close()
+
+	data := make([]byte, entry.Length)
+	if _, err := file.ReadAt(data, entry.Offset); err != nil {
+		return hand, err
+	}
+	err = json.Unmarshal(data, &hand)
+	return hand, err
+}
+
+// readRange 按写入顺序读取 index[from:to] 的手牌（跳过读取失败的记录）
+func (s *historyStore) readRange(from, to int) []HandHistory {
+	hands := make([]HandHistory, 0, to-from)
+	files := make(map[int]*os.File)
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+
+	for _, entry := range s.index[from:to] {
+		file, ok := files[entry.Segment]
+		if !ok {
+			var err error
+			if file, err = os.Open(s.segments[entry.Segment].path); err != nil {
+				log.Printf("[历史] 读取失败 | 文件=%s | 错误=%v", s.segments[entry.Segment].path, err)
+				continue
+			}
+			files[entry.Segment] = file
+		}
+
+		data := make([]byte, entry.Length)
+		if _, err := file.ReadAt(data, entry.Offset); err != nil {
+			log.Printf("[历史] 读取失败 | 手牌=#%d | 错误=%v", entry.HandID, err)
+			continue
+		}
+		var hand HandHistory
+		if err := json.Unmarshal(data, &hand); err != nil {
+			log.Printf("[历史] 解析失败 | 手牌=#%d | 错误=%v", entry.HandID, err)
+			continue
+		}
+		hands = append(hands, hand)
+	}
+	return hands
+}
+
+// closeFiles 关闭当前分段的文件
+func (s *historyStore) closeFiles() error {
+	var err error
+	if s.file != nil {
+		err = s.file.Close()
+		s.file = nil
+	}
+	if s.idxFile != nil {
+		if closeErr := s.idxFile.Close(); err == nil {
+			err = closeErr
+		}
+		s.idxFile = nil
+	}
+	return err
+}
+
+// loadLegacyHistory 读取旧版整体保存的 JSON 数组历史文件（不存在或不是数组时返回 nil）
+func loadLegacyHistory(filename string) []HandHistory {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '[' {
+		return nil
+	}
+
+	var hands []HandHistory
+	if err := json.Unmarshal(data, &hands); err != nil {
+		log.Printf("[历史] 旧版历史文件无法解析 | 文件=%s | 错误=%v", filename, err)
+		return nil
+	}
+	return hands
+}
+
+// LoadHistoryFile 只读加载手牌历史，用于导出等离线工具（不会修改任何文件）
+// filename 可以是单个 JSON Lines 分段文件、旧版 JSON 数组文件，或 HistoryManager 使用的路径前缀
+func LoadHistoryFile(filename string) ([]HandHistory, error) {
+	if _, err := os.Stat(filename); err == nil {
+		if legacy := loadLegacyHistory(filename); legacy != nil {
+			return legacy, nil
+		}
+		return readHistoryLines(filename)
+	}
+
+	s := &historyStore{prefix: strings.TrimSuffix(filename, filepath.Ext(filename))}
+	segments, err := s.listSegments()
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("no hand history found at %s", filename)
+	}
+
+	var hands []HandHistory
+	for _, seg := range segments {
+		segHands, err := readHistoryLines(seg.path)
+		if err != nil {
+			return nil, err
+		}
+		hands = append(hands, segHands...)
+	}
+	return hands, nil
+}
+
+// readHistoryLines 读取 JSON Lines 文件中的完整记录（忽略不完整的尾行和损坏的行）
+func readHistoryLines(path string) ([]HandHistory, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var hands []HandHistory
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			if err == io.EOF {
+				return hands, nil
+			}
+			return nil, err
+		}
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var hand HandHistory
+		if err := json.Unmarshal(line, &hand); err != nil {
+			log.Printf("[历史] 跳过损坏的记录 | 文件=%s | 错误=%v", path, err)
+			continue
+		}
+		hands = append(hands, hand)
+	}
+}
@@ -0,0 +1,373 @@
+package game
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+)
+
+// recordTestHand 记录一手简单的牌（Alice 加注，Bob 弃牌）
+func recordTestHand(h *HistoryManager, handID int) {
+	h.StartHand(handID, "test")
+	h.AddPlayer("p1", "Alice", 0, 1000)
+	h.AddPlayer("p2", "Bob", 1, 1000)
+	h.RecordAction("p1", "Alice", StagePreFlop, models.ActionRaise, 60, 60)
+	h.RecordAction("p2", "Bob", StagePreFlop, models.ActionFold, 0, 20)
+	h.EndHand([5]card.Card{}, 80, []WinnerInfo{{PlayerID: "p1", PlayerName: "Alice", Amount: 80}})
+}
+
+// historySegments 返回目录下的分段数据文件
+func historySegments(t *testing.T, dir string) []string {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, "hands-*.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return paths
+}
+
+// ==================== 持久化测试 ====================
+
+func TestHistoryStore_AppendAndReload(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "hands.jsonl")
+
+	h, err := NewHistoryManager(filename)
+	if err != nil {
+		t.Fatalf("NewHistoryManager failed: %v", err)
+	}
+	for id := 1; id <= 5; id++ {
+		recordTestHand(h, id)
+	}
+	h.Close()
+
+	h, err = NewHistoryManager(filename)
+	if err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	defer h.Close()
+
+	if h.GetHandCount() != 5 {
+		t.Fatalf("expected 5 hands after reload, got %d", h.GetHandCount())
+	}
+	if h.NextHandID() != 6 {
+		t.Errorf("expected next hand ID 6, got %d", h.NextHandID())
+	}
+	hand, ok := h.GetHand(3)
+	if !ok || len(hand.Actions) != 2 || hand.Winners[0].Amount != 80 {
+		t.Errorf("unexpected hand #3: %+v", hand)
+	}
+
+	// 重新打开后继续追加
+	recordTestHand(h, 6)
+	if recent := h.GetRecentHands(2); len(recent) != 2 || recent[1].HandID != 6 {
+		t.Errorf("expected hands #5 and #6, got %+v", recent)
+	}
+}
+
+func TestHistoryStore_TruncatedTail(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "hands.jsonl")
+
+	h, _ := NewHistoryManager(filename)
+	recordTestHand(h, 1)
+	recordTestHand(h, 2)
+	h.Close()
+
+	// 模拟写入第三手时崩溃：数据只写了一半，索引没有写
+	segments := historySegments(t, dir)
+	if len(segments) != 1 {
+		t.Fatalf("expected 1 segment, got %d", len(segments))
+	}
+	f, err := os.OpenFile(segments[0], os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"hand_id":3,"timestamp":"2026-`)
+	f.Close()
+
+	h, err = NewHistoryManager(filename)
+	if err != nil {
+		t.Fatalf("reload with truncated tail failed: %v", err)
+	}
+	if h.GetHandCount() != 2 {
+		t.Fatalf("expected 2 intact hands, got %d", h.GetHandCount())
+	}
+
+	// 不完整的尾行被截掉后，新记录可以正常追加和读取
+	recordTestHand(h, 3)
+	h.Close()
+
+	h, _ = NewHistoryManager(filename)
+	defer h.Close()
+	if hand, ok := h.GetHand(3); !ok || hand.HandID != 3 {
+		t.Errorf("expected hand #3 after recovery, got %+v (found=%v)", hand, ok)
+	}
+}
+
+// failingFile 模拟 fsync（以及可选的截断）失败的数据文件
+type failingFile struct {
+	*os.File
+	truncateErr error
+}
+
+func (f *failingFile) Sync() error { return errors.New("sync failed") }
+
+func (f *failingFile) Truncate(size int64) error {
+	if f.truncateErr != nil {
+		return f.truncateErr
+	}
+	return f.File.Truncate(size)
+}
+
+// blockingFile 在 fsync 时阻塞，直到 release 被关闭
+type blockingFile struct {
+	*os.File
+	syncing chan struct{}
+	release chan struct{}
+}
+
+func (f *blockingFile) Sync() error {
+	close(f.syncing)
+	<-f.release
+	return f.File.Sync()
+}
+
+// swapHistoryFile 等排队的手牌写完后替换数据文件，返回原来的文件
+func swapHistoryFile(h *HistoryManager, f historyFile) historyFile {
+	h.flush()
+	h.storeMu.Lock()
+	defer h.storeMu.Unlock()
+	old := h.store.file
+	h.store.file = f
+	return old
+}
+
+func TestHistoryStore_SyncFailure(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "hands.jsonl")
+	h, err := NewHistoryManager(filename)
+	if err != nil {
+		t.Fatalf("NewHistoryManager failed: %v", err)
+	}
+	recordTestHand(h, 1)
+
+	// fsync 失败时记录被截掉，之后的记录偏移仍然正确
+	real := swapHistoryFile(h, nil).(*os.File)
+	h.store.file = &failingFile{File: real}
+	if err := h.store.append(&HandHistory{HandID: 2}); err == nil || errors.Is(err, ErrHistoryBroken) {
+		t.Fatalf("expected the sync error, got %v", err)
+	}
+	h.store.file = real
+	recordTestHand(h, 3)
+	h.Close()
+
+	h, _ = NewHistoryManager(filename)
+	if h.GetHandCount() != 2 {
+		t.Fatalf("expected 2 hands after reload, got %d", h.GetHandCount())
+	}
+	if hand, ok := h.GetHand(3); !ok || hand.HandID != 3 || len(hand.Actions) != 2 {
+		t.Errorf("expected intact hand #3, got %+v (found=%v)", hand, ok)
+	}
+
+	// 截断也失败时存储标记为损坏，拒绝后续追加
+	recordTestHand(h, 4)
+	real = swapHistoryFile(h, nil).(*os.File)
+	h.store.file = &failingFile{File: real, truncateErr: errors.New("truncate failed")}
+	if err := h.store.append(&HandHistory{HandID: 5}); !errors.Is(err, ErrHistoryBroken) {
+		t.Fatalf("expected ErrHistoryBroken, got %v", err)
+	}
+	h.store.file = real
+	if err := h.store.append(&HandHistory{HandID: 6}); !errors.Is(err, ErrHistoryBroken) {
+		t.Errorf("expected appends to be refused after a failed rollback, got %v", err)
+	}
+	h.Close()
+}
+
+func TestHistoryManager_AppendFailure(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "hands.jsonl")
+	h, err := NewHistoryManager(filename)
+	if err != nil {
+		t.Fatalf("NewHistoryManager failed: %v", err)
+	}
+	defer h.Close()
+	recordTestHand(h, h.NextHandID())
+
+	// 第二手保存失败：从缓存中移除，但编号仍然前进，下一手不会重复使用
+	real := swapHistoryFile(h, nil).(*os.File)
+	swapHistoryFile(h, &failingFile{File: real})
+	failed := h.NextHandID()
+	recordTestHand(h, failed)
+	swapHistoryFile(h, real)
+
+	next := h.NextHandID()
+	if next != failed+1 {
+		t.Fatalf("expected next hand ID %d after a failed save, got %d", failed+1, next)
+	}
+	if _, ok := h.GetHand(failed); ok {
+		t.Errorf("expected hand #%d not to be cached after a failed save", failed)
+	}
+	recordTestHand(h, next)
+
+	if h.GetHandCount() != 2 {
+		t.Errorf("expected 2 saved hands, got %d", h.GetHandCount())
+	}
+	if recent := h.GetRecentHands(5); len(recent) != 2 || recent[0].HandID != 1 || recent[1].HandID != next {
+		t.Errorf("expected hands #1 and #%d, got %+v", next, recent)
+	}
+}
+
+func TestHistoryManager_WriteOutsideCaller(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "hands.jsonl")
+	h, err := NewHistoryManager(filename)
+	if err != nil {
+		t.Fatalf("NewHistoryManager failed: %v", err)
+	}
+	defer h.Close()
+	recordTestHand(h, 1)
+
+	// fsync 阻塞时 EndHand 仍然立即返回，手牌已在缓存中
+	real := swapHistoryFile(h, nil).(*os.File)
+	blocking := &blockingFile{File: real, syncing: make(chan struct{}), release: make(chan struct{})}
+	swapHistoryFile(h, blocking)
+
+	recordTestHand(h, 2)
+	<-blocking.syncing
+	if hand, ok := h.GetHand(2); !ok || hand.HandID != 2 {
+		t.Errorf("expected hand #2 to be cached while it is written, got %+v (found=%v)", hand, ok)
+	}
+	if h.NextHandID() != 3 {
+		t.Errorf("expected next hand ID 3, got %d", h.NextHandID())
+	}
+	close(blocking.release)
+
+	if h.GetHandCount() != 2 {
+		t.Errorf("expected 2 saved hands, got %d", h.GetHandCount())
+	}
+}
+
+func TestHistoryStore_RebuildIndex(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "hands.jsonl")
+
+	h, _ := NewHistoryManager(filename)
+	for id := 1; id <= 3; id++ {
+		recordTestHand(h, id)
+	}
+	h.Close()
+
+	// 删除索引后应从数据文件重建
+	for _, path := range historySegments(t, dir) {
+		os.Remove(path + ".idx")
+	}
+
+	opts := DefaultHistoryOptions()
+	opts.CacheSize = 1
+	h, err := NewHistoryManagerWithOptions(filename, opts)
+	if err != nil {
+		t.Fatalf("reload without index failed: %v", err)
+	}
+	defer h.Close()
+
+	// 缓存只有最近一手，更早的手牌按索引从文件读取
+	if hand, ok := h.GetHand(1); !ok || hand.HandID != 1 {
+		t.Errorf("expected hand #1 via index, got %+v (found=%v)", hand, ok)
+	}
+	if all := h.GetAllHands(); len(all) != 3 {
+		t.Errorf("expected 3 hands, got %d", len(all))
+	}
+	if _, err := os.Stat(historySegments(t, dir)[0] + ".idx"); err != nil {
+		t.Errorf("index file not rebuilt: %v", err)
+	}
+}
+
+func TestHistoryStore_RotateBySize(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "hands.jsonl")
+
+	opts := DefaultHistoryOptions()
+	opts.MaxFileSize = 1 // 每手牌一个文件
+	h, _ := NewHistoryManagerWithOptions(filename, opts)
+	for id := 1; id <= 3; id++ {
+		recordTestHand(h, id)
+	}
+	h.Close()
+
+	if segments := historySegments(t, dir); len(segments) != 3 {
+		t.Fatalf("expected 3 segments, got %d", len(segments))
+	}
+
+	h, _ = NewHistoryManagerWithOptions(filename, opts)
+	defer h.Close()
+	recent := h.GetRecentHands(3)
+	for i, hand := range recent {
+		if hand.HandID != i+1 {
+			t.Errorf("expected hand #%d at position %d, got #%d", i+1, i, hand.HandID)
+		}
+	}
+}
+
+func TestHistoryStore_RotateByDate(t *testing.T) {
+	dir := t.TempDir()
+	h, _ := NewHistoryManager(filepath.Join(dir, "hands.jsonl"))
+	defer h.Close()
+
+	h.StartHand(1, "test")
+	h.currentHand.Timestamp = time.Date(2026, 1, 1, 23, 59, 0, 0, time.Local)
+	h.EndHand([5]card.Card{}, 0, nil)
+	h.StartHand(2, "test")
+	h.currentHand.Timestamp = time.Date(2026, 1, 2, 0, 1, 0, 0, time.Local)
+	h.EndHand([5]card.Card{}, 0, nil)
+	h.flush()
+
+	segments := historySegments(t, dir)
+	if len(segments) != 2 {
+		t.Fatalf("expected 2 segments, got %v", segments)
+	}
+	if filepath.Base(segments[0]) != "hands-20260101-001.jsonl" {
+		t.Errorf("unexpected segment name %s", filepath.Base(segments[0]))
+	}
+}
+
+func TestHistoryStore_ImportLegacyFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "hands.json")
+	legacy := `[{"hand_id":1,"timestamp":"2026-01-01T10:00:00Z","pot":40},{"hand_id":2,"timestamp":"2026-01-01T10:05:00Z","pot":60}]`
+	if err := os.WriteFile(filename, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := NewHistoryManager(filename)
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	defer h.Close()
+
+	if h.GetHandCount() != 2 || h.NextHandID() != 3 {
+		t.Errorf("expected 2 imported hands, got %d (next=%d)", h.GetHandCount(), h.NextHandID())
+	}
+	if _, err := os.Stat(filename + ".bak"); err != nil {
+		t.Errorf("legacy file not renamed: %v", err)
+	}
+}
+
+func TestHistoryManager_AddHand(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "hands.jsonl")
+	h, _ := NewHistoryManager(filename)
+	recordTestHand(h, 1)
+
+	// HandID 为 0 时自动编号
+	id, err := h.AddHand(HandHistory{GameID: "imported", Pot: 40})
+	if err != nil || id != 2 {
+		t.Fatalf("expected imported hand #2, got %d (err=%v)", id, err)
+	}
+	h.Close()
+
+	h, _ = NewHistoryManager(filename)
+	defer h.Close()
+	if hand, ok := h.GetHand(2); !ok || hand.GameID != "imported" || hand.Pot != 40 {
+		t.Errorf("imported hand not persisted: %+v (found=%v)", hand, ok)
+	}
+}
@@ -2,6 +2,8 @@ package game
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,16 +26,48 @@ type PlayerStats struct {
 	TotalChecks   int       `json:"total_checks"`   // 看牌次数
 	WinRate       float64   `json:"win_rate"`       // 胜率 (获胜手牌数/参与手牌数)
 	ProfitPerHand float64   `json:"profit_per_hand"` // 每手平均盈利
+	ProfitBB      float64   `json:"profit_bb"`       // 以大盲注计的累计盈亏（用于计算 bb/100）
+	AllInEVNet    float64   `json:"all_in_ev_net"`   // 全下期望调整后的累计盈亏（河牌前全员全下的手牌按期望值计算）
+	AllInHands    int       `json:"all_in_hands"`    // 河牌前全员全下的手数
 	FirstActions  int      `json:"first_actions"`  // 首位行动次数
 	LastActions   int      `json:"last_actions"`  // 最后行动次数
+
+	// HUD 统计（由 RecordHand 根据完整的手牌记录累计）
+	VPIPHands      int `json:"vpip_hands"`        // 翻牌前主动入池的手数
+	PFRHands       int `json:"pfr_hands"`         // 翻牌前加注的手数
+	ThreeBetOpps   int `json:"three_bet_opps"`    // 面对开局加注的手数（3-bet 机会）
+	ThreeBets      int `json:"three_bets"`        // 3-bet 的手数
+	FoldTo3BetOpps int `json:"fold_to_3bet_opps"` // 开局加注后面对 3-bet 的手数
+	FoldTo3Bets    int `json:"fold_to_3bets"`     // 开局加注后面对 3-bet 弃牌的手数
+	CBetOpps       int `json:"cbet_opps"`         // 翻牌前最后加注者在翻牌圈首先获得下注机会的手数
+	CBets          int `json:"cbets"`             // 持续下注的手数
+	PostflopAggr   int `json:"postflop_aggr"`     // 翻牌后下注/加注次数
+	PostflopCalls  int `json:"postflop_calls"`    // 翻牌后跟注次数
+	SawFlop        int `json:"saw_flop"`          // 看到翻牌的手数
+	WentToShowdown int `json:"went_to_showdown"`  // 看到翻牌后进入摊牌的手数
+	WonAtShowdown  int `json:"won_at_showdown"`   // 摊牌获胜的手数
+
+	Positions map[string]*PositionStats `json:"positions,omitempty"` // 按位置（BTN/SB/BB/UTG/…/CO）的统计
+
 	CreatedAt     time.Time `json:"created_at"`    // 统计开始时间
 	UpdatedAt     time.Time `json:"updated_at"`    // 最后更新时间
 }
 
+// PositionStats 玩家在某个位置上的统计
+type PositionStats struct {
+	Hands     int `json:"hands"`      // 手数
+	VPIPHands int `json:"vpip_hands"` // 主动入池的手数
+	PFRHands  int `json:"pfr_hands"`  // 翻牌前加注的手数
+	Profit    int `json:"profit"`     // 净盈亏
+}
+
 // StatsManager 管理所有玩家的统计数据
 type StatsManager struct {
 	mu          sync.RWMutex             // 读写锁
-	playerStats map[string]*PlayerStats   // 玩家ID到统计信息的映射
+	playerStats map[string]*PlayerStats   // 玩家名称到统计信息的映射
+	filename    string                   // 保存文件名（为空时只保存在内存中）
+	dirty       bool                     // 是否有尚未保存到文件的更新
+	saveMu      sync.Mutex               // 串行化文件写入
 }
 
 // NewStatsManager 创建统计管理器
@@ -43,23 +77,30 @@ func NewStatsManager() *StatsManager {
 	}
 }
 
-// GetOrCreateStats 获取或创建玩家统计信息
+// GetOrCreateStats 获取或创建玩家统计信息（按玩家名称，玩家ID记录为最近一次的ID）
 func (s *StatsManager) GetOrCreateStats(playerID, name string) *PlayerStats {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	stats, exists := s.playerStats[playerID]
+	return s.statsLocked(playerID, name)
+}
+
+// statsLocked 按玩家名称获取或创建统计，同一玩家重新连接换了ID后仍接续之前的数据（调用方需持有写锁）
+func (s *StatsManager) statsLocked(playerID, name string) *PlayerStats {
+	stats, exists := s.playerStats[name]
 	if !exists {
 		now := time.Now()
 		stats = &PlayerStats{
-			PlayerID:  playerID,
 			Name:      name,
 			CreatedAt: now,
 			UpdatedAt: now,
 		}
-		s.playerStats[playerID] = stats
+		s.playerStats[name] = stats
+	}
+	s.dirty = true
+	if playerID != "" {
+		stats.PlayerID = playerID
 	}
-
 	return stats
 }
 
@@ -68,17 +109,7 @@ func (s *StatsManager) UpdateHandPlayed(playerID, name string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	stats, exists := s.playerStats[playerID]
-	if !exists {
-		now := time.Now()
-		stats = &PlayerStats{
-			PlayerID:  playerID,
-			Name:      name,
-			CreatedAt: now,
-			UpdatedAt: now,
-		}
-		s.playerStats[playerID] = stats
-	}
+	stats := s.statsLocked(playerID, name)
 
 	stats.HandsPlayed++
 	stats.UpdatedAt = time.Now()
@@ -89,17 +120,7 @@ func (s *StatsManager) UpdateHandWon(playerID, name string, wonChips int) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	stats, exists := s.playerStats[playerID]
-	if !exists {
-		now := time.Now()
-		stats = &PlayerStats{
-			PlayerID:  playerID,
-			Name:      name,
-			CreatedAt: now,
-			UpdatedAt: now,
-		}
-		s.playerStats[playerID] = stats
-	}
+	stats := s.statsLocked(playerID, name)
 
 	stats.HandsWon++
 	stats.TotalWinnings += wonChips
@@ -123,17 +144,7 @@ func (s *StatsManager) UpdateHandLost(playerID, name string, lostChips int) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	stats, exists := s.playerStats[playerID]
-	if !exists {
-		now := time.Now()
-		stats = &PlayerStats{
-			PlayerID:  playerID,
-			Name:      name,
-			CreatedAt: now,
-			UpdatedAt: now,
-		}
-		s.playerStats[playerID] = stats
-	}
+	stats := s.statsLocked(playerID, name)
 
 	stats.TotalLosses += lostChips
 
@@ -150,17 +161,7 @@ func (s *StatsManager) UpdateAction(playerID, name string, action models.ActionT
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	stats, exists := s.playerStats[playerID]
-	if !exists {
-		now := time.Now()
-		stats = &PlayerStats{
-			PlayerID:  playerID,
-			Name:      name,
-			CreatedAt: now,
-			UpdatedAt: now,
-		}
-		s.playerStats[playerID] = stats
-	}
+	stats := s.statsLocked(playerID, name)
 
 	switch action {
 	case models.ActionFold:
@@ -180,17 +181,41 @@ func (s *StatsManager) UpdateAction(playerID, name string, action models.ActionT
 	stats.UpdatedAt = time.Now()
 }
 
-// GetStats 获取玩家统计信息（线程安全）
-func (s *StatsManager) GetStats(playerID string) *PlayerStats {
+// GetStats 按玩家名称获取统计信息（线程安全）
+func (s *StatsManager) GetStats(name string) *PlayerStats {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	if stats, exists := s.playerStats[playerID]; exists {
+	if stats, exists := s.playerStats[name]; exists {
 		return stats
 	}
 	return nil
 }
 
+// Snapshot 返回指定名称的玩家统计的副本（不存在的玩家被跳过），可以在统计继续更新时安全地读取或序列化
+func (s *StatsManager) Snapshot(names ...string) []PlayerStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]PlayerStats, 0, len(names))
+	for _, name := range names {
+		stats, exists := s.playerStats[name]
+		if !exists {
+			continue
+		}
+		c := *stats
+		if stats.Positions != nil {
+			c.Positions = make(map[string]*PositionStats, len(stats.Positions))
+			for pos, ps := range stats.Positions {
+				p := *ps
+				c.Positions[pos] = &p
+			}
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
 // GetAllStats 获取所有玩家的统计信息
 func (s *StatsManager) GetAllStats() []*PlayerStats {
 	s.mu.RLock()
@@ -295,12 +320,13 @@ func (s *StatsManager) GetTopLoser() *PlayerStats {
 	return topLoser
 }
 
-// RemoveStats 删除玩家统计信息
-func (s *StatsManager) RemoveStats(playerID string) {
+// RemoveStats 按玩家名称删除统计信息
+func (s *StatsManager) RemoveStats(name string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	delete(s.playerStats, playerID)
+	delete(s.playerStats, name)
+	s.dirty = true
 }
 
 // Clear 清空所有统计信息
@@ -309,6 +335,7 @@ func (s *StatsManager) Clear() {
 	defer s.mu.Unlock()
 
 	s.playerStats = make(map[string]*PlayerStats)
+	s.dirty = true
 }
 
 // GetTotalHandsPlayed 获取所有玩家参与的手牌总数
@@ -335,6 +362,341 @@ func (s *StatsManager) GetTotalPotSize() int {
 	return total / 2 // 底池是所有下注的一半（两个玩家）
 }
 
+// ==================== HUD 统计 ====================
+
+// RecordHand 根据一手完整的牌局记录累计所有参与玩家的统计（包括 HUD 统计和位置统计）
+// 统计按玩家名称累计，同一玩家重新连接后仍能接续之前的数据；只更新内存，使用统计文件时由调用方定期 Save
+func (s *StatsManager) RecordHand(hand HandHistory) {
+	positions := HandPositions(hand)
+	results := analyzeHand(hand)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, hp := range hand.Players {
+		r := results[hp.ID]
+		stats := s.statsLocked(hp.ID, hp.Name)
+		stats.UpdatedAt = now
+
+		// 基础统计
+		stats.HandsPlayed++
+		if hp.IsWinner {
+			stats.HandsWon++
+			if hp.WonChips > stats.BiggestPot {
+				stats.BiggestPot = hp.WonChips
+			}
+		}
+		profit := hp.FinalChips - hp.StartChips
+		if profit > 0 {
+			stats.TotalWinnings += profit
+		} else {
+			stats.TotalLosses -= profit
+		}
+		if hand.BigBlind > 0 {
+			stats.ProfitBB += float64(profit) / float64(hand.BigBlind)
+		}
+		stats.AllInEVNet += handAllInEV(&hand, &hp)
+		if hand.AllInStage != StageWaiting && wentToShowdown(&hand, hp.ID) {
+			stats.AllInHands++
+		}
+		stats.TotalBets += r.bets
+		stats.TotalCalls += r.calls
+		stats.TotalRaises += r.raises
+		stats.TotalFolds += r.folds
+		stats.TotalChecks += r.checks
+		stats.WinRate = float64(stats.HandsWon) / float64(stats.HandsPlayed)
+		stats.ProfitPerHand = float64(stats.TotalWinnings-stats.TotalLosses) / float64(stats.HandsPlayed)
+
+		// HUD 统计
+		stats.VPIPHands += boolCount(r.vpip)
+		stats.PFRHands += boolCount(r.pfr)
+		stats.ThreeBetOpps += boolCount(r.threeBetOpp)
+		stats.ThreeBets += boolCount(r.threeBet)
+		stats.FoldTo3BetOpps += boolCount(r.foldTo3BetOpp)
+		stats.FoldTo3Bets += boolCount(r.foldTo3Bet)
+		stats.CBetOpps += boolCount(r.cbetOpp)
+		stats.CBets += boolCount(r.cbet)
+		stats.PostflopAggr += r.postflopAggr
+		stats.PostflopCalls += r.postflopCalls
+		stats.SawFlop += boolCount(r.sawFlop)
+		stats.WentToShowdown += boolCount(r.showdown)
+		stats.WonAtShowdown += boolCount(r.showdown && hp.WonChips > 0)
+
+		// 位置统计
+		if pos := positions[hp.ID]; pos != "" {
+			if stats.Positions == nil {
+				stats.Positions = make(map[string]*PositionStats)
+			}
+			ps := stats.Positions[pos]
+			if ps == nil {
+				ps = &PositionStats{}
+				stats.Positions[pos] = ps
+			}
+			ps.Hands++
+			ps.VPIPHands += boolCount(r.vpip)
+			ps.PFRHands += boolCount(r.pfr)
+			ps.Profit += profit
+		}
+	}
+}
+
+// handStats 单个玩家在一手牌中的行动汇总
+type handStats struct {
+	vpip, pfr                 bool
+	threeBetOpp, threeBet     bool
+	foldTo3BetOpp, foldTo3Bet bool
+	cbetOpp, cbet             bool
+	sawFlop, showdown         bool
+
+	bets                         int // 投入的筹码（不含盲注和前注）
+	calls, raises, folds, checks int
+	postflopAggr, postflopCalls  int
+}
+
+// analyzeHand 按行动顺序回放一手牌，得到每位玩家的行动汇总
+// 全下超过当前最高下注时视为加注，否则视为跟注
+func analyzeHand(hand HandHistory) map[string]*handStats {
+	results := make(map[string]*handStats, len(hand.Players))
+	maxBet := 0
+	for _, p := range hand.Players {
+		results[p.ID] = &handStats{}
+		if p.PostedBlind > maxBet {
+			maxBet = p.PostedBlind
+		}
+	}
+	if maxBet == 0 {
+		maxBet = hand.BigBlind
+	}
+
+	level := 1 // 翻牌前的下注层级：盲注为 1，开局加注为 2，3-bet 为 3
+	opener, aggressor := "", ""
+	foldedPreflop := make(map[string]bool)
+	stage := StagePreFlop
+	streetAggr := false
+
+	for _, a := range hand.Actions {
+		r := results[a.PlayerID]
+		if r == nil {
+			continue
+		}
+		if a.Round != stage {
+			stage = a.Round
+			maxBet = 0
+			streetAggr = false
+		}
+
+		aggressive := a.Action == models.ActionRaise || (a.Action == models.ActionAllIn && a.BetTo > maxBet)
+		passive := a.Action == models.ActionCall || (a.Action == models.ActionAllIn && !aggressive)
+		switch {
+		case aggressive:
+			r.raises++
+		case passive:
+			r.calls++
+		case a.Action == models.ActionFold:
+			r.folds++
+		case a.Action == models.ActionCheck:
+			r.checks++
+		}
+		r.bets += a.Amount
+
+		if stage == StagePreFlop {
+			if level == 2 && a.PlayerID != opener && !r.threeBetOpp {
+				r.threeBetOpp = true
+				r.threeBet = aggressive
+			}
+			if level == 3 && a.PlayerID == opener && !r.foldTo3BetOpp {
+				r.foldTo3BetOpp = true
+				r.foldTo3Bet = a.Action == models.ActionFold
+			}
+			if aggressive || passive {
+				r.vpip = true
+			}
+			if aggressive {
+				r.pfr = true
+				level++
+				if level == 2 {
+					opener = a.PlayerID
+				}
+				aggressor = a.PlayerID
+			}
+			if a.Action == models.ActionFold {
+				foldedPreflop[a.PlayerID] = true
+			}
+		} else {
+			if aggressive {
+				r.postflopAggr++
+			} else if passive {
+				r.postflopCalls++
+			}
+			// 翻牌前最后加注者在翻牌圈无人下注时获得持续下注机会
+			if stage == StageFlop && a.PlayerID == aggressor && !streetAggr && !r.cbetOpp {
+				r.cbetOpp = true
+				r.cbet = aggressive
+			}
+		}
+
+		if aggressive {
+			maxBet = a.BetTo
+			streetAggr = true
+		}
+	}
+
+	// 发出翻牌时未在翻牌前弃牌的玩家看到了翻牌，摊牌只统计看到翻牌的玩家
+	if hand.CommunityCards[0].Rank != 0 {
+		for _, p := range hand.Players {
+			results[p.ID].sawFlop = !foldedPreflop[p.ID]
+		}
+		for _, sd := range hand.Showdown {
+			if r := results[sd.PlayerID]; r != nil && r.sawFlop {
+				r.showdown = true
+			}
+		}
+	}
+	return results
+}
+
+// boolCount 将布尔值转换为计数
+func boolCount(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// HandPositions 根据座位顺序和庄家座位计算每位玩家的位置（玩家ID到 BTN、SB、BB、UTG…CO 的映射）
+func HandPositions(hand HandHistory) map[string]string {
+	players := append([]HistoryPlayer(nil), hand.Players...)
+	sort.Slice(players, func(i, j int) bool { return players[i].Seat < players[j].Seat })
+
+	positions := make(map[string]string, len(players))
+	if len(players) == 0 {
+		return positions
+	}
+
+	// 庄家离开时按之后的第一个座位计算
+	button := 0
+	for i, p := range players {
+		if p.Seat >= hand.DealerSeat {
+			button = i
+			break
+		}
+	}
+
+	names := positionNames(len(players))
+	for i := range players {
+		positions[players[(button+i)%len(players)].ID] = names[i]
+	}
+	return positions
+}
+
+// positionNames 返回从庄家开始按座位顺序的位置名称（单挑时庄家即小盲）
+func positionNames(n int) []string {
+	switch n {
+	case 1:
+		return []string{"BTN"}
+	case 2:
+		return []string{"BTN", "BB"}
+	}
+
+	names := []string{"BTN", "SB", "BB"}
+	late := []string{"MP", "HJ", "CO"}
+	rest := n - len(names)
+	if rest <= len(late) {
+		return append(names, late[len(late)-rest:]...)
+	}
+	names = append(names, "UTG")
+	for i := 1; i < rest-len(late); i++ {
+		names = append(names, fmt.Sprintf("UTG+%d", i))
+	}
+	return append(names, late...)
+}
+
+// positionRank 位置的显示顺序（按翻牌前行动顺序）
+func positionRank(pos string) int {
+	switch pos {
+	case "UTG":
+		return 0
+	case "MP":
+		return 10
+	case "HJ":
+		return 11
+	case "CO":
+		return 12
+	case "BTN":
+		return 13
+	case "SB":
+		return 14
+	case "BB":
+		return 15
+	}
+	var n int
+	fmt.Sscanf(pos, "UTG+%d", &n)
+	return n
+}
+
+// SortedPositions 按翻牌前行动顺序返回有统计的位置
+func (p *PlayerStats) SortedPositions() []string {
+	positions := make([]string, 0, len(p.Positions))
+	for pos := range p.Positions {
+		positions = append(positions, pos)
+	}
+	sort.Slice(positions, func(i, j int) bool { return positionRank(positions[i]) < positionRank(positions[j]) })
+	return positions
+}
+
+// ratio 计算比例（分母为 0 时返回 0）
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+// VPIP 翻牌前主动入池率
+func (p *PlayerStats) VPIP() float64 { return ratio(p.VPIPHands, p.HandsPlayed) }
+
+// PFR 翻牌前加注率
+func (p *PlayerStats) PFR() float64 { return ratio(p.PFRHands, p.HandsPlayed) }
+
+// ThreeBet 面对开局加注时再加注的比例
+func (p *PlayerStats) ThreeBet() float64 { return ratio(p.ThreeBets, p.ThreeBetOpps) }
+
+// FoldToThreeBet 开局加注后面对 3-bet 弃牌的比例
+func (p *PlayerStats) FoldToThreeBet() float64 { return ratio(p.FoldTo3Bets, p.FoldTo3BetOpps) }
+
+// CBet 翻牌圈持续下注的比例
+func (p *PlayerStats) CBet() float64 { return ratio(p.CBets, p.CBetOpps) }
+
+// AggressionFactor 翻牌后攻击因子：(下注+加注)/跟注，没有跟注时返回下注和加注次数
+func (p *PlayerStats) AggressionFactor() float64 {
+	if p.PostflopCalls == 0 {
+		return float64(p.PostflopAggr)
+	}
+	return ratio(p.PostflopAggr, p.PostflopCalls)
+}
+
+// WTSD 看到翻牌后进入摊牌的比例
+func (p *PlayerStats) WTSD() float64 { return ratio(p.WentToShowdown, p.SawFlop) }
+
+// WSD 摊牌获胜的比例（W$SD）
+func (p *PlayerStats) WSD() float64 { return ratio(p.WonAtShowdown, p.WentToShowdown) }
+
+// BB100 每百手赢得的大盲注数
+func (p *PlayerStats) BB100() float64 {
+	if p.HandsPlayed == 0 {
+		return 0
+	}
+	return p.ProfitBB / float64(p.HandsPlayed) * 100
+}
+
+// HUDSummary 生成单行 HUD 统计
+func (p *PlayerStats) HUDSummary() string {
+	return fmt.Sprintf("VPIP %.0f / PFR %.0f / 3B %.0f / F3B %.0f / CB %.0f / AF %.1f / WTSD %.0f / W$SD %.0f (%d手)",
+		p.VPIP()*100, p.PFR()*100, p.ThreeBet()*100, p.FoldToThreeBet()*100, p.CBet()*100,
+		p.AggressionFactor(), p.WTSD()*100, p.WSD()*100, p.HandsPlayed)
+}
+
 // Report 生成玩家统计报告
 func (p *PlayerStats) Report() string {
 	profit := p.TotalWinnings - p.TotalLosses
@@ -347,6 +709,7 @@ func (p *PlayerStats) Report() string {
 参与手牌: %d
 获胜手牌: %d (%.1f%%)
 总盈利: %s%d
+全下EV盈亏: %+.0f (全下%d手，运气%+.0f)
 每手平均: %s%.2f
 最大底池: %d
 总下注: %d
@@ -354,12 +717,14 @@ func (p *PlayerStats) Report() string {
 加注次数: %d
 弃牌次数: %d
 看牌次数: %d
-统计时间: %s - %s`,
+HUD: %s
+%s统计时间: %s - %s`,
 		p.Name,
 		p.HandsPlayed,
 		p.HandsWon,
 		p.WinRate*100,
 		profitSign, profit,
+		p.AllInEVNet, p.AllInHands, float64(profit)-p.AllInEVNet,
 		profitSign, p.ProfitPerHand,
 		p.BiggestPot,
 		p.TotalBets,
@@ -367,10 +732,23 @@ func (p *PlayerStats) Report() string {
 		p.TotalRaises,
 		p.TotalFolds,
 		p.TotalChecks,
+		p.HUDSummary(),
+		p.positionReport(),
 		p.CreatedAt.Format("2006-01-02"),
 		p.UpdatedAt.Format("2006-01-02 15:04"))
 }
 
+// positionReport 生成按位置的统计（每个位置一行）
+func (p *PlayerStats) positionReport() string {
+	var b strings.Builder
+	for _, pos := range p.SortedPositions() {
+		ps := p.Positions[pos]
+		fmt.Fprintf(&b, "  %-6s %4d手 VPIP %3.0f%% PFR %3.0f%% 盈亏 %+d\n",
+			pos, ps.Hands, ratio(ps.VPIPHands, ps.Hands)*100, ratio(ps.PFRHands, ps.Hands)*100, ps.Profit)
+	}
+	return b.String()
+}
+
 // ShortReport 生成简短的玩家统计报告
 func (p *PlayerStats) ShortReport() string {
 	profit := p.TotalWinnings - p.TotalLosses
@@ -0,0 +1,84 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ==================== 统计持久化 ====================
+
+// NewStatsManagerWithFile 创建保存到文件的统计管理器（加载已有统计，文件不存在时从空白开始）
+func NewStatsManagerWithFile(filename string) (*StatsManager, error) {
+	s := NewStatsManager()
+	s.filename = filename
+
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.playerStats); err != nil {
+		return nil, fmt.Errorf("解析统计文件 %s 失败: %w", filename, err)
+	}
+	if s.playerStats == nil {
+		s.playerStats = make(map[string]*PlayerStats)
+	}
+	return s, nil
+}
+
+// Save 把有更新的统计保存到文件（没有未保存的更新或只保存在内存中时不做任何事）
+// 只在复制数据时持有统计锁，写文件期间不阻塞 RecordHand（它在引擎锁内调用）
+func (s *StatsManager) Save() error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	s.mu.Lock()
+	if s.filename == "" || !s.dirty {
+		s.mu.Unlock()
+		return nil
+	}
+	data, err := json.MarshalIndent(s.playerStats, "", "  ")
+	s.dirty = false
+	s.mu.Unlock()
+	if err == nil {
+		err = writeFileAtomic(s.filename, data)
+	}
+	if err != nil {
+		// 保留未保存标记，下次再试
+		s.mu.Lock()
+		s.dirty = true
+		s.mu.Unlock()
+	}
+	return err
+}
+
+// writeFileAtomic 先写临时文件再重命名，写入中断不会损坏已有的文件
+func writeFileAtomic(filename string, data []byte) error {
+	dir := filepath.Dir(filename)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,328 @@
+package game
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+)
+
+// hudTestHand 四人桌：CO 开局加注，BTN 3-bet，CO 跟注；
+// 翻牌 BTN 持续下注 CO 跟注，转牌过牌，河牌 CO 下注 BTN 跟注，CO 摊牌获胜
+func hudTestHand(ids [4]string) HandHistory {
+	btn, sb, bb, co := ids[0], ids[1], ids[2], ids[3]
+	act := func(id string, round Stage, action models.ActionType, amount, betTo int) HistoryAction {
+		return HistoryAction{PlayerID: id, Round: round, Action: action, Amount: amount, BetTo: betTo}
+	}
+	return HandHistory{
+		HandID:     1,
+		SmallBlind: 10,
+		BigBlind:   20,
+		DealerSeat: 0,
+		Players: []HistoryPlayer{
+			{ID: btn, Name: "Alice", Seat: 0, StartChips: 1000, FinalChips: 320},
+			{ID: sb, Name: "Bob", Seat: 1, StartChips: 1000, PostedBlind: 10, FinalChips: 990},
+			{ID: bb, Name: "Carol", Seat: 2, StartChips: 1000, PostedBlind: 20, FinalChips: 980},
+			{ID: co, Name: "Dave", Seat: 3, StartChips: 1000, FinalChips: 1710, WonChips: 1390, IsWinner: true},
+		},
+		CommunityCards: [5]card.Card{
+			card.NewCard(card.Hearts, card.Two), card.NewCard(card.Clubs, card.Seven), card.NewCard(card.Spades, card.King),
+			card.NewCard(card.Diamonds, card.Nine), card.NewCard(card.Hearts, card.Four),
+		},
+		Actions: []HistoryAction{
+			act(co, StagePreFlop, models.ActionRaise, 60, 60),
+			act(btn, StagePreFlop, models.ActionRaise, 180, 180),
+			act(sb, StagePreFlop, models.ActionFold, 0, 10),
+			act(bb, StagePreFlop, models.ActionFold, 0, 20),
+			act(co, StagePreFlop, models.ActionCall, 120, 180),
+			act(co, StageFlop, models.ActionCheck, 0, 0),
+			act(btn, StageFlop, models.ActionRaise, 200, 200),
+			act(co, StageFlop, models.ActionCall, 200, 200),
+			act(co, StageTurn, models.ActionCheck, 0, 0),
+			act(btn, StageTurn, models.ActionCheck, 0, 0),
+			act(co, StageRiver, models.ActionRaise, 300, 300),
+			act(btn, StageRiver, models.ActionCall, 300, 300),
+		},
+		Showdown: []ShowdownInfo{{PlayerID: btn, PlayerName: "Alice"}, {PlayerID: co, PlayerName: "Dave"}},
+		Pot:      1390,
+		Winners:  []WinnerInfo{{PlayerID: co, PlayerName: "Dave", Amount: 1390}},
+	}
+}
+
+func TestStatsManager_RecordHand(t *testing.T) {
+	s := NewStatsManager()
+	s.RecordHand(hudTestHand([4]string{"p1", "p2", "p3", "p4"}))
+
+	btn := s.GetStats("Alice")
+	if btn == nil {
+		t.Fatal("expected stats keyed by player name")
+	}
+	checks := []struct {
+		name      string
+		got, want int
+	}{
+		{"BTN vpip", btn.VPIPHands, 1},
+		{"BTN pfr", btn.PFRHands, 1},
+		{"BTN 3-bet opps", btn.ThreeBetOpps, 1},
+		{"BTN 3-bets", btn.ThreeBets, 1},
+		{"BTN c-bet opps", btn.CBetOpps, 1},
+		{"BTN c-bets", btn.CBets, 1},
+		{"BTN postflop aggr", btn.PostflopAggr, 1},
+		{"BTN postflop calls", btn.PostflopCalls, 1},
+		{"BTN wtsd", btn.WentToShowdown, 1},
+		{"BTN won at showdown", btn.WonAtShowdown, 0},
+		{"BTN losses", btn.TotalLosses, 680},
+		{"BTN raises", btn.TotalRaises, 2},
+		{"BTN checks", btn.TotalChecks, 1},
+	}
+	co := s.GetStats("Dave")
+	checks = append(checks, []struct {
+		name      string
+		got, want int
+	}{
+		{"CO 3-bet opps", co.ThreeBetOpps, 0},
+		{"CO fold to 3-bet opps", co.FoldTo3BetOpps, 1},
+		{"CO fold to 3-bets", co.FoldTo3Bets, 0},
+		{"CO c-bet opps", co.CBetOpps, 0},
+		{"CO won at showdown", co.WonAtShowdown, 1},
+		{"CO winnings", co.TotalWinnings, 710},
+		{"CO bets", co.TotalBets, 680},
+	}...)
+	sb := s.GetStats("Bob")
+	checks = append(checks, []struct {
+		name      string
+		got, want int
+	}{
+		{"SB vpip", sb.VPIPHands, 0},
+		{"SB 3-bet opps", sb.ThreeBetOpps, 0},
+		{"SB saw flop", sb.SawFlop, 0},
+		{"SB folds", sb.TotalFolds, 1},
+	}...)
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s: expected %d, got %d", c.name, c.want, c.got)
+		}
+	}
+
+	if btn.AggressionFactor() != 1 || btn.WSD() != 0 || co.WSD() != 1 || btn.VPIP() != 1 {
+		t.Errorf("unexpected derived stats: %s", btn.HUDSummary())
+	}
+	if math.Abs(co.ProfitPerHand-710) > 1e-9 || co.WinRate != 1 {
+		t.Errorf("unexpected CO results: profit/hand %.2f, win rate %.2f", co.ProfitPerHand, co.WinRate)
+	}
+	if math.Abs(co.BB100()-3550) > 1e-9 || math.Abs(btn.BB100()+3400) > 1e-9 {
+		t.Errorf("unexpected bb/100: CO %.1f, BTN %.1f", co.BB100(), btn.BB100())
+	}
+
+	// 位置统计
+	for name, pos := range map[string]string{"Alice": "BTN", "Bob": "SB", "Carol": "BB", "Dave": "CO"} {
+		ps := s.GetStats(name).Positions[pos]
+		if ps == nil || ps.Hands != 1 {
+			t.Errorf("%s: expected one hand at %s, got %+v", name, pos, s.GetStats(name).Positions)
+		}
+	}
+	if p := s.GetStats("Dave").Positions["CO"]; p.VPIPHands != 1 || p.PFRHands != 1 || p.Profit != 710 {
+		t.Errorf("unexpected CO position stats %+v", p)
+	}
+}
+
+func TestStatsManager_AllInCountsAsRaiseOnlyAboveBet(t *testing.T) {
+	hand := HandHistory{
+		BigBlind:   20,
+		DealerSeat: 0,
+		Players: []HistoryPlayer{
+			{ID: "p1", Name: "Alice", Seat: 0, StartChips: 50, PostedBlind: 10},
+			{ID: "p2", Name: "Bob", Seat: 1, StartChips: 15, PostedBlind: 15},
+		},
+		Actions: []HistoryAction{
+			// 大盲只够投入 15，小盲全下到 50 超过当前下注，视为加注
+			{PlayerID: "p1", Round: StagePreFlop, Action: models.ActionAllIn, Amount: 40, BetTo: 50},
+		},
+	}
+	s := NewStatsManager()
+	s.RecordHand(hand)
+	if st := s.GetStats("Alice"); st.PFRHands != 1 || st.TotalRaises != 1 {
+		t.Errorf("all-in above the bet should count as a raise, got %+v", st)
+	}
+
+	// 面对加注到 100，大盲剩余筹码不足，全下视为跟注
+	hand.Actions = []HistoryAction{
+		{PlayerID: "p1", Round: StagePreFlop, Action: models.ActionRaise, Amount: 90, BetTo: 100},
+		{PlayerID: "p2", Round: StagePreFlop, Action: models.ActionAllIn, Amount: 0, BetTo: 15},
+	}
+	s.RecordHand(hand)
+	if st := s.GetStats("Bob"); st.VPIPHands != 1 || st.PFRHands != 0 || st.TotalCalls != 1 || st.ThreeBetOpps != 1 || st.ThreeBets != 0 {
+		t.Errorf("all-in below the bet should count as a call, got %+v", st)
+	}
+}
+
+func TestHandPositions(t *testing.T) {
+	tests := []struct {
+		seats  int
+		dealer int
+		want   []string // 按座位 0..n-1
+	}{
+		{2, 1, []string{"BB", "BTN"}},
+		{3, 0, []string{"BTN", "SB", "BB"}},
+		{6, 2, []string{"HJ", "CO", "BTN", "SB", "BB", "MP"}},
+		{9, 0, []string{"BTN", "SB", "BB", "UTG", "UTG+1", "UTG+2", "MP", "HJ", "CO"}},
+	}
+	for _, tt := range tests {
+		hand := HandHistory{DealerSeat: tt.dealer}
+		for i := tt.seats - 1; i >= 0; i-- {
+			hand.Players = append(hand.Players, HistoryPlayer{ID: string(rune('a' + i)), Seat: i})
+		}
+		positions := HandPositions(hand)
+		for i, want := range tt.want {
+			if got := positions[string(rune('a'+i))]; got != want {
+				t.Errorf("%d players, dealer %d, seat %d: expected %s, got %s", tt.seats, tt.dealer, i, want, got)
+			}
+		}
+	}
+
+	// 庄家离座时按之后的第一个座位计算
+	hand := HandHistory{DealerSeat: 1, Players: []HistoryPlayer{{ID: "a", Seat: 0}, {ID: "c", Seat: 2}, {ID: "d", Seat: 3}}}
+	if got := HandPositions(hand)["c"]; got != "BTN" {
+		t.Errorf("expected seat 2 to take the button, got %s", got)
+	}
+}
+
+func TestStatsManager_Persistence(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "data", "stats.json")
+
+	s, err := NewStatsManagerWithFile(filename)
+	if err != nil {
+		t.Fatalf("NewStatsManagerWithFile failed: %v", err)
+	}
+	s.RecordHand(hudTestHand([4]string{"p1", "p2", "p3", "p4"}))
+
+	// RecordHand 只更新内存，Save 时才写文件
+	if _, err := os.Stat(filename); !os.IsNotExist(err) {
+		t.Fatalf("expected no stats file before Save, got %v", err)
+	}
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// 重新加载后继续累计，玩家ID变化不影响按名称累计
+	reloaded, err := NewStatsManagerWithFile(filename)
+	if err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if st := reloaded.GetStats("Alice"); st == nil || st.HandsPlayed != 1 || st.ThreeBets != 1 || st.Positions["BTN"].Hands != 1 {
+		t.Fatalf("stats not restored: %+v", st)
+	}
+	reloaded.RecordHand(hudTestHand([4]string{"x1", "x2", "x3", "x4"}))
+	if err := reloaded.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// 没有新的更新时 Save 不重写文件
+	os.Remove(filename)
+	if err := reloaded.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := os.Stat(filename); !os.IsNotExist(err) {
+		t.Fatalf("expected Save to skip an unchanged manager, got %v", err)
+	}
+	reloaded.GetOrCreateStats("x9", "Erin")
+	reloaded.RemoveStats("Erin")
+	if err := reloaded.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	again, _ := NewStatsManagerWithFile(filename)
+	st := again.GetStats("Alice")
+	if st.HandsPlayed != 2 || st.PlayerID != "x1" || st.CBets != 2 {
+		t.Errorf("expected two hands for Alice, got %+v", st)
+	}
+	if len(again.GetAllStats()) != 4 {
+		t.Errorf("expected 4 players, got %d", len(again.GetAllStats()))
+	}
+}
+
+func TestEngine_RecordsStats(t *testing.T) {
+	engine := NewEngine(&Config{MinPlayers: 2, MaxPlayers: 6, SmallBlind: 10, BigBlind: 20, StartingChips: 1000})
+	history, _ := NewHistoryManager("")
+	stats := NewStatsManager()
+	engine.SetHistory(history)
+	engine.SetStats(stats)
+	var ended []int
+	engine.SetOnHandEnd(func(hand HandHistory) { ended = append(ended, hand.HandID) })
+	engine.AddPlayer("p1", "Alice", 0)
+	engine.AddPlayer("p2", "Bob", 1)
+	engine.AddPlayer("p3", "Carol", 2)
+	if err := engine.StartHand(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 枪口位加注，其余玩家弃牌
+	state := engine.GetState()
+	opener := state.Players[state.CurrentPlayer]
+	if err := engine.PlayerAction(opener.ID, models.ActionRaise, 60); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		state = engine.GetState()
+		if err := engine.PlayerAction(state.Players[state.CurrentPlayer].ID, models.ActionFold, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(ended) != 1 || ended[0] != 1 {
+		t.Errorf("expected hand end callback for hand #1, got %v", ended)
+	}
+	if stats.GetTotalHandsPlayed() != 3 {
+		t.Fatalf("expected 3 player-hands, got %d", stats.GetTotalHandsPlayed())
+	}
+	st := stats.GetStats(opener.Name)
+	if st.PFRHands != 1 || st.HandsWon != 1 || st.SawFlop != 0 {
+		t.Errorf("unexpected opener stats %+v", st)
+	}
+}
+
+func TestStatsManager_Snapshot(t *testing.T) {
+	s := NewStatsManager()
+	s.RecordHand(hudTestHand([4]string{"p1", "p2", "p3", "p4"}))
+
+	snap := s.Snapshot("Alice", "Nobody", "Dave")
+	if len(snap) != 2 || snap[0].Name != "Alice" || snap[1].Name != "Dave" {
+		t.Fatalf("unexpected snapshot %+v", snap)
+	}
+
+	// 副本不受后续统计影响
+	s.RecordHand(hudTestHand([4]string{"p1", "p2", "p3", "p4"}))
+	if snap[0].HandsPlayed != 1 || snap[0].Positions["BTN"].Hands != 1 {
+		t.Errorf("snapshot changed after RecordHand: %+v", snap[0])
+	}
+}
+
+func TestStatsManager_KeyedByName(t *testing.T) {
+	// 逐项更新和 RecordHand 累计到同一份按名称保存的统计，重新连接换了ID也接续
+	s := NewStatsManager()
+	s.UpdateHandPlayed("old-id", "Alice")
+	s.UpdateAction("old-id", "Alice", models.ActionCall, 20)
+	s.RecordHand(hudTestHand([4]string{"p1", "p2", "p3", "p4"}))
+
+	if len(s.GetAllStats()) != 4 {
+		t.Fatalf("expected 4 players, got %d", len(s.GetAllStats()))
+	}
+	st := s.GetStats("Alice")
+	if st == nil || st.HandsPlayed != 2 || st.TotalCalls != 2 || st.PlayerID != "p1" {
+		t.Errorf("expected merged stats with the latest ID, got %+v", st)
+	}
+	if s.GetStats("p1") != nil {
+		t.Error("expected no stats keyed by player ID")
+	}
+	if got := s.GetOrCreateStats("p9", "Alice"); got != st {
+		t.Error("expected GetOrCreateStats to return the existing stats")
+	}
+
+	s.RemoveStats("Alice")
+	if s.GetStats("Alice") != nil {
+		t.Error("expected stats removed by name")
+	}
+}
@@ -0,0 +1,790 @@
+package handhistory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/evaluator"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+)
+
+// ==================== Open Hand History (OHH) ====================
+
+// OHHSpecVersion 导出时使用的 OHH 规范版本
+const OHHSpecVersion = "1.4.7"
+
+// OHH 街道名称
+const (
+	OHHStreetPreflop  = "Preflop"
+	OHHStreetFlop     = "Flop"
+	OHHStreetTurn     = "Turn"
+	OHHStreetRiver    = "River"
+	OHHStreetShowdown = "Showdown"
+)
+
+// OHH 动作类型
+const (
+	OHHActionDealtCards = "Dealt Cards"
+	OHHActionMucksCards = "Mucks Cards"
+	OHHActionShowsCards = "Shows Cards"
+	OHHActionPostAnte   = "Post Ante"
+	OHHActionPostSB     = "Post SB"
+	OHHActionPostBB     = "Post BB"
+	OHHActionPostDead   = "Post Dead"
+	OHHActionFold       = "Fold"
+	OHHActionCheck      = "Check"
+	OHHActionBet        = "Bet"
+	OHHActionRaise      = "Raise"
+	OHHActionCall       = "Call"
+)
+
+// OHHFile OHH 文件中的一手牌（顶层对象只有一个 "ohh" 字段）
+type OHHFile struct {
+	OHH OHHHand `json:"ohh"`
+}
+
+// OHHHand 一手牌
+type OHHHand struct {
+	SpecVersion      string      `json:"spec_version"`
+	SiteName         string      `json:"site_name"`
+	NetworkName      string      `json:"network_name"`
+	InternalVersion  string      `json:"internal_version"`
+	Tournament       bool        `json:"tournament"`
+	GameNumber       string      `json:"game_number"`
+	StartDateUTC     time.Time   `json:"start_date_utc"`
+	TableName        string      `json:"table_name"`
+	TableHandle      string      `json:"table_handle,omitempty"`
+	GameType         string      `json:"game_type"`
+	BetLimit         OHHBetLimit `json:"bet_limit"`
+	TableSize        int         `json:"table_size"`
+	Currency         string      `json:"currency"`
+	DealerSeat       int         `json:"dealer_seat"`
+	SmallBlindAmount float64     `json:"small_blind_amount"`
+	BigBlindAmount   float64     `json:"big_blind_amount"`
+	AnteAmount       float64     `json:"ante_amount"`
+	HeroPlayerID     int         `json:"hero_player_id,omitempty"`
+	Players          []OHHPlayer `json:"players"`
+	Rounds           []OHHRound  `json:"rounds"`
+	Pots             []OHHPot    `json:"pots"`
+}
+
+// OHHBetLimit 下注限制
+type OHHBetLimit struct {
+	BetType string  `json:"bet_type"` // NL / PL / FL
+	BetCap  float64 `json:"bet_cap"`
+}
+
+// OHHPlayer 玩家
+type OHHPlayer struct {
+	ID            int     `json:"id"`
+	Seat          int     `json:"seat"` // 从 1 开始
+	Name          string  `json:"name"`
+	Display       string  `json:"display,omitempty"`
+	StartingStack float64 `json:"starting_stack"`
+	IsSittingOut  bool    `json:"is_sitting_out,omitempty"`
+}
+
+// OHHRound 一条街
+type OHHRound struct {
+	ID      int         `json:"id"`
+	Street  string      `json:"street"`
+	Cards   []string    `json:"cards,omitempty"` // 本街新发的公共牌
+	Actions []OHHAction `json:"actions"`
+}
+
+// OHHAction 动作（amount 为本次动作投入的筹码）
+type OHHAction struct {
+	ActionNumber int      `json:"action_number"`
+	PlayerID     int      `json:"player_id"`
+	Action       string   `json:"action"`
+	Amount       float64  `json:"amount,omitempty"`
+	IsAllIn      bool     `json:"is_allin,omitempty"`
+	Cards        []string `json:"cards,omitempty"`
+}
+
+// OHHPot 底池及其赢家
+type OHHPot struct {
+	Number     int            `json:"number"` // 0 为主池
+	Amount     float64        `json:"amount"`
+	Rake       float64        `json:"rake"`
+	PlayerWins []OHHPlayerWin `json:"player_wins"`
+}
+
+// OHHPlayerWin 玩家从某个底池赢得的筹码
+type OHHPlayerWin struct {
+	PlayerID  int     `json:"player_id"`
+	WinAmount float64 `json:"win_amount"`
+}
+
+// OHHOptions OHH 导出选项
+type OHHOptions struct {
+	Hero      string // 主视角玩家（名称或ID）
+	SiteName  string // 站点名称（默认 "Texas-Holdem"）
+	TableName string // 桌名（默认 "Texas Holdem"）
+	TableSize int    // 最大座位数（默认 9）
+	Currency  string // 货币（默认 "CHIPS"）
+}
+
+// ==================== 导出 ====================
+
+// ToOHH 将一手牌转换为 OHH 格式
+func ToOHH(hand game.HandHistory, opts OHHOptions) OHHHand {
+	if opts.SiteName == "" {
+		opts.SiteName = "Texas-Holdem"
+	}
+	if opts.TableName == "" {
+		opts.TableName = "Texas Holdem"
+	}
+	if opts.TableSize <= 0 {
+		opts.TableSize = 9
+	}
+	if opts.Currency == "" {
+		opts.Currency = "CHIPS"
+	}
+
+	players := sortedPlayers(hand.Players)
+	ids := make(map[string]int, len(players))
+	out := OHHHand{
+		SpecVersion:      OHHSpecVersion,
+		SiteName:         opts.SiteName,
+		NetworkName:      opts.SiteName,
+		InternalVersion:  "1",
+		GameNumber:       strconv.Itoa(hand.HandID),
+		StartDateUTC:     hand.Timestamp.UTC(),
+		TableName:        opts.TableName,
+		GameType:         "Holdem",
+		BetLimit:         OHHBetLimit{BetType: "NL"},
+		TableSize:        opts.TableSize,
+		Currency:         opts.Currency,
+		DealerSeat:       hand.DealerSeat + 1,
+		SmallBlindAmount: float64(hand.SmallBlind),
+		BigBlindAmount:   float64(hand.BigBlind),
+		AnteAmount:       float64(hand.Ante),
+	}
+
+	for i, p := range players {
+		id := i + 1
+		ids[p.ID] = id
+		out.Players = append(out.Players, OHHPlayer{
+			ID:            id,
+			Seat:          p.Seat + 1,
+			Name:          p.Name,
+			Display:       p.Name,
+			StartingStack: float64(p.StartChips),
+		})
+		if opts.Hero != "" && (p.Name == opts.Hero || p.ID == opts.Hero) {
+			out.HeroPlayerID = id
+		}
+	}
+
+	// 翻牌前：前注、盲注、发牌
+	actionNo := 0
+	next := func() int {
+		actionNo++
+		return actionNo
+	}
+	stacks := make(map[string]int, len(players))
+	for _, p := range players {
+		stacks[p.ID] = p.StartChips
+	}
+	post := func(p game.HistoryPlayer, action string, amount int) OHHAction {
+		stacks[p.ID] -= amount
+		return OHHAction{ActionNumber: next(), PlayerID: ids[p.ID], Action: action, Amount: float64(amount), IsAllIn: stacks[p.ID] <= 0}
+	}
+
+	preflop := OHHRound{ID: 0, Street: OHHStreetPreflop}
+	for _, p := range players {
+		if p.PostedAnte > 0 {
+			preflop.Actions = append(preflop.Actions, post(p, OHHActionPostAnte, p.PostedAnte))
+		}
+	}
+	for _, p := range blindOrder(players, hand.DealerSeat, hand.BigBlind) {
+		action := OHHActionPostBB
+		if p.role == "sb" {
+			action = OHHActionPostSB
+		}
+		preflop.Actions = append(preflop.Actions, post(p.HistoryPlayer, action, p.PostedBlind))
+	}
+	for _, p := range players {
+		if ids[p.ID] == out.HeroPlayerID && p.HoleCards[0].Rank != 0 {
+			preflop.Actions = append(preflop.Actions, OHHAction{
+				ActionNumber: next(), PlayerID: ids[p.ID], Action: OHHActionDealtCards, Cards: cardStrings(p.HoleCards[:]),
+			})
+		}
+	}
+
+	// 各街行动
+	board := boardCards(hand.CommunityCards)
+	rounds := []OHHRound{preflop}
+	streetBet := make(map[string]int, len(players))
+	for _, p := range players {
+		streetBet[p.ID] = p.PostedBlind
+	}
+	maxBet := hand.BigBlind
+	for _, p := range players {
+		maxBet = max(maxBet, p.PostedBlind)
+	}
+
+	current := game.StagePreFlop
+	for _, a := range hand.Actions {
+		if _, ok := ids[a.PlayerID]; !ok {
+			continue
+		}
+		for current < a.Round && current < game.StageRiver {
+			current++
+			rounds = append(rounds, newOHHRound(len(rounds), current, board))
+			streetBet = make(map[string]int, len(players))
+			maxBet = 0
+		}
+
+		stacks[a.PlayerID] -= a.Amount
+		streetBet[a.PlayerID] += a.Amount
+		action := OHHAction{
+			ActionNumber: next(),
+			PlayerID:     ids[a.PlayerID],
+			Amount:       float64(a.Amount),
+			IsAllIn:      a.Amount > 0 && stacks[a.PlayerID] <= 0,
+		}
+		switch a.Action {
+		case models.ActionFold:
+			action.Action = OHHActionFold
+		case models.ActionCheck:
+			action.Action = OHHActionCheck
+		default:
+			switch {
+			case streetBet[a.PlayerID] <= maxBet:
+				action.Action = OHHActionCall
+			case maxBet == 0:
+				action.Action = OHHActionBet
+			default:
+				action.Action = OHHActionRaise
+			}
+			maxBet = max(maxBet, streetBet[a.PlayerID])
+		}
+		rounds[len(rounds)-1].Actions = append(rounds[len(rounds)-1].Actions, action)
+	}
+
+	// 全下后直接发完的公共牌
+	for current < game.StageRiver && len(board) >= streetCardCount(current+1) {
+		current++
+		rounds = append(rounds, newOHHRound(len(rounds), current, board))
+	}
+
+	// 摊牌亮牌
+	shown := make(map[string]bool)
+	for _, sd := range hand.Showdown {
+		shown[sd.PlayerID] = true
+	}
+	if len(shown) > 0 {
+		showdown := OHHRound{ID: len(rounds), Street: OHHStreetShowdown}
+		for _, p := range players {
+			if shown[p.ID] && p.HoleCards[0].Rank != 0 {
+				showdown.Actions = append(showdown.Actions, OHHAction{
+					ActionNumber: next(), PlayerID: ids[p.ID], Action: OHHActionShowsCards, Cards: cardStrings(p.HoleCards[:]),
+				})
+			}
+		}
+		rounds = append(rounds, showdown)
+	}
+	out.Rounds = rounds
+
+	// 底池
+	for i, pot := range buildPots(hand) {
+		op := OHHPot{Number: i, Amount: float64(pot.amount)}
+		for _, w := range pot.wins {
+			op.PlayerWins = append(op.PlayerWins, OHHPlayerWin{PlayerID: ids[w.playerID], WinAmount: float64(w.amount)})
+		}
+		out.Pots = append(out.Pots, op)
+	}
+
+	return out
+}
+
+// WriteOHH 将多手牌以 OHH 格式写入 w（每手一个 JSON 对象，之间空一行）
+func WriteOHH(w io.Writer, hands []game.HandHistory, opts OHHOptions) error {
+	for _, hand := range hands {
+		data, err := json.Marshal(OHHFile{OHH: ToOHH(hand, opts)})
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(append(data, '\n', '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newOHHRound 创建一条街，附带本街新发的公共牌
+func newOHHRound(id int, street game.Stage, board []card.Card) OHHRound {
+	round := OHHRound{ID: id, Street: ohhStreet(street)}
+	from, to := streetCardCount(street-1), streetCardCount(street)
+	if street == game.StageFlop {
+		from = 0
+	}
+	if len(board) >= to {
+		round.Cards = cardStrings(board[from:to])
+	}
+	return round
+}
+
+// ohhStreet 返回阶段对应的 OHH 街道名称
+func ohhStreet(stage game.Stage) string {
+	switch stage {
+	case game.StageFlop:
+		return OHHStreetFlop
+	case game.StageTurn:
+		return OHHStreetTurn
+	case game.StageRiver:
+		return OHHStreetRiver
+	case game.StageShowdown:
+		return OHHStreetShowdown
+	}
+	return OHHStreetPreflop
+}
+
+// ==================== 导入 ====================
+
+// ReadOHH 读取 OHH 文件中的所有手牌（各手之间可以有任意空白）
+func ReadOHH(r io.Reader) ([]game.HandHistory, error) {
+	decoder := json.NewDecoder(r)
+	var hands []game.HandHistory
+	for {
+		var file OHHFile
+		if err := decoder.Decode(&file); err != nil {
+			if errors.Is(err, io.EOF) {
+				return hands, nil
+			}
+			return hands, err
+		}
+		hand, err := FromOHH(file.OHH)
+		if err != nil {
+			return hands, fmt.Errorf("hand %s: %w", file.OHH.GameNumber, err)
+		}
+		hands = append(hands, hand)
+	}
+}
+
+// FromOHH 将 OHH 手牌转换为 game.HandHistory
+// 摊牌亮出的牌会用 Evaluator 重新评估，填充牌型和最佳五张牌
+func FromOHH(oh OHHHand) (game.HandHistory, error) {
+	if oh.GameType != "" && oh.GameType != "Holdem" {
+		return game.HandHistory{}, fmt.Errorf("unsupported game type %q", oh.GameType)
+	}
+
+	hand := game.HandHistory{
+		GameID:     oh.GameNumber,
+		Timestamp:  oh.StartDateUTC,
+		EndTime:    oh.StartDateUTC,
+		SmallBlind: int(oh.SmallBlindAmount),
+		BigBlind:   int(oh.BigBlindAmount),
+		Ante:       int(oh.AnteAmount),
+		DealerSeat: oh.DealerSeat - 1,
+	}
+	if id, err := strconv.Atoi(oh.GameNumber); err == nil {
+		hand.HandID = id
+	}
+
+	index := make(map[int]int, len(oh.Players)) // OHH 玩家ID → Players 下标
+	for _, p := range oh.Players {
+		if p.IsSittingOut {
+			continue
+		}
+		index[p.ID] = len(hand.Players)
+		hand.Players = append(hand.Players, game.HistoryPlayer{
+			ID:         strconv.Itoa(p.ID),
+			Name:       p.Name,
+			Seat:       p.Seat - 1,
+			StartChips: int(p.StartingStack),
+		})
+	}
+	player := func(id int) (*game.HistoryPlayer, error) {
+		i, ok := index[id]
+		if !ok {
+			return nil, fmt.Errorf("unknown player id %d", id)
+		}
+		return &hand.Players[i], nil
+	}
+
+	var board []card.Card
+	invested := make(map[int]int)
+	for _, round := range oh.Rounds {
+		stage := stageFromOHH(round.Street)
+		cards, err := parseCards(round.Cards)
+		if err != nil {
+			return hand, err
+		}
+		board = append(board, cards...)
+
+		streetBet := make(map[int]int)
+		for _, a := range round.Actions {
+			p, err := player(a.PlayerID)
+			if err != nil {
+				return hand, err
+			}
+			amount := int(a.Amount)
+
+			switch a.Action {
+			case OHHActionPostAnte:
+				p.PostedAnte += amount
+				invested[a.PlayerID] += amount
+			case OHHActionPostSB, OHHActionPostBB, OHHActionPostDead:
+				p.PostedBlind += amount
+				invested[a.PlayerID] += amount
+				streetBet[a.PlayerID] += amount
+			case OHHActionDealtCards, OHHActionShowsCards, OHHActionMucksCards:
+				cards, err := parseCards(a.Cards)
+				if err != nil {
+					return hand, err
+				}
+				if len(cards) == 2 {
+					p.HoleCards = [2]card.Card{cards[0], cards[1]}
+				}
+				if a.Action == OHHActionShowsCards {
+					hand.Showdown = append(hand.Showdown, game.ShowdownInfo{PlayerID: p.ID, PlayerName: p.Name})
+				}
+			case OHHActionFold, OHHActionCheck, OHHActionCall, OHHActionBet, OHHActionRaise:
+				invested[a.PlayerID] += amount
+				streetBet[a.PlayerID] += amount
+				hand.Actions = append(hand.Actions, game.HistoryAction{
+					PlayerID:   p.ID,
+					PlayerName: p.Name,
+					Round:      stage,
+					Action:     actionFromOHH(a),
+					Amount:     amount,
+					BetTo:      streetBet[a.PlayerID],
+					Timestamp:  hand.Timestamp,
+				})
+			}
+		}
+	}
+	if len(board) > 5 {
+		return hand, fmt.Errorf("too many board cards: %d", len(board))
+	}
+	copy(hand.CommunityCards[:], board)
+
+	// 用 Evaluator 评估亮出的牌
+	eval := evaluator.NewEvaluator()
+	for i, sd := range hand.Showdown {
+		p := findPlayer(hand.Players, sd.PlayerID)
+		if p == nil || p.HoleCards[0].Rank == 0 || len(board) < 5 {
+			continue
+		}
+		result, err := evaluator.EvaluateHand(eval, p.HoleCards, hand.CommunityCards)
+		if err != nil {
+			return hand, fmt.Errorf("evaluate showdown for player %s: %w", sd.PlayerID, err)
+		}
+		hand.Showdown[i].HandRank = result.Rank
+		hand.Showdown[i].HandName = result.Rank.String()
+		hand.Showdown[i].BestCards = result.RawCards
+	}
+
+	// 底池与赢家
+	won := make(map[int]int)
+	for _, pot := range oh.Pots {
+		hand.Pot += int(pot.Amount)
+		for _, w := range pot.PlayerWins {
+			won[w.PlayerID] += int(w.WinAmount)
+		}
+	}
+	for id, amount := range won {
+		p, err := player(id)
+		if err != nil {
+			return hand, err
+		}
+		p.WonChips = amount
+		p.IsWinner = amount > 0
+	}
+	for id, i := range index {
+		p := &hand.Players[i]
+		// 未被跟注的下注退回给自己：投入多于其他人最大投入的部分
+		p.FinalChips = p.StartChips - invested[id] + p.WonChips + uncalledAmount(invested, id)
+	}
+	for _, p := range sortedPlayers(hand.Players) {
+		if p.IsWinner {
+			hand.Winners = append(hand.Winners, game.WinnerInfo{PlayerID: p.ID, PlayerName: p.Name, Amount: p.WonChips})
+		}
+	}
+
+	return hand, nil
+}
+
+// stageFromOHH 将 OHH 街道名称转换为游戏阶段
+func stageFromOHH(street string) game.Stage {
+	switch street {
+	case OHHStreetFlop:
+		return game.StageFlop
+	case OHHStreetTurn:
+		return game.StageTurn
+	case OHHStreetRiver:
+		return game.StageRiver
+	case OHHStreetShowdown:
+		return game.StageShowdown
+	}
+	return game.StagePreFlop
+}
+
+// actionFromOHH 将 OHH 动作转换为玩家动作类型（全下的下注/加注/跟注记为 ActionAllIn）
+func actionFromOHH(a OHHAction) models.ActionType {
+	switch a.Action {
+	case OHHActionFold:
+		return models.ActionFold
+	case OHHActionCheck:
+		return models.ActionCheck
+	}
+	if a.IsAllIn {
+		return models.ActionAllIn
+	}
+	if a.Action == OHHActionCall {
+		return models.ActionCall
+	}
+	return models.ActionRaise
+}
+
+// uncalledAmount 返回玩家投入中没有被任何人跟到的部分
+func uncalledAmount(invested map[int]int, id int) int {
+	second := 0
+	for other, amount := range invested {
+		if other != id && amount > second {
+			second = amount
+		}
+	}
+	return max(invested[id]-second, 0)
+}
+
+// ==================== 底池计算 ====================
+
+// potWin 某位玩家从底池赢得的筹码
+type potWin struct {
+	playerID string
+	amount   int
+}
+
+// handPot 按全下层级划分的底池
+type handPot struct {
+	amount   int
+	eligible []string // 有资格争夺该底池的玩家（未弃牌）
+	wins     []potWin
+}
+
+// buildPots 根据每位玩家的投入划分主池和边池，并确定每个底池的赢家
+// 只有一人投入的层级是未被跟注的下注，会退回给该玩家，不计入底池
+func buildPots(hand game.HandHistory) []handPot {
+	players := sortedPlayers(hand.Players)
+	invested := make(map[string]int, len(players))
+	folded := make(map[string]bool)
+	for _, p := range players {
+		invested[p.ID] = p.PostedAnte + p.PostedBlind
+	}
+	for _, a := range hand.Actions {
+		invested[a.PlayerID] += a.Amount
+		if a.Action == models.ActionFold {
+			folded[a.PlayerID] = true
+		}
+	}
+
+	// 所有不同的投入层级
+	var levels []int
+	seen := make(map[int]bool)
+	for _, amount := range invested {
+		if amount > 0 && !seen[amount] {
+			seen[amount] = true
+			levels = append(levels, amount)
+		}
+	}
+	sort.Ints(levels)
+
+	var pots []handPot
+	prev := 0
+	for _, level := range levels {
+		pot := handPot{}
+		contributors := 0
+		for _, p := range players {
+			c := invested[p.ID]
+			if c <= prev {
+				continue
+			}
+			pot.amount += min(c, level) - prev
+			contributors++
+			if !folded[p.ID] {
+				pot.eligible = append(pot.eligible, p.ID)
+			}
+		}
+		prev = level
+		if contributors <= 1 || len(pot.eligible) == 0 {
+			continue
+		}
+
+		// 有资格的玩家相同（中间层级只是弃牌玩家的投入）时合并
+		if n := len(pots); n > 0 && sameIDs(pots[n-1].eligible, pot.eligible) {
+			pots[n-1].amount += pot.amount
+			continue
+		}
+		pots = append(pots, pot)
+	}
+
+	winners := potWinners(hand)
+	for i := range pots {
+		pots[i].wins = splitPot(pots[i], winners(pots[i].eligible))
+	}
+	return pots
+}
+
+// potWinners 返回一个函数：给定有资格的玩家，返回赢得该底池的玩家
+// 所有人的底牌和公共牌都已知时用 Evaluator 比较，否则按记录的获胜者判断
+func potWinners(hand game.HandHistory) func(eligible []string) []string {
+	eval := evaluator.NewEvaluator()
+	board := boardCards(hand.CommunityCards)
+
+	return func(eligible []string) []string {
+		if len(eligible) == 1 {
+			return eligible
+		}
+
+		var best []string
+		var bestEval evaluator.HandEvaluation
+		evaluable := len(board) == 5
+		for _, id := range eligible {
+			p := findPlayer(hand.Players, id)
+			if p == nil || p.HoleCards[0].Rank == 0 {
+				evaluable = false
+				break
+			}
+			result, err := evaluator.EvaluateHand(eval, p.HoleCards, hand.CommunityCards)
+			if err != nil {
+				evaluable = false
+				break
+			}
+			switch {
+			case best == nil || eval.IsBetter(result, bestEval):
+				best, bestEval = []string{id}, result
+			case eval.IsTie(result, bestEval):
+				best = append(best, id)
+			}
+		}
+		if evaluable {
+			return best
+		}
+
+		var recorded []string
+		for _, id := range eligible {
+			if p := findPlayer(hand.Players, id); p != nil && p.IsWinner {
+				recorded = append(recorded, id)
+			}
+		}
+		if len(recorded) == 0 {
+			return eligible
+		}
+		return recorded
+	}
+}
+
+// splitPot 将底池平分给赢家，零头给座位靠前的赢家
+func splitPot(pot handPot, winners []string) []potWin {
+	if len(winners) == 0 {
+		return nil
+	}
+	share := pot.amount / len(winners)
+	remainder := pot.amount % len(winners)
+
+	wins := make([]potWin, len(winners))
+	for i, id := range winners {
+		wins[i] = potWin{playerID: id, amount: share}
+		if i < remainder {
+			wins[i].amount++
+		}
+	}
+	return wins
+}
+
+// ==================== 工具函数 ====================
+
+// sortedPlayers 返回按座位排序的玩家副本
+func sortedPlayers(players []game.HistoryPlayer) []game.HistoryPlayer {
+	sorted := append([]game.HistoryPlayer(nil), players...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Seat < sorted[j].Seat })
+	return sorted
+}
+
+// blindPlayer 付了盲注的玩家及其角色（"sb" / "bb"）
+type blindPlayer struct {
+	game.HistoryPlayer
+	role string
+}
+
+// blindOrder 按小盲、大盲的顺序返回付了盲注的玩家（单挑时庄家是小盲）
+func blindOrder(players []game.HistoryPlayer, dealerSeat, bigBlind int) []blindPlayer {
+	start := 0
+	for i, p := range players {
+		if p.Seat > dealerSeat {
+			start = i
+			break
+		}
+	}
+	order := append(append([]game.HistoryPlayer{}, players[start:]...), players[:start]...)
+	if len(order) == 2 {
+		order[0], order[1] = order[1], order[0]
+	}
+
+	var posted []blindPlayer
+	for _, p := range order {
+		if p.PostedBlind > 0 {
+			posted = append(posted, blindPlayer{HistoryPlayer: p, role: "bb"})
+		}
+	}
+	if len(posted) > 1 || (len(posted) == 1 && posted[0].PostedBlind < bigBlind) {
+		posted[0].role = "sb"
+	}
+	return posted
+}
+
+// findPlayer 按ID查找玩家
+func findPlayer(players []game.HistoryPlayer, id string) *game.HistoryPlayer {
+	for i := range players {
+		if players[i].ID == id {
+			return &players[i]
+		}
+	}
+	return nil
+}
+
+// sameIDs 判断两个ID列表是否相同
+func sameIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// cardStrings 将牌转换为 "Ah" 形式的字符串列表
+func cardStrings(cards []card.Card) []string {
+	out := make([]string, len(cards))
+	for i, c := range cards {
+		out[i] = c.ShortString()
+	}
+	return out
+}
+
+// parseCards 解析 "Ah" 形式的字符串列表
+func parseCards(list []string) ([]card.Card, error) {
+	cards := make([]card.Card, 0, len(list))
+	for _, s := range list {
+		c, err := card.ParseCard(s)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, c)
+	}
+	return cards, nil
+}
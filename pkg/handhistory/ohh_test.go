@@ -0,0 +1,211 @@
+package handhistory
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/evaluator"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+)
+
+// sidePotHand 三人翻牌前全下：Alice 100 全下，Bob 300 全下，Carol 跟注，Alice 赢主池，Bob 赢边池
+func sidePotHand(t *testing.T) game.HandHistory {
+	hole := func(s string) [2]card.Card {
+		c := mustCards(t, s)
+		return [2]card.Card{c[0], c[1]}
+	}
+	var board [5]card.Card
+	copy(board[:], mustCards(t, "2c 7d 9h Js 3c"))
+
+	return game.HandHistory{
+		HandID:     9,
+		Timestamp:  time.Date(2026, 3, 1, 21, 0, 0, 0, time.UTC),
+		SmallBlind: 10,
+		BigBlind:   20,
+		DealerSeat: 0,
+		Players: []game.HistoryPlayer{
+			{ID: "p1", Name: "Alice", Seat: 0, HoleCards: hole("Ah Ad"), StartChips: 100, FinalChips: 300, WonChips: 300, IsWinner: true},
+			{ID: "p2", Name: "Bob", Seat: 1, HoleCards: hole("Kh Kd"), StartChips: 300, PostedBlind: 10, FinalChips: 400, WonChips: 400, IsWinner: true},
+			{ID: "p3", Name: "Carol", Seat: 2, HoleCards: hole("Qh Qd"), StartChips: 500, PostedBlind: 20, FinalChips: 200},
+		},
+		CommunityCards: board,
+		Actions: []game.HistoryAction{
+			{PlayerID: "p1", PlayerName: "Alice", Round: game.StagePreFlop, Action: models.ActionAllIn, Amount: 100, BetTo: 100},
+			{PlayerID: "p2", PlayerName: "Bob", Round: game.StagePreFlop, Action: models.ActionAllIn, Amount: 290, BetTo: 300},
+			{PlayerID: "p3", PlayerName: "Carol", Round: game.StagePreFlop, Action: models.ActionCall, Amount: 280, BetTo: 300},
+		},
+		Showdown: []game.ShowdownInfo{
+			{PlayerID: "p1", PlayerName: "Alice", HandRank: evaluator.RankOnePair},
+			{PlayerID: "p2", PlayerName: "Bob", HandRank: evaluator.RankOnePair},
+			{PlayerID: "p3", PlayerName: "Carol", HandRank: evaluator.RankOnePair},
+		},
+		Pot: 700,
+		Winners: []game.WinnerInfo{
+			{PlayerID: "p1", PlayerName: "Alice", Amount: 300},
+			{PlayerID: "p2", PlayerName: "Bob", Amount: 400},
+		},
+	}
+}
+
+func TestToOHH_Showdown(t *testing.T) {
+	oh := ToOHH(showdownHand(t), OHHOptions{Hero: "Bob"})
+
+	if oh.GameNumber != "42" || oh.DealerSeat != 1 || oh.HeroPlayerID != 2 {
+		t.Errorf("unexpected header: game=%s dealer=%d hero=%d", oh.GameNumber, oh.DealerSeat, oh.HeroPlayerID)
+	}
+	if len(oh.Rounds) != 5 {
+		t.Fatalf("expected 5 rounds, got %d", len(oh.Rounds))
+	}
+
+	var actions []string
+	for _, a := range oh.Rounds[0].Actions {
+		actions = append(actions, a.Action)
+	}
+	want := "Post SB,Post BB,Dealt Cards,Raise,Call,Fold"
+	if got := strings.Join(actions, ","); got != want {
+		t.Errorf("preflop actions = %s, want %s", got, want)
+	}
+
+	flop := oh.Rounds[1]
+	if flop.Street != OHHStreetFlop || strings.Join(flop.Cards, " ") != "Ah 7d 2c" {
+		t.Errorf("unexpected flop: %+v", flop)
+	}
+	if flop.Actions[0].Action != OHHActionBet || flop.Actions[1].Action != OHHActionCall {
+		t.Errorf("flop actions should be bet/call, got %+v", flop.Actions)
+	}
+	if oh.Rounds[3].Cards[0] != "9h" || oh.Rounds[4].Street != OHHStreetShowdown {
+		t.Errorf("unexpected river/showdown rounds: %+v", oh.Rounds[3:])
+	}
+
+	if len(oh.Pots) != 1 || oh.Pots[0].Amount != 300 || oh.Pots[0].PlayerWins[0].PlayerID != 1 {
+		t.Errorf("unexpected pots: %+v", oh.Pots)
+	}
+}
+
+func TestToOHH_SidePots(t *testing.T) {
+	oh := ToOHH(sidePotHand(t), OHHOptions{})
+
+	if len(oh.Pots) != 2 {
+		t.Fatalf("expected main pot and side pot, got %+v", oh.Pots)
+	}
+	main, side := oh.Pots[0], oh.Pots[1]
+	if main.Amount != 300 || len(main.PlayerWins) != 1 || main.PlayerWins[0].PlayerID != 1 {
+		t.Errorf("main pot should go to Alice: %+v", main)
+	}
+	if side.Amount != 400 || len(side.PlayerWins) != 1 || side.PlayerWins[0].PlayerID != 2 {
+		t.Errorf("side pot should go to Bob: %+v", side)
+	}
+
+	preflop := oh.Rounds[0].Actions
+	last := preflop[len(preflop)-1]
+	if last.Action != OHHActionCall || last.Amount != 280 || last.IsAllIn {
+		t.Errorf("Carol should call 280 without all-in, got %+v", last)
+	}
+	if !preflop[len(preflop)-3].IsAllIn || preflop[len(preflop)-2].Action != OHHActionRaise {
+		t.Errorf("unexpected all-in actions: %+v", preflop)
+	}
+	// 全下后直接发完公共牌
+	if len(oh.Rounds) != 5 || strings.Join(oh.Rounds[3].Cards, " ") != "3c" {
+		t.Errorf("expected remaining streets to be dealt, got %+v", oh.Rounds)
+	}
+}
+
+func TestOHH_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	hands := []game.HandHistory{showdownHand(t), sidePotHand(t)}
+	if err := WriteOHH(&buf, hands, OHHOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	imported, err := ReadOHH(&buf)
+	if err != nil {
+		t.Fatalf("ReadOHH failed: %v", err)
+	}
+	if len(imported) != 2 {
+		t.Fatalf("expected 2 hands, got %d", len(imported))
+	}
+
+	for i, hand := range imported {
+		orig := hands[i]
+		if hand.HandID != orig.HandID || hand.Pot != orig.Pot || hand.CommunityCards != orig.CommunityCards {
+			t.Errorf("hand %d: header mismatch: id=%d pot=%d board=%v", i, hand.HandID, hand.Pot, hand.CommunityCards)
+		}
+		if len(hand.Actions) != len(orig.Actions) {
+			t.Fatalf("hand %d: expected %d actions, got %d", i, len(orig.Actions), len(hand.Actions))
+		}
+		for j, a := range hand.Actions {
+			o := orig.Actions[j]
+			if a.Round != o.Round || a.Amount != o.Amount || (a.Action != models.ActionFold && a.BetTo != o.BetTo) {
+				t.Errorf("hand %d action %d: got %+v, want %+v", i, j, a, o)
+			}
+		}
+		for j, p := range hand.Players {
+			o := orig.Players[j]
+			if p.Seat != o.Seat || p.StartChips != o.StartChips || p.FinalChips != o.FinalChips || p.WonChips != o.WonChips {
+				t.Errorf("hand %d player %s: got %+v, want %+v", i, o.Name, p, o)
+			}
+		}
+		for j, s := range hand.Showdown {
+			if s.HandRank != orig.Showdown[j].HandRank || s.HandName == "" {
+				t.Errorf("hand %d showdown %d: got %+v", i, j, s)
+			}
+		}
+	}
+
+	// 全下动作导入为 ActionAllIn
+	if a := imported[1].Actions[1]; a.Action != models.ActionAllIn {
+		t.Errorf("expected all-in, got %v", a.Action)
+	}
+}
+
+func TestReadOHH_External(t *testing.T) {
+	// 其他站点导出的单手牌（浮点金额、缩进格式）
+	data := `{"ohh": {
+		"spec_version": "1.4.7", "site_name": "Other", "game_number": "abc-1",
+		"start_date_utc": "2026-01-01T12:00:00Z", "game_type": "Holdem",
+		"bet_limit": {"bet_type": "NL"}, "table_size": 6, "dealer_seat": 2,
+		"small_blind_amount": 1, "big_blind_amount": 2, "ante_amount": 0,
+		"players": [
+			{"id": 7, "seat": 2, "name": "X", "starting_stack": 200},
+			{"id": 8, "seat": 5, "name": "Y", "starting_stack": 200}
+		],
+		"rounds": [{"id": 0, "street": "Preflop", "actions": [
+			{"action_number": 1, "player_id": 7, "action": "Post SB", "amount": 1},
+			{"action_number": 2, "player_id": 8, "action": "Post BB", "amount": 2},
+			{"action_number": 3, "player_id": 7, "action": "Raise", "amount": 5},
+			{"action_number": 4, "player_id": 8, "action": "Fold"}
+		]}],
+		"pots": [{"number": 0, "amount": 4, "rake": 0, "player_wins": [{"player_id": 7, "win_amount": 4}]}]
+	}}`
+
+	hands, err := ReadOHH(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadOHH failed: %v", err)
+	}
+	hand := hands[0]
+	if hand.HandID != 0 || hand.GameID != "abc-1" || hand.DealerSeat != 1 {
+		t.Errorf("unexpected header: %+v", hand)
+	}
+	x := hand.Players[0]
+	if x.Seat != 1 || x.PostedBlind != 1 || x.FinalChips != 202 || !x.IsWinner {
+		t.Errorf("unexpected player X: %+v", x)
+	}
+	if a := hand.Actions[0]; a.Action != models.ActionRaise || a.BetTo != 6 {
+		t.Errorf("unexpected raise: %+v", a)
+	}
+
+	// 导出结果是合法的 JSON
+	var buf bytes.Buffer
+	if err := WriteOHH(&buf, hands, OHHOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	var file OHHFile
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &file); err != nil {
+		t.Errorf("exported OHH is not valid JSON: %v", err)
+	}
+}
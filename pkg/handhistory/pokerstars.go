@@ -0,0 +1,451 @@
+// Package handhistory 将手牌历史转换为通用的第三方格式（供 HUD / 统计软件导入）
+package handhistory
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/evaluator"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+)
+
+// ==================== PokerStars 格式导出 ====================
+
+// PokerStarsOptions PokerStars 格式导出选项
+type PokerStarsOptions struct {
+	Hero      string // 主视角玩家（名称或ID），输出其 "Dealt to" 底牌行
+	TableName string // 桌名（默认 "Texas Holdem"）
+	MaxSeats  int    // 最大座位数（默认 9）
+}
+
+// WritePokerStars 将多手牌以 PokerStars 文本格式写入 w（每手之间空两行）
+func WritePokerStars(w io.Writer, hands []game.HandHistory, opts PokerStarsOptions) error {
+	for _, hand := range hands {
+		if _, err := io.WriteString(w, FormatPokerStars(hand, opts)+"\n\n\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// psPlayer 导出过程中的玩家状态
+type psPlayer struct {
+	game.HistoryPlayer
+	invested  int        // 本局累计投入（含前注和盲注）
+	streetBet int        // 本街累计下注（不含前注）
+	stack     int        // 当前剩余筹码
+	foldedOn  game.Stage // 弃牌的阶段（未弃牌为 StageWaiting）
+	folded    bool
+	position  string // 摘要中的位置标记，如 " (button)"
+}
+
+// psHand 单手牌导出器
+type psHand struct {
+	hand    game.HandHistory
+	opts    PokerStarsOptions
+	players []*psPlayer
+	byID    map[string]*psPlayer
+	b       strings.Builder
+}
+
+// FormatPokerStars 将一手牌转换为 PokerStars 文本格式
+func FormatPokerStars(hand game.HandHistory, opts PokerStarsOptions) string {
+	if opts.TableName == "" {
+		opts.TableName = "Texas Holdem"
+	}
+	if opts.MaxSeats <= 0 {
+		opts.MaxSeats = 9
+	}
+
+	h := &psHand{hand: hand, opts: opts, byID: make(map[string]*psPlayer)}
+	for _, p := range hand.Players {
+		pp := &psPlayer{HistoryPlayer: p, stack: p.StartChips}
+		h.players = append(h.players, pp)
+		h.byID[p.ID] = pp
+	}
+	sort.Slice(h.players, func(i, j int) bool { return h.players[i].Seat < h.players[j].Seat })
+
+	h.writeHeader()
+	h.writePosts()
+	h.writeActions()
+	h.writeShowdown()
+	h.writeSummary()
+	return strings.TrimRight(h.b.String(), "\n")
+}
+
+// line 写入一行
+func (h *psHand) line(format string, args ...interface{}) {
+	fmt.Fprintf(&h.b, format, args...)
+	h.b.WriteByte('\n')
+}
+
+// writeHeader 写入手牌头、桌子信息和座位
+func (h *psHand) writeHeader() {
+	h.line("PokerStars Hand #%d: Hold'em No Limit (%d/%d) - %s",
+		h.hand.HandID, h.hand.SmallBlind, h.hand.BigBlind, formatTime(h.hand.Timestamp))
+	h.line("Table '%s' %d-max Seat #%d is the button", h.opts.TableName, h.opts.MaxSeats, h.hand.DealerSeat+1)
+
+	for _, p := range h.players {
+		h.line("Seat %d: %s (%d in chips)", p.Seat+1, p.Name, p.StartChips)
+	}
+}
+
+// writePosts 写入前注和盲注，并确定庄家、小盲、大盲的位置标记
+func (h *psHand) writePosts() {
+	for _, p := range h.players {
+		if p.PostedAnte > 0 {
+			h.invest(p, p.PostedAnte)
+			h.line("%s: posts the ante %d%s", p.Name, p.PostedAnte, h.allInSuffix(p))
+		}
+	}
+
+	sb, bb := h.blindPlayers()
+	for _, p := range h.players {
+		if p.Seat == h.hand.DealerSeat {
+			p.position = " (button)"
+		}
+	}
+	if sb != nil {
+		sb.position += " (small blind)"
+		h.invest(sb, sb.PostedBlind)
+		h.line("%s: posts small blind %d%s", sb.Name, sb.PostedBlind, h.allInSuffix(sb))
+	}
+	if bb != nil {
+		bb.position += " (big blind)"
+		h.invest(bb, bb.PostedBlind)
+		h.line("%s: posts big blind %d%s", bb.Name, bb.PostedBlind, h.allInSuffix(bb))
+	}
+
+	h.line("*** HOLE CARDS ***")
+	for _, p := range h.players {
+		if h.isHero(p) && p.HoleCards[0].Rank != 0 {
+			h.line("Dealt to %s %s", p.Name, formatCards(p.HoleCards[:]))
+		}
+	}
+}
+
+// blindPlayers 按座位顺序找出小盲和大盲（单挑时庄家是小盲）
+func (h *psHand) blindPlayers() (sb, bb *psPlayer) {
+	var posted []*psPlayer
+	// 从庄家下一位开始按座位顺序排列，庄家在最后
+	start := 0
+	for i, p := range h.players {
+		if p.Seat > h.hand.DealerSeat {
+			start = i
+			break
+		}
+	}
+	order := append(append([]*psPlayer{}, h.players[start:]...), h.players[:start]...)
+	if len(order) == 2 {
+		order[0], order[1] = order[1], order[0]
+	}
+	for _, p := range order {
+		if p.PostedBlind > 0 {
+			posted = append(posted, p)
+		}
+	}
+
+	switch len(posted) {
+	case 0:
+		return nil, nil
+	case 1:
+		// 只有一人付了盲注：按金额判断是小盲还是大盲
+		if posted[0].PostedBlind < h.hand.BigBlind {
+			return posted[0], nil
+		}
+		return nil, posted[0]
+	default:
+		return posted[0], posted[1]
+	}
+}
+
+// writeActions 写入各街的行动和街道标题
+// 下注额按每位玩家本街实际投入的筹码计算，保证导出的底池与筹码变化一致
+func (h *psHand) writeActions() {
+	board := boardCards(h.hand.CommunityCards)
+	street := game.StagePreFlop
+	streetBet := h.hand.BigBlind
+	for _, p := range h.players {
+		p.streetBet = p.PostedBlind
+		streetBet = max(streetBet, p.PostedBlind)
+	}
+
+	for _, a := range h.hand.Actions {
+		p := h.byID[a.PlayerID]
+		if p == nil {
+			continue
+		}
+		for street < a.Round && street < game.StageRiver {
+			street++
+			h.writeStreet(street, board)
+			streetBet = 0
+			for _, p := range h.players {
+				p.streetBet = 0
+			}
+		}
+
+		h.invest(p, a.Amount)
+		p.streetBet += a.Amount
+		switch a.Action {
+		case models.ActionFold:
+			p.folded = true
+			p.foldedOn = a.Round
+			h.line("%s: folds", p.Name)
+		case models.ActionCheck:
+			h.line("%s: checks", p.Name)
+		case models.ActionCall:
+			h.line("%s: calls %d%s", p.Name, a.Amount, h.allInSuffix(p))
+		case models.ActionRaise, models.ActionAllIn:
+			switch {
+			case p.streetBet <= streetBet:
+				h.line("%s: calls %d%s", p.Name, a.Amount, h.allInSuffix(p))
+			case streetBet == 0:
+				h.line("%s: bets %d%s", p.Name, a.Amount, h.allInSuffix(p))
+			default:
+				h.line("%s: raises %d to %d%s", p.Name, p.streetBet-streetBet, p.streetBet, h.allInSuffix(p))
+			}
+			streetBet = max(streetBet, p.streetBet)
+		}
+	}
+
+	// 多余的下注退回（其他人都没有跟到这个数额）
+	if p, amount := h.uncalledBet(); p != nil {
+		h.line("Uncalled bet (%d) returned to %s", amount, p.Name)
+		p.invested -= amount
+		p.WonChips = max(p.WonChips-amount, 0)
+	}
+
+	// 全下后直接发完的公共牌
+	for street < game.StageRiver && len(board) >= streetCardCount(street+1) {
+		street++
+		h.writeStreet(street, board)
+	}
+}
+
+// writeStreet 写入街道标题
+func (h *psHand) writeStreet(street game.Stage, board []card.Card) {
+	switch street {
+	case game.StageFlop:
+		if len(board) >= 3 {
+			h.line("*** FLOP *** %s", formatCards(board[:3]))
+		}
+	case game.StageTurn:
+		if len(board) >= 4 {
+			h.line("*** TURN *** %s %s", formatCards(board[:3]), formatCards(board[3:4]))
+		}
+	case game.StageRiver:
+		if len(board) >= 5 {
+			h.line("*** RIVER *** %s %s", formatCards(board[:4]), formatCards(board[4:5]))
+		}
+	}
+}
+
+// uncalledBet 返回投入最多、且超出其他人最大投入的玩家和超出的数额
+func (h *psHand) uncalledBet() (*psPlayer, int) {
+	var top *psPlayer
+	second := 0
+	for _, p := range h.players {
+		switch {
+		case top == nil || p.invested > top.invested:
+			if top != nil {
+				second = top.invested
+			}
+			top = p
+		case p.invested > second:
+			second = p.invested
+		}
+	}
+	if top == nil || top.invested <= second {
+		return nil, 0
+	}
+	return top, top.invested - second
+}
+
+// writeShowdown 写入摊牌和收池
+func (h *psHand) writeShowdown() {
+	shown := h.shownHands()
+	if len(shown) > 0 {
+		h.line("*** SHOW DOWN ***")
+		for _, p := range h.players {
+			if sd, ok := shown[p.ID]; ok {
+				h.line("%s: shows %s (%s)", p.Name, formatCards(p.HoleCards[:]), handDescription(sd.HandRank))
+			}
+		}
+	}
+
+	for _, p := range h.players {
+		if p.WonChips > 0 {
+			h.line("%s collected %d from pot", p.Name, p.WonChips)
+		}
+	}
+	if len(shown) == 0 {
+		for _, p := range h.players {
+			if p.WonChips > 0 {
+				h.line("%s: doesn't show hand", p.Name)
+			}
+		}
+	}
+}
+
+// writeSummary 写入摘要
+func (h *psHand) writeSummary() {
+	total := 0
+	for _, p := range h.players {
+		total += p.invested
+	}
+
+	h.line("*** SUMMARY ***")
+	h.line("Total pot %d | Rake 0", total)
+	if board := boardCards(h.hand.CommunityCards); len(board) > 0 {
+		h.line("Board %s", formatCards(board))
+	}
+
+	shown := h.shownHands()
+	for _, p := range h.players {
+		prefix := fmt.Sprintf("Seat %d: %s%s", p.Seat+1, p.Name, p.position)
+		sd, showed := shown[p.ID]
+		switch {
+		case p.folded && p.foldedOn <= game.StagePreFlop:
+			if p.invested == 0 {
+				h.line("%s folded before Flop (didn't bet)", prefix)
+			} else {
+				h.line("%s folded before Flop", prefix)
+			}
+		case p.folded:
+			h.line("%s folded on the %s", prefix, streetName(p.foldedOn))
+		case showed && p.WonChips > 0:
+			h.line("%s showed %s and won (%d) with %s", prefix, formatCards(p.HoleCards[:]), p.WonChips, handDescription(sd.HandRank))
+		case showed:
+			h.line("%s showed %s and lost with %s", prefix, formatCards(p.HoleCards[:]), handDescription(sd.HandRank))
+		case p.WonChips > 0:
+			h.line("%s collected (%d)", prefix, p.WonChips)
+		default:
+			h.line("%s mucked", prefix)
+		}
+	}
+}
+
+// shownHands 返回亮牌玩家的摊牌信息（底牌未知的不算亮牌）
+func (h *psHand) shownHands() map[string]game.ShowdownInfo {
+	shown := make(map[string]game.ShowdownInfo)
+	for _, sd := range h.hand.Showdown {
+		if p := h.byID[sd.PlayerID]; p != nil && p.HoleCards[0].Rank != 0 {
+			shown[sd.PlayerID] = sd
+		}
+	}
+	return shown
+}
+
+// invest 记录玩家投入
+func (h *psHand) invest(p *psPlayer, amount int) {
+	p.invested += amount
+	p.stack -= amount
+}
+
+// allInSuffix 玩家筹码用尽时追加全下标记
+func (h *psHand) allInSuffix(p *psPlayer) string {
+	if p.stack <= 0 {
+		return " and is all-in"
+	}
+	return ""
+}
+
+// isHero 判断是否为主视角玩家
+func (h *psHand) isHero(p *psPlayer) bool {
+	return h.opts.Hero != "" && (p.Name == h.opts.Hero || p.ID == h.opts.Hero)
+}
+
+// ==================== 工具函数 ====================
+
+// easternTime PokerStars 手牌时间使用美东时间
+var easternTime = func() *time.Location {
+	if loc, err := time.LoadLocation("America/New_York"); err == nil {
+		return loc
+	}
+	return nil
+}()
+
+// formatTime 格式化手牌时间（无法加载时区数据时使用 UTC）
+func formatTime(t time.Time) string {
+	if easternTime == nil {
+		return t.UTC().Format("2006/01/02 15:04:05") + " UTC"
+	}
+	return t.In(easternTime).Format("2006/01/02 15:04:05") + " ET"
+}
+
+// formatCards 格式化为 "[Ah Kd]"
+func formatCards(cards []card.Card) string {
+	parts := make([]string, len(cards))
+	for i, c := range cards {
+		parts[i] = c.ShortString()
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
+// boardCards 返回已发出的公共牌
+func boardCards(community [5]card.Card) []card.Card {
+	var cards []card.Card
+	for _, c := range community {
+		if c.Rank != 0 {
+			cards = append(cards, c)
+		}
+	}
+	return cards
+}
+
+// streetCardCount 返回到达某条街时公共牌的张数
+func streetCardCount(street game.Stage) int {
+	switch street {
+	case game.StageFlop:
+		return 3
+	case game.StageTurn:
+		return 4
+	case game.StageRiver:
+		return 5
+	}
+	return 0
+}
+
+// streetName 返回街道的英文名称
+func streetName(street game.Stage) string {
+	switch street {
+	case game.StageFlop:
+		return "Flop"
+	case game.StageTurn:
+		return "Turn"
+	default:
+		return "River"
+	}
+}
+
+// handDescription 返回牌型的英文描述
+func handDescription(rank evaluator.HandRank) string {
+	switch rank {
+	case evaluator.RankHighCard:
+		return "high card"
+	case evaluator.RankOnePair:
+		return "a pair"
+	case evaluator.RankTwoPair:
+		return "two pair"
+	case evaluator.RankThreeOfAKind:
+		return "three of a kind"
+	case evaluator.RankStraight:
+		return "a straight"
+	case evaluator.RankFlush:
+		return "a flush"
+	case evaluator.RankFullHouse:
+		return "a full house"
+	case evaluator.RankFourOfAKind:
+		return "four of a kind"
+	case evaluator.RankStraightFlush:
+		return "a straight flush"
+	case evaluator.RankRoyalFlush:
+		return "a Royal Flush"
+	}
+	return "a hand"
+}
@@ -0,0 +1,172 @@
+package handhistory
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/evaluator"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+)
+
+// mustCards 解析牌面字符串列表
+func mustCards(t *testing.T, s string) []card.Card {
+	t.Helper()
+	var cards []card.Card
+	for _, f := range strings.Fields(s) {
+		c, err := card.ParseCard(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cards = append(cards, c)
+	}
+	return cards
+}
+
+// showdownHand 三人局：Alice 加注，Bob 跟注，Carol 弃牌，翻牌后 Bob 下注被 Alice 跟注，摊牌 Alice 获胜
+func showdownHand(t *testing.T) game.HandHistory {
+	hole := func(s string) [2]card.Card {
+		c := mustCards(t, s)
+		return [2]card.Card{c[0], c[1]}
+	}
+	var board [5]card.Card
+	copy(board[:], mustCards(t, "Ah 7d 2c Ks 9h"))
+
+	return game.HandHistory{
+		HandID:     42,
+		Timestamp:  time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC),
+		SmallBlind: 10,
+		BigBlind:   20,
+		DealerSeat: 0,
+		Players: []game.HistoryPlayer{
+			{ID: "p1", Name: "Alice", Seat: 0, HoleCards: hole("As Kd"), StartChips: 1000, FinalChips: 1160, WonChips: 300, IsWinner: true},
+			{ID: "p2", Name: "Bob", Seat: 1, HoleCards: hole("Qh Qc"), StartChips: 1000, PostedBlind: 10, FinalChips: 860},
+			{ID: "p3", Name: "Carol", Seat: 2, StartChips: 1000, PostedBlind: 20, FinalChips: 980},
+		},
+		CommunityCards: board,
+		Actions: []game.HistoryAction{
+			{PlayerID: "p1", PlayerName: "Alice", Round: game.StagePreFlop, Action: models.ActionRaise, Amount: 60, BetTo: 60},
+			{PlayerID: "p2", PlayerName: "Bob", Round: game.StagePreFlop, Action: models.ActionCall, Amount: 50, BetTo: 60},
+			{PlayerID: "p3", PlayerName: "Carol", Round: game.StagePreFlop, Action: models.ActionFold},
+			{PlayerID: "p2", PlayerName: "Bob", Round: game.StageFlop, Action: models.ActionRaise, Amount: 80, BetTo: 80},
+			{PlayerID: "p1", PlayerName: "Alice", Round: game.StageFlop, Action: models.ActionCall, Amount: 80, BetTo: 80},
+			{PlayerID: "p2", PlayerName: "Bob", Round: game.StageTurn, Action: models.ActionCheck},
+			{PlayerID: "p1", PlayerName: "Alice", Round: game.StageTurn, Action: models.ActionCheck},
+			{PlayerID: "p2", PlayerName: "Bob", Round: game.StageRiver, Action: models.ActionCheck},
+			{PlayerID: "p1", PlayerName: "Alice", Round: game.StageRiver, Action: models.ActionCheck},
+		},
+		Showdown: []game.ShowdownInfo{
+			{PlayerID: "p1", PlayerName: "Alice", HandRank: evaluator.RankTwoPair},
+			{PlayerID: "p2", PlayerName: "Bob", HandRank: evaluator.RankOnePair},
+		},
+		Pot:     300,
+		Winners: []game.WinnerInfo{{PlayerID: "p1", PlayerName: "Alice", Amount: 300}},
+	}
+}
+
+func TestFormatPokerStars_Showdown(t *testing.T) {
+	text := FormatPokerStars(showdownHand(t), PokerStarsOptions{Hero: "Bob", TableName: "Home"})
+	lines := strings.Split(text, "\n")
+
+	if !strings.HasPrefix(lines[0], "PokerStars Hand #42: Hold'em No Limit (10/20) - 2026/03/01 ") {
+		t.Errorf("unexpected header: %s", lines[0])
+	}
+
+	want := []string{
+		"Table 'Home' 9-max Seat #1 is the button",
+		"Seat 1: Alice (1000 in chips)",
+		"Seat 2: Bob (1000 in chips)",
+		"Seat 3: Carol (1000 in chips)",
+		"Bob: posts small blind 10",
+		"Carol: posts big blind 20",
+		"*** HOLE CARDS ***",
+		"Dealt to Bob [Qh Qc]",
+		"Alice: raises 40 to 60",
+		"Bob: calls 50",
+		"Carol: folds",
+		"*** FLOP *** [Ah 7d 2c]",
+		"Bob: bets 80",
+		"Alice: calls 80",
+		"*** TURN *** [Ah 7d 2c] [Ks]",
+		"Bob: checks",
+		"Alice: checks",
+		"*** RIVER *** [Ah 7d 2c Ks] [9h]",
+		"Bob: checks",
+		"Alice: checks",
+		"*** SHOW DOWN ***",
+		"Alice: shows [As Kd] (two pair)",
+		"Bob: shows [Qh Qc] (a pair)",
+		"Alice collected 300 from pot",
+		"*** SUMMARY ***",
+		"Total pot 300 | Rake 0",
+		"Board [Ah 7d 2c Ks 9h]",
+		"Seat 1: Alice (button) showed [As Kd] and won (300) with two pair",
+		"Seat 2: Bob (small blind) showed [Qh Qc] and lost with a pair",
+		"Seat 3: Carol (big blind) folded before Flop",
+	}
+	if len(lines)-1 != len(want) {
+		t.Fatalf("expected %d lines, got %d:\n%s", len(want)+1, len(lines), text)
+	}
+	for i, w := range want {
+		if lines[i+1] != w {
+			t.Errorf("line %d: expected %q, got %q", i+2, w, lines[i+1])
+		}
+	}
+}
+
+func TestFormatPokerStars_UncalledBet(t *testing.T) {
+	hand := game.HandHistory{
+		HandID:     7,
+		Timestamp:  time.Now(),
+		SmallBlind: 10,
+		BigBlind:   20,
+		DealerSeat: 1,
+		Players: []game.HistoryPlayer{
+			{ID: "p1", Name: "Alice", Seat: 0, StartChips: 500, PostedBlind: 20, FinalChips: 480},
+			{ID: "p2", Name: "Bob", Seat: 1, StartChips: 500, PostedBlind: 10, FinalChips: 520, WonChips: 120, IsWinner: true},
+		},
+		Actions: []game.HistoryAction{
+			{PlayerID: "p2", PlayerName: "Bob", Round: game.StagePreFlop, Action: models.ActionRaise, Amount: 90, BetTo: 100},
+			{PlayerID: "p1", PlayerName: "Alice", Round: game.StagePreFlop, Action: models.ActionFold},
+		},
+		Pot:     120,
+		Winners: []game.WinnerInfo{{PlayerID: "p2", PlayerName: "Bob", Amount: 120}},
+	}
+
+	text := FormatPokerStars(hand, PokerStarsOptions{})
+	for _, want := range []string{
+		"Bob: posts small blind 10", // 单挑时庄家是小盲
+		"Alice: posts big blind 20",
+		"Bob: raises 80 to 100",
+		"Uncalled bet (80) returned to Bob",
+		"Bob collected 40 from pot",
+		"Bob: doesn't show hand",
+		"Total pot 40 | Rake 0",
+		"Seat 1: Alice (big blind) folded before Flop",
+		"Seat 2: Bob (button) (small blind) collected (40)",
+	} {
+		if !strings.Contains(text+"\n", want+"\n") {
+			t.Errorf("missing line %q in:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Dealt to") {
+		t.Error("no hero set, should not print hole cards")
+	}
+}
+
+func TestWritePokerStars_SeparatesHands(t *testing.T) {
+	var buf bytes.Buffer
+	hand := showdownHand(t)
+	if err := WritePokerStars(&buf, []game.HandHistory{hand, hand}, PokerStarsOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), "PokerStars Hand #42"); n != 2 {
+		t.Errorf("expected 2 hands, got %d", n)
+	}
+	if !strings.Contains(buf.String(), "folded before Flop\n\n\nPokerStars Hand") {
+		t.Error("hands should be separated by two blank lines")
+	}
+}
@@ -0,0 +1,278 @@
+// Package replay 通过 GameEngine 逐步重新执行已记录的手牌
+//
+// 手牌的牌序来自记录的完整牌序或可验证洗牌的种子（外部导入的手牌则用已知的底牌和公共牌重建），
+// 按记录的行动依次调用 PlayerAction，每一步的 GameState 都会保存为检查点，
+// 因此可以任意前进、后退或跳转，也可以校验重放结果与记录是否一致，用于复现引擎问题。
+package replay
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/fair"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+)
+
+// ==================== 错误定义 ====================
+var (
+	ErrNoPlayers   = errors.New("手牌没有玩家")
+	ErrAtStart     = errors.New("已经是第一步")
+	ErrAtEnd       = errors.New("已经是最后一步")
+	ErrInvalidStep = errors.New("无效步数")
+	ErrMismatch    = errors.New("重放结果与记录不一致")
+)
+
+// Replayer 手牌重放器
+type Replayer struct {
+	hand   game.HandHistory
+	engine *game.GameEngine
+	states []*game.GameState // states[i] 为执行前 i 个行动后的状态（检查点）
+	pos    int               // 当前所在步数（已执行的行动数）
+}
+
+// New 创建重放器：按记录的座位、筹码、盲注、庄家和牌序开始这一手牌
+func New(hand game.HandHistory) (*Replayer, error) {
+	if len(hand.Players) == 0 {
+		return nil, ErrNoPlayers
+	}
+
+	engine := game.NewEngine(&game.Config{
+		MinPlayers: 2,
+		MaxPlayers: 9,
+		SmallBlind: hand.SmallBlind,
+		BigBlind:   hand.BigBlind,
+		Ante:       hand.Ante,
+	})
+	for _, p := range hand.Players {
+		if _, err := engine.AddPlayer(p.ID, p.Name, p.Seat); err != nil {
+			return nil, fmt.Errorf("玩家 %s 入座失败: %w", p.Name, err)
+		}
+		if err := engine.AdjustChips(p.ID, p.StartChips); err != nil {
+			return nil, fmt.Errorf("玩家 %s 设置筹码失败: %w", p.Name, err)
+		}
+	}
+
+	if err := engine.SetNextDeck(DeckOrder(hand)); err != nil {
+		return nil, err
+	}
+	if err := engine.SetNextButton(hand.DealerSeat); err != nil {
+		return nil, err
+	}
+	if err := engine.StartHand(); err != nil {
+		return nil, err
+	}
+
+	return &Replayer{
+		hand:   hand,
+		engine: engine,
+		states: []*game.GameState{snapshot(engine)},
+	}, nil
+}
+
+// Hand 返回正在重放的手牌记录
+func (r *Replayer) Hand() game.HandHistory {
+	return r.hand
+}
+
+// Len 返回总步数（记录的行动数）
+func (r *Replayer) Len() int {
+	return len(r.hand.Actions)
+}
+
+// Position 返回当前步数（0 表示刚发完底牌）
+func (r *Replayer) Position() int {
+	return r.pos
+}
+
+// State 返回当前步数的游戏状态
+func (r *Replayer) State() *game.GameState {
+	return cloneState(r.states[r.pos])
+}
+
+// LastAction 返回到达当前步数所执行的行动（第 0 步没有）
+func (r *Replayer) LastAction() (game.HistoryAction, bool) {
+	if r.pos == 0 {
+		return game.HistoryAction{}, false
+	}
+	return r.hand.Actions[r.pos-1], true
+}
+
+// Forward 前进一步
+func (r *Replayer) Forward() error {
+	if r.pos >= r.Len() {
+		return ErrAtEnd
+	}
+	return r.Seek(r.pos + 1)
+}
+
+// Backward 后退一步
+func (r *Replayer) Backward() error {
+	if r.pos == 0 {
+		return ErrAtStart
+	}
+	return r.Seek(r.pos - 1)
+}
+
+// Seek 跳到指定步数
+func (r *Replayer) Seek(step int) error {
+	if err := r.runTo(step); err != nil {
+		return err
+	}
+	r.pos = step
+	return nil
+}
+
+// Checkpoint 返回执行前 step 个行动后的游戏状态（不改变当前步数）
+func (r *Replayer) Checkpoint(step int) (*game.GameState, error) {
+	if err := r.runTo(step); err != nil {
+		return nil, err
+	}
+	return cloneState(r.states[step]), nil
+}
+
+// Verify 重放到最后一步，检查公共牌和每位玩家的最终筹码是否与记录一致
+func (r *Replayer) Verify() error {
+	if err := r.runTo(r.Len()); err != nil {
+		return err
+	}
+	final := r.states[r.Len()]
+
+	var diffs []string
+	if final.Stage != game.StageShowdown && final.Stage != game.StageEnd {
+		diffs = append(diffs, fmt.Sprintf("手牌未结束(阶段=%s)", final.Stage))
+	}
+	if final.CommunityCards != r.hand.CommunityCards && r.hand.CommunityCards != [5]card.Card{} {
+		diffs = append(diffs, fmt.Sprintf("公共牌 %v ≠ 记录 %v", final.CommunityCards, r.hand.CommunityCards))
+	}
+	for _, hp := range r.hand.Players {
+		for _, p := range final.Players {
+			if p.ID == hp.ID && p.Chips != hp.FinalChips {
+				diffs = append(diffs, fmt.Sprintf("%s 筹码 %d ≠ 记录 %d", hp.Name, p.Chips, hp.FinalChips))
+			}
+		}
+	}
+
+	if len(diffs) > 0 {
+		return fmt.Errorf("%w: %s", ErrMismatch, strings.Join(diffs, "; "))
+	}
+	return nil
+}
+
+// runTo 执行行动直到第 step 步的检查点可用
+func (r *Replayer) runTo(step int) error {
+	if step < 0 || step > r.Len() {
+		return ErrInvalidStep
+	}
+
+	for len(r.states) <= step {
+		n := len(r.states) - 1
+		a := r.hand.Actions[n]
+		state := r.states[n]
+
+		// 加注的目标金额 = 行动前本轮已下注 + 本次投入
+		amount := 0
+		if a.Action == models.ActionRaise {
+			for _, p := range state.Players {
+				if p.ID == a.PlayerID {
+					amount = p.CurrentBet + a.Amount
+				}
+			}
+		}
+
+		if err := r.engine.PlayerAction(a.PlayerID, a.Action, amount); err != nil {
+			return fmt.Errorf("第 %d 步 %s %s 执行失败: %w", n+1, a.PlayerName, a.Action, err)
+		}
+		r.states = append(r.states, snapshot(r.engine))
+	}
+	return nil
+}
+
+// ==================== 牌序 ====================
+
+// DeckOrder 返回重放使用的牌序：优先使用记录的牌序，其次用可验证洗牌的种子重新洗牌，
+// 都没有时（例如外部导入的手牌）按引擎的发牌顺序放回已知的底牌和公共牌，其余位置用剩下的牌补齐
+func DeckOrder(hand game.HandHistory) []card.Card {
+	if len(hand.Deck) == 52 {
+		return append([]card.Card(nil), hand.Deck...)
+	}
+	if hand.FairSeed != "" {
+		if shuffle, err := fair.ParseSeed(hand.FairSeed); err == nil {
+			return shuffle.Deck().Cards()
+		}
+	}
+
+	players := append([]game.HistoryPlayer(nil), hand.Players...)
+	sort.Slice(players, func(i, j int) bool { return players[i].Seat < players[j].Seat })
+
+	// 发牌顺序：烧一张，每人两张底牌，然后翻牌、转牌、河牌前各烧一张
+	var order []card.Card
+	order = append(order, card.Card{})
+	for _, p := range players {
+		order = append(order, p.HoleCards[0], p.HoleCards[1])
+	}
+	b := hand.CommunityCards
+	order = append(order, card.Card{}, b[0], b[1], b[2], card.Card{}, b[3], card.Card{}, b[4])
+
+	used := make(map[card.Card]bool, len(order))
+	for _, c := range order {
+		if c.Rank != 0 {
+			used[c] = true
+		}
+	}
+	var rest []card.Card
+	for _, c := range card.NewDeck().Cards() {
+		if !used[c] {
+			rest = append(rest, c)
+		}
+	}
+
+	for i, c := range order {
+		if c.Rank == 0 || !firstUse(order, i) {
+			order[i], rest = rest[0], rest[1:]
+		}
+	}
+	return append(order, rest...)
+}
+
+// firstUse 判断 order[i] 是否是这张牌第一次出现（记录有重复的牌时只保留第一次）
+func firstUse(order []card.Card, i int) bool {
+	for _, c := range order[:i] {
+		if c == order[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ==================== 工具函数 ====================
+
+// snapshot 保存引擎当前状态
+func snapshot(engine *game.GameEngine) *game.GameState {
+	return cloneState(engine.GetState())
+}
+
+// cloneState 深拷贝游戏状态，避免检查点之间共享切片
+func cloneState(s *game.GameState) *game.GameState {
+	c := *s
+	c.Players = make([]*models.Player, len(s.Players))
+	for i, p := range s.Players {
+		player := *p
+		c.Players[i] = &player
+	}
+	c.SidePots = make([]game.SidePot, len(s.SidePots))
+	for i, pot := range s.SidePots {
+		pot.EligiblePlayers = append([]int(nil), pot.EligiblePlayers...)
+		c.SidePots[i] = pot
+	}
+	c.Actions = append([]models.PlayerAction(nil), s.Actions...)
+	if s.LastShowdown != nil {
+		showdown := *s.LastShowdown
+		showdown.Players = append([]game.PlayerResult(nil), s.LastShowdown.Players...)
+		c.LastShowdown = &showdown
+	}
+	return &c
+}
@@ -0,0 +1,189 @@
+package replay
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+)
+
+// playHand 用引擎打一手三人牌并返回记录：翻牌前加注被跟注，翻牌下注，之后过牌到摊牌
+func playHand(t *testing.T, ante int, provablyFair bool) game.HandHistory {
+	t.Helper()
+	engine := game.NewEngine(&game.Config{
+		MinPlayers:    2,
+		MaxPlayers:    6,
+		SmallBlind:    10,
+		BigBlind:      20,
+		Ante:          ante,
+		StartingChips: 1000,
+	})
+	history, _ := game.NewHistoryManager("")
+	engine.SetHistory(history)
+	engine.SetProvablyFair(provablyFair)
+	engine.AddPlayer("p1", "Alice", 0)
+	engine.AddPlayer("p2", "Bob", 2)
+	engine.AddPlayer("p3", "Carol", 4)
+
+	if err := engine.StartHand(); err != nil {
+		t.Fatalf("StartHand failed: %v", err)
+	}
+
+	raised := map[game.Stage]bool{}
+	for i := 0; i < 30; i++ {
+		state := engine.GetState()
+		if state.Stage == game.StageShowdown || state.Stage == game.StageEnd {
+			break
+		}
+		p := state.Players[state.CurrentPlayer]
+
+		action, amount := models.ActionCheck, 0
+		switch {
+		case (state.Stage == game.StagePreFlop || state.Stage == game.StageFlop) && !raised[state.Stage]:
+			raised[state.Stage] = true
+			action, amount = models.ActionRaise, state.CurrentBet+40
+		case p.CurrentBet < state.CurrentBet:
+			action = models.ActionCall
+		}
+		if err := engine.PlayerAction(p.ID, action, amount); err != nil {
+			t.Fatalf("%s %s failed: %v", p.Name, action, err)
+		}
+	}
+
+	hand, ok := history.GetHand(1)
+	if !ok {
+		t.Fatal("hand not recorded")
+	}
+	return hand
+}
+
+func TestReplayer_Verify(t *testing.T) {
+	hand := playHand(t, 5, false)
+	if len(hand.Deck) != 52 {
+		t.Fatalf("expected deck order to be recorded, got %d cards", len(hand.Deck))
+	}
+
+	r, err := New(hand)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := r.Verify(); err != nil {
+		t.Errorf("replay diverged: %v", err)
+	}
+
+	// 底牌和庄家与记录一致
+	state := r.State()
+	for _, hp := range hand.Players {
+		for _, p := range state.Players {
+			if p.ID == hp.ID && p.HoleCards != hp.HoleCards {
+				t.Errorf("%s: hole cards %v, recorded %v", hp.Name, p.HoleCards, hp.HoleCards)
+			}
+		}
+	}
+	if dealer := state.Players[state.DealerButton]; dealer.Seat != hand.DealerSeat {
+		t.Errorf("expected dealer seat %d, got %d", hand.DealerSeat, dealer.Seat)
+	}
+}
+
+func TestReplayer_StepAndCheckpoint(t *testing.T) {
+	hand := playHand(t, 0, false)
+	r, _ := New(hand)
+
+	if err := r.Backward(); !errors.Is(err, ErrAtStart) {
+		t.Errorf("expected ErrAtStart, got %v", err)
+	}
+
+	start := r.State()
+	for r.Position() < r.Len() {
+		if err := r.Forward(); err != nil {
+			t.Fatalf("Forward failed at step %d: %v", r.Position(), err)
+		}
+		a, _ := r.LastAction()
+		if a != hand.Actions[r.Position()-1] {
+			t.Errorf("step %d: unexpected last action %+v", r.Position(), a)
+		}
+	}
+	if err := r.Forward(); !errors.Is(err, ErrAtEnd) {
+		t.Errorf("expected ErrAtEnd, got %v", err)
+	}
+	if stage := r.State().Stage; stage != game.StageShowdown {
+		t.Errorf("expected showdown at the end, got %s", stage)
+	}
+
+	// 后退回到开头，状态与第一次看到的一致
+	for r.Position() > 0 {
+		r.Backward()
+	}
+	if again := r.State(); again.Pot != start.Pot || again.CurrentPlayer != start.CurrentPlayer {
+		t.Errorf("state after rewinding differs: pot %d/%d", again.Pot, start.Pot)
+	}
+
+	// 检查点不受后续步骤影响
+	flop := -1
+	for i, a := range hand.Actions {
+		if a.Round == game.StageFlop {
+			flop = i
+			break
+		}
+	}
+	cp, err := r.Checkpoint(flop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.Stage != game.StageFlop || cp.CommunityCards[3].Rank != 0 {
+		t.Errorf("checkpoint %d should be on the flop before the turn, got %s %v", flop, cp.Stage, cp.CommunityCards)
+	}
+	cp.Players[0].Chips = -1
+	if again, _ := r.Checkpoint(flop); again.Players[0].Chips == -1 {
+		t.Error("checkpoint shares state with caller")
+	}
+	if r.Position() != 0 {
+		t.Errorf("Checkpoint should not move position, got %d", r.Position())
+	}
+	if _, err := r.Checkpoint(r.Len() + 1); !errors.Is(err, ErrInvalidStep) {
+		t.Errorf("expected ErrInvalidStep, got %v", err)
+	}
+}
+
+func TestReplayer_FairSeedOnly(t *testing.T) {
+	hand := playHand(t, 0, true)
+	if hand.FairHash == "" || hand.FairSeed == "" {
+		t.Fatalf("expected fair shuffle to be recorded, got hash=%q seed=%q", hand.FairHash, hand.FairSeed)
+	}
+	hand.Deck = nil
+
+	r, err := New(hand)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Verify(); err != nil {
+		t.Errorf("replay from seed diverged: %v", err)
+	}
+}
+
+func TestDeckOrder_RebuildsFromKnownCards(t *testing.T) {
+	hand := playHand(t, 0, false)
+	hand.Deck = nil
+
+	order := DeckOrder(hand)
+	if _, err := card.NewDeckFromCards(order); err != nil {
+		t.Fatalf("rebuilt deck invalid: %v", err)
+	}
+
+	r, err := New(hand)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Verify(); err != nil {
+		t.Errorf("replay from rebuilt deck diverged: %v", err)
+	}
+
+	// 记录被篡改时能发现不一致
+	hand.Players[0].FinalChips++
+	r, _ = New(hand)
+	if err := r.Verify(); !errors.Is(err, ErrMismatch) {
+		t.Errorf("expected ErrMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,409 @@
+// Package sim 无网络的机器人对战模拟：每张牌桌在自己的协程中直接驱动 GameEngine，
+// 统计各策略的 bb/100 及其 95% 置信区间，并在每一局结束后校验筹码守恒等不变量。
+//
+// 牌桌的洗牌和策略的随机数都由 Config.Seed 和牌桌编号推出，相同的配置总是得到相同的结果
+// （与并行的协程数无关），发现违例时用同样的种子重新运行即可复现。
+package sim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/protocol"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/bot"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+)
+
+// ==================== 错误定义 ====================
+var (
+	ErrInvalidConfig = errors.New("无效的模拟配置")
+)
+
+// 模拟参数
+const (
+	maxActionsPerHand = 500 // 单局动作数上限，超过时认为引擎卡在下注轮中
+	maxViolations     = 100 // 报告中最多保留的违例条数
+)
+
+// ==================== 配置与结果 ====================
+
+// Config 模拟配置
+type Config struct {
+	Strategies    []string // 参与的策略名称（按座位轮流分配，每张牌桌错开一个座位）
+	Seats         int      // 每张牌桌的玩家数（2-9）
+	Tables        int      // 牌桌数
+	HandsPerTable int      // 每张牌桌的手数
+	Workers       int      // 并行的协程数（0 表示 CPU 核数）
+	Seed          uint64   // 随机种子
+	SmallBlind    int      // 小盲注
+	BigBlind      int      // 大盲注
+	Ante          int      // 前注（可选）
+	StartingChips int      // 每局开始前把所有玩家的筹码补足（或扣减）到这个数
+}
+
+// StrategyResult 单个策略的统计结果
+type StrategyResult struct {
+	Name  string // 策略名称
+	Hands int    // 参与的手数（同一局中多个座位使用同一策略时分别计数）
+	Net   int64  // 净赢筹码
+
+	sumSq float64 // 每手净赢筹码的平方和（用于计算方差）
+}
+
+// BBPer100 每 100 手平均赢得的大盲数
+func (r StrategyResult) BBPer100(bigBlind int) float64 {
+	if r.Hands == 0 {
+		return 0
+	}
+	return float64(r.Net) / float64(r.Hands) / float64(bigBlind) * 100
+}
+
+// CI95 bb/100 的 95% 置信区间半宽（按每手结果独立近似）
+func (r StrategyResult) CI95(bigBlind int) float64 {
+	if r.Hands < 2 {
+		return math.Inf(1)
+	}
+	n := float64(r.Hands)
+	mean := float64(r.Net) / n
+	variance := (r.sumSq - n*mean*mean) / (n - 1)
+	return 1.96 * math.Sqrt(max(variance, 0)/n) / float64(bigBlind) * 100
+}
+
+// add 记录一手的净赢筹码
+func (r *StrategyResult) add(net int) {
+	r.Hands++
+	r.Net += int64(net)
+	r.sumSq += float64(net) * float64(net)
+}
+
+// merge 合并另一张牌桌的结果
+func (r *StrategyResult) merge(o *StrategyResult) {
+	r.Hands += o.Hands
+	r.Net += o.Net
+	r.sumSq += o.sumSq
+}
+
+// Violation 一手牌结束后发现的不变量违例
+type Violation struct {
+	Table  int    // 牌桌编号（从 0 开始）
+	Hand   int    // 该牌桌的第几手（从 1 开始）
+	Err    error  // 违例原因
+	Detail string // 违例时的牌局快照（玩家筹码、下注、状态和动作记录）
+}
+
+// Error 返回违例描述
+func (v Violation) Error() string {
+	return fmt.Sprintf("牌桌 %d 第 %d 手: %v", v.Table, v.Hand, v.Err)
+}
+
+// Report 模拟报告
+type Report struct {
+	Hands          int              // 完成的手数（所有牌桌）
+	Results        []StrategyResult // 各策略的结果（按 bb/100 从高到低）
+	Rejected       int              // 修正为合法动作后仍被引擎拒绝的次数
+	Violations     []Violation      // 不变量违例（最多保留 maxViolations 条）
+	ViolationCount int              // 违例总数
+	Elapsed        time.Duration    // 耗时
+}
+
+// ==================== 运行 ====================
+
+// Run 按配置运行模拟，ctx 取消时停止发新牌并返回已完成部分的报告
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, cfg.Tables)
+
+	start := time.Now()
+	results := make([]*tableResult, cfg.Tables)
+	tables := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range tables {
+				results[t] = playTable(ctx, cfg, t)
+			}
+		}()
+	}
+	for t := 0; t < cfg.Tables; t++ {
+		tables <- t
+	}
+	close(tables)
+	wg.Wait()
+
+	// 按牌桌顺序合并，保证结果与协程数无关
+	report := &Report{Elapsed: time.Since(start)}
+	byName := make(map[string]*StrategyResult)
+	for _, tr := range results {
+		report.Hands += tr.hands
+		report.Rejected += tr.rejected
+		report.ViolationCount += len(tr.violations)
+		for _, v := range tr.violations {
+			if len(report.Violations) < maxViolations {
+				report.Violations = append(report.Violations, v)
+			}
+		}
+		for name, r := range tr.results {
+			if byName[name] == nil {
+				byName[name] = &StrategyResult{Name: name}
+			}
+			byName[name].merge(r)
+		}
+	}
+	for _, r := range byName {
+		report.Results = append(report.Results, *r)
+	}
+	sort.Slice(report.Results, func(i, j int) bool {
+		bi, bj := report.Results[i].BBPer100(cfg.BigBlind), report.Results[j].BBPer100(cfg.BigBlind)
+		if bi != bj {
+			return bi > bj
+		}
+		return report.Results[i].Name < report.Results[j].Name
+	})
+	return report, nil
+}
+
+// validate 检查配置并确认所有策略名称有效
+func (cfg Config) validate() error {
+	switch {
+	case len(cfg.Strategies) == 0:
+		return fmt.Errorf("%w: 没有策略", ErrInvalidConfig)
+	case cfg.Seats < 2 || cfg.Seats > 9:
+		return fmt.Errorf("%w: 每桌玩家数 %d 不在 2-9 之间", ErrInvalidConfig, cfg.Seats)
+	case cfg.Tables <= 0 || cfg.HandsPerTable <= 0:
+		return fmt.Errorf("%w: 牌桌数和手数必须为正数", ErrInvalidConfig)
+	case cfg.SmallBlind <= 0 || cfg.BigBlind < cfg.SmallBlind || cfg.Ante < 0:
+		return fmt.Errorf("%w: 盲注 %d/%d 前注 %d", ErrInvalidConfig, cfg.SmallBlind, cfg.BigBlind, cfg.Ante)
+	case cfg.StartingChips < cfg.BigBlind:
+		return fmt.Errorf("%w: 筹码 %d 少于一个大盲", ErrInvalidConfig, cfg.StartingChips)
+	}
+	for _, name := range cfg.Strategies {
+		if _, err := bot.NewStrategy(name, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ==================== 单张牌桌 ====================
+
+// tableResult 单张牌桌的模拟结果
+type tableResult struct {
+	hands      int
+	rejected   int
+	results    map[string]*StrategyResult
+	violations []Violation
+}
+
+// seat 牌桌上的一个模拟玩家
+type seat struct {
+	name     string // 策略名称
+	strategy bot.Strategy
+}
+
+// playTable 在一张牌桌上连续打 HandsPerTable 手，发现违例或引擎卡住时放弃这张牌桌
+func playTable(ctx context.Context, cfg Config, table int) *tableResult {
+	tr := &tableResult{results: make(map[string]*StrategyResult)}
+	engine := game.NewEngine(&game.Config{
+		MinPlayers:    2,
+		MaxPlayers:    cfg.Seats,
+		SmallBlind:    cfg.SmallBlind,
+		BigBlind:      cfg.BigBlind,
+		Ante:          cfg.Ante,
+		StartingChips: cfg.StartingChips,
+	})
+	engine.SetRandomSource(card.NewSeededSource(int64(deriveSeed(cfg.Seed, table, 0))))
+	engine.SetAllInEV(false)
+
+	seats := make(map[string]*seat, cfg.Seats)
+	for i := 0; i < cfg.Seats; i++ {
+		name := strings.ToLower(cfg.Strategies[(i+table)%len(cfg.Strategies)])
+		strategy, _ := bot.NewStrategy(name, deriveSeed(cfg.Seed, table, i+1))
+		id := fmt.Sprintf("p%d", i+1)
+		if _, err := engine.AddPlayer(id, fmt.Sprintf("%s#%d", name, i+1), i); err != nil {
+			tr.violations = append(tr.violations, Violation{Table: table, Err: err})
+			return tr
+		}
+		seats[id] = &seat{name: name, strategy: strategy}
+		if tr.results[name] == nil {
+			tr.results[name] = &StrategyResult{Name: name}
+		}
+	}
+
+	for hand := 1; hand <= cfg.HandsPerTable && ctx.Err() == nil; hand++ {
+		fail := func(err error) {
+			tr.violations = append(tr.violations, Violation{Table: table, Hand: hand, Err: err,
+				Detail: describeHand(engine.GetState())})
+		}
+
+		// 补足筹码，每一手都从相同的筹码深度开始
+		before := make(map[string]int, cfg.Seats)
+		for _, p := range engine.GetState().Players {
+			if p.Chips != cfg.StartingChips {
+				if err := engine.AdjustChips(p.ID, cfg.StartingChips-p.Chips); err != nil {
+					fail(fmt.Errorf("补足筹码失败: %w", err))
+					return tr
+				}
+			}
+			before[p.ID] = cfg.StartingChips
+		}
+
+		if err := engine.StartHand(); err != nil {
+			fail(fmt.Errorf("开局失败: %w", err))
+			return tr
+		}
+		if err := playHand(engine, seats, cfg.BigBlind, &tr.rejected); err != nil {
+			fail(err)
+			return tr
+		}
+
+		// 违例之后的每一局都会继承同样的差额，放弃这张牌桌
+		state := engine.GetState()
+		if err := checkInvariants(engine, state); err != nil {
+			fail(err)
+			return tr
+		}
+		for _, p := range state.Players {
+			tr.results[seats[p.ID].name].add(p.Chips - before[p.ID])
+		}
+		tr.hands++
+	}
+	return tr
+}
+
+// playHand 让各座位的策略依次行动直到本局结束
+// 修正后的动作仍被拒绝时记一次拒绝并代为弃牌；弃牌也失败或动作数超过上限说明引擎卡住
+func playHand(engine *game.GameEngine, seats map[string]*seat, bigBlind int, rejected *int) error {
+	for actions := 0; ; actions++ {
+		state := engine.GetState()
+		if !state.Stage.IsBetting() {
+			return nil
+		}
+		if actions >= maxActionsPerHand {
+			return fmt.Errorf("单局动作超过 %d 次仍未结束", maxActionsPerHand)
+		}
+		if state.CurrentPlayer < 0 || state.CurrentPlayer >= len(state.Players) {
+			return fmt.Errorf("当前玩家索引 %d 越界", state.CurrentPlayer)
+		}
+
+		p := state.Players[state.CurrentPlayer]
+		view, turn := playerView(state, state.CurrentPlayer, bigBlind)
+		situation, err := bot.NewSituation(view, turn)
+		if err != nil {
+			return err
+		}
+		d := situation.Legalize(seats[p.ID].strategy.Decide(view, turn))
+		if err := engine.PlayerAction(p.ID, d.Action, d.Amount); err != nil {
+			*rejected++
+			d = bot.Decision{Action: models.ActionFold}
+			if err := engine.PlayerAction(p.ID, d.Action, 0); err != nil {
+				return fmt.Errorf("%s 弃牌被拒绝: %w", p.Name, err)
+			}
+		}
+		observe(seats, &protocol.PlayerActed{PlayerID: p.ID, PlayerName: p.Name, Action: d.Action, Amount: d.Amount, Stage: state.Stage})
+	}
+}
+
+// observe 把动作通知给需要观察对手的策略（与服务器广播的动作消息相同）
+func observe(seats map[string]*seat, acted *protocol.PlayerActed) {
+	for _, s := range seats {
+		if o, ok := s.strategy.(bot.Observer); ok {
+			o.Observe(acted)
+		}
+	}
+}
+
+// checkInvariants 一局结束后的不变量：筹码守恒、底池和边池已分完、没有负筹码
+func checkInvariants(engine *game.GameEngine, state *game.GameState) error {
+	if inPlay, issued := engine.ChipBalance(); inPlay != issued {
+		return fmt.Errorf("筹码不守恒: 牌桌 %d ≠ 发放 %d（差 %+d）", inPlay, issued, inPlay-issued)
+	}
+	if state.Pot != 0 || len(state.SidePots) > 0 {
+		return fmt.Errorf("结算后底池未清空: 底池 %d, 边池 %d 个", state.Pot, len(state.SidePots))
+	}
+	for _, p := range state.Players {
+		if p.Chips < 0 {
+			return fmt.Errorf("%s 筹码为负: %d", p.Name, p.Chips)
+		}
+	}
+	return nil
+}
+
+// playerView 生成第 idx 位玩家视角的状态和行动通知（与服务器发给客户端的内容一致）
+func playerView(state *game.GameState, idx int, bigBlind int) (*protocol.GameState, *protocol.YourTurn) {
+	view := &protocol.GameState{
+		Stage:          state.Stage,
+		DealerButton:   state.DealerButton,
+		CurrentPlayer:  state.CurrentPlayer,
+		CurrentBet:     state.CurrentBet,
+		Pot:            state.Pot,
+		CommunityCards: state.CommunityCards,
+		BigBlind:       bigBlind,
+		Players:        make([]protocol.PlayerInfo, len(state.Players)),
+	}
+	for _, pot := range state.SidePots {
+		view.Pot += pot.Amount
+	}
+	for i, p := range state.Players {
+		view.Players[i] = protocol.PlayerInfo{
+			ID:         p.ID,
+			Name:       p.Name,
+			Seat:       p.Seat,
+			Chips:      p.Chips,
+			CurrentBet: p.CurrentBet,
+			Status:     p.Status,
+			IsDealer:   p.IsDealer,
+			IsSelf:     i == idx,
+		}
+		if i == idx {
+			view.Players[i].HoleCards = p.HoleCards
+		}
+	}
+
+	self := state.Players[idx]
+	turn := &protocol.YourTurn{
+		PlayerID:   self.ID,
+		MinAction:  state.CurrentBet - self.CurrentBet,
+		MaxAction:  state.CurrentBet + self.Chips,
+		CurrentBet: state.CurrentBet,
+	}
+	return view, turn
+}
+
+// describeHand 违例时的牌局快照
+func describeHand(state *game.GameState) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "阶段=%s 底池=%d 当前下注=%d 边池=%v\n", state.Stage, state.Pot, state.CurrentBet, state.SidePots)
+	for _, p := range state.Players {
+		fmt.Fprintf(&b, "  %s 座位%d 筹码=%d 下注=%d 状态=%s 底牌=[%s %s]\n",
+			p.Name, p.Seat+1, p.Chips, p.CurrentBet, p.Status, p.HoleCards[0], p.HoleCards[1])
+	}
+	for _, a := range state.Actions {
+		fmt.Fprintf(&b, "  %s %s %d\n", a.PlayerID, a.Action, a.Amount)
+	}
+	return b.String()
+}
+
+// deriveSeed 由总种子、牌桌编号和座位（0 表示洗牌）推出独立的种子（splitmix64）
+func deriveSeed(seed uint64, table, slot int) uint64 {
+	z := seed + uint64(table)*0x9E3779B97F4A7C15 + uint64(slot)*0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	return z ^ (z >> 31)
+}
@@ -0,0 +1,115 @@
+package sim
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"math"
+	"testing"
+)
+
+// testConfig 小规模模拟配置（关闭引擎日志）
+func testConfig(t *testing.T) Config {
+	t.Helper()
+	out := log.Writer()
+	log.SetOutput(io.Discard)
+	t.Cleanup(func() { log.SetOutput(out) })
+	return Config{
+		Strategies:    []string{"random", "station", "tag", "rule"},
+		Seats:         6,
+		Tables:        4,
+		HandsPerTable: 300,
+		Seed:          42,
+		SmallBlind:    10,
+		BigBlind:      20,
+		StartingChips: 400,
+	}
+}
+
+func TestRun(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.Workers = 1
+	report, err := Run(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Hands != 1200 || report.Rejected != 0 || report.ViolationCount != 0 {
+		t.Fatalf("expected 1200 clean hands, got %d hands, %d rejected, violations %v",
+			report.Hands, report.Rejected, report.Violations)
+	}
+
+	// 筹码只在玩家之间转移，各策略净赢之和为 0；每手牌每个座位记录一次
+	var net int64
+	hands := 0
+	for _, r := range report.Results {
+		net += r.Net
+		hands += r.Hands
+	}
+	if net != 0 || hands != 1200*6 || len(report.Results) != 4 {
+		t.Errorf("expected zero-sum results over %d seat-hands, got net %d over %d: %+v", 1200*6, net, hands, report.Results)
+	}
+
+	// 相同种子的结果与并行协程数无关
+	cfg.Workers = 3
+	again, err := Run(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range report.Results {
+		if report.Results[i] != again.Results[i] {
+			t.Errorf("expected the same results, got %+v and %+v", report.Results[i], again.Results[i])
+		}
+	}
+}
+
+func TestRun_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err := Run(ctx, testConfig(t))
+	if err != nil || report.Hands != 0 {
+		t.Errorf("expected an empty report, got %v, %v", report, err)
+	}
+}
+
+func TestRun_InvalidConfig(t *testing.T) {
+	tests := []func(*Config){
+		func(c *Config) { c.Strategies = nil },
+		func(c *Config) { c.Seats = 10 },
+		func(c *Config) { c.Tables = 0 },
+		func(c *Config) { c.BigBlind = 5 },
+		func(c *Config) { c.StartingChips = 10 },
+	}
+	for i, modify := range tests {
+		cfg := testConfig(t)
+		modify(&cfg)
+		if _, err := Run(context.Background(), cfg); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("case %d: expected ErrInvalidConfig, got %v", i, err)
+		}
+	}
+
+	cfg := testConfig(t)
+	cfg.Strategies = []string{"nope"}
+	if _, err := Run(context.Background(), cfg); err == nil {
+		t.Error("expected an error for an unknown strategy")
+	}
+}
+
+func TestStrategyResult(t *testing.T) {
+	var r StrategyResult
+	for _, net := range []int{40, -20, 40, -20} {
+		r.add(net)
+	}
+	// 平均每手 +10 = 0.5 BB，即 50 bb/100
+	if got := r.BBPer100(20); got != 50 {
+		t.Errorf("expected 50 bb/100, got %v", got)
+	}
+	// 样本方差 3600/3=1200，标准误 sqrt(1200/4) 筹码，换算为 bb/100 再乘 1.96
+	want := 1.96 * math.Sqrt(300) / 20 * 100
+	if got := r.CI95(20); math.Abs(got-want) > 1e-9 {
+		t.Errorf("expected a %.2f bb/100 half-width, got %v", want, got)
+	}
+	if ci := (StrategyResult{Hands: 1}).CI95(20); !math.IsInf(ci, 1) {
+		t.Errorf("expected an unbounded interval for one hand, got %v", ci)
+	}
+}
@@ -28,6 +28,13 @@ type Client struct {
 	onTurn         func(*protocol.YourTurn)       // 轮到玩家回合回调
 	onShowdown     func(*protocol.Showdown)       // 摊牌结果回调
 	onPlayerReady  func(*protocol.PlayerReadyNotify) // 玩家准备状态回调
+	onWaitlist     func(*protocol.WaitlistNotify)    // 候补队列状态回调
+	onAdminResult  func(*protocol.AdminResult)       // 房主指令结果回调
+	onHistory      func(*protocol.HistoryResponse)   // 手牌历史回调
+	onStats        func(*protocol.StatsResponse)     // 玩家统计回调
+	onLeaderboard  func(*protocol.Leaderboard)       // 排行榜回调
+	onGraph        func(*protocol.GraphResponse)     // 资金曲线回调
+	onPlayerActed  func(*protocol.PlayerActed)       // 玩家动作回调
 	onChat         func(*protocol.ChatMessage)    // 收到聊天消息回调
 	onError        func(error)                    // 错误回调
 	onConnect      func()                         // 连接成功回调
@@ -46,6 +53,13 @@ type Config struct {
 	OnTurn         func(*protocol.YourTurn)       // 轮到玩家回合回调
 	OnShowdown     func(*protocol.Showdown)       // 摊牌结果回调
 	OnPlayerReady  func(*protocol.PlayerReadyNotify) // 玩家准备状态回调
+	OnWaitlist     func(*protocol.WaitlistNotify)    // 候补队列状态回调
+	OnAdminResult  func(*protocol.AdminResult)       // 房主指令结果回调
+	OnHistory      func(*protocol.HistoryResponse)   // 手牌历史回调
+	OnStats        func(*protocol.StatsResponse)     // 玩家统计回调
+	OnLeaderboard  func(*protocol.Leaderboard)       // 排行榜回调（查询结果或本场最终排名）
+	OnGraph        func(*protocol.GraphResponse)     // 资金曲线回调
+	OnPlayerActed  func(*protocol.PlayerActed)       // 玩家动作回调
 	OnChat         func(*protocol.ChatMessage)    // 收到聊天消息回调
 	OnError        func(error)                    // 错误回调
 	OnConnect      func()                         // 连接成功回调
@@ -64,6 +78,13 @@ func NewClient(config *Config) *Client {
 		onTurn:         config.OnTurn,
 		onShowdown:     config.OnShowdown,
 		onPlayerReady:  config.OnPlayerReady,
+		onWaitlist:     config.OnWaitlist,
+		onAdminResult:  config.OnAdminResult,
+		onHistory:      config.OnHistory,
+		onStats:        config.OnStats,
+		onLeaderboard:  config.OnLeaderboard,
+		onGraph:        config.OnGraph,
+		onPlayerActed:  config.OnPlayerActed,
 		onChat:         config.OnChat,
 		onError:        config.OnError,
 		onConnect:      config.OnConnect,
@@ -237,6 +258,63 @@ func (c *Client) SendReadyForNext() error {
 	return c.Send(req)
 }
 
+// SendSitOut 发送暂时离座请求
+func (c *Client) SendSitOut() error {
+	req := protocol.NewSitOutRequest(c.playerID)
+	return c.Send(req)
+}
+
+// SendSitIn 发送回到座位请求
+func (c *Client) SendSitIn() error {
+	req := protocol.NewSitInRequest(c.playerID)
+	return c.Send(req)
+}
+
+// SendSeatChange 发送换座请求
+func (c *Client) SendSeatChange(seat int) error {
+	req := protocol.NewSeatChangeRequest(c.playerID, seat)
+	return c.Send(req)
+}
+
+// SendLeave 发送离开游戏请求（候补中则退出队列）
+func (c *Client) SendLeave() error {
+	req := &protocol.LeaveRequest{
+		BaseMessage: protocol.NewBaseMessage(protocol.MsgTypeLeave),
+		PlayerID:    c.playerID,
+	}
+	return c.Send(req)
+}
+
+// SendAdminCommand 发送房主管理指令（需在指令中携带房主令牌）
+func (c *Client) SendAdminCommand(cmd *protocol.AdminCommand) error {
+	return c.Send(cmd)
+}
+
+// SendHistoryRequest 请求最近 count 手牌历史
+func (c *Client) SendHistoryRequest(count int) error {
+	return c.Send(protocol.NewHistoryRequest(count))
+}
+
+// SendHandHistoryRequest 请求指定编号的手牌历史
+func (c *Client) SendHandHistoryRequest(handID int) error {
+	return c.Send(protocol.NewHandHistoryRequest(handID))
+}
+
+// SendStatsRequest 请求玩家统计（不指定名称时查询牌桌上的所有玩家）
+func (c *Client) SendStatsRequest(names ...string) error {
+	return c.Send(protocol.NewStatsRequest(names...))
+}
+
+// SendGraphRequest 请求玩家的资金曲线（name 为空时查询自己，count 为 0 时使用服务器默认手数）
+func (c *Client) SendGraphRequest(name string, count int) error {
+	return c.Send(protocol.NewGraphRequest(name, count))
+}
+
+// SendLeaderboardRequest 请求排行榜（limit 为 0 时使用服务器默认名次数）
+func (c *Client) SendLeaderboardRequest(order protocol.LeaderboardOrder, limit int) error {
+	return c.Send(protocol.NewLeaderboardRequest(order, limit))
+}
+
 // PlayerID 获取玩家ID
 func (c *Client) PlayerID() string {
 	return c.playerID
@@ -345,6 +423,24 @@ func (c *Client) handleMessage(data []byte) {
 	case protocol.MsgTypePlayerReady:
 		c.handlePlayerReady(data)
 
+	case protocol.MsgTypeWaitlist:
+		c.handleWaitlist(data)
+
+	case protocol.MsgTypeAdminResult:
+		c.handleAdminResult(data)
+
+	case protocol.MsgTypeHistoryResponse:
+		c.handleHistoryResponse(data)
+
+	case protocol.MsgTypeStatsResponse:
+		c.handleStatsResponse(data)
+
+	case protocol.MsgTypeLeaderboard:
+		c.handleLeaderboard(data)
+
+	case protocol.MsgTypeGraphResponse:
+		c.handleGraphResponse(data)
+
 	case protocol.MsgTypePlayerJoined:
 		c.handlePlayerJoined(data)
 
@@ -458,6 +554,84 @@ func (c *Client) handlePlayerReady(data []byte) {
 	}
 }
 
+// handleWaitlist 处理候补队列状态通知
+func (c *Client) handleWaitlist(data []byte) {
+	var msg protocol.WaitlistNotify
+	if err := json.Unmarshal(data, &msg); err != nil {
+		log.Printf("Failed to unmarshal Waitlist: %v", err)
+		return
+	}
+
+	if c.onWaitlist != nil {
+		c.onWaitlist(&msg)
+	}
+}
+
+// handleAdminResult 处理房主指令执行结果
+func (c *Client) handleAdminResult(data []byte) {
+	var msg protocol.AdminResult
+	if err := json.Unmarshal(data, &msg); err != nil {
+		log.Printf("Failed to unmarshal AdminResult: %v", err)
+		return
+	}
+
+	if c.onAdminResult != nil {
+		c.onAdminResult(&msg)
+	}
+}
+
+// handleHistoryResponse 处理手牌历史响应
+func (c *Client) handleHistoryResponse(data []byte) {
+	var msg protocol.HistoryResponse
+	if err := json.Unmarshal(data, &msg); err != nil {
+		log.Printf("Failed to unmarshal HistoryResponse: %v", err)
+		return
+	}
+
+	if c.onHistory != nil {
+		c.onHistory(&msg)
+	}
+}
+
+// handleStatsResponse 处理玩家统计响应
+func (c *Client) handleStatsResponse(data []byte) {
+	var msg protocol.StatsResponse
+	if err := json.Unmarshal(data, &msg); err != nil {
+		log.Printf("Failed to unmarshal StatsResponse: %v", err)
+		return
+	}
+
+	if c.onStats != nil {
+		c.onStats(&msg)
+	}
+}
+
+// handleLeaderboard 处理排行榜
+func (c *Client) handleLeaderboard(data []byte) {
+	var msg protocol.Leaderboard
+	if err := json.Unmarshal(data, &msg); err != nil {
+		log.Printf("Failed to unmarshal Leaderboard: %v", err)
+		return
+	}
+
+	if c.onLeaderboard != nil {
+		c.onLeaderboard(&msg)
+	}
+}
+
+// handleGraphResponse 处理资金曲线查询结果
+func (c *Client) handleGraphResponse(data []byte) {
+	var msg protocol.GraphResponse
+	if err := json.Unmarshal(data, &msg); err != nil {
+		log.Printf("Failed to unmarshal GraphResponse: %v", err)
+		return
+	}
+
+	if c.onGraph != nil {
+		c.onGraph(&msg)
+	}
+}
+
 // handlePlayerJoined 处理玩家加入通知
 func (c *Client) handlePlayerJoined(data []byte) {
 	var msg protocol.PlayerJoined
@@ -486,6 +660,9 @@ func (c *Client) handlePlayerActed(data []byte) {
 		return
 	}
 	// 通过回调传递（如果有）
+	if c.onPlayerActed != nil {
+		c.onPlayerActed(&msg)
+	}
 }
 
 // handleError 处理错误消息
@@ -0,0 +1,319 @@
+package host
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/protocol"
+	gamepkg "github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+)
+
+// ==================== 房主管理指令 ====================
+
+// SetHostToken 设置房主令牌（为空时保留自动生成的令牌）
+func (s *Server) SetHostToken(token string) {
+	if token != "" {
+		s.hostToken = token
+	}
+}
+
+// HostToken 返回房主令牌
+func (s *Server) HostToken() string {
+	return s.hostToken
+}
+
+// IsPaused 返回游戏是否被房主暂停
+func (s *Server) IsPaused() bool {
+	return s.paused.Load()
+}
+
+// Snapshot 返回当前游戏状态快照（不含任何玩家底牌，供房主控制台展示）
+func (s *Server) Snapshot() *protocol.GameState {
+	return s.getGameStateInfo("")
+}
+
+// ExecuteAdmin 执行房主管理指令并返回结果（在 Run 协程中执行，Run 必须已启动）
+func (s *Server) ExecuteAdmin(cmd *protocol.AdminCommand) *protocol.AdminResult {
+	result := make(chan *protocol.AdminResult, 1)
+	s.control <- func() {
+		result <- s.executeAdmin(cmd)
+	}
+	return <-result
+}
+
+// handleAdminCommand 处理客户端发来的房主管理指令
+func (s *Server) handleAdminCommand(client *Client, data []byte) {
+	var cmd protocol.AdminCommand
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		s.sendError(client.ID, "Invalid admin command format", 1001)
+		return
+	}
+
+	log.Printf("[管理] 收到指令 | 来源=%s(%s) | 指令=%s | 目标=%s", client.Name, client.ID, cmd.Command, cmd.TargetID)
+	s.sendToClient(client.ID, s.executeAdmin(&cmd))
+}
+
+// executeAdmin 执行管理指令并发布执行结果事件
+func (s *Server) executeAdmin(cmd *protocol.AdminCommand) *protocol.AdminResult {
+	result := s.dispatchAdmin(cmd)
+
+	status := "成功"
+	if !result.Success {
+		status = "失败"
+	}
+	s.publish(ServerEvent{Kind: EventAdmin, Message: fmt.Sprintf("[管理] %s %s: %s", result.Command, status, result.Message)})
+	return result
+}
+
+// dispatchAdmin 校验令牌并分发管理指令
+func (s *Server) dispatchAdmin(cmd *protocol.AdminCommand) *protocol.AdminResult {
+	if s.hostToken == "" || subtle.ConstantTimeCompare([]byte(cmd.Token), []byte(s.hostToken)) != 1 {
+		log.Printf("[管理] 拒绝 | 指令=%s | 原因=令牌无效", cmd.Command)
+		return adminResult(cmd.Command, 5001, "invalid host token")
+	}
+
+	switch cmd.Command {
+	case protocol.AdminStart:
+		return s.adminStart(cmd)
+	case protocol.AdminPause:
+		return s.adminPause(cmd)
+	case protocol.AdminResume:
+		return s.adminResume(cmd)
+	case protocol.AdminKick, protocol.AdminBan:
+		return s.adminKick(cmd)
+	case protocol.AdminAdjustChips:
+		return s.adminAdjustChips(cmd)
+	case protocol.AdminSetBlinds:
+		return s.adminSetBlinds(cmd)
+	case protocol.AdminBroadcast:
+		return s.adminBroadcast(cmd)
+	case protocol.AdminEndSession:
+		return s.adminEndSession(cmd)
+	case protocol.AdminAddBot:
+		return s.adminAddBot(cmd)
+	case protocol.AdminRemoveBot:
+		return s.adminRemoveBot(cmd)
+	default:
+		return adminResult(cmd.Command, 5002, "unknown admin command")
+	}
+}
+
+// adminStart 房主手动开始下一局
+func (s *Server) adminStart(cmd *protocol.AdminCommand) *protocol.AdminResult {
+	if s.paused.Load() {
+		return adminResult(cmd.Command, 5003, "game is paused")
+	}
+
+	state := s.gameEngine.GetState()
+	if state.Stage.IsBetting() {
+		return adminResult(cmd.Command, 5003, "hand in progress")
+	}
+
+	s.startNextHand()
+	if !s.gameEngine.GetState().Stage.IsBetting() {
+		return adminResult(cmd.Command, 5003, "not enough players to start")
+	}
+	return adminResult(cmd.Command, 0, "hand started")
+}
+
+// adminPause 暂停游戏：停止行动计时和开局倒计时，拒绝玩家动作
+func (s *Server) adminPause(cmd *protocol.AdminCommand) *protocol.AdminResult {
+	if s.paused.Load() {
+		return adminResult(cmd.Command, 5003, "game is already paused")
+	}
+
+	s.paused.Store(true)
+	s.stopTurnTimer()
+	s.stopNextHandCountdown()
+	s.broadcastSystemMessage("房主暂停了游戏")
+	s.onGameStateChange(s.gameEngine.GetState())
+	return adminResult(cmd.Command, 0, "game paused")
+}
+
+// adminResume 继续游戏：重新通知当前行动玩家，或恢复等待开局
+func (s *Server) adminResume(cmd *protocol.AdminCommand) *protocol.AdminResult {
+	if !s.paused.Load() {
+		return adminResult(cmd.Command, 5003, "game is not paused")
+	}
+
+	s.paused.Store(false)
+	s.broadcastSystemMessage("房主恢复了游戏")
+	s.onGameStateChange(s.gameEngine.GetState())
+
+	state := s.gameEngine.GetState()
+	if state.Stage.IsBetting() {
+		if state.CurrentPlayer < len(state.Players) {
+			s.notifyTurn(state.Players[state.CurrentPlayer].ID)
+		}
+	} else if s.nextHand.Policy == NextHandTimed {
+		s.beginReadyWait()
+	} else {
+		s.startIfAllReady()
+	}
+	return adminResult(cmd.Command, 0, "game resumed")
+}
+
+// adminKick 踢出玩家（ban 额外封禁名称和IP）
+func (s *Server) adminKick(cmd *protocol.AdminCommand) *protocol.AdminResult {
+	client := s.findClient(cmd.TargetID)
+	if client == nil {
+		return adminResult(cmd.Command, 5003, "player not found")
+	}
+
+	reason := "You have been kicked by the host"
+	if cmd.Command == protocol.AdminBan {
+		s.bannedNames[client.Name] = true
+		// IP 封禁需要显式指定：同一局域网出口或本机的玩家共用地址，回环地址始终不封禁
+		if cmd.BanIP && client.Addr != "" {
+			if ip := net.ParseIP(client.Addr); ip != nil && ip.IsLoopback() {
+				log.Printf("[管理] 跳过IP封禁 | 玩家=%s | IP=%s (回环地址)", client.Name, client.Addr)
+			} else {
+				s.bannedIPs[client.Addr] = true
+			}
+		}
+		reason = "You have been banned by the host"
+		s.broadcastSystemMessage(fmt.Sprintf("%s 已被房主封禁", client.Name))
+	} else {
+		s.broadcastSystemMessage(fmt.Sprintf("%s 已被房主请出牌桌", client.Name))
+	}
+
+	log.Printf("[管理] %s | 玩家=%s | IP=%s", cmd.Command, client.Name, client.Addr)
+	s.kickClient(client, reason)
+	return adminResult(cmd.Command, 0, fmt.Sprintf("%s removed", client.Name))
+}
+
+// adminAdjustChips 调整玩家筹码（两局之间）
+func (s *Server) adminAdjustChips(cmd *protocol.AdminCommand) *protocol.AdminResult {
+	client := s.findClient(cmd.TargetID)
+	if client == nil {
+		return adminResult(cmd.Command, 5003, "player not found")
+	}
+
+	if err := s.gameEngine.AdjustChips(client.ID, cmd.Amount); err != nil {
+		return adminResult(cmd.Command, 5003, err.Error())
+	}
+
+	s.broadcastSystemMessage(fmt.Sprintf("房主调整了 %s 的筹码 (%+d)", client.Name, cmd.Amount))
+	return adminResult(cmd.Command, 0, fmt.Sprintf("%s chips %+d", client.Name, cmd.Amount))
+}
+
+// adminSetBlinds 修改盲注（手牌进行中则下一局生效）
+func (s *Server) adminSetBlinds(cmd *protocol.AdminCommand) *protocol.AdminResult {
+	if err := s.gameEngine.SetBlinds(cmd.SmallBlind, cmd.BigBlind, cmd.Ante); err != nil {
+		return adminResult(cmd.Command, 5003, err.Error())
+	}
+
+	msg := fmt.Sprintf("盲注调整为 %d/%d", cmd.SmallBlind, cmd.BigBlind)
+	if cmd.Ante > 0 {
+		msg += fmt.Sprintf("，前注 %d", cmd.Ante)
+	}
+	if s.gameEngine.GetState().Stage.IsBetting() {
+		msg += "，下一局生效"
+	}
+	s.broadcastSystemMessage(msg)
+	return adminResult(cmd.Command, 0, msg)
+}
+
+// adminBroadcast 广播房主消息
+func (s *Server) adminBroadcast(cmd *protocol.AdminCommand) *protocol.AdminResult {
+	content := strings.TrimSpace(cmd.Message)
+	if content == "" {
+		return adminResult(cmd.Command, 5003, "empty message")
+	}
+
+	s.broadcastSystemMessage("[房主] " + content)
+	return adminResult(cmd.Command, 0, "message sent")
+}
+
+// adminEndSession 结束本场：广播最终排名后重新开始本场统计
+func (s *Server) adminEndSession(cmd *protocol.AdminCommand) *protocol.AdminResult {
+	if s.gameEngine.GetState().Stage.IsBetting() {
+		return adminResult(cmd.Command, 5003, "hand in progress")
+	}
+	if !s.endSession("房主结束本场") {
+		return adminResult(cmd.Command, 5003, "no hands played this session")
+	}
+	return adminResult(cmd.Command, 0, "session ended")
+}
+
+// kickClient 将客户端移出牌桌（或候补队列），通知原因后断开连接
+func (s *Server) kickClient(client *Client, reason string) {
+	if !s.removeFromWaitlist(client.ID) {
+		if err := s.removeSeatedPlayer(client.ID, client.Name); err != nil && err != gamepkg.ErrPlayerNotFound {
+			log.Printf("[管理] 移除玩家失败 | 玩家=%s | 错误=%v", client.Name, err)
+		}
+	}
+
+	s.sendError(client.ID, reason, 5004)
+
+	// 稍后关闭连接，让错误消息先发送出去
+	time.AfterFunc(500*time.Millisecond, func() {
+		client.Conn.Close()
+	})
+}
+
+// findClient 按客户端ID或玩家名称查找已加入的客户端
+func (s *Server) findClient(target string) *Client {
+	if target == "" {
+		return nil
+	}
+
+	s.clientsMu.RLock()
+	defer s.clientsMu.RUnlock()
+
+	if client, ok := s.clients[target]; ok && client.Name != "" {
+		return client
+	}
+	for _, client := range s.clients {
+		if client.Name == target {
+			return client
+		}
+	}
+	return nil
+}
+
+// isBanned 判断客户端名称或IP是否已被封禁
+func (s *Server) isBanned(client *Client) bool {
+	return s.bannedNames[client.Name] || (client.Addr != "" && s.bannedIPs[client.Addr])
+}
+
+// adminResult 构建管理指令结果（code 为 0 表示成功）
+func adminResult(command protocol.AdminCommandType, code int, message string) *protocol.AdminResult {
+	if code != 0 {
+		log.Printf("[管理] 失败 | 指令=%s | 错误码=%d | 原因=%s", command, code, message)
+	} else {
+		log.Printf("[管理] 成功 | 指令=%s | %s", command, message)
+	}
+	return &protocol.AdminResult{
+		BaseMessage: protocol.NewBaseMessage(protocol.MsgTypeAdminResult),
+		Command:     command,
+		Success:     code == 0,
+		Code:        code,
+		Message:     message,
+	}
+}
+
+// generateHostToken 生成随机房主令牌
+func generateHostToken() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return randomID(16)
+	}
+	return hex.EncodeToString(b)
+}
+
+// remoteIP 从 RemoteAddr 中提取IP
+func remoteIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
@@ -0,0 +1,234 @@
+package host
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/protocol"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/bot"
+)
+
+// 机器人默认配置
+const (
+	defaultBotStrategy  = "equity"                // 默认策略
+	defaultBotThinkTime = 1500 * time.Millisecond // 默认思考时间
+)
+
+// ==================== 机器人座位 ====================
+
+// serverBot 房主添加到牌桌上的机器人：直接坐在游戏引擎上，在服务器进程内决策，不占用 WebSocket 连接
+type serverBot struct {
+	id       string        // 玩家ID
+	name     string        // 玩家名称
+	strategy bot.Strategy  // 行动策略
+	think    time.Duration // 每次行动前的思考时间
+	mu       sync.Mutex    // 保证同一时间只有一个决策在运行（策略实例不是并发安全的）
+
+	// 还没交给策略的玩家动作（Run 协程不等待 mu，先排队，由持有 mu 的一方按顺序交给策略）
+	pendingMu sync.Mutex
+	pending   []*protocol.PlayerActed
+}
+
+// drainObservations 把排队的玩家动作按顺序交给策略（调用方必须持有 b.mu）
+func (b *serverBot) drainObservations() {
+	o, ok := b.strategy.(bot.Observer)
+	if !ok {
+		return
+	}
+	for {
+		b.pendingMu.Lock()
+		pending := b.pending
+		b.pending = nil
+		b.pendingMu.Unlock()
+		if len(pending) == 0 {
+			return
+		}
+		for _, acted := range pending {
+			o.Observe(acted)
+		}
+	}
+}
+
+// findBot 按玩家ID查找机器人（不是机器人时返回 nil）
+func (s *Server) findBot(playerID string) *serverBot {
+	s.botsMu.RLock()
+	defer s.botsMu.RUnlock()
+	return s.bots[playerID]
+}
+
+// findBotByTarget 按玩家ID或名称查找机器人
+func (s *Server) findBotByTarget(target string) *serverBot {
+	s.botsMu.RLock()
+	defer s.botsMu.RUnlock()
+	if b, ok := s.bots[target]; ok {
+		return b
+	}
+	for _, b := range s.bots {
+		if b.name == target {
+			return b
+		}
+	}
+	return nil
+}
+
+// adminAddBot 在空座位上添加机器人（手牌进行中加入的机器人等下一局参与）
+func (s *Server) adminAddBot(cmd *protocol.AdminCommand) *protocol.AdminResult {
+	strategyName := strings.TrimSpace(cmd.Strategy)
+	if strategyName == "" {
+		strategyName = defaultBotStrategy
+	}
+	strategy, err := bot.NewStrategy(strategyName, uint64(time.Now().UnixNano()))
+	if err != nil {
+		return adminResult(cmd.Command, 5003, err.Error())
+	}
+	think := defaultBotThinkTime
+	if cmd.ThinkTime > 0 {
+		think = time.Duration(cmd.ThinkTime) * time.Millisecond
+	}
+
+	seat := s.findAvailableSeat()
+	if seat < 0 {
+		return adminResult(cmd.Command, 5003, "table is full")
+	}
+	name := strings.TrimSpace(cmd.TargetID)
+	if name == "" {
+		name = s.nextBotName()
+	} else if s.nameTaken(name) {
+		return adminResult(cmd.Command, 5003, "name already taken")
+	}
+
+	b := &serverBot{id: "bot-" + randomID(8), name: name, strategy: strategy, think: think}
+	player, err := s.gameEngine.AddPlayer(b.id, b.name, seat)
+	if err != nil {
+		return adminResult(cmd.Command, 5003, err.Error())
+	}
+	s.botsMu.Lock()
+	s.bots[b.id] = b
+	s.botsMu.Unlock()
+
+	if s.gameEngine.GetState().Stage.IsBetting() {
+		s.gameEngine.SetPlayerStatus(b.id, models.PlayerStatusFolded)
+	}
+	log.Printf("[机器人] 入座 | 玩家=%s | 座位=%d | 策略=%s | 思考=%v", b.name, seat, strategy.Name(), think)
+
+	joinedMsg := &protocol.PlayerJoined{
+		BaseMessage: protocol.NewBaseMessage(protocol.MsgTypePlayerJoined),
+		Player: protocol.PlayerInfo{
+			ID:     player.ID,
+			Name:   player.Name,
+			Seat:   player.Seat,
+			Chips:  player.Chips,
+			Status: player.Status,
+			IsBot:  true,
+		},
+	}
+	data, _ := json.Marshal(joinedMsg)
+	s.broadcast <- data
+	s.publish(ServerEvent{Kind: EventPlayerJoined, PlayerID: b.id, PlayerName: b.name,
+		Message: fmt.Sprintf("机器人 %s 入座 座位%d (策略 %s)", b.name, seat+1, strategy.Name())})
+	s.broadcastSystemMessage(fmt.Sprintf("房主添加了机器人 %s", b.name))
+
+	return adminResult(cmd.Command, 0, fmt.Sprintf("bot %s seated (%s)", b.name, strategy.Name()))
+}
+
+// adminRemoveBot 移除机器人（手牌进行中先弃牌，本局结束后离开座位）
+func (s *Server) adminRemoveBot(cmd *protocol.AdminCommand) *protocol.AdminResult {
+	b := s.findBotByTarget(cmd.TargetID)
+	if b == nil {
+		return adminResult(cmd.Command, 5003, "bot not found")
+	}
+
+	s.botsMu.Lock()
+	delete(s.bots, b.id)
+	s.botsMu.Unlock()
+	if err := s.removeSeatedPlayer(b.id, b.name); err != nil {
+		return adminResult(cmd.Command, 5003, err.Error())
+	}
+
+	s.broadcastSystemMessage(fmt.Sprintf("房主移除了机器人 %s", b.name))
+	return adminResult(cmd.Command, 0, fmt.Sprintf("bot %s removed", b.name))
+}
+
+// scheduleBotTurn 轮到机器人时在后台思考，思考结束后回到 Run 协程执行决定
+func (s *Server) scheduleBotTurn(b *serverBot, turn *protocol.YourTurn) {
+	s.botSeq++
+	seq := s.botSeq
+	state := s.getGameStateInfo(b.id)
+
+	time.AfterFunc(b.think, func() {
+		b.mu.Lock()
+		b.drainObservations()
+		d := b.strategy.Decide(state, turn)
+		b.mu.Unlock()
+		s.control <- func() {
+			s.botAct(b, seq, d)
+		}
+	})
+}
+
+// botAct 执行机器人的决定（期间暂停、换人或重新通知过时丢弃）
+func (s *Server) botAct(b *serverBot, seq int, d bot.Decision) {
+	if seq != s.botSeq || s.paused.Load() {
+		return
+	}
+	state := s.gameEngine.GetState()
+	if !state.Stage.IsBetting() || state.CurrentPlayer >= len(state.Players) || state.Players[state.CurrentPlayer].ID != b.id {
+		return
+	}
+
+	situation, err := bot.NewSituation(s.getGameStateInfo(b.id), nil)
+	if err != nil {
+		log.Printf("[机器人] 无法行动 | 玩家=%s | 原因=%v", b.name, err)
+		return
+	}
+	d = situation.Legalize(d)
+	log.Printf("[机器人] 行动 | 玩家=%s | 决定=%s", b.name, d)
+	if s.processAction(b.id, b.name, d.Action, d.Amount) {
+		delete(s.timeoutCount, b.id)
+	}
+}
+
+// observeBots 把玩家动作告诉需要观察对手的机器人策略
+// 在 Run 协程中调用，不能等待正在决策的机器人：动作先排队，机器人空闲时立即交给策略，否则在下次决策前交给策略
+func (s *Server) observeBots(acted *protocol.PlayerActed) {
+	s.botsMu.RLock()
+	defer s.botsMu.RUnlock()
+	for _, b := range s.bots {
+		if _, ok := b.strategy.(bot.Observer); !ok {
+			continue
+		}
+		b.pendingMu.Lock()
+		b.pending = append(b.pending, acted)
+		b.pendingMu.Unlock()
+		if b.mu.TryLock() {
+			b.drainObservations()
+			b.mu.Unlock()
+		}
+	}
+}
+
+// nextBotName 生成未被占用的默认机器人名称（Bot1、Bot2 …）
+func (s *Server) nextBotName() string {
+	for {
+		s.botNext++
+		name := fmt.Sprintf("Bot%d", s.botNext)
+		if !s.nameTaken(name) {
+			return name
+		}
+	}
+}
+
+// nameTaken 判断牌桌上是否已有同名玩家
+func (s *Server) nameTaken(name string) bool {
+	for _, p := range s.gameEngine.GetState().Players {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
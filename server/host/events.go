@@ -0,0 +1,192 @@
+package host
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/protocol"
+)
+
+// ==================== 服务器事件流 ====================
+
+// EventKind 服务器事件类型
+type EventKind string
+
+const (
+	EventConnected    EventKind = "connected"     // 客户端建立连接
+	EventDisconnected EventKind = "disconnected"  // 客户端断开连接
+	EventPlayerJoined EventKind = "player_joined" // 玩家入座
+	EventPlayerLeft   EventKind = "player_left"   // 玩家离开牌桌
+	EventHandStarted  EventKind = "hand_started"  // 新一局开始（携带 State）
+	EventAction       EventKind = "action"        // 玩家动作
+	EventHandEnded    EventKind = "hand_ended"    // 本局结算（携带 Showdown）
+	EventState        EventKind = "state"         // 游戏状态变化（携带 State）
+	EventChat         EventKind = "chat"          // 玩家聊天
+	EventSystem       EventKind = "system"        // 系统消息
+	EventAdmin        EventKind = "admin"         // 房主管理指令结果
+)
+
+// ServerEvent 服务器事件（供房主控制台等订阅者使用）
+type ServerEvent struct {
+	Kind       EventKind
+	Time       time.Time
+	Message    string              // 可读描述
+	PlayerID   string              // 相关玩家ID（可能为空）
+	PlayerName string              // 相关玩家名称（可能为空）
+	Action     models.ActionType   // EventAction 的动作
+	Amount     int                 // EventAction 的金额
+	State      *protocol.GameState // EventHandStarted / EventState 的状态快照（不含底牌）
+	Showdown   *protocol.Showdown  // EventHandEnded 的结算详情
+}
+
+// eventHub 事件订阅管理
+type eventHub struct {
+	mu     sync.Mutex
+	nextID int
+	subs   map[int]chan ServerEvent
+}
+
+// Subscribe 订阅服务器事件，返回事件通道和取消订阅函数
+// 订阅者处理过慢时（缓冲区满）事件会被丢弃，不会阻塞服务器
+func (s *Server) Subscribe(buffer int) (<-chan ServerEvent, func()) {
+	if buffer <= 0 {
+		buffer = 64
+	}
+	ch := make(chan ServerEvent, buffer)
+
+	s.events.mu.Lock()
+	if s.events.subs == nil {
+		s.events.subs = make(map[int]chan ServerEvent)
+	}
+	id := s.events.nextID
+	s.events.nextID++
+	s.events.subs[id] = ch
+	s.events.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			s.events.mu.Lock()
+			delete(s.events.subs, id)
+			s.events.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, cancel
+}
+
+// publish 向所有订阅者发送事件（非阻塞，可在任意协程调用）
+func (s *Server) publish(ev ServerEvent) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+
+	s.events.mu.Lock()
+	defer s.events.mu.Unlock()
+
+	for _, ch := range s.events.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// actionDescription 生成玩家动作的可读描述
+func actionDescription(playerName string, action models.ActionType, amount int) string {
+	if amount > 0 && action != models.ActionFold && action != models.ActionCheck {
+		return fmt.Sprintf("%s %s %d", playerName, actionName(action), amount)
+	}
+	return fmt.Sprintf("%s %s", playerName, actionName(action))
+}
+
+// showdownDescription 生成结算结果的可读描述
+func showdownDescription(sd *protocol.Showdown) string {
+	total := 0
+	parts := make([]string, 0, len(sd.Winners))
+	for _, w := range sd.Winners {
+		total += w.WonChips
+		if w.HandName != "" && !sd.IsEarlyEnd {
+			parts = append(parts, fmt.Sprintf("%s 以%s赢得 %d", w.PlayerName, w.HandName, w.WonChips))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s 赢得 %d", w.PlayerName, w.WonChips))
+		}
+	}
+	return fmt.Sprintf("本局结束 | 底池 %d | %s", total, strings.Join(parts, ", "))
+}
+
+// ==================== 客户端连接信息 ====================
+
+// ClientInfo 客户端连接信息快照
+type ClientInfo struct {
+	ID          string
+	Name        string        // 玩家名称（未加入时为空）
+	Addr        string        // 客户端IP
+	Seat        int           // 座位号
+	Seated      bool          // 是否已在牌桌上（否则为未加入或候补中）
+	ConnectedAt time.Time     // 连接时间
+	Latency     time.Duration // 最近一次 ping/pong 往返时延（0 表示尚未测得）
+}
+
+// ClientInfos 返回所有连接客户端的信息，按连接时间排序（在 Run 协程中采集，Run 必须已启动）
+func (s *Server) ClientInfos() []ClientInfo {
+	result := make(chan []ClientInfo, 1)
+	s.control <- func() {
+		result <- s.clientInfos()
+	}
+	return <-result
+}
+
+// clientInfos 采集客户端连接信息（仅在 Run 协程中调用）
+func (s *Server) clientInfos() []ClientInfo {
+	seated := make(map[string]bool)
+	for _, p := range s.gameEngine.GetState().Players {
+		seated[p.ID] = true
+	}
+
+	s.clientsMu.RLock()
+	infos := make([]ClientInfo, 0, len(s.clients))
+	for _, c := range s.clients {
+		infos = append(infos, ClientInfo{
+			ID:          c.ID,
+			Name:        c.Name,
+			Addr:        c.Addr,
+			Seat:        c.Seat,
+			Seated:      seated[c.ID],
+			ConnectedAt: c.JoinedAt,
+			Latency:     time.Duration(c.latency.Load()),
+		})
+	}
+	s.clientsMu.RUnlock()
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ConnectedAt.Before(infos[j].ConnectedAt)
+	})
+	return infos
+}
+
+// ChipBalance 返回牌桌筹码总量和累计发放的筹码，两者不等说明筹码不守恒
+func (s *Server) ChipBalance() (inPlay, issued int) {
+	return s.gameEngine.ChipBalance()
+}
+
+// pingPayload 生成携带发送时间的 ping 数据
+func pingPayload() []byte {
+	return []byte(strconv.FormatInt(time.Now().UnixNano(), 10))
+}
+
+// recordPong 根据 pong 中回传的发送时间记录往返时延
+func (c *Client) recordPong(payload string) {
+	sent, err := strconv.ParseInt(payload, 10, 64)
+	if err != nil {
+		return
+	}
+	if rtt := time.Since(time.Unix(0, sent)); rtt >= 0 {
+		c.latency.Store(int64(rtt))
+	}
+}
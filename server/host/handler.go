@@ -24,6 +24,12 @@ func (s *Server) handleJoin(client *Client, data []byte) {
 	client.Name = req.PlayerName
 	log.Printf("[加入] 收到请求 | 玩家=%s | 客户端ID=%s | 请求座位=%d", req.PlayerName, client.ID, req.Seat)
 
+	if s.isBanned(client) {
+		log.Printf("[加入] 拒绝 | 玩家=%s | IP=%s | 原因=已被封禁", req.PlayerName, client.Addr)
+		s.sendError(client.ID, "You are banned from this table", 2008)
+		return
+	}
+
 	// 检查座位号
 	seat := req.Seat
 	if seat < 0 {
@@ -38,7 +44,8 @@ func (s *Server) handleJoin(client *Client, data []byte) {
 		log.Printf("[加入] 失败 | 玩家=%s | 座位=%d | 错误=%v", req.PlayerName, seat, err)
 		switch err {
 		case gamepkg.ErrGameFull:
-			s.sendError(client.ID, "Game is full", 2001)
+			// 牌桌已满，进入候补队列
+			s.addToWaitlist(client, req.Seat)
 		case gamepkg.ErrInvalidSeat:
 			s.sendError(client.ID, "Invalid seat number", 2002)
 		case gamepkg.ErrSeatOccupied:
@@ -49,14 +56,19 @@ func (s *Server) handleJoin(client *Client, data []byte) {
 		return
 	}
 
-	client.Seat = seat
+	s.completeJoin(client, player)
+}
+
+// completeJoin 玩家入座后发送加入确认并广播（直接加入和候补入座共用）
+func (s *Server) completeJoin(client *Client, player *models.Player) {
+	client.Seat = player.Seat
 
 	// 发送加入确认
 	ack := &protocol.JoinAck{
 		BaseMessage: protocol.NewBaseMessage(protocol.MsgTypeJoinAck),
 		Success:     true,
 		PlayerID:    client.ID,
-		Seat:        seat,
+		Seat:        player.Seat,
 		Message:     "Successfully joined the game!",
 		GameState:   s.getGameStateInfo(client.ID),
 	}
@@ -75,19 +87,21 @@ func (s *Server) handleJoin(client *Client, data []byte) {
 			IsSelf:   false,
 		},
 	}
-	data, _ = json.Marshal(joinedMsg)
+	data, _ := json.Marshal(joinedMsg)
 	s.broadcastToOthers(client.ID, data)
 
 	// 打印当前牌桌玩家列表
 	state := s.gameEngine.GetState()
 	log.Printf("[加入] 成功 | 玩家=%s | 座位=%d | 筹码=%d | 当前玩家数=%d",
-		req.PlayerName, seat, player.Chips, len(state.Players))
+		player.Name, player.Seat, player.Chips, len(state.Players))
+	s.publish(ServerEvent{Kind: EventPlayerJoined, PlayerID: player.ID, PlayerName: player.Name,
+		Message: fmt.Sprintf("%s 入座 座位%d (筹码 %d)", player.Name, player.Seat+1, player.Chips)})
 	s.logPlayerList(state)
 
 	// 如果游戏正在进行中（不是等待/结算阶段），将新玩家标记为弃牌，等下一局参与
-	if state.Stage != gamepkg.StageWaiting && state.Stage != gamepkg.StageShowdown && state.Stage != gamepkg.StageEnd {
+	if state.Stage.IsBetting() {
 		s.gameEngine.SetPlayerStatus(client.ID, models.PlayerStatusFolded)
-		log.Printf("[加入] 游戏进行中，玩家 %s 标记为弃牌，等待下一局参与", req.PlayerName)
+		log.Printf("[加入] 游戏进行中，玩家 %s 标记为弃牌，等待下一局参与", player.Name)
 	}
 	// 不再自动开局，改为大厅准备制：所有玩家按准备后才开始
 }
@@ -96,7 +110,13 @@ func (s *Server) handleJoin(client *Client, data []byte) {
 func (s *Server) handleLeave(client *Client) {
 	log.Printf("[离开] 收到请求 | 玩家=%s | 客户端ID=%s | 座位=%d", client.Name, client.ID, client.Seat)
 
-	if err := s.gameEngine.RemovePlayer(client.ID); err != nil {
+	// 候补中的客户端直接退出队列
+	if s.removeFromWaitlist(client.ID) {
+		log.Printf("[离开] 退出候补队列 | 玩家=%s", client.Name)
+		return
+	}
+
+	if err := s.removeSeatedPlayer(client.ID, client.Name); err != nil {
 		log.Printf("[离开] 失败 | 玩家=%s | 错误=%v", client.Name, err)
 		s.sendError(client.ID, "Failed to leave game", 2004)
 		return
@@ -107,6 +127,32 @@ func (s *Server) handleLeave(client *Client) {
 		client.Name, len(state.Players), state.Stage)
 }
 
+// removeSeatedPlayer 将玩家（或机器人）从牌桌移除
+// 轮到该玩家行动时先代为弃牌；手牌进行中的玩家在本局结束后才真正移除，空出的座位交给候补队列
+func (s *Server) removeSeatedPlayer(playerID, playerName string) error {
+	state := s.gameEngine.GetState()
+	if state.CurrentPlayer < len(state.Players) && state.Players[state.CurrentPlayer].ID == playerID &&
+		state.Stage.IsBetting() {
+		s.processAction(playerID, playerName, models.ActionFold, 0)
+	}
+
+	if err := s.gameEngine.RemovePlayer(playerID); err != nil {
+		return err
+	}
+	delete(s.timeoutCount, playerID)
+	s.cancelSeatChange(playerID)
+	s.publish(ServerEvent{Kind: EventPlayerLeft, PlayerID: playerID, PlayerName: playerName,
+		Message: fmt.Sprintf("%s 离开牌桌", playerName)})
+
+	// 两局之间离开会立即空出座位
+	state = s.gameEngine.GetState()
+	if !state.Stage.IsBetting() {
+		s.seatFromWaitlist()
+		s.startIfAllReady()
+	}
+	return nil
+}
+
 // handlePlayerAction 处理玩家动作
 func (s *Server) handlePlayerAction(client *Client, data []byte) {
 	var req protocol.PlayerActionRequest
@@ -116,70 +162,78 @@ func (s *Server) handlePlayerAction(client *Client, data []byte) {
 		return
 	}
 
+	// 玩家主动行动，清零连续超时计数
+	if s.processAction(client.ID, client.Name, req.Action, req.Amount) {
+		delete(s.timeoutCount, client.ID)
+	}
+}
+
+// processAction 执行玩家动作并推进牌局（玩家请求和超时自动动作共用），返回动作是否执行成功
+func (s *Server) processAction(playerID, playerName string, action models.ActionType, amount int) bool {
 	// 获取动作前的状态快照
 	beforeState := s.gameEngine.GetState()
 	log.Printf("[动作] 收到请求 | 玩家=%s | 动作=%s | 金额=%d | 当前阶段=%s | 底池=%d | 当前下注=%d",
-		client.Name, actionName(req.Action), req.Amount, beforeState.Stage, beforeState.Pot, beforeState.CurrentBet)
+		playerName, actionName(action), amount, beforeState.Stage, beforeState.Pot, beforeState.CurrentBet)
+
+	// 房主暂停期间不接受玩家动作
+	if s.paused.Load() {
+		log.Printf("[动作] 拒绝 | 玩家=%s | 原因=游戏已暂停", playerName)
+		s.sendError(playerID, "Game is paused", 3004)
+		return false
+	}
 
 	// 检查游戏是否在下注阶段（等待/摊牌/结束阶段不接受玩家动作）
-	if beforeState.Stage == gamepkg.StageWaiting || beforeState.Stage == gamepkg.StageShowdown || beforeState.Stage == gamepkg.StageEnd {
-		log.Printf("[动作] 拒绝 | 玩家=%s | 原因=当前阶段(%s)不是下注阶段", client.Name, beforeState.Stage)
-		s.sendError(client.ID, "Game is not in a betting stage", 3003)
-		return
+	if !beforeState.Stage.IsBetting() {
+		log.Printf("[动作] 拒绝 | 玩家=%s | 原因=当前阶段(%s)不是下注阶段", playerName, beforeState.Stage)
+		s.sendError(playerID, "Game is not in a betting stage", 3003)
+		return false
 	}
 
 	// 验证是否是该玩家的回合
 	if beforeState.CurrentPlayer >= len(beforeState.Players) {
 		log.Printf("[动作] 拒绝 | 玩家=%s | 原因=CurrentPlayer(%d) >= 玩家数(%d)",
-			client.Name, beforeState.CurrentPlayer, len(beforeState.Players))
-		s.sendError(client.ID, "Not your turn", 3001)
-		return
+			playerName, beforeState.CurrentPlayer, len(beforeState.Players))
+		s.sendError(playerID, "Not your turn", 3001)
+		return false
 	}
 
 	currentPlayer := beforeState.Players[beforeState.CurrentPlayer]
-	if currentPlayer.ID != client.ID {
+	if currentPlayer.ID != playerID {
 		log.Printf("[动作] 拒绝 | 玩家=%s | 原因=不是你的回合 | 当前行动玩家=%s(idx=%d)",
-			client.Name, currentPlayer.Name, beforeState.CurrentPlayer)
-		s.sendError(client.ID, "Not your turn", 3001)
-		return
+			playerName, currentPlayer.Name, beforeState.CurrentPlayer)
+		s.sendError(playerID, "Not your turn", 3001)
+		return false
 	}
 
 	// 打印动作前的玩家状态
 	log.Printf("[动作] 执行前状态 | 玩家=%s | 筹码=%d | 已下注=%d | 状态=%s",
-		client.Name, currentPlayer.Chips, currentPlayer.CurrentBet, playerStatusName(currentPlayer.Status))
+		playerName, currentPlayer.Chips, currentPlayer.CurrentBet, playerStatusName(currentPlayer.Status))
 
 	// 执行动作
-	if err := s.gameEngine.PlayerAction(client.ID, req.Action, req.Amount); err != nil {
+	if err := s.gameEngine.PlayerAction(playerID, action, amount); err != nil {
 		log.Printf("[动作] 执行失败 | 玩家=%s | 动作=%s | 金额=%d | 错误=%v",
-			client.Name, actionName(req.Action), req.Amount, err)
-		s.sendError(client.ID, err.Error(), 3002)
+			playerName, actionName(action), amount, err)
+		s.sendError(playerID, err.Error(), 3002)
 
 		// 动作被拒绝，仅在游戏处于下注阶段时重新发送 YourTurn
 		// 非下注阶段（等待/摊牌/结束）不应重发，避免客户端误以为轮到自己
 		afterRejectState := s.gameEngine.GetState()
-		if afterRejectState.Stage == gamepkg.StagePreFlop || afterRejectState.Stage == gamepkg.StageFlop ||
-			afterRejectState.Stage == gamepkg.StageTurn || afterRejectState.Stage == gamepkg.StageRiver {
-			minAction := s.getMinAction(client.ID)
-			maxAction := s.getMaxAction(client.ID)
-			turnMsg := &protocol.YourTurn{
-				BaseMessage: protocol.NewBaseMessage(protocol.MsgTypeYourTurn),
-				PlayerID:    client.ID,
-				MinAction:   minAction,
-				MaxAction:   maxAction,
-				CurrentBet:  beforeState.CurrentBet,
-				TimeLeft:    30,
-			}
-			s.sendToClient(client.ID, turnMsg)
-			log.Printf("[动作] 重发行动通知 | 玩家=%s | 需补=%d | 最大=%d", client.Name, minAction, maxAction)
+		if afterRejectState.Stage.IsBetting() {
+			s.notifyTurn(playerID)
+			log.Printf("[动作] 重发行动通知 | 玩家=%s", playerName)
 		}
-		return
+		return false
 	}
 
+	s.stopTurnTimer()
+
 	// 获取动作后的状态
 	afterState := s.gameEngine.GetState()
 
 	// 打印详细的动作结果
-	log.Printf("[动作] 执行成功 | 玩家=%s | 动作=%s | 金额=%d", client.Name, actionName(req.Action), req.Amount)
+	log.Printf("[动作] 执行成功 | 玩家=%s | 动作=%s | 金额=%d", playerName, actionName(action), amount)
+	s.publish(ServerEvent{Kind: EventAction, PlayerID: playerID, PlayerName: playerName, Action: action, Amount: amount,
+		Message: actionDescription(playerName, action, amount)})
 	log.Printf("[动作] 状态变化 | 阶段: %s→%s | 底池: %d→%d | 当前下注: %d→%d",
 		beforeState.Stage, afterState.Stage, beforeState.Pot, afterState.Pot, beforeState.CurrentBet, afterState.CurrentBet)
 
@@ -189,13 +243,15 @@ func (s *Server) handlePlayerAction(client *Client, data []byte) {
 	// 广播玩家动作
 	actedMsg := &protocol.PlayerActed{
 		BaseMessage: protocol.NewBaseMessage(protocol.MsgTypePlayerActed),
-		PlayerID:    client.ID,
-		PlayerName:  client.Name,
-		Action:      req.Action,
-		Amount:      req.Amount,
+		PlayerID:    playerID,
+		PlayerName:  playerName,
+		Action:      action,
+		Amount:      amount,
+		Stage:       beforeState.Stage,
 	}
-	data, _ = json.Marshal(actedMsg)
+	data, _ := json.Marshal(actedMsg)
 	s.broadcast <- data
+	s.observeBots(actedMsg)
 
 	// 检查游戏状态
 	if afterState.Stage == gamepkg.StageEnd || afterState.Stage == gamepkg.StageShowdown {
@@ -205,37 +261,150 @@ func (s *Server) handlePlayerAction(client *Client, data []byte) {
 		// 广播结算详情给所有玩家
 		s.broadcastShowdownResult(afterState)
 
-		// 重置准备状态，等待玩家确认下一局
+		// 处理离开、换座和候补入座
+		s.settleSeats()
+
+		// 重置准备状态，按开局策略等待下一局
 		s.resetReadyState()
-		log.Printf("[状态机] 等待所有玩家确认下一局...")
-		return
+		s.beginReadyWait()
+		return true
 	}
 
 	// 如果游戏仍在进行，通知下一个行动玩家
 	if afterState.CurrentPlayer < len(afterState.Players) {
 		nextPlayer := afterState.Players[afterState.CurrentPlayer]
-		minAction := s.getMinAction(nextPlayer.ID)
-		maxAction := s.getMaxAction(nextPlayer.ID)
 
 		stageChanged := beforeState.Stage != afterState.Stage
-		log.Printf("[轮转] 下一个行动 | 玩家=%s(idx=%d) | 筹码=%d | 已下注=%d | 需补=%d | 最大=%d | 换轮=%v",
-			nextPlayer.Name, afterState.CurrentPlayer, nextPlayer.Chips, nextPlayer.CurrentBet, minAction, maxAction, stageChanged)
+		log.Printf("[轮转] 下一个行动 | 玩家=%s(idx=%d) | 筹码=%d | 已下注=%d | 换轮=%v",
+			nextPlayer.Name, afterState.CurrentPlayer, nextPlayer.Chips, nextPlayer.CurrentBet, stageChanged)
 
 		// 发送行动通知：换轮时无论是否同一人都要通知，同一轮内只通知不同玩家
-		if stageChanged || nextPlayer.ID != client.ID {
-			turnMsg := &protocol.YourTurn{
-				BaseMessage: protocol.NewBaseMessage(protocol.MsgTypeYourTurn),
-				PlayerID:    nextPlayer.ID,
-				MinAction:   minAction,
-				MaxAction:   maxAction,
-				CurrentBet:  afterState.CurrentBet,
-				TimeLeft:    30,
-			}
-			s.sendToClient(nextPlayer.ID, turnMsg)
+		if stageChanged || nextPlayer.ID != playerID {
+			s.notifyTurn(nextPlayer.ID)
+		} else {
+			s.startTurnTimer(nextPlayer.ID)
+		}
+	}
+	return true
+}
+
+// notifyTurn 通知玩家轮到其行动，并启动行动计时
+func (s *Server) notifyTurn(playerID string) {
+	state := s.gameEngine.GetState()
+	config := s.gameEngine.GetConfig()
+	minAction := s.getMinAction(playerID)
+	maxAction := s.getMaxAction(playerID)
+
+	turnMsg := &protocol.YourTurn{
+		BaseMessage: protocol.NewBaseMessage(protocol.MsgTypeYourTurn),
+		PlayerID:    playerID,
+		MinAction:   minAction,
+		MaxAction:   maxAction,
+		CurrentBet:  state.CurrentBet,
+		TimeLeft:    config.ActionTimeout,
+	}
+	if b := s.findBot(playerID); b != nil {
+		s.scheduleBotTurn(b, turnMsg)
+	} else {
+		s.sendToClient(playerID, turnMsg)
+	}
+	log.Printf("[轮转] 发送行动通知 | 玩家ID=%s | 需补=%d | 最大=%d | 时限=%ds", playerID, minAction, maxAction, config.ActionTimeout)
+
+	s.startTurnTimer(playerID)
+}
+
+// ==================== 行动超时与离座 ====================
+
+// startTurnTimer 为行动玩家启动超时计时器（ActionTimeout<=0 时不计时）
+func (s *Server) startTurnTimer(playerID string) {
+	s.stopTurnTimer()
+
+	timeout := s.gameEngine.GetConfig().ActionTimeout
+	if timeout <= 0 {
+		return
+	}
+
+	s.turnSeq++
+	seq := s.turnSeq
+	s.turnPlayerID = playerID
+	s.turnTimer = time.AfterFunc(time.Duration(timeout)*time.Second, func() {
+		s.timeouts <- seq
+	})
+}
+
+// stopTurnTimer 停止当前行动计时器
+func (s *Server) stopTurnTimer() {
+	if s.turnTimer != nil {
+		s.turnTimer.Stop()
+		s.turnTimer = nil
+	}
+	s.turnPlayerID = ""
+}
+
+// handleTurnTimeout 处理行动超时：能过牌则过牌，否则弃牌；连续超时达到上限时自动离座
+func (s *Server) handleTurnTimeout(seq int) {
+	if seq != s.turnSeq || s.turnPlayerID == "" || s.paused.Load() {
+		return // 过期的超时事件
+	}
+	playerID := s.turnPlayerID
+	s.turnTimer = nil
+	s.turnPlayerID = ""
+
+	state := s.gameEngine.GetState()
+	if state.CurrentPlayer >= len(state.Players) || state.Players[state.CurrentPlayer].ID != playerID {
+		return
+	}
+	player := state.Players[state.CurrentPlayer]
+
+	action := models.ActionFold
+	if player.CurrentBet >= state.CurrentBet {
+		action = models.ActionCheck
+	}
+
+	s.timeoutCount[playerID]++
+	count := s.timeoutCount[playerID]
+	log.Printf("[超时] 玩家行动超时 | 玩家=%s | 自动动作=%s | 连续超时=%d", player.Name, actionName(action), count)
+
+	s.processAction(playerID, player.Name, action, 0)
+
+	maxTimeouts := s.gameEngine.GetConfig().MaxTimeouts
+	if maxTimeouts > 0 && count >= maxTimeouts {
+		delete(s.timeoutCount, playerID)
+		if err := s.gameEngine.SitOut(playerID); err == nil {
+			s.broadcastSystemMessage(fmt.Sprintf("%s 连续超时 %d 次，已自动离座", player.Name, count))
 		}
 	}
 }
 
+// handleSitOut 处理玩家暂时离座请求
+func (s *Server) handleSitOut(client *Client) {
+	log.Printf("[离座] 收到请求 | 玩家=%s | 客户端ID=%s", client.Name, client.ID)
+
+	if err := s.gameEngine.SitOut(client.ID); err != nil {
+		log.Printf("[离座] 失败 | 玩家=%s | 错误=%v", client.Name, err)
+		s.sendError(client.ID, "Failed to sit out", 2005)
+		return
+	}
+	delete(s.timeoutCount, client.ID)
+	s.broadcastSystemMessage(fmt.Sprintf("%s 暂时离座", client.Name))
+
+	// 离座玩家不再参与准备判定，剩余玩家可能已全部准备
+	s.startIfAllReady()
+}
+
+// handleSitIn 处理玩家回到座位请求
+func (s *Server) handleSitIn(client *Client) {
+	log.Printf("[回座] 收到请求 | 玩家=%s | 客户端ID=%s", client.Name, client.ID)
+
+	if err := s.gameEngine.SitIn(client.ID); err != nil {
+		log.Printf("[回座] 失败 | 玩家=%s | 错误=%v", client.Name, err)
+		s.sendError(client.ID, "Failed to sit in", 2006)
+		return
+	}
+	delete(s.timeoutCount, client.ID)
+	s.broadcastSystemMessage(fmt.Sprintf("%s 回到座位", client.Name))
+}
+
 // handleChat 处理聊天消息
 func (s *Server) handleChat(client *Client, data []byte) {
 	var req protocol.ChatRequest
@@ -245,6 +414,8 @@ func (s *Server) handleChat(client *Client, data []byte) {
 	}
 
 	log.Printf("[聊天] 玩家=%s | 内容=%s", client.Name, req.Content)
+	s.publish(ServerEvent{Kind: EventChat, PlayerID: client.ID, PlayerName: client.Name,
+		Message: fmt.Sprintf("%s: %s", client.Name, req.Content)})
 
 	chatMsg := &protocol.ChatMessage{
 		BaseMessage: protocol.NewBaseMessage(protocol.MsgTypeChat),
@@ -267,18 +438,10 @@ func (s *Server) handlePing(client *Client) {
 	s.sendToClient(client.ID, pong)
 }
 
-// findAvailableSeat 查找可用座位
+// findAvailableSeat 查找可用座位（没有空位时返回 -1）
 func (s *Server) findAvailableSeat() int {
-	state := s.gameEngine.GetState()
-	usedSeats := make(map[int]bool)
-	for _, p := range state.Players {
-		usedSeats[p.Seat] = true
-	}
-
-	for i := 0; i < 9; i++ {
-		if !usedSeats[i] {
-			return i
-		}
+	if seats := s.gameEngine.FreeSeats(); len(seats) > 0 {
+		return seats[0]
 	}
 	return -1
 }
@@ -313,8 +476,29 @@ func (s *Server) tryAutoStartHand() {
 
 	log.Printf("[自动开局] 检查条件 | 当前阶段=%s | 玩家数=%d | 最少=%d", state.Stage, len(state.Players), minPlayers)
 
+	if s.paused.Load() {
+		log.Printf("[自动开局] 跳过 | 原因=游戏已暂停")
+		return
+	}
+
+	// 移除离座时间过长的玩家
+	if removed := s.gameEngine.RemoveIdlePlayers(); len(removed) > 0 {
+		for _, p := range removed {
+			leftMsg := &protocol.PlayerLeft{
+				BaseMessage: protocol.NewBaseMessage(protocol.MsgTypePlayerLeft),
+				PlayerID:    p.ID,
+				PlayerName:  p.Name,
+			}
+			data, _ := json.Marshal(leftMsg)
+			s.broadcast <- data
+			s.broadcastSystemMessage(fmt.Sprintf("%s 离座时间过长，已离开牌桌", p.Name))
+		}
+	}
+	s.settleSeats()
+	state = s.gameEngine.GetState()
+
 	// 仅在等待、摊牌、局结束状态下才能开始新的一局
-	if state.Stage != gamepkg.StageWaiting && state.Stage != gamepkg.StageShowdown && state.Stage != gamepkg.StageEnd {
+	if state.Stage.IsBetting() {
 		log.Printf("[自动开局] 跳过 | 原因=当前阶段(%s)不允许开始新局", state.Stage)
 		return
 	}
@@ -325,10 +509,10 @@ func (s *Server) tryAutoStartHand() {
 		return
 	}
 
-	// 统计有筹码的活跃玩家
+	// 统计有筹码且未离座的玩家
 	activeWithChips := 0
 	for _, p := range state.Players {
-		if p.Chips > 0 {
+		if p.Chips > 0 && p.Status != models.PlayerStatusSittingOut && !p.SitOutPending {
 			activeWithChips++
 		}
 	}
@@ -346,6 +530,8 @@ func (s *Server) tryAutoStartHand() {
 	newState := s.gameEngine.GetState()
 	log.Printf("[自动开局] 成功! | 玩家数=%d | 庄家位置=%d | 底池=%d | 当前下注=%d",
 		len(newState.Players), newState.DealerButton, newState.Pot, newState.CurrentBet)
+	s.publish(ServerEvent{Kind: EventHandStarted, State: s.getGameStateInfo(""),
+		Message: fmt.Sprintf("新一局开始 (%s)", newState.ID)})
 	s.logPlayerList(newState)
 
 	// 打印公共牌信息
@@ -354,21 +540,9 @@ func (s *Server) tryAutoStartHand() {
 	// 通知当前行动玩家
 	if newState.CurrentPlayer < len(newState.Players) {
 		nextPlayer := newState.Players[newState.CurrentPlayer]
-		minAction := s.getMinAction(nextPlayer.ID)
-		maxAction := s.getMaxAction(nextPlayer.ID)
-
-		log.Printf("[自动开局] 第一个行动 | 玩家=%s(idx=%d) | 筹码=%d | 已下注=%d | 需补=%d",
-			nextPlayer.Name, newState.CurrentPlayer, nextPlayer.Chips, nextPlayer.CurrentBet, minAction)
-
-		turnMsg := &protocol.YourTurn{
-			BaseMessage: protocol.NewBaseMessage(protocol.MsgTypeYourTurn),
-			PlayerID:    nextPlayer.ID,
-			MinAction:   minAction,
-			MaxAction:   maxAction,
-			CurrentBet:  newState.CurrentBet,
-			TimeLeft:    30,
-		}
-		s.sendToClient(nextPlayer.ID, turnMsg)
+		log.Printf("[自动开局] 第一个行动 | 玩家=%s(idx=%d) | 筹码=%d | 已下注=%d",
+			nextPlayer.Name, newState.CurrentPlayer, nextPlayer.Chips, nextPlayer.CurrentBet)
+		s.notifyTurn(nextPlayer.ID)
 	}
 }
 
@@ -416,6 +590,10 @@ func (s *Server) broadcastShowdownResult(state *gamepkg.GameState) {
 			IsFolded:    pr.IsFolded,
 			ChipsAfter:  pr.ChipsAfter,
 		}
+		if sd.AllInStage != gamepkg.StageWaiting {
+			detail.AllInEquity = pr.AllInEquity
+			detail.EVWonAmount = pr.EVWonAmount
+		}
 		showdownMsg.AllPlayers = append(showdownMsg.AllPlayers, detail)
 	}
 
@@ -427,9 +605,168 @@ func (s *Server) broadcastShowdownResult(state *gamepkg.GameState) {
 
 	log.Printf("[结算广播] 广播结算结果 | 总底池=%d | 赢家数=%d | 提前结束=%v",
 		sd.TotalPot, len(winners), sd.IsEarlyEnd)
+	s.publish(ServerEvent{Kind: EventHandEnded, Showdown: showdownMsg, Message: showdownDescription(showdownMsg)})
 	s.broadcast <- data
 }
 
+// ==================== 候补队列与换座 ====================
+
+// addToWaitlist 将客户端加入候补队列（牌桌已满时），seat 为期望座位（-1 表示任意）
+func (s *Server) addToWaitlist(client *Client, seat int) {
+	for _, w := range s.waitlist {
+		if w.client.ID == client.ID {
+			s.notifyWaitlist()
+			return
+		}
+	}
+
+	s.waitlist = append(s.waitlist, &waitEntry{client: client, seat: seat})
+	log.Printf("[候补] 加入队列 | 玩家=%s | 期望座位=%d | 排队人数=%d", client.Name, seat, len(s.waitlist))
+	s.notifyWaitlist()
+}
+
+// removeFromWaitlist 将客户端移出候补队列，返回是否在队列中
+func (s *Server) removeFromWaitlist(clientID string) bool {
+	for i, w := range s.waitlist {
+		if w.client.ID == clientID {
+			s.waitlist = append(s.waitlist[:i], s.waitlist[i+1:]...)
+			s.notifyWaitlist()
+			return true
+		}
+	}
+	return false
+}
+
+// notifyWaitlist 通知每个候补客户端当前排队位置
+func (s *Server) notifyWaitlist() {
+	for i, w := range s.waitlist {
+		notify := &protocol.WaitlistNotify{
+			BaseMessage: protocol.NewBaseMessage(protocol.MsgTypeWaitlist),
+			Position:    i + 1,
+			Total:       len(s.waitlist),
+			Message:     fmt.Sprintf("牌桌已满，排队等待空位（第 %d/%d 位）", i+1, len(s.waitlist)),
+		}
+		s.sendToClient(w.client.ID, notify)
+	}
+}
+
+// seatFromWaitlist 按先来先到把候补客户端安排到空座位
+func (s *Server) seatFromWaitlist() {
+	seated := false
+	for len(s.waitlist) > 0 {
+		free := s.gameEngine.FreeSeats()
+		if len(free) == 0 {
+			break
+		}
+
+		w := s.waitlist[0]
+		s.waitlist = s.waitlist[1:]
+
+		// 期望座位空闲时优先安排
+		seat := free[0]
+		for _, f := range free {
+			if f == w.seat {
+				seat = f
+				break
+			}
+		}
+
+		player, err := s.gameEngine.AddPlayer(w.client.ID, w.client.Name, seat)
+		if err != nil {
+			log.Printf("[候补] 入座失败 | 玩家=%s | 座位=%d | 错误=%v", w.client.Name, seat, err)
+			continue
+		}
+		log.Printf("[候补] 入座 | 玩家=%s | 座位=%d | 剩余排队=%d", w.client.Name, seat, len(s.waitlist))
+		s.completeJoin(w.client, player)
+		seated = true
+	}
+
+	if seated {
+		s.notifyWaitlist()
+	}
+}
+
+// handleSeatChange 处理换座请求（手牌进行中则在本局结束后生效）
+func (s *Server) handleSeatChange(client *Client, data []byte) {
+	var req protocol.SeatChangeRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		s.sendError(client.ID, "Invalid seat change format", 1001)
+		return
+	}
+
+	log.Printf("[换座] 收到请求 | 玩家=%s | 座位%d → 座位%d", client.Name, client.Seat, req.Seat)
+	if req.Seat == client.Seat {
+		return
+	}
+
+	state := s.gameEngine.GetState()
+	if state.Stage.IsBetting() {
+		if req.Seat < 0 || req.Seat >= s.gameEngine.GetConfig().MaxPlayers {
+			s.sendError(client.ID, "Invalid seat number", 2002)
+			return
+		}
+		s.cancelSeatChange(client.ID)
+		s.seatChanges = append(s.seatChanges, seatChange{playerID: client.ID, seat: req.Seat})
+		s.broadcastSystemMessage(fmt.Sprintf("%s 申请换到座位 %d，本局结束后生效", client.Name, req.Seat+1))
+		return
+	}
+
+	s.applySeatChange(client.ID, req.Seat)
+}
+
+// applySeatChange 执行换座并通知结果，返回是否成功
+func (s *Server) applySeatChange(playerID string, seat int) bool {
+	s.clientsMu.RLock()
+	client, ok := s.clients[playerID]
+	s.clientsMu.RUnlock()
+	if !ok {
+		return false
+	}
+
+	if err := s.gameEngine.ChangeSeat(playerID, seat); err != nil {
+		log.Printf("[换座] 失败 | 玩家=%s | 座位=%d | 错误=%v", client.Name, seat, err)
+		switch err {
+		case gamepkg.ErrInvalidSeat:
+			s.sendError(playerID, "Invalid seat number", 2002)
+		case gamepkg.ErrSeatOccupied:
+			s.sendError(playerID, "Seat is already occupied", 2003)
+		default:
+			s.sendError(playerID, "Failed to change seat", 2007)
+		}
+		return false
+	}
+
+	client.Seat = seat
+	s.broadcastSystemMessage(fmt.Sprintf("%s 换到了座位 %d", client.Name, seat+1))
+	return true
+}
+
+// cancelSeatChange 取消玩家尚未生效的换座申请
+func (s *Server) cancelSeatChange(playerID string) {
+	for i, c := range s.seatChanges {
+		if c.playerID == playerID {
+			s.seatChanges = append(s.seatChanges[:i], s.seatChanges[i+1:]...)
+			return
+		}
+	}
+}
+
+// settleSeats 两局之间整理座位：移除已离开的玩家、执行换座申请、安排候补入座
+func (s *Server) settleSeats() {
+	for _, p := range s.gameEngine.ApplyPendingRemovals() {
+		log.Printf("[离开] 本局结束，移除玩家 | 玩家=%s | 座位=%d", p.Name, p.Seat)
+	}
+
+	// 换座优先于候补入座，按申请顺序执行
+	changes := s.seatChanges
+	s.seatChanges = nil
+	for _, c := range changes {
+		s.applySeatChange(c.playerID, c.seat)
+	}
+
+	s.seatFromWaitlist()
+}
+
 // ==================== 准备下一局相关方法 ====================
 
 // handleReadyForNext 处理玩家准备下一局请求
@@ -438,7 +775,7 @@ func (s *Server) handleReadyForNext(client *Client, data []byte) {
 
 	// 检查当前是否处于等待准备状态
 	state := s.gameEngine.GetState()
-	if state.Stage != gamepkg.StageEnd && state.Stage != gamepkg.StageShowdown && state.Stage != gamepkg.StageWaiting {
+	if state.Stage.IsBetting() {
 		log.Printf("[准备] 拒绝 | 玩家=%s | 原因=当前阶段(%s)不在结算状态", client.Name, state.Stage)
 		s.sendError(client.ID, "当前不在结算阶段", 4001)
 		return
@@ -450,34 +787,132 @@ func (s *Server) handleReadyForNext(client *Client, data []byte) {
 	s.waitingReady = true
 	s.readyMu.Unlock()
 
-	// 构建已准备玩家名称列表
-	readyNames := s.getReadyPlayerNames()
-	totalPlayers := len(state.Players)
-	allReady := len(readyNames) >= totalPlayers
-
-	log.Printf("[准备] 玩家 %s 已准备 | 已准备=%d/%d | 全部准备=%v",
-		client.Name, len(readyNames), totalPlayers, allReady)
+	// 定时策略下，大厅中第一位玩家准备时启动倒计时
+	if s.nextHand.Policy == NextHandTimed && s.nextHandTimer == nil {
+		s.startNextHandCountdown()
+	}
 
 	// 广播准备状态给所有玩家
+	allReady := s.broadcastReadyNotify(state, client.ID, client.Name)
+
+	// 如果所有玩家都准备好了且策略允许，开始下一局
+	if allReady && s.startOnAllReady() {
+		log.Printf("[准备] 所有玩家已准备，开始下一局!")
+		s.startNextHand()
+	}
+}
+
+// broadcastReadyNotify 广播准备进度（含开局策略和倒计时），返回是否所有玩家都已准备
+// playerID 为空表示非玩家触发的通知（如本局结束、倒计时开始）
+func (s *Server) broadcastReadyNotify(state *gamepkg.GameState, playerID, playerName string) bool {
+	// 离座玩家不参与准备判定
+	readyNames, totalPlayers := s.readyProgress(state)
+	allReady := totalPlayers > 0 && len(readyNames) >= totalPlayers
+	countdown := s.nextHandCountdown()
+
+	log.Printf("[准备] 准备进度 | 玩家=%s | 已准备=%d/%d | 全部准备=%v | 策略=%s | 倒计时=%ds",
+		playerName, len(readyNames), totalPlayers, allReady, s.nextHand.Policy, countdown)
+
 	readyMsg := &protocol.PlayerReadyNotify{
 		BaseMessage:  protocol.NewBaseMessage(protocol.MsgTypePlayerReady),
-		PlayerID:     client.ID,
-		PlayerName:   client.Name,
+		PlayerID:     playerID,
+		PlayerName:   playerName,
 		ReadyPlayers: readyNames,
 		TotalPlayers: totalPlayers,
 		AllReady:     allReady,
+		Countdown:    countdown,
+		Policy:       s.nextHand.Policy.String(),
 	}
 	msgData, _ := json.Marshal(readyMsg)
 	s.broadcast <- msgData
+	return allReady
+}
 
-	// 如果所有玩家都准备好了，开始下一局
-	if allReady {
-		log.Printf("[准备] 所有玩家已准备，开始下一局!")
-		s.resetReadyState()
-		s.tryAutoStartHand()
+// startOnAllReady 判断当前策略下全员准备是否立即开局
+func (s *Server) startOnAllReady() bool {
+	switch s.nextHand.Policy {
+	case NextHandTimed:
+		return s.nextHand.SkipWhenReady
+	case NextHandManual:
+		return false
+	default:
+		return true
 	}
 }
 
+// beginReadyWait 本局结束后按开局策略进入等待（定时策略启动倒计时）
+func (s *Server) beginReadyWait() {
+	switch s.nextHand.Policy {
+	case NextHandTimed:
+		s.startNextHandCountdown()
+		log.Printf("[状态机] 下一局将在 %d 秒后自动开始...", s.nextHand.Delay)
+	case NextHandManual:
+		log.Printf("[状态机] 等待房主手动发牌...")
+	default:
+		log.Printf("[状态机] 等待所有玩家确认下一局...")
+	}
+	s.broadcastReadyNotify(s.gameEngine.GetState(), "", "")
+}
+
+// startNextHand 清理准备状态和倒计时并开始下一局
+func (s *Server) startNextHand() {
+	state := s.gameEngine.GetState()
+	if state.Stage.IsBetting() {
+		log.Printf("[准备] 跳过开局 | 原因=当前阶段(%s)手牌进行中", state.Stage)
+		return
+	}
+
+	s.stopNextHandCountdown()
+	s.resetReadyState()
+	s.tryAutoStartHand()
+}
+
+// startNextHandCountdown 启动下一局倒计时
+func (s *Server) startNextHandCountdown() {
+	s.stopNextHandCountdown()
+
+	delay := time.Duration(s.nextHand.Delay) * time.Second
+	s.nextHandSeq++
+	seq := s.nextHandSeq
+	s.nextHandDeadline = time.Now().Add(delay)
+	s.nextHandTimer = time.AfterFunc(delay, func() {
+		s.nextHandCh <- seq
+	})
+}
+
+// stopNextHandCountdown 停止下一局倒计时
+func (s *Server) stopNextHandCountdown() {
+	if s.nextHandTimer != nil {
+		s.nextHandTimer.Stop()
+		s.nextHandTimer = nil
+	}
+	s.nextHandDeadline = time.Time{}
+}
+
+// nextHandCountdown 返回倒计时剩余秒数（向上取整），无倒计时返回 0
+func (s *Server) nextHandCountdown() int {
+	if s.nextHandTimer == nil {
+		return 0
+	}
+	remaining := time.Until(s.nextHandDeadline)
+	if remaining <= 0 {
+		return 0
+	}
+	return int((remaining + time.Second - 1) / time.Second)
+}
+
+// handleNextHandCountdown 处理倒计时结束：不再等待未准备的玩家，直接开始下一局
+func (s *Server) handleNextHandCountdown(seq int) {
+	if seq != s.nextHandSeq || s.nextHandTimer == nil {
+		return // 过期的倒计时事件
+	}
+	s.nextHandTimer = nil
+	s.nextHandDeadline = time.Time{}
+
+	log.Printf("[准备] 倒计时结束，开始下一局!")
+	s.startNextHand()
+}
+
 // resetReadyState 重置所有玩家的准备状态
 func (s *Server) resetReadyState() {
 	s.readyMu.Lock()
@@ -488,21 +923,40 @@ func (s *Server) resetReadyState() {
 	log.Printf("[准备] 已重置所有玩家准备状态")
 }
 
-// getReadyPlayerNames 获取已准备玩家的名称列表
-func (s *Server) getReadyPlayerNames() []string {
+// readyProgress 统计准备进度，返回已准备玩家名称和需要准备的总人数
+// 离座（或即将离座）的玩家和机器人不计入，避免阻塞整桌开局
+func (s *Server) readyProgress(state *gamepkg.GameState) ([]string, int) {
 	s.readyMu.RLock()
 	defer s.readyMu.RUnlock()
 
-	s.clientsMu.RLock()
-	defer s.clientsMu.RUnlock()
-
 	var names []string
-	for playerID := range s.readyPlayers {
-		if client, ok := s.clients[playerID]; ok {
-			names = append(names, client.Name)
+	total := 0
+	for _, p := range state.Players {
+		if p.Status == models.PlayerStatusSittingOut || p.SitOutPending || s.findBot(p.ID) != nil {
+			continue
+		}
+		total++
+		if s.readyPlayers[p.ID] {
+			names = append(names, p.Name)
 		}
 	}
-	return names
+	return names, total
+}
+
+// startIfAllReady 在等待准备期间，如果剩余玩家都已准备且策略允许则开始下一局
+func (s *Server) startIfAllReady() {
+	state := s.gameEngine.GetState()
+	if state.Stage.IsBetting() {
+		return
+	}
+
+	readyNames, totalPlayers := s.readyProgress(state)
+	if len(readyNames) == 0 || len(readyNames) < totalPlayers || !s.startOnAllReady() {
+		return
+	}
+
+	log.Printf("[准备] 剩余玩家均已准备，开始下一局!")
+	s.startNextHand()
 }
 
 // ==================== 日志辅助方法 ====================
@@ -581,6 +1035,8 @@ func playerStatusName(status models.PlayerStatus) string {
 		return "已弃牌"
 	case models.PlayerStatusAllIn:
 		return "全下"
+	case models.PlayerStatusSittingOut:
+		return "离座"
 	default:
 		return fmt.Sprintf("未知(%d)", status)
 	}
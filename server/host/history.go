@@ -0,0 +1,141 @@
+package host
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/protocol"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+)
+
+// ==================== 手牌历史 ====================
+
+// 单次查询返回的手数限制
+const (
+	defaultHistoryCount = 10
+	maxHistoryCount     = 50
+)
+
+// 资金曲线默认/最多统计的手数
+const (
+	defaultGraphCount = 200
+	maxGraphCount     = 1000
+)
+
+// SetHistoryFile 使用指定文件保存手牌历史（加载已有记录，应在 Run 之前调用）
+func (s *Server) SetHistoryFile(filename string, opts game.HistoryOptions) error {
+	history, err := game.NewHistoryManagerWithOptions(filename, opts)
+	if err != nil {
+		return err
+	}
+	s.gameEngine.SetHistory(history)
+	log.Printf("[历史] 使用历史文件 | 文件=%s | 已有手数=%d", filename, history.GetHandCount())
+	return nil
+}
+
+// History 返回手牌历史记录器
+func (s *Server) History() *game.HistoryManager {
+	return s.gameEngine.History()
+}
+
+// handleHistoryRequest 处理手牌历史查询
+func (s *Server) handleHistoryRequest(client *Client, data []byte) {
+	var req protocol.HistoryRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		s.sendError(client.ID, "Invalid history request format", 1001)
+		return
+	}
+
+	history := s.History()
+	if history == nil {
+		s.sendError(client.ID, "Hand history is disabled", 6002)
+		return
+	}
+
+	var hands []game.HandHistory
+	if req.HandID > 0 {
+		hand, ok := history.GetHand(req.HandID)
+		if !ok {
+			log.Printf("[历史] 查询失败 | 玩家=%s | 手牌=#%d | 原因=不存在", client.Name, req.HandID)
+			s.sendError(client.ID, "Hand not found", 6001)
+			return
+		}
+		hands = []game.HandHistory{hand}
+	} else {
+		count := req.Count
+		if count <= 0 {
+			count = defaultHistoryCount
+		}
+		if count > maxHistoryCount {
+			count = maxHistoryCount
+		}
+		hands = history.GetRecentHands(count)
+	}
+
+	for i := range hands {
+		hands[i] = redactHand(hands[i], client.ID)
+	}
+
+	log.Printf("[历史] 查询 | 玩家=%s | 手牌=#%d | 数量=%d | 返回=%d", client.Name, req.HandID, req.Count, len(hands))
+	s.sendToClient(client.ID, &protocol.HistoryResponse{
+		BaseMessage: protocol.NewBaseMessage(protocol.MsgTypeHistoryResponse),
+		Hands:       hands,
+		Total:       history.GetHandCount(),
+	})
+}
+
+// handleGraphRequest 处理资金曲线查询（由最近的手牌历史计算，只返回数值不含底牌）
+func (s *Server) handleGraphRequest(client *Client, data []byte) {
+	var req protocol.GraphRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		s.sendError(client.ID, "Invalid graph request format", 1001)
+		return
+	}
+
+	history := s.History()
+	if history == nil {
+		s.sendError(client.ID, "Hand history is disabled", 6002)
+		return
+	}
+
+	name := req.Name
+	if name == "" {
+		name = client.Name
+	}
+	count := req.Count
+	if count <= 0 {
+		count = defaultGraphCount
+	}
+	if count > maxGraphCount {
+		count = maxGraphCount
+	}
+
+	points := game.BuildSessionGraph(history.GetRecentHands(count), name)
+	log.Printf("[历史] 资金曲线 | 玩家=%s | 查询=%s | 手数=%d | 返回=%d", client.Name, name, count, len(points))
+	s.sendToClient(client.ID, &protocol.GraphResponse{
+		BaseMessage: protocol.NewBaseMessage(protocol.MsgTypeGraphResponse),
+		Name:        name,
+		Points:      points,
+	})
+}
+
+// redactHand 隐藏查询者看不到的底牌（只保留自己和摊牌亮出的底牌）
+func redactHand(hand game.HandHistory, viewerID string) game.HandHistory {
+	shown := make(map[string]bool, len(hand.Showdown))
+	for _, sd := range hand.Showdown {
+		shown[sd.PlayerID] = true
+	}
+
+	players := make([]game.HistoryPlayer, 0, len(hand.Players))
+	for _, p := range hand.Players {
+		if p.ID != viewerID && !shown[p.ID] {
+			p.HoleCards = [2]card.Card{}
+		}
+		players = append(players, p)
+	}
+	hand.Players = players
+	// 牌序会暴露所有底牌和未发的公共牌（可验证洗牌的种子在本局结束时已经公布，保留供玩家验证）
+	hand.Deck = nil
+	return hand
+}
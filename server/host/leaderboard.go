@@ -0,0 +1,170 @@
+package host
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/protocol"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+)
+
+// ==================== 排行榜 ====================
+
+// 单次查询返回的名次数限制
+const (
+	defaultLeaderboardLimit = 20
+	maxLeaderboardLimit     = 100
+)
+
+// handleLeaderboardRequest 处理排行榜查询
+func (s *Server) handleLeaderboardRequest(client *Client, data []byte) {
+	var req protocol.LeaderboardRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		s.sendError(client.ID, "Invalid leaderboard request format", 1001)
+		return
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultLeaderboardLimit
+	}
+	if limit > maxLeaderboardLimit {
+		limit = maxLeaderboardLimit
+	}
+
+	board := s.buildLeaderboard(req.Order, limit)
+	log.Printf("[排行] 查询 | 玩家=%s | 排序=%s | 本场=%d | 累计=%d", client.Name, board.Order, len(board.Session), len(board.AllTime))
+	s.sendToClient(client.ID, board)
+}
+
+// SessionStats 返回本场统计（服务器启动或上次结束本场以来）
+func (s *Server) SessionStats() *game.StatsManager {
+	return s.session
+}
+
+// EndSession 结束本场：广播最终排名后重新开始本场统计（在 Run 协程中执行，Run 必须已启动）
+// 本场还没有打完的手牌时返回 false
+func (s *Server) EndSession(reason string) bool {
+	result := make(chan bool, 1)
+	s.control <- func() {
+		result <- s.endSession(reason)
+	}
+	return <-result
+}
+
+// Shutdown 服务器关闭前广播本场最终排名，并保存玩家统计和关闭手牌历史（Run 必须已启动）
+func (s *Server) Shutdown() {
+	if s.EndSession("服务器关闭") {
+		// 等待最终排名发送给客户端
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	s.saveStats()
+	if history := s.History(); history != nil {
+		if err := history.Close(); err != nil {
+			log.Printf("[历史] 关闭失败 | 错误=%v", err)
+		}
+	}
+}
+
+// endSession 广播本场最终排名和最大赢家/输家，然后清空本场统计
+func (s *Server) endSession(reason string) bool {
+	if s.session.GetTotalHandsPlayed() == 0 {
+		return false
+	}
+
+	board := s.buildLeaderboard(protocol.LeaderboardByProfit, 0)
+	board.Final = true
+	data, _ := json.Marshal(board)
+	s.broadcast <- data
+
+	summary := fmt.Sprintf("本场结束（%s）", reason)
+	if w := board.TopWinner; w != nil {
+		summary += fmt.Sprintf("，最大赢家 %s %+d", w.Name, w.Net)
+	}
+	if l := board.TopLoser; l != nil {
+		summary += fmt.Sprintf("，最大输家 %s %+d", l.Name, l.Net)
+	}
+	s.broadcastSystemMessage(summary)
+
+	log.Printf("[排行] 本场结束 | 原因=%s | 玩家数=%d", reason, len(board.Session))
+	s.session.Clear()
+	return true
+}
+
+// buildLeaderboard 生成本场和历史累计排行榜
+func (s *Server) buildLeaderboard(order protocol.LeaderboardOrder, limit int) *protocol.Leaderboard {
+	if order != protocol.LeaderboardByWinRate {
+		order = protocol.LeaderboardByProfit
+	}
+
+	board := &protocol.Leaderboard{
+		BaseMessage: protocol.NewBaseMessage(protocol.MsgTypeLeaderboard),
+		Order:       order,
+		Session:     leaderboardEntries(s.session, order, limit),
+	}
+	if all := s.Stats(); all != nil {
+		board.AllTime = leaderboardEntries(all, order, limit)
+	}
+
+	// 只有盈利的玩家才算最大赢家，亏损的玩家才算最大输家
+	if top := s.session.GetTopWinner(); top != nil {
+		if e := sessionEntry(s.session, top.Name); e != nil && e.Net > 0 {
+			board.TopWinner = e
+		}
+	}
+	if top := s.session.GetTopLoser(); top != nil {
+		if e := sessionEntry(s.session, top.Name); e != nil && e.Net < 0 {
+			board.TopLoser = e
+		}
+	}
+	return board
+}
+
+// leaderboardEntries 按排序方式生成排行榜条目
+func leaderboardEntries(stats *game.StatsManager, order protocol.LeaderboardOrder, limit int) []protocol.LeaderboardEntry {
+	var ranked []*game.PlayerStats
+	if order == protocol.LeaderboardByWinRate {
+		ranked = stats.GetWinRateLeaderboard(limit)
+	} else {
+		ranked = stats.GetLeaderboard(limit)
+	}
+
+	// 排名只用到不会变化的名称，条目内容取自副本，避免与正在累计的统计竞争
+	names := make([]string, 0, len(ranked))
+	for _, p := range ranked {
+		names = append(names, p.Name)
+	}
+
+	snapshot := stats.Snapshot(names...)
+	entries := make([]protocol.LeaderboardEntry, 0, len(snapshot))
+	for i := range snapshot {
+		entries = append(entries, newLeaderboardEntry(i+1, &snapshot[i]))
+	}
+	return entries
+}
+
+// sessionEntry 返回单个玩家的排行榜条目（不含名次）
+func sessionEntry(stats *game.StatsManager, name string) *protocol.LeaderboardEntry {
+	snapshot := stats.Snapshot(name)
+	if len(snapshot) == 0 {
+		return nil
+	}
+	e := newLeaderboardEntry(0, &snapshot[0])
+	return &e
+}
+
+// newLeaderboardEntry 由玩家统计生成排行榜条目
+func newLeaderboardEntry(rank int, p *game.PlayerStats) protocol.LeaderboardEntry {
+	return protocol.LeaderboardEntry{
+		Rank:     rank,
+		Name:     p.Name,
+		Net:      p.TotalWinnings - p.TotalLosses,
+		Hands:    p.HandsPlayed,
+		HandsWon: p.HandsWon,
+		WinRate:  p.WinRate,
+		BB100:    p.BB100(),
+	}
+}
@@ -2,10 +2,12 @@ package host
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"math/rand"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -33,7 +35,47 @@ type Client struct {
 	Seat     int           // 座位号
 	Name     string        // 玩家名称
 	JoinedAt time.Time     // 加入时间
+	Addr     string        // 客户端IP
 	mu       sync.Mutex    // 连接锁
+
+	latency atomic.Int64 // 最近一次 ping/pong 往返时延（纳秒）
+}
+
+// NextHandPolicy 下一局开局策略
+type NextHandPolicy int
+
+const (
+	NextHandAllReady NextHandPolicy = iota // 所有玩家准备后开局
+	NextHandTimed                          // 倒计时结束后自动开局
+	NextHandManual                         // 由房主手动发牌
+)
+
+func (p NextHandPolicy) String() string {
+	names := []string{"all_ready", "timed", "manual"}
+	if int(p) < len(names) {
+		return names[p]
+	}
+	return "unknown"
+}
+
+// ParseNextHandPolicy 解析开局策略名称（all_ready / timed / manual）
+func ParseNextHandPolicy(name string) (NextHandPolicy, bool) {
+	switch name {
+	case "all_ready", "ready":
+		return NextHandAllReady, true
+	case "timed", "timer":
+		return NextHandTimed, true
+	case "manual":
+		return NextHandManual, true
+	}
+	return NextHandAllReady, false
+}
+
+// NextHandConfig 下一局开局配置
+type NextHandConfig struct {
+	Policy        NextHandPolicy // 开局策略
+	Delay         int            // 倒计时秒数（仅 NextHandTimed 生效）
+	SkipWhenReady bool           // 倒计时期间所有玩家准备后立即开局
 }
 
 // Server WebSocket 服务器
@@ -52,6 +94,55 @@ type Server struct {
 	readyPlayers map[string]bool        // 已准备好下一局的玩家（playerID -> ready）
 	readyMu      sync.RWMutex          // 准备状态锁
 	waitingReady bool                   // 是否正在等待玩家准备
+
+	// 行动超时（仅在 Run 协程中访问）
+	turnTimer    *time.Timer    // 当前行动计时器
+	turnSeq      int            // 行动通知序号（用于识别过期的超时事件）
+	turnPlayerID string         // 当前计时中的行动玩家
+	timeouts     chan int       // 行动超时事件通道（携带通知序号）
+	timeoutCount map[string]int // 玩家连续超时次数
+
+	// 下一局开局策略（仅在 Run 协程中访问）
+	nextHand         NextHandConfig // 开局策略配置
+	nextHandTimer    *time.Timer    // 开局倒计时
+	nextHandSeq      int            // 倒计时序号（用于识别过期的倒计时事件）
+	nextHandDeadline time.Time      // 倒计时截止时间
+	nextHandCh       chan int       // 倒计时结束事件通道
+
+	// 候补与换座（仅在 Run 协程中访问）
+	waitlist    []*waitEntry // 候补队列（先来先到）
+	seatChanges []seatChange // 待生效的换座申请（按申请顺序）
+
+	// 房主管理
+	hostToken    string          // 房主令牌
+	paused       atomic.Bool     // 游戏是否暂停（状态推送协程也会读取）
+	oddsDisabled bool            // 是否禁用客户端的胜率面板
+	bannedNames  map[string]bool // 被封禁的玩家名称
+	bannedIPs    map[string]bool // 被封禁的IP
+
+	// 机器人座位（状态推送协程也会读取，由 botsMu 保护）
+	bots    map[string]*serverBot // 牌桌上的机器人（按玩家ID）
+	botsMu  sync.RWMutex          // 机器人表锁
+	botSeq  int                   // 机器人行动序号（仅在 Run 协程中访问，用于丢弃过期的决定）
+	botNext int                   // 下一个默认机器人名称的编号
+
+	control chan func() // 外部控制指令通道（在 Run 协程中执行）
+	events  eventHub    // 事件订阅者
+
+	// 本场统计（服务器启动或上次结束本场以来，只保存在内存中）
+	session *game.StatsManager
+}
+
+// waitEntry 候补队列条目
+type waitEntry struct {
+	client *Client // 排队的客户端
+	seat   int     // 期望座位（-1 表示任意）
+}
+
+// seatChange 待生效的换座申请
+type seatChange struct {
+	playerID string // 玩家ID
+	seat     int    // 目标座位
 }
 
 // ClientMessage 客户端消息
@@ -74,11 +165,29 @@ func NewServer(config *game.Config) *Server {
 		gameStarted:  false,
 		readyPlayers: make(map[string]bool),
 		waitingReady: false,
+		timeouts:     make(chan int, 10),
+		timeoutCount: make(map[string]int),
+		nextHand:     NextHandConfig{Policy: NextHandAllReady},
+		nextHandCh:   make(chan int, 10),
+		control:      make(chan func(), 10),
+		hostToken:    generateHostToken(),
+		bannedNames:  make(map[string]bool),
+		bannedIPs:    make(map[string]bool),
+		bots:         make(map[string]*serverBot),
+		session:      game.NewStatsManager(),
 	}
 
 	// 设置状态变化回调
 	s.gameEngine.SetOnStateChange(s.onGameStateChange)
 
+	// 默认在内存中记录手牌历史（SetHistoryFile 可改为持久化到文件）
+	history, _ := game.NewHistoryManager("")
+	s.gameEngine.SetHistory(history)
+
+	// 默认在内存中累计玩家统计（SetStatsFile 可改为持久化到文件）
+	s.gameEngine.SetStats(game.NewStatsManager())
+	s.gameEngine.SetOnHandEnd(s.session.RecordHand)
+
 	return s
 }
 
@@ -104,6 +213,7 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		GameID:   gameID,
 		Send:     make(chan []byte, 256),
 		JoinedAt: time.Now(),
+		Addr:     remoteIP(r.RemoteAddr),
 	}
 
 	// 注册客户端
@@ -116,6 +226,10 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 // Run 服务器主循环
 func (s *Server) Run() {
+	// 玩家统计在引擎锁外定期保存，避免每手牌结束时在锁内写盘
+	saveTicker := time.NewTicker(statsSaveInterval)
+	defer saveTicker.Stop()
+
 	for {
 		select {
 		case client := <-s.register:
@@ -129,10 +243,52 @@ func (s *Server) Run() {
 
 		case data := <-s.broadcast:
 			s.broadcastMessage(data)
+
+		case seq := <-s.timeouts:
+			s.handleTurnTimeout(seq)
+
+		case seq := <-s.nextHandCh:
+			s.handleNextHandCountdown(seq)
+
+		case fn := <-s.control:
+			fn()
+
+		case <-saveTicker.C:
+			s.saveStats()
 		}
 	}
 }
 
+// SetNextHandConfig 设置下一局开局策略（应在 Run 之前调用）
+func (s *Server) SetNextHandConfig(config NextHandConfig) {
+	if config.Policy == NextHandTimed && config.Delay <= 0 {
+		config.Delay = 10
+	}
+	s.nextHand = config
+	log.Printf("[配置] 开局策略=%s | 倒计时=%ds | 全员准备提前开局=%v", config.Policy, config.Delay, config.SkipWhenReady)
+}
+
+// SetProvablyFair 开启可验证公平洗牌（应在 Run 之前调用），
+// 每局开局时向玩家公布种子摘要，本局结束后公布种子
+func (s *Server) SetProvablyFair(enabled bool) {
+	s.gameEngine.SetProvablyFair(enabled)
+	log.Printf("[配置] 可验证公平洗牌=%v", enabled)
+}
+
+// SetOddsDisabled 禁用客户端的胜率面板（应在 Run 之前调用），用于正式比赛
+func (s *Server) SetOddsDisabled(disabled bool) {
+	s.oddsDisabled = disabled
+	log.Printf("[配置] 禁用胜率面板=%v", disabled)
+}
+
+// DealNextHand 房主手动发牌，立即开始下一局（可在任意协程调用）
+func (s *Server) DealNextHand() {
+	s.control <- func() {
+		log.Printf("[准备] 房主手动发牌")
+		s.startNextHand()
+	}
+}
+
 // handleRegister 处理客户端注册（仅建立连接，不发送JoinAck，等待客户端发送join请求）
 func (s *Server) handleRegister(client *Client) {
 	s.clientsMu.Lock()
@@ -140,23 +296,38 @@ func (s *Server) handleRegister(client *Client) {
 	s.clientsMu.Unlock()
 
 	log.Printf("[连接] 新客户端连接 | ID=%s | 游戏=%s | 当前连接数=%d", client.ID, client.GameID, len(s.clients))
+	s.publish(ServerEvent{Kind: EventConnected, PlayerID: client.ID, Message: fmt.Sprintf("新连接 %s (%s)", client.ID, client.Addr)})
 }
 
 // handleUnregister 处理客户端注销
 func (s *Server) handleUnregister(client *Client) {
 	s.clientsMu.Lock()
-	if _, ok := s.clients[client.ID]; ok {
+	_, ok := s.clients[client.ID]
+	if ok {
 		delete(s.clients, client.ID)
 		close(client.Send)
 	}
 	s.clientsMu.Unlock()
 
+	// readPump 和 writePump 退出时都会注销，只处理第一次
+	if !ok {
+		return
+	}
+
 	name := client.Name
 	if name == "" {
 		name = "(未命名)"
 	}
 	log.Printf("[断开] 客户端断开 | ID=%s | 玩家=%s | 座位=%d | 剩余连接数=%d",
 		client.ID, name, client.Seat, len(s.clients))
+	s.publish(ServerEvent{Kind: EventDisconnected, PlayerID: client.ID, PlayerName: client.Name, Message: fmt.Sprintf("%s 断开连接", name)})
+
+	// 退出候补队列，或释放座位给候补玩家
+	if !s.removeFromWaitlist(client.ID) && client.Name != "" {
+		if err := s.removeSeatedPlayer(client.ID, client.Name); err != nil && err != game.ErrPlayerNotFound {
+			log.Printf("[断开] 移除玩家失败 | 玩家=%s | 错误=%v", name, err)
+		}
+	}
 
 	// 通知其他玩家该玩家离开
 	s.broadcastPlayerLeft(client)
@@ -194,6 +365,30 @@ func (s *Server) handleMessage(msg *ClientMessage) {
 	case protocol.MsgTypeReadyForNext:
 		s.handleReadyForNext(client, msg.Data)
 
+	case protocol.MsgTypeSitOut:
+		s.handleSitOut(client)
+
+	case protocol.MsgTypeSitIn:
+		s.handleSitIn(client)
+
+	case protocol.MsgTypeSeatChange:
+		s.handleSeatChange(client, msg.Data)
+
+	case protocol.MsgTypeAdminCommand:
+		s.handleAdminCommand(client, msg.Data)
+
+	case protocol.MsgTypeHistoryRequest:
+		s.handleHistoryRequest(client, msg.Data)
+
+	case protocol.MsgTypeStatsRequest:
+		s.handleStatsRequest(client, msg.Data)
+
+	case protocol.MsgTypeLeaderboardRequest:
+		s.handleLeaderboardRequest(client, msg.Data)
+
+	case protocol.MsgTypeGraphRequest:
+		s.handleGraphRequest(client, msg.Data)
+
 	default:
 		log.Printf("[消息] 未知类型 | 类型=%s | 客户端=%s", baseMsg.Type, client.ID)
 		s.sendError(client.ID, "Unknown message type", 1002)
@@ -265,6 +460,21 @@ func (s *Server) sendError(clientID string, message string, code int) {
 	s.sendToClient(clientID, errMsg)
 }
 
+// broadcastSystemMessage 广播系统消息（显示在聊天栏）
+func (s *Server) broadcastSystemMessage(content string) {
+	log.Printf("[系统] %s", content)
+	s.publish(ServerEvent{Kind: EventSystem, Message: content})
+	msg := &protocol.ChatMessage{
+		BaseMessage: protocol.NewBaseMessage(protocol.MsgTypeChat),
+		PlayerID:    "system",
+		PlayerName:  "系统",
+		Content:     content,
+		IsSystem:    true,
+	}
+	data, _ := json.Marshal(msg)
+	s.broadcast <- data
+}
+
 // broadcastPlayerLeft 广播玩家离开消息
 func (s *Server) broadcastPlayerLeft(client *Client) {
 	msg := &protocol.PlayerLeft{
@@ -281,6 +491,7 @@ func (s *Server) broadcastPlayerLeft(client *Client) {
 func (s *Server) onGameStateChange(state *game.GameState) {
 	log.Printf("[状态推送] 引擎状态变化 | 阶段=%s | 底池=%d | 当前下注=%d | 当前玩家idx=%d | 玩家数=%d",
 		state.Stage, state.Pot, state.CurrentBet, state.CurrentPlayer, len(state.Players))
+	s.publish(ServerEvent{Kind: EventState, State: s.getGameStateInfo("")})
 
 	s.clientsMu.RLock()
 	defer s.clientsMu.RUnlock()
@@ -300,6 +511,11 @@ func (s *Server) onGameStateChange(state *game.GameState) {
 			Players:        stateInfo.Players,
 			MinRaise:       stateInfo.MinRaise,
 			MaxRaise:       stateInfo.MaxRaise,
+			BigBlind:       stateInfo.BigBlind,
+			Paused:         stateInfo.Paused,
+			OddsDisabled:   stateInfo.OddsDisabled,
+			FairHash:       stateInfo.FairHash,
+			FairSeed:       stateInfo.FairSeed,
 		}
 
 		data, err := json.Marshal(stateMsg)
@@ -333,6 +549,8 @@ func (s *Server) getGameStateInfo(requestorID string) *protocol.GameState {
 			Status:     p.Status,
 			IsDealer:   p.IsDealer,
 			IsSelf:     p.ID == requestorID,
+			SitOutPending: p.SitOutPending,
+			IsBot:      s.findBot(p.ID) != nil,
 		}
 
 		// 如果是玩家自己，显示底牌
@@ -354,6 +572,11 @@ func (s *Server) getGameStateInfo(requestorID string) *protocol.GameState {
 		Players:       players,
 		MinRaise:      state.CurrentBet * 2,
 		MaxRaise:      state.CurrentBet + s.getPlayerChips(requestorID),
+		BigBlind:      s.gameEngine.GetConfig().BigBlind,
+		Paused:        s.paused.Load(),
+		OddsDisabled:  s.oddsDisabled,
+		FairHash:      state.FairHash,
+		FairSeed:      state.FairSeed,
 	}
 }
 
@@ -370,7 +593,8 @@ func (s *Server) getPlayerChips(playerID string) int {
 
 // writePump 处理向客户端写入消息
 func (c *Client) writePump(s *Server) {
-	ticker := time.NewTicker(30 * time.Second)
+	// 定期 ping 保活并测量往返时延
+	ticker := time.NewTicker(10 * time.Second)
 	defer func() {
 		ticker.Stop()
 		c.Conn.Close()
@@ -393,7 +617,7 @@ func (c *Client) writePump(s *Server) {
 
 		case <-ticker.C:
 			c.Conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-			if err := c.Conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+			if err := c.Conn.WriteMessage(websocket.PingMessage, pingPayload()); err != nil {
 				return
 			}
 		}
@@ -409,7 +633,8 @@ func (c *Client) readPump(s *Server) {
 
 	c.Conn.SetReadLimit(512 * 1024) // 512KB
 	c.Conn.SetReadDeadline(time.Now().Add(60 * time.Second))
-	c.Conn.SetPongHandler(func(string) error {
+	c.Conn.SetPongHandler(func(payload string) error {
+		c.recordPong(payload)
 		c.Conn.SetReadDeadline(time.Now().Add(60 * time.Second))
 		return nil
 	})
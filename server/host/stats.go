@@ -0,0 +1,69 @@
+package host
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/protocol"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+)
+
+// ==================== 玩家统计 ====================
+
+// statsSaveInterval 玩家统计文件的保存间隔（有更新时才写文件，关闭服务器时再保存一次）
+const statsSaveInterval = 30 * time.Second
+
+// SetStatsFile 使用指定文件保存玩家统计（加载已有统计，应在 Run 之前调用）
+func (s *Server) SetStatsFile(filename string) error {
+	stats, err := game.NewStatsManagerWithFile(filename)
+	if err != nil {
+		return err
+	}
+	s.gameEngine.SetStats(stats)
+	log.Printf("[统计] 使用统计文件 | 文件=%s | 玩家数=%d", filename, len(stats.GetAllStats()))
+	return nil
+}
+
+// Stats 返回玩家统计
+func (s *Server) Stats() *game.StatsManager {
+	return s.gameEngine.Stats()
+}
+
+// saveStats 保存有更新的玩家统计（由 Run 协程定期调用，关闭服务器时也会调用）
+func (s *Server) saveStats() {
+	if stats := s.Stats(); stats != nil {
+		if err := stats.Save(); err != nil {
+			log.Printf("[统计] 保存失败 | 错误=%v", err)
+		}
+	}
+}
+
+// handleStatsRequest 处理玩家统计查询（未指定名称时返回牌桌上所有玩家的统计）
+func (s *Server) handleStatsRequest(client *Client, data []byte) {
+	var req protocol.StatsRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		s.sendError(client.ID, "Invalid stats request format", 1001)
+		return
+	}
+
+	stats := s.Stats()
+	if stats == nil {
+		s.sendError(client.ID, "Player stats are disabled", 6003)
+		return
+	}
+
+	names := req.Names
+	if len(names) == 0 {
+		for _, p := range s.gameEngine.GetState().Players {
+			names = append(names, p.Name)
+		}
+	}
+
+	result := stats.Snapshot(names...)
+	log.Printf("[统计] 查询 | 玩家=%s | 请求=%d | 返回=%d", client.Name, len(names), len(result))
+	s.sendToClient(client.ID, &protocol.StatsResponse{
+		BaseMessage: protocol.NewBaseMessage(protocol.MsgTypeStatsResponse),
+		Stats:       result,
+	})
+}
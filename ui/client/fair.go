@@ -0,0 +1,63 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/protocol"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/fair"
+)
+
+// ==================== 可验证公平洗牌 ====================
+
+// trackFairShuffle 记录开局公布的种子摘要和自己的底牌，本局结束公布种子后进行验证
+func (m *Model) trackFairShuffle(state *protocol.GameState) {
+	if state.FairHash == "" {
+		return
+	}
+	if state.FairHash != m.fairHash {
+		// 新的一局
+		m.fairHash = state.FairHash
+		m.fairHole = [2]card.Card{}
+		m.fairChecked = false
+		m.fairErr = nil
+	}
+
+	for _, p := range state.Players {
+		if p.IsSelf && p.HoleCards[0].Rank != 0 {
+			m.fairHole = p.HoleCards
+		}
+	}
+
+	if state.FairSeed == "" || m.fairChecked {
+		return
+	}
+	m.fairChecked = true
+
+	deck, err := fair.Verify(state.FairHash, state.FairSeed)
+	if err == nil {
+		var board []card.Card
+		for _, c := range state.CommunityCards {
+			if c.Rank != 0 {
+				board = append(board, c)
+			}
+		}
+		err = fair.CheckDeal(deck, m.fairHole, board)
+	}
+	m.fairErr = err
+}
+
+// renderFairStatus 渲染洗牌承诺或验证结果（未开启可验证洗牌时为空）
+func (m *Model) renderFairStatus() string {
+	if m.fairHash == "" {
+		return ""
+	}
+	switch {
+	case !m.fairChecked:
+		return styleInactive.Render(fmt.Sprintf("🔒 洗牌承诺 %s…", m.fairHash[:12]))
+	case m.fairErr != nil:
+		return styleError.Render(fmt.Sprintf("✗ 洗牌验证失败: %v", m.fairErr))
+	default:
+		return styleActive.Render(fmt.Sprintf("✓ 洗牌已验证（承诺 %s…）", m.fairHash[:12]))
+	}
+}
@@ -0,0 +1,170 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/protocol"
+	"github.com/wilenwang/just_play/Texas-Holdem/ui/components"
+)
+
+// 资金曲线绘图区大小（不含坐标轴）
+const (
+	graphWidth  = 56
+	graphHeight = 14
+)
+
+// ==================== 资金曲线屏幕 ====================
+
+// openGraph 打开资金曲线屏幕并请求自己最近的手牌曲线
+func (m *Model) openGraph() tea.Cmd {
+	if m.screen != ScreenGraph {
+		m.graphReturn = m.screen
+	}
+	m.screen = ScreenGraph
+	m.graphLoading = true
+	m.err = nil
+
+	return func() tea.Msg {
+		if err := m.client.SendGraphRequest("", 0); err != nil {
+			return ErrorMsg{Err: err}
+		}
+		return nil
+	}
+}
+
+// applyGraph 保存服务器返回的资金曲线
+func (m *Model) applyGraph(resp *protocol.GraphResponse) {
+	m.graphLoading = false
+	m.graph = resp
+}
+
+// updateGraph 更新资金曲线屏幕
+func (m *Model) updateGraph(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "tab", "left", "right", "s":
+		// 切换累计盈亏/筹码
+		m.graphStack = !m.graphStack
+
+	case "r":
+		// 刷新
+		return m, tea.Batch(m.openGraph(), m.tick())
+
+	case "esc", "q", "g", "enter":
+		// 返回
+		m.screen = m.graphReturn
+	}
+
+	return m, m.tick()
+}
+
+// viewGraph 渲染资金曲线屏幕
+func (m *Model) viewGraph() string {
+	var content strings.Builder
+
+	title := "资金曲线"
+	if m.graph != nil {
+		title += " - " + m.graph.Name
+	}
+	content.WriteString(styleTitle.Render(title))
+	content.WriteString("\n\n")
+
+	// 累计盈亏/筹码标签
+	tabs := []string{"累计盈亏", "筹码"}
+	for i, tab := range tabs {
+		if (i == 1) == m.graphStack {
+			tabs[i] = styleHighlight.Render("[" + tab + "]")
+		} else {
+			tabs[i] = styleInactive.Render(" " + tab + " ")
+		}
+	}
+	content.WriteString(strings.Join(tabs, " "))
+	content.WriteString("\n\n")
+
+	switch {
+	case m.graphLoading && m.graph == nil:
+		content.WriteString(styleInactive.Render("加载中..."))
+		content.WriteString("\n")
+	case m.err != nil:
+		content.WriteString(styleError.Render(fmt.Sprintf("错误: %v", m.err)))
+		content.WriteString("\n")
+	case m.graph == nil || len(m.graph.Points) == 0:
+		content.WriteString(styleInactive.Render("还没有参与过的手牌"))
+		content.WriteString("\n")
+	default:
+		content.WriteString(m.renderGraph())
+	}
+
+	content.WriteString("\n")
+	content.WriteString(styleInactive.Render("[Tab] 盈亏/筹码  [R] 刷新  [Esc] 返回"))
+
+	return lipgloss.Place(
+		80, 32,
+		lipgloss.Center, lipgloss.Center,
+		styleBox.Render(content.String()),
+	)
+}
+
+// renderGraph 渲染折线图、图例和汇总
+func (m *Model) renderGraph() string {
+	points := m.graph.Points
+	n := len(points)
+
+	var series []components.ChartSeries
+	if m.graphStack {
+		stack := make([]float64, n)
+		for i, p := range points {
+			stack[i] = float64(p.Stack)
+		}
+		series = []components.ChartSeries{
+			{Name: "筹码", Values: stack, Color: lipgloss.Color("226")},
+		}
+	} else {
+		net := make([]float64, n)
+		ev := make([]float64, n)
+		showdown := make([]float64, n)
+		nonShowdown := make([]float64, n)
+		for i, p := range points {
+			net[i] = float64(p.Net)
+			ev[i] = p.AllInEV
+			showdown[i] = float64(p.Showdown)
+			nonShowdown[i] = float64(p.NonShowdown)
+		}
+		// 净盈亏最后绘制，重叠时显示在最上层；没有全下的手牌时全下EV与净盈亏相同，不单独绘制
+		series = []components.ChartSeries{
+			{Name: "摊牌", Values: showdown, Color: lipgloss.Color("81")},
+			{Name: "非摊牌", Values: nonShowdown, Color: lipgloss.Color("196")},
+		}
+		if points[n-1].AllInHands > 0 {
+			series = append(series, components.ChartSeries{Name: "全下EV", Values: ev, Color: lipgloss.Color("214")})
+		}
+		series = append(series, components.ChartSeries{Name: "净盈亏", Values: net, Color: lipgloss.Color("50")})
+	}
+
+	var b strings.Builder
+	b.WriteString(components.RenderLineChart(series, graphWidth, graphHeight,
+		fmt.Sprintf("#%d", points[0].HandID), fmt.Sprintf("#%d", points[n-1].HandID)))
+	b.WriteString("\n\n")
+	b.WriteString(components.RenderChartLegend(series))
+	b.WriteString("\n\n")
+
+	last := points[n-1]
+	if m.graphStack {
+		low, high := last.Stack, last.Stack
+		for _, p := range points {
+			low = min(low, p.Stack)
+			high = max(high, p.Stack)
+		}
+		b.WriteString(fmt.Sprintf("%d 手  当前 %d  最高 %d  最低 %d", n, last.Stack, high, low))
+	} else {
+		b.WriteString(fmt.Sprintf("%d 手  净盈亏 %+d", n, last.Net))
+		if last.AllInHands > 0 {
+			b.WriteString(fmt.Sprintf("  全下EV %+.0f（%d 次全下）", last.AllInEV, last.AllInHands))
+		}
+		b.WriteString(fmt.Sprintf("  摊牌 %+d  非摊牌 %+d", last.Showdown, last.NonShowdown))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
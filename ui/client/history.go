@@ -0,0 +1,315 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/protocol"
+	game "github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+	"github.com/wilenwang/just_play/Texas-Holdem/ui/components"
+)
+
+// historyFetchCount 每次向服务器请求的手牌数
+const historyFetchCount = 20
+
+// ==================== 手牌历史屏幕 ====================
+
+// openHistory 打开手牌历史屏幕并请求最近的手牌
+func (m *Model) openHistory() tea.Cmd {
+	if m.screen != ScreenHistory {
+		m.historyReturn = m.screen
+	}
+	m.screen = ScreenHistory
+	m.historyDetail = false
+	m.historyLoading = true
+	m.err = nil
+
+	return func() tea.Msg {
+		if err := m.client.SendHistoryRequest(historyFetchCount); err != nil {
+			return ErrorMsg{Err: err}
+		}
+		return nil
+	}
+}
+
+// applyHistory 保存服务器返回的手牌历史（按最新在前排列）
+func (m *Model) applyHistory(resp *protocol.HistoryResponse) {
+	m.historyLoading = false
+	m.historyTotal = resp.Total
+
+	hands := make([]game.HandHistory, 0, len(resp.Hands))
+	for i := len(resp.Hands) - 1; i >= 0; i-- {
+		hands = append(hands, resp.Hands[i])
+	}
+	m.history = hands
+
+	if m.historyCursor >= len(m.history) {
+		m.historyCursor = 0
+	}
+	if len(m.history) == 0 {
+		m.historyDetail = false
+	}
+}
+
+// updateHistory 更新手牌历史屏幕
+func (m *Model) updateHistory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.historyDetail {
+		return m.updateHistoryReplay(msg)
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if m.historyCursor > 0 {
+			m.historyCursor--
+		}
+
+	case "down", "j":
+		if m.historyCursor < len(m.history)-1 {
+			m.historyCursor++
+		}
+
+	case "enter", " ":
+		// 回放选中的手牌
+		if m.historyCursor < len(m.history) {
+			m.historyDetail = true
+			m.historyStep = 0
+		}
+
+	case "r":
+		// 刷新
+		return m, tea.Batch(m.openHistory(), m.tick())
+
+	case "esc", "q", "v":
+		// 返回之前的屏幕
+		m.screen = m.historyReturn
+	}
+
+	return m, m.tick()
+}
+
+// updateHistoryReplay 更新手牌回放视图
+func (m *Model) updateHistoryReplay(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	hand := m.history[m.historyCursor]
+
+	switch msg.String() {
+	case "right", "l", " ":
+		// 下一步
+		if m.historyStep < len(hand.Actions) {
+			m.historyStep++
+		}
+
+	case "left", "h":
+		// 上一步
+		if m.historyStep > 0 {
+			m.historyStep--
+		}
+
+	case "home":
+		m.historyStep = 0
+
+	case "end", "enter":
+		// 直接跳到结果
+		m.historyStep = len(hand.Actions)
+
+	case "esc", "q", "backspace":
+		// 返回列表
+		m.historyDetail = false
+	}
+
+	return m, m.tick()
+}
+
+// viewHistory 渲染手牌历史屏幕
+func (m *Model) viewHistory() string {
+	var content string
+	if m.historyDetail && m.historyCursor < len(m.history) {
+		content = m.viewHistoryReplay(m.history[m.historyCursor])
+	} else {
+		content = m.viewHistoryList()
+	}
+
+	return lipgloss.Place(
+		80, 32,
+		lipgloss.Center, lipgloss.Center,
+		styleBox.Render(content),
+	)
+}
+
+// viewHistoryList 渲染最近手牌列表
+func (m *Model) viewHistoryList() string {
+	var content strings.Builder
+
+	content.WriteString(styleTitle.Render("手牌历史"))
+	content.WriteString("\n\n")
+
+	switch {
+	case m.historyLoading:
+		content.WriteString(styleSubtitle.Render("正在加载..."))
+		content.WriteString("\n\n")
+	case m.err != nil:
+		content.WriteString(styleError.Render(fmt.Sprintf("错误: %v", m.err)))
+		content.WriteString("\n\n")
+	case len(m.history) == 0:
+		content.WriteString(styleInactive.Render("还没有已完成的手牌"))
+		content.WriteString("\n\n")
+	default:
+		content.WriteString(styleSubtitle.Render(fmt.Sprintf("最近 %d 手（共 %d 手）", len(m.history), m.historyTotal)))
+		content.WriteString("\n")
+
+		for i, hand := range m.history {
+			line := fmt.Sprintf("#%-4d %s  盲注 %d/%d  底池 %6d  %-16s %s",
+				hand.HandID, hand.Timestamp.Format("15:04:05"), hand.SmallBlind, hand.BigBlind,
+				hand.Pot, historyWinnerNames(hand), m.historyResultText(hand))
+			if i == m.historyCursor {
+				content.WriteString(styleActive.Render("▶ " + line))
+			} else {
+				content.WriteString("  " + line)
+			}
+			content.WriteString("\n")
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString(styleInactive.Render("[↑/↓] 选择  [Enter] 回放  [R] 刷新  [Esc] 返回"))
+	return content.String()
+}
+
+// viewHistoryReplay 按行动逐步回放一手牌
+func (m *Model) viewHistoryReplay(hand game.HandHistory) string {
+	var content strings.Builder
+
+	content.WriteString(styleTitle.Render(fmt.Sprintf("手牌 #%d 回放", hand.HandID)))
+	content.WriteString("\n")
+	content.WriteString(styleInactive.Render(fmt.Sprintf("%s  盲注 %d/%d  前注 %d",
+		hand.Timestamp.Format("2006-01-02 15:04:05"), hand.SmallBlind, hand.BigBlind, hand.Ante)))
+	content.WriteString("\n\n")
+
+	finished := m.historyStep >= len(hand.Actions)
+
+	// 公共牌：按当前回放到的阶段逐步亮出
+	round := game.StageShowdown
+	if !finished {
+		round = hand.Actions[m.historyStep].Round
+	}
+	board := visibleBoard(hand.CommunityCards, round)
+	content.WriteString(styleSubtitle.Render("公共牌: "))
+	if len(board) > 0 {
+		content.WriteString(components.RenderCardsCompact(board, true))
+	} else {
+		content.WriteString(styleInactive.Render("-"))
+	}
+	content.WriteString("\n")
+
+	// 底池 = 前注和盲注 + 已回放行动的投入
+	pot := 0
+	for _, p := range hand.Players {
+		pot += p.PostedAnte + p.PostedBlind
+	}
+	for _, a := range hand.Actions[:m.historyStep] {
+		pot += a.Amount
+	}
+	content.WriteString(stylePot.Render(fmt.Sprintf("底池: %d", pot)))
+	content.WriteString("\n\n")
+
+	// 玩家
+	for _, p := range hand.Players {
+		marker := "  "
+		if p.ID == m.playerID {
+			marker = "★ "
+		}
+		dealer := " "
+		if p.Seat == hand.DealerSeat {
+			dealer = "D"
+		}
+		cards := styleInactive.Render("[??] [??]")
+		if p.HoleCards[0].Rank != 0 {
+			cards = components.RenderCardsCompact(p.HoleCards[:], true)
+		}
+		line := fmt.Sprintf("%s%s 座位%d %-12s 筹码 %6d  ", marker, dealer, p.Seat+1, p.Name, p.StartChips)
+		if finished {
+			line += fmt.Sprintf("→ %6d (%+d)  ", p.FinalChips, p.FinalChips-p.StartChips)
+		}
+		content.WriteString(line + cards)
+		content.WriteString("\n")
+	}
+	content.WriteString("\n")
+
+	// 行动记录
+	content.WriteString(styleSubtitle.Render(fmt.Sprintf("行动 (%d/%d)", m.historyStep, len(hand.Actions))))
+	content.WriteString("\n")
+	shown := hand.Actions[:m.historyStep]
+	const maxActionLines = 10
+	if len(shown) > maxActionLines {
+		shown = shown[len(shown)-maxActionLines:]
+	}
+	for i, a := range shown {
+		line := fmt.Sprintf("  [%s] %s %s", a.Round.ShortString(), a.PlayerName, getActionText(a.Action))
+		if a.Amount > 0 {
+			line += fmt.Sprintf(" %d", a.Amount)
+		}
+		if i == len(shown)-1 {
+			line = styleHighlight.Render(line)
+		}
+		content.WriteString(line)
+		content.WriteString("\n")
+	}
+
+	// 结果
+	if finished {
+		content.WriteString("\n")
+		for _, s := range hand.Showdown {
+			content.WriteString(fmt.Sprintf("  %s: %s\n", s.PlayerName, s.HandName))
+		}
+		for _, w := range hand.Winners {
+			content.WriteString(styleActive.Render(fmt.Sprintf("  %s 赢得 %d", w.PlayerName, w.Amount)))
+			content.WriteString("\n")
+		}
+	}
+	content.WriteString("\n")
+
+	content.WriteString(styleInactive.Render("[←/→] 上一步/下一步  [Home/End] 开始/结果  [Esc] 返回列表"))
+	return content.String()
+}
+
+// historyResultText 返回自己在该手牌中的盈亏
+func (m *Model) historyResultText(hand game.HandHistory) string {
+	for _, p := range hand.Players {
+		if p.ID == m.playerID {
+			return fmt.Sprintf("你 %+d", p.FinalChips-p.StartChips)
+		}
+	}
+	return ""
+}
+
+// historyWinnerNames 返回获胜者名称列表
+func historyWinnerNames(hand game.HandHistory) string {
+	names := make([]string, 0, len(hand.Winners))
+	for _, w := range hand.Winners {
+		names = append(names, w.PlayerName)
+	}
+	return strings.Join(names, ",")
+}
+
+// visibleBoard 返回进行到指定阶段时已亮出的公共牌
+func visibleBoard(community [5]card.Card, round game.Stage) []card.Card {
+	n := 0
+	switch round {
+	case game.StageFlop:
+		n = 3
+	case game.StageTurn:
+		n = 4
+	case game.StageRiver, game.StageShowdown, game.StageEnd:
+		n = 5
+	}
+
+	var cards []card.Card
+	for _, c := range community[:n] {
+		if c.Rank != 0 {
+			cards = append(cards, c)
+		}
+	}
+	return cards
+}
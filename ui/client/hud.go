@@ -0,0 +1,158 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/protocol"
+	game "github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+)
+
+// ==================== HUD 统计 ====================
+
+// requestStats 请求牌桌上所有玩家的统计
+func (m *Model) requestStats() tea.Cmd {
+	return func() tea.Msg {
+		if err := m.client.SendStatsRequest(); err != nil {
+			return ErrorMsg{Err: err}
+		}
+		return nil
+	}
+}
+
+// applyStats 保存服务器返回的玩家统计（按玩家名称）
+func (m *Model) applyStats(resp *protocol.StatsResponse) {
+	if m.hudStats == nil {
+		m.hudStats = make(map[string]game.PlayerStats)
+	}
+	for _, s := range resp.Stats {
+		m.hudStats[s.Name] = s
+	}
+}
+
+// toggleHUD 显示/隐藏玩家卡片上的 HUD，打开时刷新统计
+func (m *Model) toggleHUD() tea.Cmd {
+	m.hudHidden = !m.hudHidden
+	if m.hudHidden {
+		m.hudDetail = false
+		return nil
+	}
+	return m.requestStats()
+}
+
+// openHUDDetail 打开选中玩家的详细统计（默认选中第一位对手）
+func (m *Model) openHUDDetail() tea.Cmd {
+	if m.gameState == nil || len(m.gameState.Players) == 0 {
+		return nil
+	}
+	m.hudDetail = true
+	if m.hudSelected >= len(m.gameState.Players) || m.gameState.Players[m.hudSelected].IsSelf {
+		m.hudSelected = 0
+		for i, p := range m.gameState.Players {
+			if !p.IsSelf {
+				m.hudSelected = i
+				break
+			}
+		}
+	}
+	return m.requestStats()
+}
+
+// moveHUDSelection 在玩家之间移动详细统计的选择
+func (m *Model) moveHUDSelection(delta int) {
+	if m.gameState == nil || len(m.gameState.Players) == 0 {
+		return
+	}
+	n := len(m.gameState.Players)
+	m.hudSelected = ((m.hudSelected+delta)%n + n) % n
+}
+
+// updateHUDDetail 处理详细统计弹窗中的按键，返回是否已处理
+func (m *Model) updateHUDDetail(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "left", "shift+tab":
+		m.moveHUDSelection(-1)
+	case "right", "tab":
+		m.moveHUDSelection(1)
+	case "esc", "i":
+		m.hudDetail = false
+	default:
+		return false
+	}
+	return true
+}
+
+// renderHUDLine 渲染玩家卡片上的单行 HUD：看到的手数、VPIP/PFR 和攻击因子
+func (m *Model) renderHUDLine(name string, selected bool) string {
+	if m.hudHidden {
+		return ""
+	}
+	s, ok := m.hudStats[name]
+	line := "📊 --"
+	if ok && s.HandsPlayed > 0 {
+		line = fmt.Sprintf("📊 %d手 %.0f/%.0f AF %.1f", s.HandsPlayed, s.VPIP()*100, s.PFR()*100, s.AggressionFactor())
+	}
+	if selected {
+		return styleHighlight.Render("▶ " + line)
+	}
+	return styleInactive.Render(line)
+}
+
+// renderHUDDetail 渲染选中玩家的详细统计弹窗
+func (m *Model) renderHUDDetail() string {
+	if !m.hudDetail || m.gameState == nil || m.hudSelected >= len(m.gameState.Players) {
+		return ""
+	}
+	name := m.gameState.Players[m.hudSelected].Name
+
+	var b strings.Builder
+	b.WriteString(styleSubtitle.Render(fmt.Sprintf("📊 %s 的统计", name)))
+	b.WriteString("\n")
+
+	s, ok := m.hudStats[name]
+	if !ok || s.HandsPlayed == 0 {
+		b.WriteString(styleInactive.Render("暂无统计数据"))
+		b.WriteString("\n")
+	} else {
+		profit := s.TotalWinnings - s.TotalLosses
+		rows := [][2]string{
+			{"手数", fmt.Sprintf("%d", s.HandsPlayed)},
+			{"盈亏", fmt.Sprintf("%+d (%+.1f/手)", profit, s.ProfitPerHand)},
+			{"VPIP / PFR", fmt.Sprintf("%.0f%% / %.0f%%", s.VPIP()*100, s.PFR()*100)},
+			{"3-Bet", fmt.Sprintf("%.0f%% (%d/%d)", s.ThreeBet()*100, s.ThreeBets, s.ThreeBetOpps)},
+			{"Fold to 3-Bet", fmt.Sprintf("%.0f%% (%d/%d)", s.FoldToThreeBet()*100, s.FoldTo3Bets, s.FoldTo3BetOpps)},
+			{"C-Bet", fmt.Sprintf("%.0f%% (%d/%d)", s.CBet()*100, s.CBets, s.CBetOpps)},
+			{"AF", fmt.Sprintf("%.1f", s.AggressionFactor())},
+			{"WTSD / W$SD", fmt.Sprintf("%.0f%% / %.0f%%", s.WTSD()*100, s.WSD()*100)},
+		}
+		label := lipgloss.NewStyle().Width(14)
+		for _, r := range rows {
+			b.WriteString(fmt.Sprintf("  %s %s\n", label.Render(r[0]), r[1]))
+		}
+
+		if positions := s.SortedPositions(); len(positions) > 0 {
+			b.WriteString(styleSubtitle.Render("按位置"))
+			b.WriteString("\n")
+			for _, pos := range positions {
+				ps := s.Positions[pos]
+				vpip, pfr := 0.0, 0.0
+				if ps.Hands > 0 {
+					vpip = float64(ps.VPIPHands) / float64(ps.Hands) * 100
+					pfr = float64(ps.PFRHands) / float64(ps.Hands) * 100
+				}
+				b.WriteString(fmt.Sprintf("  %-6s %4d手  %3.0f/%-3.0f  %+d\n", pos, ps.Hands, vpip, pfr, ps.Profit))
+			}
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(styleInactive.Render("[←/→] 切换玩家  [I/Esc] 关闭"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("228")).
+		Padding(0, 1).
+		Render(strings.TrimRight(b.String(), "\n"))
+}
@@ -0,0 +1,183 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/protocol"
+)
+
+// ==================== 排行榜屏幕 ====================
+
+// openLeaderboard 打开排行榜屏幕并请求最新排名
+func (m *Model) openLeaderboard() tea.Cmd {
+	if m.screen != ScreenLeaderboard {
+		m.leaderboardReturn = m.screen
+	}
+	m.screen = ScreenLeaderboard
+	m.leaderboardLoading = true
+	m.err = nil
+
+	order := m.leaderboardOrder
+	return func() tea.Msg {
+		if err := m.client.SendLeaderboardRequest(order, 0); err != nil {
+			return ErrorMsg{Err: err}
+		}
+		return nil
+	}
+}
+
+// applyLeaderboard 保存服务器返回的排行榜，本场最终排名会直接打开排行榜屏幕
+func (m *Model) applyLeaderboard(board *protocol.Leaderboard) {
+	m.leaderboardLoading = false
+	m.leaderboard = board
+	if !board.Final {
+		return
+	}
+
+	m.leaderboardAllTime = false
+	if m.screen != ScreenLeaderboard {
+		m.leaderboardReturn = m.screen
+		m.screen = ScreenLeaderboard
+	}
+	m.addNotification("本场结束，最终排名已公布")
+}
+
+// updateLeaderboard 更新排行榜屏幕
+func (m *Model) updateLeaderboard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "tab", "left", "right":
+		// 切换本场/历史累计
+		m.leaderboardAllTime = !m.leaderboardAllTime
+
+	case "w":
+		// 切换按盈亏/胜率排序
+		if m.leaderboardOrder == protocol.LeaderboardByWinRate {
+			m.leaderboardOrder = protocol.LeaderboardByProfit
+		} else {
+			m.leaderboardOrder = protocol.LeaderboardByWinRate
+		}
+		return m, tea.Batch(m.openLeaderboard(), m.tick())
+
+	case "r":
+		// 刷新
+		return m, tea.Batch(m.openLeaderboard(), m.tick())
+
+	case "esc", "q", "l", "enter":
+		// 返回
+		m.screen = m.leaderboardReturn
+	}
+
+	return m, m.tick()
+}
+
+// viewLeaderboard 渲染排行榜屏幕
+func (m *Model) viewLeaderboard() string {
+	var content strings.Builder
+
+	title := "排行榜"
+	if m.leaderboard != nil && m.leaderboard.Final {
+		title = "本场最终排名"
+	}
+	content.WriteString(styleTitle.Render(title))
+	content.WriteString("\n\n")
+
+	// 本场/历史累计标签
+	tabs := []string{"本场", "历史累计"}
+	for i, tab := range tabs {
+		if (i == 1) == m.leaderboardAllTime {
+			tabs[i] = styleHighlight.Render("[" + tab + "]")
+		} else {
+			tabs[i] = styleInactive.Render(" " + tab + " ")
+		}
+	}
+	orderName := "净盈亏"
+	if m.leaderboardOrder == protocol.LeaderboardByWinRate {
+		orderName = "胜率（至少 10 手）"
+	}
+	content.WriteString(strings.Join(tabs, " "))
+	content.WriteString(styleInactive.Render("   排序: " + orderName))
+	content.WriteString("\n\n")
+
+	switch {
+	case m.leaderboardLoading && m.leaderboard == nil:
+		content.WriteString(styleInactive.Render("加载中..."))
+		content.WriteString("\n")
+	case m.leaderboard == nil:
+		content.WriteString(styleInactive.Render("暂无排行数据"))
+		content.WriteString("\n")
+	default:
+		entries := m.leaderboard.Session
+		if m.leaderboardAllTime {
+			entries = m.leaderboard.AllTime
+		}
+		content.WriteString(m.renderLeaderboardTable(entries))
+
+		if !m.leaderboardAllTime {
+			if w := m.leaderboard.TopWinner; w != nil {
+				content.WriteString(styleActive.Render(fmt.Sprintf("🏆 最大赢家: %s %+d", w.Name, w.Net)))
+				content.WriteString("\n")
+			}
+			if l := m.leaderboard.TopLoser; l != nil {
+				content.WriteString(styleError.Render(fmt.Sprintf("💸 最大输家: %s %+d", l.Name, l.Net)))
+				content.WriteString("\n")
+			}
+		}
+	}
+
+	if m.err != nil {
+		content.WriteString("\n")
+		content.WriteString(styleError.Render(fmt.Sprintf("错误: %v", m.err)))
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(styleInactive.Render("[Tab] 本场/历史  [W] 切换排序  [R] 刷新  [Esc] 返回"))
+
+	return lipgloss.Place(
+		80, 32,
+		lipgloss.Center, lipgloss.Center,
+		styleBox.Render(content.String()),
+	)
+}
+
+// renderLeaderboardTable 渲染排行榜表格（自己所在的行高亮）
+func (m *Model) renderLeaderboardTable(entries []protocol.LeaderboardEntry) string {
+	if len(entries) == 0 {
+		return styleInactive.Render("暂无排行数据") + "\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(styleSubtitle.Render(leaderboardRow("名次", "玩家", "净盈亏", "手数", "胜率", "bb/100")))
+	b.WriteString("\n")
+	for _, e := range entries {
+		line := leaderboardRow(fmt.Sprintf("%d", e.Rank), e.Name, fmt.Sprintf("%+d", e.Net),
+			fmt.Sprintf("%d", e.Hands), fmt.Sprintf("%.1f%%", e.WinRate*100), fmt.Sprintf("%+.1f", e.BB100))
+		switch {
+		case e.Name == m.playerName:
+			line = styleActive.Render(line + " ★")
+		case e.Net < 0:
+			line = styleInactive.Render(line)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// leaderboardRow 按列宽排列一行（按显示宽度对齐，兼容中文名称）
+func leaderboardRow(rank, name, net, hands, winRate, bb100 string) string {
+	left := lipgloss.NewStyle()
+	right := lipgloss.NewStyle().Align(lipgloss.Right)
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		left.Width(6).Render(rank),
+		left.Width(14).Render(name),
+		right.Width(9).Render(net),
+		right.Width(7).Render(hands),
+		right.Width(8).Render(winRate),
+		right.Width(9).Render(bb100),
+	)
+}
@@ -252,11 +252,14 @@ const (
 	ScreenShowdown                  // 摊牌结果屏幕
 	ScreenResult                    // 结算屏幕
 	ScreenChat                      // 聊天屏幕
+	ScreenHistory                   // 手牌历史屏幕
+	ScreenLeaderboard               // 排行榜屏幕
+	ScreenGraph                     // 资金曲线屏幕
 )
 
 // String 返回屏幕类型的字符串表示
 func (s ScreenType) String() string {
-	names := []string{"连接", "大厅", "游戏", "动作", "摊牌", "结算", "聊天"}
+	names := []string{"连接", "大厅", "游戏", "动作", "摊牌", "结算", "聊天", "历史", "排行", "曲线"}
 	if int(s) < len(names) {
 		return names[s]
 	}
@@ -304,14 +307,64 @@ type Model struct {
 	gameWon         bool               // 是否获胜
 
 	// 结算后准备状态
-	readyPlayers    []string // 已准备好的玩家名称列表
-	totalPlayers    int      // 总玩家数
-	selfReady       bool     // 自己是否已准备
-	resultChoice    int      // 结算屏幕选择：0=下一局，1=退出
+	readyPlayers   []string  // 已准备好的玩家名称列表
+	totalPlayers   int       // 总玩家数
+	selfReady      bool      // 自己是否已准备
+	nextHandPolicy string    // 开局策略：all_ready / timed / manual
+	nextHandAt     time.Time // 下一局自动开始时间（零值表示无倒计时）
+
+	// 候补队列
+	waitlistPos   int // 候补排队位置（0 表示未排队）
+	waitlistTotal int // 候补排队总人数
+	resultChoice   int       // 结算屏幕选择：0=下一局，1=退出
 
 	// 聊天
 	chatModel *components.ChatModel // 聊天组件
 
+	// 手牌历史
+	history        []game.HandHistory // 最近的手牌（最新的在前）
+	historyTotal   int                // 服务器记录的总手数
+	historyCursor  int                // 列表中选中的手牌
+	historyDetail  bool               // 是否在回放选中的手牌
+	historyStep    int                // 回放进度（已展示的行动数）
+	historyLoading bool               // 是否正在等待服务器响应
+	historyReturn  ScreenType         // 关闭历史后返回的屏幕
+
+	// 排行榜
+	leaderboard        *protocol.Leaderboard     // 最近收到的排行榜
+	leaderboardOrder   protocol.LeaderboardOrder // 排序方式
+	leaderboardAllTime bool                      // 是否显示历史累计（否则显示本场）
+	leaderboardLoading bool                      // 是否正在等待服务器响应
+	leaderboardReturn  ScreenType                // 关闭排行榜后返回的屏幕
+
+	// 资金曲线
+	graph        *protocol.GraphResponse // 最近收到的资金曲线
+	graphStack   bool                    // 是否显示筹码曲线（否则显示累计盈亏）
+	graphLoading bool                    // 是否正在等待服务器响应
+	graphReturn  ScreenType              // 关闭资金曲线后返回的屏幕
+
+	// HUD 统计
+	hudStats    map[string]game.PlayerStats // 玩家统计（按名称）
+	hudHidden   bool                        // 是否隐藏玩家卡片上的 HUD
+	hudDetail   bool                        // 是否显示选中玩家的详细统计
+	hudSelected int                         // 详细统计选中的玩家（GameState.Players 下标）
+
+	// 胜率面板
+	oddsShown     bool    // 是否显示胜率面板
+	oddsKey       string  // 当前局面（底牌、公共牌和对手数，为空表示不在牌局中）
+	oddsHand      string  // 当前牌型
+	oddsOuts      int     // 补牌数（-1 表示翻牌前或河牌）
+	oddsUnseen    int     // 未见过的牌数
+	oddsOpponents int     // 未弃牌的对手数
+	oddsEquity    float64 // 对随机手牌的权益（-1 表示正在计算）
+	oddsErr       error   // 权益计算失败原因
+
+	// 可验证公平洗牌
+	fairHash    string       // 本局公布的种子摘要
+	fairHole    [2]card.Card // 本局自己的底牌
+	fairChecked bool         // 本局种子是否已公布并验证
+	fairErr     error        // 验证失败原因
+
 	// 通知消息（带时间戳，用于定时自动消失）
 	notifications []timedNotification // 通知消息列表
 
@@ -426,38 +479,45 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case JoinAckResultMsg:
 		if msg.Success {
 			m.playerID = msg.PlayerID
+			m.waitlistPos = 0
+			m.waitlistTotal = 0
 			m.addNotification(fmt.Sprintf("加入成功! 座位: %d", msg.Seat+1))
 			m.screen = ScreenLobby
-		} else {
-			m.connecting = false
-			m.err = fmt.Errorf("加入游戏失败")
+			return m, tea.Batch(m.requestStats(), m.tick())
 		}
+		m.connecting = false
+		m.err = fmt.Errorf("加入游戏失败")
 		return m, m.tick()
 
 	case GameStateMsg:
 		m.gameState = msg.State
+		m.trackFairShuffle(msg.State)
 		// 只有当游戏真正开始（进入下注阶段）才从大厅切换到游戏屏幕
 		// 等待阶段的状态推送不应触发屏幕切换，玩家需要在大厅按准备
 		if m.screen == ScreenLobby {
 			stage := msg.State.Stage
-			if stage == game.StagePreFlop || stage == game.StageFlop ||
-				stage == game.StageTurn || stage == game.StageRiver {
+			if stage.IsBetting() {
 				m.screen = ScreenGame
 				m.selfReady = false
 				m.readyPlayers = nil
+				m.nextHandAt = time.Time{}
 			}
 		}
-		// 只有当新局真正开始（活跃游戏阶段）时，才从结算屏幕返回游戏屏幕
+		// 只有当新局真正开始（活跃游戏阶段）时，才从结算屏幕（或历史、排行榜、资金曲线屏幕）返回游戏屏幕
 		// 避免摊牌阶段的异步状态推送将客户端从结算屏幕拉回游戏屏幕（竞态条件）
-		if m.screen == ScreenShowdown || m.screen == ScreenResult {
+		if m.screen == ScreenShowdown || m.screen == ScreenResult || m.screen == ScreenHistory || m.screen == ScreenLeaderboard || m.screen == ScreenGraph {
 			stage := msg.State.Stage
-			if stage == game.StagePreFlop || stage == game.StageFlop ||
-				stage == game.StageTurn || stage == game.StageRiver {
+			if stage.IsBetting() {
 				m.screen = ScreenGame
 				m.selfReady = false
 				m.readyPlayers = nil
+				m.nextHandAt = time.Time{}
 			}
 		}
+		return m, tea.Batch(m.tick(), m.refreshOdds())
+
+	case OddsMsg:
+		m.applyOdds(msg)
 		return m, m.tick()
 
 	case ActionSentMsg:
@@ -478,7 +538,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case PlayerJoinedMsg:
 		m.addNotification(fmt.Sprintf("玩家 %s 加入了游戏 (座位 %d)",
 			msg.Player.Name, msg.Player.Seat+1))
-		return m, m.tick()
+		return m, tea.Batch(m.requestStats(), m.tick())
 
 	case PlayerLeftMsg:
 		m.addNotification(fmt.Sprintf("玩家 %s 离开了游戏", msg.PlayerName))
@@ -519,23 +579,56 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.totalPlayers = len(msg.Showdown.AllPlayers)
 		m.resultChoice = 0
 
-		// 先显示摊牌屏幕
+		// 先显示摊牌屏幕，同时刷新本局结束后的统计
 		m.screen = ScreenShowdown
+		m.hudDetail = false
+		return m, tea.Batch(m.requestStats(), m.tick())
+
+	case WaitlistMsg:
+		m.waitlistPos = msg.Notify.Position
+		m.waitlistTotal = msg.Notify.Total
 		return m, m.tick()
 
 	case PlayerReadyMsg:
 		// 更新准备状态
 		m.readyPlayers = msg.Notify.ReadyPlayers
 		m.totalPlayers = msg.Notify.TotalPlayers
+		m.nextHandPolicy = msg.Notify.Policy
+		if msg.Notify.Countdown > 0 {
+			m.nextHandAt = time.Now().Add(time.Duration(msg.Notify.Countdown) * time.Second)
+		} else {
+			m.nextHandAt = time.Time{}
+		}
 
-		if msg.Notify.AllReady {
+		switch {
+		case msg.Notify.PlayerID == "":
+			// 本局结束时的策略通知，不单独提示
+		case msg.Notify.AllReady && msg.Notify.Policy == "manual":
+			m.addNotification("所有玩家已准备，等待房主发牌")
+		case msg.Notify.AllReady:
 			m.addNotification("所有玩家已准备，开始下一局!")
-		} else {
+		default:
 			m.addNotification(fmt.Sprintf("玩家 %s 已准备 (%d/%d)",
 				msg.Notify.PlayerName, len(msg.Notify.ReadyPlayers), msg.Notify.TotalPlayers))
 		}
 		return m, m.tick()
 
+	case HistoryMsg:
+		m.applyHistory(msg.Response)
+		return m, m.tick()
+
+	case StatsMsg:
+		m.applyStats(msg.Response)
+		return m, m.tick()
+
+	case LeaderboardMsg:
+		m.applyLeaderboard(msg.Board)
+		return m, m.tick()
+
+	case GraphMsg:
+		m.applyGraph(msg.Response)
+		return m, m.tick()
+
 	case ChatMsg:
 		// 添加聊天消息
 		if msg.Message.IsSystem {
@@ -547,6 +640,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case ErrorMsg:
 		m.err = msg.Err
+		m.historyLoading = false
+		m.leaderboardLoading = false
+		m.graphLoading = false
 		return m, m.tick()
 	}
 
@@ -572,6 +668,12 @@ func (m *Model) View() string {
 		content = m.viewResult()
 	case ScreenChat:
 		content = m.viewChat()
+	case ScreenHistory:
+		content = m.viewHistory()
+	case ScreenLeaderboard:
+		content = m.viewLeaderboard()
+	case ScreenGraph:
+		content = m.viewGraph()
 	default:
 		content = "未知屏幕"
 	}
@@ -617,6 +719,12 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.updateResult(msg)
 	case ScreenChat:
 		return m.updateChat(msg)
+	case ScreenHistory:
+		return m.updateHistory(msg)
+	case ScreenLeaderboard:
+		return m.updateLeaderboard(msg)
+	case ScreenGraph:
+		return m.updateGraph(msg)
 	}
 
 	return m, m.tick()
@@ -628,8 +736,8 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m *Model) updateConnect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "enter":
-		// 尝试连接
-		if !m.connecting {
+		// 尝试连接（候补排队中不重复连接）
+		if !m.connecting && m.waitlistPos == 0 {
 			m.connecting = true
 			m.err = nil
 			cmd := m.doConnect()
@@ -642,6 +750,15 @@ func (m *Model) updateConnect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.connectField = (m.connectField + 1) % 2
 		return m, m.tick()
 
+	case "esc":
+		// 退出候补队列
+		if m.waitlistPos > 0 {
+			m.waitlistPos = 0
+			m.waitlistTotal = 0
+			return m, tea.Batch(m.leaveWaitlist(), m.tick())
+		}
+		return m, m.tick()
+
 	case "up", "shift+tab":
 		// 上一个输入框
 		m.connectField = (m.connectField - 1 + 2) % 2
@@ -705,6 +822,21 @@ func (m *Model) doConnect() tea.Cmd {
 		OnPlayerReady: func(notify *protocol.PlayerReadyNotify) {
 			m.extMsgChan <- PlayerReadyMsg{Notify: notify}
 		},
+		OnWaitlist: func(notify *protocol.WaitlistNotify) {
+			m.extMsgChan <- WaitlistMsg{Notify: notify}
+		},
+		OnHistory: func(resp *protocol.HistoryResponse) {
+			m.extMsgChan <- HistoryMsg{Response: resp}
+		},
+		OnStats: func(resp *protocol.StatsResponse) {
+			m.extMsgChan <- StatsMsg{Response: resp}
+		},
+		OnLeaderboard: func(board *protocol.Leaderboard) {
+			m.extMsgChan <- LeaderboardMsg{Board: board}
+		},
+		OnGraph: func(resp *protocol.GraphResponse) {
+			m.extMsgChan <- GraphMsg{Response: resp}
+		},
 		OnChat: func(chatMsg *protocol.ChatMessage) {
 			m.extMsgChan <- ChatMsg{Message: chatMsg}
 		},
@@ -773,7 +905,11 @@ func (m *Model) viewConnect() string {
 	content.WriteString(fmt.Sprintf("%s %s\n\n", playerLabel, playerInput))
 
 	// 连接状态
-	if m.connecting {
+	if m.waitlistPos > 0 {
+		content.WriteString(styleHighlight.Render(fmt.Sprintf("牌桌已满，候补排队中：第 %d/%d 位", m.waitlistPos, m.waitlistTotal)))
+		content.WriteString("\n")
+		content.WriteString(styleInactive.Render("有空位时将自动入座，Esc 退出排队"))
+	} else if m.connecting {
 		content.WriteString(styleSubtitle.Render("正在连接..."))
 	} else if m.err != nil {
 		content.WriteString(styleError.Render(fmt.Sprintf("错误: %v", m.err)))
@@ -811,6 +947,28 @@ func (m *Model) updateLobby(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.chatModel.SetVisible(true)
 		m.screen = ScreenChat
 		return m, m.tick()
+
+	case "s":
+		// 暂时离座/回到座位
+		return m, tea.Batch(m.toggleSitOut(), m.tick())
+
+	case "v":
+		// 查看手牌历史
+		return m, tea.Batch(m.openHistory(), m.tick())
+
+	case "l":
+		// 查看排行榜
+		return m, tea.Batch(m.openLeaderboard(), m.tick())
+
+	case "g":
+		// 查看资金曲线
+		return m, tea.Batch(m.openGraph(), m.tick())
+
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		// 换到指定座位
+		seat := int(msg.String()[0] - '1')
+		m.addNotification(fmt.Sprintf("申请换到座位 %d", seat+1))
+		return m, tea.Batch(m.sendSeatChange(seat), m.tick())
 	}
 
 	return m, m.tick()
@@ -855,7 +1013,11 @@ func (m *Model) viewLobby() string {
 
 			// 准备状态
 			readyTag := styleInactive.Render("[等待中]")
-			if isReady {
+			if p.Status == models.PlayerStatusSittingOut || p.SitOutPending {
+				readyTag = styleInactive.Render("[离座]")
+			} else if p.IsBot {
+				readyTag = styleInactive.Render("[机器人]")
+			} else if isReady {
 				readyTag = styleActive.Render("[已准备]")
 			}
 
@@ -872,6 +1034,10 @@ func (m *Model) viewLobby() string {
 		} else {
 			content.WriteString(styleInactive.Render("等待玩家准备..."))
 		}
+		if hint := m.nextHandHint(); hint != "" {
+			content.WriteString("\n")
+			content.WriteString(styleHighlight.Render(hint))
+		}
 	} else {
 		content.WriteString(styleInactive.Render("等待其他玩家加入..."))
 	}
@@ -896,7 +1062,7 @@ func (m *Model) viewLobby() string {
 	}
 
 	// 快捷键提示
-	content.WriteString(styleInactive.Render("[H] 聊天  [Ctrl+C] 退出"))
+	content.WriteString(styleInactive.Render("[H] 聊天  [V] 历史  [L] 排行  [G] 曲线  [S] 离座/回座  [1-9] 换座  [Ctrl+C] 退出"))
 
 	return lipgloss.Place(
 		65, 30,
@@ -909,6 +1075,10 @@ func (m *Model) viewLobby() string {
 
 // updateGame 更新游戏屏幕
 func (m *Model) updateGame(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.hudDetail && m.updateHUDDetail(msg) {
+		return m, m.tick()
+	}
+
 	switch msg.String() {
 	case "f":
 		// 弃牌
@@ -945,6 +1115,30 @@ func (m *Model) updateGame(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.screen = ScreenChat
 		return m, m.tick()
 
+	case "s":
+		// 暂时离座/回到座位
+		return m, tea.Batch(m.toggleSitOut(), m.tick())
+
+	case "u":
+		// 显示/隐藏 HUD
+		return m, tea.Batch(m.toggleHUD(), m.tick())
+
+	case "i":
+		// 查看玩家详细统计
+		return m, tea.Batch(m.openHUDDetail(), m.tick())
+
+	case "l":
+		// 查看排行榜
+		return m, tea.Batch(m.openLeaderboard(), m.tick())
+
+	case "g":
+		// 查看资金曲线
+		return m, tea.Batch(m.openGraph(), m.tick())
+
+	case "o":
+		// 显示/隐藏胜率面板
+		return m, tea.Batch(m.toggleOdds(), m.tick())
+
 	case "q":
 		// 退出
 		return m, tea.Quit
@@ -978,6 +1172,9 @@ func (m *Model) viewGame() string {
 		connStatus = styleWarning.Render("○ 未连接")
 	}
 	playerInfo := styleSubtitle.Render(fmt.Sprintf("玩家: %s", m.playerName))
+	if m.gameState != nil && m.gameState.Paused {
+		connStatus += "  " + styleWarning.Render("⏸ 房主已暂停")
+	}
 	content.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, title, "  ", connStatus, "  ", playerInfo))
 	content.WriteString("\n")
 
@@ -1000,6 +1197,9 @@ func (m *Model) viewGame() string {
 			statusParts = append(statusParts, "  ", betDisplay)
 		}
 		statusParts = append(statusParts, "  ", dealerDisplay)
+		if fairStatus := m.renderFairStatus(); fairStatus != "" {
+			statusParts = append(statusParts, "  ", fairStatus)
+		}
 
 		content.WriteString(lipgloss.JoinHorizontal(lipgloss.Center, statusParts...))
 		content.WriteString("\n\n")
@@ -1013,6 +1213,18 @@ func (m *Model) viewGame() string {
 	content.WriteString(m.renderPlayers())
 	content.WriteString("\n")
 
+	// 胜率面板
+	if odds := m.renderOdds(); odds != "" {
+		content.WriteString(odds)
+		content.WriteString("\n")
+	}
+
+	// 选中玩家的详细统计
+	if detail := m.renderHUDDetail(); detail != "" {
+		content.WriteString(detail)
+		content.WriteString("\n")
+	}
+
 	// 通知消息（只显示未过期的）
 	activeNotifs := m.getActiveNotifications()
 	if len(activeNotifs) > 0 {
@@ -1092,7 +1304,7 @@ func (m *Model) renderPlayers() string {
 
 	var playerCards []string
 
-	for _, p := range m.gameState.Players {
+	for i, p := range m.gameState.Players {
 		var cardContent strings.Builder
 
 		// 第一行：庄家标记 + 玩家名 + 状态标签
@@ -1108,6 +1320,9 @@ func (m *Model) renderPlayers() string {
 		case models.PlayerStatusAllIn:
 			nameLine += lipgloss.NewStyle().Foreground(lipgloss.Color("213")).Bold(true).Render(p.Name)
 			nameLine += " " + styleBtnAllIn.Render(" ALL IN ")
+		case models.PlayerStatusSittingOut:
+			nameLine += styleInactive.Render(p.Name)
+			nameLine += " " + styleInactive.Render("[离座]")
 		default:
 			if p.IsSelf {
 				nameLine += styleActive.Render(p.Name) + " " + styleActive.Render("★")
@@ -1115,6 +1330,9 @@ func (m *Model) renderPlayers() string {
 				nameLine += lipgloss.NewStyle().Foreground(lipgloss.Color("255")).Render(p.Name)
 			}
 		}
+		if p.IsBot {
+			nameLine += " " + styleInactive.Render("🤖")
+		}
 		cardContent.WriteString(nameLine)
 		cardContent.WriteString("\n")
 
@@ -1124,7 +1342,7 @@ func (m *Model) renderPlayers() string {
 		if p.CurrentBet > 0 {
 			betText = fmt.Sprintf("  🎯 下注: %d", p.CurrentBet)
 		}
-		if p.Status == models.PlayerStatusFolded {
+		if p.Status == models.PlayerStatusFolded || p.Status == models.PlayerStatusSittingOut {
 			cardContent.WriteString(styleInactive.Render(chipsText + betText))
 		} else {
 			cardContent.WriteString(stylePot.Render(chipsText))
@@ -1142,13 +1360,19 @@ func (m *Model) renderPlayers() string {
 			holeCards := components.RenderCardsCompact(p.HoleCards[:], true)
 			cardContent.WriteString("🃏 " + holeCards)
 		} else {
-			if p.Status == models.PlayerStatusFolded {
+			if p.Status == models.PlayerStatusFolded || p.Status == models.PlayerStatusSittingOut {
 				cardContent.WriteString(styleInactive.Render("🃏 [--] [--]"))
 			} else {
 				cardContent.WriteString(styleSubtitle.Render("🃏 [??] [??]"))
 			}
 		}
 
+		// 第四行：HUD 统计
+		if hud := m.renderHUDLine(p.Name, m.hudDetail && i == m.hudSelected); hud != "" {
+			cardContent.WriteString("\n")
+			cardContent.WriteString(hud)
+		}
+
 		// 选择卡片样式
 		var cardStyle lipgloss.Style
 		isCurrentPlayer := m.gameState != nil && m.gameState.CurrentPlayer == p.Seat
@@ -1156,7 +1380,7 @@ func (m *Model) renderPlayers() string {
 			cardStyle = stylePlayerCardActive
 		} else if p.IsSelf {
 			cardStyle = stylePlayerCardSelf
-		} else if p.Status == models.PlayerStatusFolded {
+		} else if p.Status == models.PlayerStatusFolded || p.Status == models.PlayerStatusSittingOut {
 			cardStyle = stylePlayerCardFolded
 		} else {
 			cardStyle = stylePlayerCard
@@ -1249,8 +1473,16 @@ func (m *Model) renderActionPrompt() string {
 	content.WriteString("\n\n")
 	funcActions := []string{
 		styleBtnFunc.Render(" H 聊天 "),
-		styleBtnFunc.Render(" Q 退出 "),
+		styleBtnFunc.Render(" S 离座/回座 "),
+		styleBtnFunc.Render(" U HUD "),
+		styleBtnFunc.Render(" I 统计 "),
+		styleBtnFunc.Render(" L 排行 "),
+		styleBtnFunc.Render(" G 曲线 "),
+	}
+	if m.gameState == nil || !m.gameState.OddsDisabled {
+		funcActions = append(funcActions, styleBtnFunc.Render(" O 胜率 "))
 	}
+	funcActions = append(funcActions, styleBtnFunc.Render(" Q 退出 "))
 	content.WriteString(strings.Join(funcActions, sep))
 
 	return styleActionBar.Render(content.String())
@@ -1337,6 +1569,18 @@ func (m *Model) updateShowdown(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// 进入结算屏幕
 		m.screen = ScreenResult
 		return m, m.tick()
+
+	case "v":
+		// 查看手牌历史
+		return m, tea.Batch(m.openHistory(), m.tick())
+
+	case "l":
+		// 查看排行榜
+		return m, tea.Batch(m.openLeaderboard(), m.tick())
+
+	case "g":
+		// 查看资金曲线
+		return m, tea.Batch(m.openGraph(), m.tick())
 	}
 
 	return m, m.tick()
@@ -1398,7 +1642,7 @@ func (m *Model) viewShowdown() string {
 	content.WriteString("\n")
 	content.WriteString(m.renderPlayerDetails(m.showdown.AllPlayers, ""))
 	content.WriteString("\n")
-	content.WriteString(styleInactive.Render("[Enter] 查看结算"))
+	content.WriteString(styleInactive.Render("[Enter] 查看结算  [V] 历史  [L] 排行  [G] 曲线"))
 
 	return lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -1440,6 +1684,18 @@ func (m *Model) updateResult(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// 选择"退出"
 		return m, tea.Quit
 
+	case "v":
+		// 查看手牌历史
+		return m, tea.Batch(m.openHistory(), m.tick())
+
+	case "l":
+		// 查看排行榜
+		return m, tea.Batch(m.openLeaderboard(), m.tick())
+
+	case "g":
+		// 查看资金曲线
+		return m, tea.Batch(m.openGraph(), m.tick())
+
 	case "q":
 		// 退出游戏
 		return m, tea.Quit
@@ -1458,6 +1714,58 @@ func (m *Model) sendReadyForNext() tea.Cmd {
 	}
 }
 
+// sendSeatChange 发送换座请求
+func (m *Model) sendSeatChange(seat int) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.client.SendSeatChange(seat); err != nil {
+			return ErrorMsg{Err: err}
+		}
+		return nil
+	}
+}
+
+// leaveWaitlist 退出候补队列
+func (m *Model) leaveWaitlist() tea.Cmd {
+	return func() tea.Msg {
+		if err := m.client.SendLeave(); err != nil {
+			return ErrorMsg{Err: err}
+		}
+		return nil
+	}
+}
+
+// toggleSitOut 根据自身状态发送离座或回座请求
+func (m *Model) toggleSitOut() tea.Cmd {
+	sittingOut := false
+	if m.gameState != nil {
+		for _, p := range m.gameState.Players {
+			if p.ID == m.playerID {
+				sittingOut = p.Status == models.PlayerStatusSittingOut || p.SitOutPending
+				break
+			}
+		}
+	}
+
+	if sittingOut {
+		m.addNotification("正在回到座位...")
+	} else {
+		m.addNotification("已申请离座，本局结束后生效")
+	}
+
+	return func() tea.Msg {
+		var err error
+		if sittingOut {
+			err = m.client.SendSitIn()
+		} else {
+			err = m.client.SendSitOut()
+		}
+		if err != nil {
+			return ErrorMsg{Err: err}
+		}
+		return nil
+	}
+}
+
 // viewResult 渲染结算屏幕
 func (m *Model) viewResult() string {
 	var content strings.Builder
@@ -1476,6 +1784,12 @@ func (m *Model) viewResult() string {
 	content.WriteString(fmt.Sprintf("最终筹码: %d", m.finalChips))
 	content.WriteString("\n\n")
 
+	// 可验证洗牌结果
+	if fairStatus := m.renderFairStatus(); fairStatus != "" {
+		content.WriteString(fairStatus)
+		content.WriteString("\n\n")
+	}
+
 	// 获胜者列表
 	if m.gameResult != nil && len(m.gameResult.Winners) > 0 {
 		content.WriteString(styleHighlight.Render("获胜者:"))
@@ -1524,6 +1838,11 @@ func (m *Model) viewResult() string {
 func (m *Model) renderReadyStatus() string {
 	var content strings.Builder
 
+	if hint := m.nextHandHint(); hint != "" {
+		content.WriteString(styleHighlight.Render("  " + hint))
+		content.WriteString("\n")
+	}
+
 	if len(m.readyPlayers) > 0 {
 		content.WriteString(styleSubtitle.Render(fmt.Sprintf("  准备状态 (%d/%d):",
 			len(m.readyPlayers), m.totalPlayers)))
@@ -1543,6 +1862,21 @@ func (m *Model) renderReadyStatus() string {
 	return content.String()
 }
 
+// nextHandHint 返回下一局开局提示（倒计时或等待房主发牌）
+func (m *Model) nextHandHint() string {
+	if !m.nextHandAt.IsZero() {
+		remaining := int(time.Until(m.nextHandAt).Seconds() + 0.999)
+		if remaining > 0 {
+			return fmt.Sprintf("⏱ 下一局将在 %d 秒后自动开始", remaining)
+		}
+		return "⏱ 即将开始下一局..."
+	}
+	if m.nextHandPolicy == "manual" {
+		return "等待房主发牌..."
+	}
+	return ""
+}
+
 // renderResultMenu 渲染结算屏幕选择菜单
 func (m *Model) renderResultMenu() string {
 	var content strings.Builder
@@ -1568,7 +1902,7 @@ func (m *Model) renderResultMenu() string {
 	content.WriteString("\n\n")
 
 	// 快捷键提示
-	content.WriteString(styleInactive.Render("[↑/↓] 选择  [Enter] 确认  [Q] 退出"))
+	content.WriteString(styleInactive.Render("[↑/↓] 选择  [Enter] 确认  [V] 历史  [L] 排行  [G] 曲线  [Q] 退出"))
 
 	return content.String()
 }
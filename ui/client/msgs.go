@@ -63,6 +63,38 @@ type PlayerReadyMsg struct {
 	Notify *protocol.PlayerReadyNotify
 }
 
+// WaitlistMsg 候补队列状态消息
+type WaitlistMsg struct {
+	Notify *protocol.WaitlistNotify
+}
+
+// HistoryMsg 手牌历史响应消息
+type HistoryMsg struct {
+	Response *protocol.HistoryResponse
+}
+
+// StatsMsg 玩家统计响应消息
+type StatsMsg struct {
+	Response *protocol.StatsResponse
+}
+
+// LeaderboardMsg 排行榜消息
+type LeaderboardMsg struct {
+	Board *protocol.Leaderboard
+}
+
+// GraphMsg 资金曲线消息
+type GraphMsg struct {
+	Response *protocol.GraphResponse
+}
+
+// OddsMsg 胜率面板的后台权益计算结果
+type OddsMsg struct {
+	Key    string  // 计算时的局面
+	Equity float64 // 对随机手牌的权益
+	Err    error   // 计算失败原因
+}
+
 // ChatMsg 聊天消息
 type ChatMsg struct {
 	Message *protocol.ChatMessage
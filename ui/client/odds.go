@@ -0,0 +1,174 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/equity"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/evaluator"
+)
+
+// 胜率面板的估算精度（对随机手牌的粗略权益，不需要太精确）
+const (
+	oddsIterations = 20000  // 蒙特卡洛最多模拟次数
+	oddsExactLimit = 200000 // 发牌情况不超过该数时精确枚举
+	oddsPrecision  = 0.01   // 误差小于该值时提前结束
+)
+
+// ==================== 胜率面板 ====================
+
+// toggleOdds 显示/隐藏胜率面板（房主禁用时不可打开）
+func (m *Model) toggleOdds() tea.Cmd {
+	if !m.oddsShown && m.gameState != nil && m.gameState.OddsDisabled {
+		m.addNotification("房主已禁用胜率面板")
+		return nil
+	}
+	m.oddsShown = !m.oddsShown
+	m.oddsKey = ""
+	return m.refreshOdds()
+}
+
+// refreshOdds 局面变化时重新计算牌型和补牌，并在后台估算对随机手牌的权益
+func (m *Model) refreshOdds() tea.Cmd {
+	if !m.oddsShown || m.gameState == nil {
+		return nil
+	}
+	if m.gameState.OddsDisabled {
+		m.oddsShown = false
+		return nil
+	}
+
+	var hole [2]card.Card
+	inHand := false
+	opponents := 0
+	for _, p := range m.gameState.Players {
+		if p.Status != models.PlayerStatusActive && p.Status != models.PlayerStatusAllIn {
+			continue
+		}
+		if p.IsSelf {
+			hole = p.HoleCards
+			inHand = hole[0].Rank != 0 && hole[1].Rank != 0
+		} else {
+			opponents++
+		}
+	}
+	if !inHand || opponents == 0 {
+		m.oddsKey = ""
+		return nil
+	}
+
+	var board []card.Card
+	for _, c := range m.gameState.CommunityCards {
+		if c.Rank != 0 {
+			board = append(board, c)
+		}
+	}
+
+	key := fmt.Sprint(hole, board, opponents)
+	if key == m.oddsKey {
+		return nil
+	}
+	m.oddsKey = key
+	m.oddsOpponents = opponents
+	m.oddsHand = madeHand(hole, board)
+	m.oddsOuts = -1
+	if outs, err := equity.Outs(hole, board); err == nil {
+		m.oddsOuts = len(outs)
+	}
+	m.oddsUnseen = 52 - 2 - len(board)
+	m.oddsEquity = -1
+	m.oddsErr = nil
+
+	return func() tea.Msg {
+		ranges := []*equity.Range{equity.NewRange(hole)}
+		for i := 0; i < opponents; i++ {
+			ranges = append(ranges, equity.RandomRange())
+		}
+		r, err := equity.CalculateRanges(ranges, board, nil, equity.Options{
+			Iterations: oddsIterations,
+			ExactLimit: oddsExactLimit,
+			Precision:  oddsPrecision,
+		})
+		if err != nil {
+			return OddsMsg{Key: key, Err: err}
+		}
+		return OddsMsg{Key: key, Equity: r.Hands[0].Equity}
+	}
+}
+
+// applyOdds 保存后台计算的权益（局面已变化时丢弃）
+func (m *Model) applyOdds(msg OddsMsg) {
+	if msg.Key != m.oddsKey {
+		return
+	}
+	m.oddsEquity = msg.Equity
+	m.oddsErr = msg.Err
+}
+
+// madeHand 返回当前的牌型名称（翻牌前只区分对子和高牌）
+func madeHand(hole [2]card.Card, board []card.Card) string {
+	if len(board) < 3 {
+		if hole[0].Rank == hole[1].Rank {
+			return evaluator.RankOnePair.String()
+		}
+		return evaluator.RankHighCard.String()
+	}
+	eval, err := evaluator.NewFastEvaluator().EvaluateCards(append([]card.Card{hole[0], hole[1]}, board...))
+	if err != nil {
+		return "--"
+	}
+	return eval.Rank.String()
+}
+
+// renderOdds 渲染胜率面板：牌型、补牌、底池赔率和对随机手牌的权益
+func (m *Model) renderOdds() string {
+	if !m.oddsShown || m.oddsKey == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(styleSubtitle.Render("🎲 胜率"))
+	b.WriteString("\n")
+	label := lipgloss.NewStyle().Width(10)
+
+	b.WriteString(fmt.Sprintf("  %s %s\n", label.Render("牌型"), m.oddsHand))
+
+	if m.oddsOuts >= 0 {
+		b.WriteString(fmt.Sprintf("  %s %d 张（下一张命中 %.1f%%）\n", label.Render("补牌"),
+			m.oddsOuts, float64(m.oddsOuts)/float64(m.oddsUnseen)*100))
+	}
+
+	// 底池赔率：跟注额占跟注后底池的比例，即不亏损所需的最低权益
+	need := 0.0
+	toCall := m.calculateToCall()
+	if toCall > 0 {
+		need = float64(toCall) / float64(m.gameState.Pot+toCall)
+		b.WriteString(fmt.Sprintf("  %s 跟注 %d / 底池 %d，需要 %.1f%%\n", label.Render("底池赔率"),
+			toCall, m.gameState.Pot, need*100))
+	} else {
+		b.WriteString(fmt.Sprintf("  %s 无需跟注\n", label.Render("底池赔率")))
+	}
+
+	var eq string
+	switch {
+	case m.oddsErr != nil:
+		eq = styleInactive.Render("--")
+	case m.oddsEquity < 0:
+		eq = styleInactive.Render("计算中...")
+	case toCall > 0 && m.oddsEquity < need:
+		eq = styleWarning.Render(fmt.Sprintf("%.1f%%", m.oddsEquity*100))
+	default:
+		eq = styleActive.Render(fmt.Sprintf("%.1f%%", m.oddsEquity*100))
+	}
+	b.WriteString(fmt.Sprintf("  %s %s（对 %d 名随机对手）", label.Render("权益"), eq, m.oddsOpponents))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("117")).
+		Padding(0, 1).
+		Render(b.String())
+}
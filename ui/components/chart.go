@@ -0,0 +1,216 @@
+package components
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// 折线图颜色
+var (
+	chartAxisColor = lipgloss.Color("240") // 坐标轴
+	chartZeroColor = lipgloss.Color("238") // 零线
+)
+
+// ChartSeries 折线图中的一条曲线
+type ChartSeries struct {
+	Name   string         // 曲线名称（用于图例）
+	Values []float64      // 数据点（按横轴顺序）
+	Color  lipgloss.Color // 曲线颜色
+}
+
+// chartCell 绘图区中的一个字符格
+type chartCell struct {
+	char   rune // 字符
+	series int  // 所属曲线（-1 表示空白，-2 表示零线）
+}
+
+// RenderLineChart 将多条曲线渲染为字符折线图
+// width/height 为绘图区大小（不含坐标轴），xStart/xEnd 为横轴两端的标注；
+// 曲线按顺序绘制，后面的曲线覆盖前面的；数据点多于宽度时按列抽样
+func RenderLineChart(series []ChartSeries, width, height int, xStart, xEnd string) string {
+	if width < 2 || height < 2 {
+		return ""
+	}
+	n := 0
+	values := make([][]float64, len(series))
+	for i, s := range series {
+		values[i] = resampleChart(s.Values, width)
+		n = max(n, len(values[i]))
+	}
+	if n == 0 {
+		return ""
+	}
+
+	// 纵轴范围（始终包含零）
+	lo, hi := 0.0, 0.0
+	for _, vs := range values {
+		for _, v := range vs {
+			lo = math.Min(lo, v)
+			hi = math.Max(hi, v)
+		}
+	}
+	if hi == lo {
+		hi = lo + 1
+	}
+	row := func(v float64) int {
+		return int(math.Round((hi - v) / (hi - lo) * float64(height-1)))
+	}
+	col := func(i int) int {
+		if n == 1 {
+			return 0
+		}
+		return i * (width - 1) / (n - 1)
+	}
+
+	grid := make([][]chartCell, height)
+	for y := range grid {
+		grid[y] = make([]chartCell, width)
+		for x := range grid[y] {
+			grid[y][x] = chartCell{char: ' ', series: -1}
+		}
+	}
+
+	// 零线
+	zero := row(0)
+	if lo < 0 && hi > 0 {
+		for x := range grid[zero] {
+			grid[zero][x] = chartCell{char: '┈', series: -2}
+		}
+	}
+
+	for si, vs := range values {
+		if len(vs) == 0 {
+			continue
+		}
+		set := func(x, y int, c rune) {
+			grid[y][x] = chartCell{char: c, series: si}
+		}
+
+		prev := row(vs[0])
+		set(0, prev, '─')
+		for i := 1; i < len(vs); i++ {
+			// 两个数据点之间线性插值
+			x0, x1 := col(i-1), col(i)
+			for x := x0 + 1; x <= x1; x++ {
+				t := float64(x-x0) / float64(x1-x0)
+				prev = drawChartStep(set, x, prev, row(vs[i-1]+(vs[i]-vs[i-1])*t))
+			}
+		}
+	}
+
+	// 纵轴标注：最大值、零和最小值
+	labels := make(map[int]string, 3)
+	labels[row(hi)] = formatChartValue(hi)
+	labels[row(lo)] = formatChartValue(lo)
+	if lo < 0 && hi > 0 {
+		labels[zero] = "0"
+	}
+	labelWidth := 0
+	for _, l := range labels {
+		labelWidth = max(labelWidth, len(l))
+	}
+
+	axis := lipgloss.NewStyle().Foreground(chartAxisColor)
+	var b strings.Builder
+	for y := range grid {
+		tick := "│"
+		if _, ok := labels[y]; ok {
+			tick = "┤"
+		}
+		b.WriteString(axis.Render(fmt.Sprintf("%*s %s", labelWidth, labels[y], tick)))
+		b.WriteString(renderChartRow(grid[y], series))
+		b.WriteString("\n")
+	}
+
+	// 横轴
+	pad := strings.Repeat(" ", labelWidth+1)
+	b.WriteString(axis.Render(pad + "└" + strings.Repeat("─", width)))
+	b.WriteString("\n")
+	gap := width + 1 - lipgloss.Width(xStart) - lipgloss.Width(xEnd)
+	b.WriteString(axis.Render(pad + xStart + strings.Repeat(" ", max(gap, 1)) + xEnd))
+
+	return b.String()
+}
+
+// resampleChart 数据点多于宽度时，每列取该列最后一个数据点
+func resampleChart(values []float64, width int) []float64 {
+	if len(values) <= width {
+		return values
+	}
+	out := make([]float64, width)
+	for x := range out {
+		out[x] = values[(x+1)*len(values)/width-1]
+	}
+	return out
+}
+
+// drawChartStep 从上一列的行 prev 连接到当前列的行 cur，返回当前行
+func drawChartStep(set func(x, y int, c rune), x, prev, cur int) int {
+	switch {
+	case cur == prev:
+		set(x, cur, '─')
+	case cur < prev:
+		// 上升
+		set(x, prev, '╯')
+		for y := cur + 1; y < prev; y++ {
+			set(x, y, '│')
+		}
+		set(x, cur, '╭')
+	default:
+		// 下降
+		set(x, prev, '╮')
+		for y := prev + 1; y < cur; y++ {
+			set(x, y, '│')
+		}
+		set(x, cur, '╰')
+	}
+	return cur
+}
+
+// renderChartRow 按曲线颜色渲染一行（相同颜色的连续字符合并渲染）
+func renderChartRow(cells []chartCell, series []ChartSeries) string {
+	var b strings.Builder
+	for start := 0; start < len(cells); {
+		end := start
+		var run strings.Builder
+		for end < len(cells) && cells[end].series == cells[start].series {
+			run.WriteRune(cells[end].char)
+			end++
+		}
+
+		switch owner := cells[start].series; {
+		case owner >= 0:
+			b.WriteString(lipgloss.NewStyle().Foreground(series[owner].Color).Render(run.String()))
+		case owner == -2:
+			b.WriteString(lipgloss.NewStyle().Foreground(chartZeroColor).Render(run.String()))
+		default:
+			b.WriteString(run.String())
+		}
+		start = end
+	}
+	return b.String()
+}
+
+// formatChartValue 格式化纵轴标注（大数值使用 k 单位）
+func formatChartValue(v float64) string {
+	if math.Abs(v) >= 10000 {
+		return fmt.Sprintf("%.1fk", v/1000)
+	}
+	if v == 0 {
+		return "0"
+	}
+	return fmt.Sprintf("%.0f", v)
+}
+
+// RenderChartLegend 渲染折线图图例
+func RenderChartLegend(series []ChartSeries) string {
+	items := make([]string, 0, len(series))
+	for _, s := range series {
+		mark := lipgloss.NewStyle().Foreground(s.Color).Render("━━")
+		items = append(items, mark+" "+s.Name)
+	}
+	return strings.Join(items, "   ")
+}
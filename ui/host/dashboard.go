@@ -0,0 +1,331 @@
+package host
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+	"github.com/wilenwang/just_play/Texas-Holdem/server/host"
+)
+
+// 仪表盘样式
+var (
+	stylePanel = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	styleWide  = stylePanel.Width(118) // 与上方牌桌和连接面板等宽
+	styleAlert = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF5555"))
+	styleOK    = lipgloss.NewStyle().Foreground(lipgloss.Color("#50FA7B"))
+)
+
+// 日志面板行数
+const (
+	logLinesCollapsed = 8
+	logLinesExpanded  = 25
+)
+
+// ==================== 面板渲染 ====================
+
+// renderHeader 渲染概况：阶段、暂停状态、运行时长、房主令牌
+func (m *Model) renderHeader() string {
+	stage := "等待开始"
+	if m.gameState != nil {
+		stage = m.gameState.Stage.String()
+	}
+
+	parts := []string{
+		styleSubtitle.Render("阶段: ") + styleActive.Render(stage),
+		styleSubtitle.Render("运行: ") + formatDuration(time.Since(m.startedAt)),
+		styleSubtitle.Render("已完成: ") + fmt.Sprintf("%d 局", m.stats.handsEnded),
+		styleSubtitle.Render("房主令牌: ") + styleInactive.Render(m.server.HostToken()),
+	}
+	if m.server.IsPaused() {
+		parts = append(parts, stylePot.Render("⏸ 已暂停"))
+	}
+	return strings.Join(parts, "   ")
+}
+
+// renderTable 渲染实时牌桌：公共牌、底池和玩家座位
+func (m *Model) renderTable() string {
+	var lines []string
+	lines = append(lines, styleSubtitle.Render("牌桌"))
+
+	if m.gameState == nil || len(m.gameState.Players) == 0 {
+		lines = append(lines, styleInactive.Render("等待玩家加入..."))
+		return stylePanel.Width(64).Render(strings.Join(lines, "\n"))
+	}
+
+	var cards []string
+	for _, c := range m.gameState.CommunityCards {
+		if c.Rank != 0 {
+			cards = append(cards, c.String())
+		}
+	}
+	board := "-"
+	if len(cards) > 0 {
+		board = strings.Join(cards, " ")
+	}
+	lines = append(lines, "公共牌: "+board)
+	lines = append(lines, stylePot.Render(fmt.Sprintf("底池: %d", m.gameState.Pot))+
+		fmt.Sprintf("   当前下注: %d", m.gameState.CurrentBet))
+	lines = append(lines, "")
+
+	stage := m.gameState.Stage
+	inHand := stage.IsBetting()
+	for i, p := range m.gameState.Players {
+		marker := "  "
+		if i == m.selectedPlayer {
+			marker = "▶ "
+		}
+		dealer := " "
+		if p.IsDealer {
+			dealer = "D"
+		}
+		turn := " "
+		if inHand && i == m.gameState.CurrentPlayer {
+			turn = "*"
+		}
+
+		line := fmt.Sprintf("%s%s%s 座位%d %-10s 筹码 %6d  下注 %5d  %s",
+			marker, dealer, turn, p.Seat+1, truncate(p.Name, 10), p.Chips, p.CurrentBet, p.Status)
+		if p.IsBot {
+			line += " [机器人]"
+		}
+		if p.SitOutPending {
+			line += " (待离座)"
+		}
+
+		switch {
+		case i == m.selectedPlayer:
+			line = styleActive.Render(line)
+		case p.Status == models.PlayerStatusFolded || p.Status == models.PlayerStatusSittingOut:
+			line = styleInactive.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "", styleInactive.Render("D=庄家  *=当前行动  ▶=管理目标"))
+
+	return stylePanel.Width(64).Render(strings.Join(lines, "\n"))
+}
+
+// renderClients 渲染连接中的客户端和延迟
+func (m *Model) renderClients() string {
+	lines := []string{styleSubtitle.Render(fmt.Sprintf("连接 (%d)", len(m.clients)))}
+	if len(m.clients) == 0 {
+		lines = append(lines, styleInactive.Render("无连接"))
+	}
+
+	for _, c := range m.clients {
+		name := c.Name
+		where := "未加入"
+		switch {
+		case c.Seated:
+			where = fmt.Sprintf("座位%d", c.Seat+1)
+		case c.Name != "":
+			where = "候补"
+		default:
+			name = c.ID
+		}
+
+		latency := "-"
+		if c.Latency > 0 {
+			latency = "<1ms"
+			if ms := c.Latency.Milliseconds(); ms > 0 {
+				latency = fmt.Sprintf("%dms", ms)
+			}
+		}
+		line := fmt.Sprintf("%-10s %-6s %-15s %6s %s",
+			truncate(name, 10), where, truncate(c.Addr, 15), latency, formatDuration(time.Since(c.ConnectedAt)))
+		if c.Latency > 500*time.Millisecond {
+			line = styleAlert.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	return stylePanel.Width(52).Render(strings.Join(lines, "\n"))
+}
+
+// renderChipCheck 渲染筹码守恒校验结果
+func (m *Model) renderChipCheck() string {
+	title := styleSubtitle.Render("筹码守恒")
+	var status string
+	if m.chipLeak {
+		status = styleAlert.Render(fmt.Sprintf("✗ 牌桌 %d ≠ 发放 %d (差 %+d)",
+			m.chipsInPlay, m.chipsIssued, m.chipsInPlay-m.chipsIssued))
+	} else {
+		status = styleOK.Render(fmt.Sprintf("✓ 牌桌 %d = 发放 %d", m.chipsInPlay, m.chipsIssued))
+	}
+	return stylePanel.Width(52).Render(title + "\n" + status)
+}
+
+// renderStats 渲染本次会话的玩家统计
+func (m *Model) renderStats() string {
+	lines := []string{styleSubtitle.Render("玩家统计（本次会话）")}
+
+	players := m.stats.sorted()
+	if len(players) == 0 {
+		lines = append(lines, styleInactive.Render("暂无数据"))
+		return styleWide.Render(strings.Join(lines, "\n"))
+	}
+
+	lines = append(lines, fmt.Sprintf("%-10s %5s %5s %6s %8s %8s %5s %5s %5s",
+		"玩家", "手数", "赢", "胜率", "盈亏", "最大赢池", "加注", "跟注", "弃牌"))
+	for _, p := range players {
+		winRate := "-"
+		if p.hands > 0 {
+			winRate = fmt.Sprintf("%.0f%%", float64(p.wins)*100/float64(p.hands))
+		}
+		line := fmt.Sprintf("%-10s %5d %5d %6s %+8d %8d %5d %5d %5d",
+			truncate(p.name, 10), p.hands, p.wins, winRate, p.net(), p.biggestPot, p.raises, p.calls, p.folds)
+		if p.net() < 0 {
+			line = styleInactive.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	return styleWide.Render(strings.Join(lines, "\n"))
+}
+
+// renderLog 渲染事件日志（PgUp/PgDn 滚动）
+func (m *Model) renderLog() string {
+	height := logLinesCollapsed
+	if m.showLog {
+		height = logLinesExpanded
+	}
+
+	end := len(m.logs) - m.logScroll
+	if end < 0 {
+		end = 0
+	}
+	start := end - height
+	if start < 0 {
+		start = 0
+	}
+
+	title := "事件日志"
+	if m.logScroll > 0 {
+		title += fmt.Sprintf("（向上 %d 行）", m.logScroll)
+	}
+	lines := []string{styleSubtitle.Render(title)}
+	lines = append(lines, m.logs[start:end]...)
+	for len(lines) <= height {
+		lines = append(lines, "")
+	}
+	return styleWide.Render(strings.Join(lines, "\n"))
+}
+
+// ==================== 会话统计 ====================
+
+// playerSessionStats 单个玩家的会话统计
+type playerSessionStats struct {
+	name       string
+	hands      int // 参与手数
+	wins       int // 赢得底池次数
+	biggestPot int // 单局最大赢得筹码
+	raises     int
+	calls      int
+	folds      int
+	firstChips int // 首次出现时的筹码
+	chips      int // 最新筹码
+}
+
+// net 会话盈亏
+func (p *playerSessionStats) net() int {
+	return p.chips - p.firstChips
+}
+
+// sessionStats 根据服务器事件流累计的会话统计（按玩家名称）
+type sessionStats struct {
+	players    map[string]*playerSessionStats
+	handsEnded int
+}
+
+// newSessionStats 创建会话统计
+func newSessionStats() *sessionStats {
+	return &sessionStats{players: make(map[string]*playerSessionStats)}
+}
+
+// player 获取或创建玩家统计
+func (s *sessionStats) player(name string, chips int) *playerSessionStats {
+	p, ok := s.players[name]
+	if !ok {
+		p = &playerSessionStats{name: name, firstChips: chips, chips: chips}
+		s.players[name] = p
+	}
+	return p
+}
+
+// apply 根据事件更新统计
+func (s *sessionStats) apply(ev host.ServerEvent) {
+	if ev.State != nil {
+		for _, pi := range ev.State.Players {
+			s.player(pi.Name, pi.Chips+pi.CurrentBet).chips = pi.Chips
+		}
+	}
+
+	switch ev.Kind {
+	case host.EventHandStarted:
+		for _, pi := range ev.State.Players {
+			if pi.Status == models.PlayerStatusActive || pi.Status == models.PlayerStatusAllIn {
+				s.player(pi.Name, pi.Chips).hands++
+			}
+		}
+
+	case host.EventAction:
+		p := s.player(ev.PlayerName, 0)
+		switch ev.Action {
+		case models.ActionRaise, models.ActionAllIn:
+			p.raises++
+		case models.ActionCall:
+			p.calls++
+		case models.ActionFold:
+			p.folds++
+		}
+
+	case host.EventHandEnded:
+		s.handsEnded++
+		for _, d := range ev.Showdown.AllPlayers {
+			p := s.player(d.PlayerName, d.ChipsAfter)
+			p.chips = d.ChipsAfter
+			if d.IsWinner {
+				p.wins++
+				if d.WonAmount > p.biggestPot {
+					p.biggestPot = d.WonAmount
+				}
+			}
+		}
+	}
+}
+
+// sorted 按盈亏从高到低返回玩家统计
+func (s *sessionStats) sorted() []*playerSessionStats {
+	list := make([]*playerSessionStats, 0, len(s.players))
+	for _, p := range s.players {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].net() != list[j].net() {
+			return list[i].net() > list[j].net()
+		}
+		return list[i].name < list[j].name
+	})
+	return list
+}
+
+// ==================== 工具函数 ====================
+
+// truncate 截断过长的字符串
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// formatDuration 格式化时长为 h:mm:ss
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
@@ -2,14 +2,17 @@ package host
 
 import (
 	"fmt"
-	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/wilenwang/just_play/Texas-Holdem/internal/protocol"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/bot"
 	"github.com/wilenwang/just_play/Texas-Holdem/server/host"
 )
 
@@ -25,34 +28,126 @@ var (
 	styleSelected = lipgloss.NewStyle().Background(lipgloss.Color("#FF79C6")).Foreground(lipgloss.Color("#F8F8F2")).Padding(0, 2)
 )
 
+// 菜单项索引
+const (
+	menuStart = iota
+	menuPause
+	menuKick
+	menuBan
+	menuChips
+	menuBlinds
+	menuBroadcast
+	menuEndSession
+	menuAddBot
+	menuRemoveBot
+	menuLog
+	menuQuit
+)
+
+// maxLogLines 事件日志保留的最大行数
+const maxLogLines = 500
+
 // Model TUI 模型
 type Model struct {
-	server       *host.Server
-	gameState    *protocol.GameState
-	selectedMenu int
-	menuItems    []string
-	width        int
-	height       int
-	err          error
+	server         *host.Server
+	gameState      *protocol.GameState
+	selectedMenu   int
+	selectedPlayer int // 当前选中的玩家索引（踢出/封禁/调整筹码的目标）
+	menuItems      []string
+	width          int
+	height         int
+	err            error
+
+	// 输入框（调整筹码、修改盲注、广播消息时使用）
+	inputMode   bool                      // 是否正在输入
+	inputPrompt string                    // 输入提示
+	input       string                    // 输入内容
+	inputCmd    protocol.AdminCommandType // 输入完成后执行的指令
+
+	// 服务器事件流
+	events      <-chan host.ServerEvent // 事件订阅通道
+	unsubscribe func()                  // 取消订阅
+
+	// 仪表盘数据
+	startedAt   time.Time         // 控制台启动时间
+	clients     []host.ClientInfo // 连接中的客户端
+	stats       *sessionStats     // 本次会话的玩家统计
+	chipsInPlay int               // 牌桌筹码总量
+	chipsIssued int               // 累计发放的筹码
+	chipLeak    bool              // 当前是否筹码不守恒
+
+	logs      []string // 事件日志
+	logScroll int      // 日志向上滚动的行数
+	showLog   bool     // 是否展开日志面板
 }
 
-// NewModel 创建新的 TUI 模型
+// refreshMsg 定时采集的仪表盘数据
+type refreshMsg struct {
+	state       *protocol.GameState
+	clients     []host.ClientInfo
+	chipsInPlay int
+	chipsIssued int
+}
+
+// eventMsg 服务器事件
+type eventMsg host.ServerEvent
+
+// NewModel 创建新的 TUI 模型（订阅服务器事件流）
 func NewModel(server *host.Server) *Model {
+	events, unsubscribe := server.Subscribe(256)
 	return &Model{
-		server:    server,
-		menuItems: []string{"开始游戏", "暂停游戏", "查看日志", "退出"},
+		server:      server,
+		menuItems:   []string{"开始游戏", "暂停/继续", "踢出玩家", "封禁玩家", "调整筹码", "修改盲注", "广播消息", "结束本场", "添加机器人", "移除机器人", "展开日志", "退出"},
+		events:      events,
+		unsubscribe: unsubscribe,
+		startedAt:   time.Now(),
+		stats:       newSessionStats(),
 	}
 }
 
 // Init 初始化
 func (m *Model) Init() tea.Cmd {
-	return nil
+	return tea.Batch(m.refresh(), m.waitEvent())
+}
+
+// refresh 采集仪表盘数据（在命令协程中执行，ClientInfos 需要服务器主循环响应）
+func (m *Model) refresh() tea.Cmd {
+	return func() tea.Msg {
+		inPlay, issued := m.server.ChipBalance()
+		return refreshMsg{
+			state:       m.server.Snapshot(),
+			clients:     m.server.ClientInfos(),
+			chipsInPlay: inPlay,
+			chipsIssued: issued,
+		}
+	}
+}
+
+// scheduleRefresh 一秒后再次采集
+func (m *Model) scheduleRefresh() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return m.refresh()()
+	})
+}
+
+// waitEvent 等待下一个服务器事件
+func (m *Model) waitEvent() tea.Cmd {
+	return func() tea.Msg {
+		ev, ok := <-m.events
+		if !ok {
+			return nil
+		}
+		return eventMsg(ev)
+	}
 }
 
 // Update 更新模型
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.inputMode {
+			return m.handleInputKey(msg)
+		}
 		return m.handleKeyMsg(msg)
 
 	case tea.WindowSizeMsg:
@@ -60,6 +155,16 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
+	case refreshMsg:
+		m.SetGameState(msg.state)
+		m.clients = msg.clients
+		m.checkChips(msg.chipsInPlay, msg.chipsIssued)
+		return m, m.scheduleRefresh()
+
+	case eventMsg:
+		m.handleEvent(host.ServerEvent(msg))
+		return m, m.waitEvent()
+
 	case error:
 		m.err = msg
 		return m, nil
@@ -72,18 +177,37 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "ctrl+c", "q":
+		m.unsubscribe()
 		return m, tea.Quit
 
-	case "up", "k":
+	case "pgup":
+		m.logScroll += 5
+		if max := len(m.logs) - 1; m.logScroll > max {
+			m.logScroll = max
+		}
+
+	case "pgdown":
+		m.logScroll -= 5
+		if m.logScroll < 0 {
+			m.logScroll = 0
+		}
+
+	case "up", "k", "left", "h":
 		if m.selectedMenu > 0 {
 			m.selectedMenu--
 		}
 
-	case "down", "j":
+	case "down", "j", "right", "l":
 		if m.selectedMenu < len(m.menuItems)-1 {
 			m.selectedMenu++
 		}
 
+	case "tab":
+		// 切换目标玩家
+		if m.gameState != nil && len(m.gameState.Players) > 0 {
+			m.selectedPlayer = (m.selectedPlayer + 1) % len(m.gameState.Players)
+		}
+
 	case "enter":
 		return m.handleMenuSelect()
 	}
@@ -94,118 +218,270 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 // handleMenuSelect 处理菜单选择
 func (m *Model) handleMenuSelect() (tea.Model, tea.Cmd) {
 	switch m.selectedMenu {
-	case 0: // 开始游戏
-		// TODO: 开始游戏逻辑
+	case menuStart: // 开始游戏
+		return m, m.execute(m.newCommand(protocol.AdminStart))
 
-	case 1: // 暂停游戏
-		// TODO: 暂停游戏逻辑
+	case menuPause: // 暂停/继续
+		if m.server.IsPaused() {
+			return m, m.execute(m.newCommand(protocol.AdminResume))
+		}
+		return m, m.execute(m.newCommand(protocol.AdminPause))
+
+	case menuKick, menuBan: // 踢出/封禁玩家
+		target := m.selectedTarget()
+		if target == nil {
+			m.addLog("✗ 没有可选择的玩家（Tab 切换目标）")
+			return m, nil
+		}
+		command := protocol.AdminKick
+		if m.selectedMenu == menuBan {
+			command = protocol.AdminBan
+		}
+		cmd := m.newCommand(command)
+		cmd.TargetID = target.ID
+		return m, m.execute(cmd)
+
+	case menuChips: // 调整筹码
+		target := m.selectedTarget()
+		if target == nil {
+			m.addLog("✗ 没有可选择的玩家（Tab 切换目标）")
+			return m, nil
+		}
+		m.startInput(protocol.AdminAdjustChips, fmt.Sprintf("调整 %s 的筹码（如 +500 或 -200）:", target.Name))
+
+	case menuBlinds: // 修改盲注
+		m.startInput(protocol.AdminSetBlinds, "新盲注（小盲 大盲 [前注]，如 20 40 5）:")
+
+	case menuBroadcast: // 广播消息
+		m.startInput(protocol.AdminBroadcast, "广播消息:")
 
-	case 2: // 查看日志
-		// TODO: 查看日志逻辑
+	case menuEndSession: // 结束本场并广播最终排名
+		return m, m.execute(m.newCommand(protocol.AdminEndSession))
 
-	case 3: // 退出
+	case menuAddBot: // 添加机器人
+		m.startInput(protocol.AdminAddBot, fmt.Sprintf("机器人策略 [思考毫秒]（可选: %s，留空使用默认）:", strings.Join(bot.StrategyNames(), ", ")))
+
+	case menuRemoveBot: // 移除选中的机器人
+		target := m.selectedTarget()
+		if target == nil || !target.IsBot {
+			m.addLog("✗ 请先用 Tab 选中一个机器人")
+			return m, nil
+		}
+		cmd := m.newCommand(protocol.AdminRemoveBot)
+		cmd.TargetID = target.ID
+		return m, m.execute(cmd)
+
+	case menuLog: // 展开/收起日志
+		m.showLog = !m.showLog
+
+	case menuQuit: // 退出
+		m.unsubscribe()
 		return m, tea.Quit
 	}
 
 	return m, nil
 }
 
-// View 渲染视图
-func (m *Model) View() string {
-	if m.err != nil {
-		return fmt.Sprintf("错误: %v\n", m.err)
+// handleInputKey 处理输入框按键
+func (m *Model) handleInputKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.inputMode = false
+		return m, nil
+
+	case tea.KeyEnter:
+		m.inputMode = false
+		cmd, err := m.buildInputCommand()
+		if err != nil {
+			m.addLog("✗ " + err.Error())
+			return m, nil
+		}
+		return m, m.execute(cmd)
+
+	case tea.KeyBackspace:
+		if runes := []rune(m.input); len(runes) > 0 {
+			m.input = string(runes[:len(runes)-1])
+		}
+
+	case tea.KeySpace:
+		m.input += " "
+
+	case tea.KeyRunes:
+		m.input += string(msg.Runes)
 	}
 
-	var content string
+	return m, nil
+}
 
-	// 标题
-	content += styleTitle.Render("Texas Hold'em Poker - 游戏控制台") + "\n\n"
+// startInput 打开输入框
+func (m *Model) startInput(command protocol.AdminCommandType, prompt string) {
+	m.inputMode = true
+	m.inputCmd = command
+	m.inputPrompt = prompt
+	m.input = ""
+}
 
-	// 游戏状态
-	content += m.renderGameState() + "\n"
+// buildInputCommand 根据输入内容构建管理指令
+func (m *Model) buildInputCommand() (*protocol.AdminCommand, error) {
+	cmd := m.newCommand(m.inputCmd)
+	input := strings.TrimSpace(m.input)
 
-	// 公共牌
-	content += m.renderCommunityCards() + "\n"
+	switch m.inputCmd {
+	case protocol.AdminAdjustChips:
+		target := m.selectedTarget()
+		if target == nil {
+			return nil, fmt.Errorf("目标玩家已离开")
+		}
+		amount, err := strconv.Atoi(strings.TrimPrefix(input, "+"))
+		if err != nil {
+			return nil, fmt.Errorf("无效的筹码数: %s", input)
+		}
+		cmd.TargetID = target.ID
+		cmd.Amount = amount
 
-	// 玩家列表
-	content += m.renderPlayers() + "\n"
+	case protocol.AdminSetBlinds:
+		fields := strings.Fields(input)
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("格式应为: 小盲 大盲 [前注]")
+		}
+		values := make([]int, len(fields))
+		for i, f := range fields {
+			v, err := strconv.Atoi(f)
+			if err != nil {
+				return nil, fmt.Errorf("无效的数字: %s", f)
+			}
+			values[i] = v
+		}
+		cmd.SmallBlind, cmd.BigBlind = values[0], values[1]
+		if len(values) == 3 {
+			cmd.Ante = values[2]
+		}
 
-	// 底池
-	content += m.renderPot() + "\n"
+	case protocol.AdminBroadcast:
+		cmd.Message = input
 
-	// 菜单
-	content += m.renderMenu()
+	case protocol.AdminAddBot:
+		fields := strings.Fields(input)
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("格式应为: 策略 [思考毫秒]")
+		}
+		if len(fields) > 0 {
+			cmd.Strategy = fields[0]
+		}
+		if len(fields) > 1 {
+			think, err := strconv.Atoi(fields[1])
+			if err != nil || think < 0 {
+				return nil, fmt.Errorf("无效的思考时间: %s", fields[1])
+			}
+			cmd.ThinkTime = think
+		}
+	}
 
-	return content
+	return cmd, nil
+}
+
+// newCommand 创建携带房主令牌的管理指令
+func (m *Model) newCommand(command protocol.AdminCommandType) *protocol.AdminCommand {
+	return protocol.NewAdminCommand(m.server.HostToken(), command)
 }
 
-// renderGameState 渲染游戏状态
-func (m *Model) renderGameState() string {
-	stage := "等待开始"
-	if m.gameState != nil {
-		stage = m.gameState.Stage.String()
+// execute 异步执行管理指令（指令在服务器主循环中执行，结果通过事件流写入日志）
+func (m *Model) execute(cmd *protocol.AdminCommand) tea.Cmd {
+	return func() tea.Msg {
+		m.server.ExecuteAdmin(cmd)
+		return nil
 	}
+}
 
-	return styleBox.Render(
-		lipgloss.JoinHorizontal(
-			lipgloss.Center,
-			styleSubtitle.Render("当前阶段: ")+styleActive.Render(stage),
-		),
-	)
+// selectedTarget 返回当前选中的玩家
+func (m *Model) selectedTarget() *protocol.PlayerInfo {
+	if m.gameState == nil || len(m.gameState.Players) == 0 {
+		return nil
+	}
+	if m.selectedPlayer >= len(m.gameState.Players) {
+		m.selectedPlayer = 0
+	}
+	return &m.gameState.Players[m.selectedPlayer]
 }
 
-// renderCommunityCards 渲染公共牌
-func (m *Model) renderCommunityCards() string {
-	cards := make([]string, 0)
+// handleEvent 处理服务器事件：更新状态和统计，写入事件日志
+func (m *Model) handleEvent(ev host.ServerEvent) {
+	if ev.State != nil {
+		m.SetGameState(ev.State)
+	}
+	m.stats.apply(ev)
 
-	if m.gameState != nil {
-		for _, c := range m.gameState.CommunityCards {
-			if c.Rank != 0 {
-				cards = append(cards, renderCard(c))
-			}
-		}
+	// 状态变化过于频繁，不写入日志
+	if ev.Kind == host.EventState || ev.Message == "" {
+		return
 	}
+	m.appendLog(ev.Time, ev.Message)
+}
 
-	if len(cards) == 0 {
-		cards = []string{"[  ?  ]", "[  ?  ]", "[  ?  ]"}
+// addLog 追加一条控制台本地日志
+func (m *Model) addLog(line string) {
+	m.appendLog(time.Now(), line)
+}
+
+// appendLog 追加日志行（滚动查看时保持当前视图位置）
+func (m *Model) appendLog(t time.Time, line string) {
+	m.logs = append(m.logs, t.Format("15:04:05")+" "+line)
+	if m.logScroll > 0 {
+		m.logScroll++
+	}
+	if len(m.logs) > maxLogLines {
+		m.logs = m.logs[len(m.logs)-maxLogLines:]
 	}
+}
 
-	return styleSubtitle.Render("公共牌: ") + lipgloss.JoinHorizontal(lipgloss.Center, cards...)
+// checkChips 校验筹码守恒，首次发现不守恒时记录日志
+func (m *Model) checkChips(inPlay, issued int) {
+	m.chipsInPlay, m.chipsIssued = inPlay, issued
+	leak := inPlay != issued
+	if leak && !m.chipLeak {
+		m.addLog(fmt.Sprintf("✗ 筹码不守恒: 牌桌 %d / 发放 %d (差 %+d)", inPlay, issued, inPlay-issued))
+	}
+	m.chipLeak = leak
 }
 
-// renderPlayers 渲染玩家列表
-func (m *Model) renderPlayers() string {
-	if m.gameState == nil || len(m.gameState.Players) == 0 {
-		return styleSubtitle.Render("等待玩家加入...") + "\n"
+// View 渲染视图
+func (m *Model) View() string {
+	if m.err != nil {
+		return fmt.Sprintf("错误: %v\n", m.err)
 	}
 
-	var players []string
-	for i, p := range m.gameState.Players {
-		status := p.Status.String()
+	var content string
+
+	// 标题与概况
+	content += styleTitle.Render("Texas Hold'em Poker - 房主控制台") + "\n"
+	content += m.renderHeader() + "\n"
 
-		var holeCards string
-		if p.HoleCards[0].Rank != 0 {
-			holeCards = renderCard(p.HoleCards[0]) + " " + renderCard(p.HoleCards[1])
-		} else {
-			holeCards = "[  ?  ] [  ?  ]"
-		}
+	// 牌桌 | 连接与筹码校验
+	content += lipgloss.JoinHorizontal(lipgloss.Top,
+		m.renderTable(),
+		lipgloss.JoinVertical(lipgloss.Left, m.renderClients(), m.renderChipCheck()),
+	) + "\n"
 
-		playerStr := fmt.Sprintf("%d. %s (座位%d) | 筹码: %d | 下注: %d | %s",
-			i+1, p.Name, p.Seat, p.Chips, p.CurrentBet, status)
-		players = append(players, playerStr)
-		players = append(players, "    "+holeCards)
+	// 玩家统计
+	if !m.showLog {
+		content += m.renderStats() + "\n"
 	}
 
-	return styleSubtitle.Render("玩家:") + "\n" + lipgloss.JoinVertical(lipgloss.Left, players...)
-}
+	// 事件日志
+	content += m.renderLog() + "\n"
+
+	// 菜单
+	content += m.renderMenu() + "\n"
 
-// renderPot 渲染底池
-func (m *Model) renderPot() string {
-	pot := 0
-	if m.gameState != nil {
-		pot = m.gameState.Pot
+	// 输入框
+	if m.inputMode {
+		content += "\n" + styleSubtitle.Render(m.inputPrompt) + " " + styleActive.Render(m.input+"█") + "\n"
+		content += styleInactive.Render("Enter 确认  Esc 取消") + "\n"
 	}
-	return stylePot.Render(fmt.Sprintf("底池: %d", pot))
+
+	content += "\n" + styleInactive.Render("[←/→] 选择菜单  [Tab] 切换目标玩家  [Enter] 执行  [PgUp/PgDn] 滚动日志  [Q] 退出")
+
+	return content
 }
 
 // renderMenu 渲染菜单
@@ -219,7 +495,7 @@ func (m *Model) renderMenu() string {
 		}
 	}
 
-	return "\n" + lipgloss.JoinHorizontal(lipgloss.Center, items...)
+	return lipgloss.JoinHorizontal(lipgloss.Center, items...)
 }
 
 // SetGameState 设置游戏状态
@@ -227,12 +503,8 @@ func (m *Model) SetGameState(state *protocol.GameState) {
 	m.gameState = state
 }
 
-// renderCard 渲染单张牌
-func renderCard(card interface{ String() string }) string {
-	return card.String()
-}
-
-// Start 启动 TUI
+// Start 启动房主控制台 TUI（阻塞直到退出）
+// 服务器主循环和 HTTP 服务由调用方启动
 func Start(server *host.Server) error {
 	model := NewModel(server)
 
@@ -252,16 +524,6 @@ func Start(server *host.Server) error {
 		p.Kill()
 	}()
 
-	// 启动 HTTP 服务器（用于 WebSocket 连接）
-	go func() {
-		http.HandleFunc("/ws", server.ServeHTTP)
-		addr := ":8080"
-		fmt.Printf("游戏服务器启动在 http://%s\n", addr)
-		if err := http.ListenAndServe(addr, nil); err != nil && err != http.ErrServerClosed {
-			fmt.Printf("HTTP 服务器错误: %v\n", err)
-		}
-	}()
-
 	// 运行 TUI
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("TUI 运行错误: %w", err)
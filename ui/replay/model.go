@@ -0,0 +1,270 @@
+package replay
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/card"
+	"github.com/wilenwang/just_play/Texas-Holdem/internal/common/models"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/game"
+	"github.com/wilenwang/just_play/Texas-Holdem/pkg/replay"
+	"github.com/wilenwang/just_play/Texas-Holdem/ui/components"
+)
+
+// 样式定义
+var (
+	styleTitle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF79C6")).MarginBottom(1)
+	styleSubtitle = lipgloss.NewStyle().Foreground(lipgloss.Color("#6272A4"))
+	styleBox      = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1, 2)
+	styleActive   = lipgloss.NewStyle().Foreground(lipgloss.Color("#50FA7B"))
+	styleInactive = lipgloss.NewStyle().Foreground(lipgloss.Color("#6272A4"))
+	stylePot      = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#F1FA8C"))
+	styleError    = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5555"))
+	styleCurrent  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8BE9FD"))
+)
+
+// maxActionLines 行动记录最多显示的行数
+const maxActionLines = 8
+
+// Model 手牌重放查看器
+type Model struct {
+	hands    []game.HandHistory // 可重放的手牌
+	index    int                // 当前手牌下标
+	replayer *replay.Replayer   // 当前手牌的重放器
+	verify   error              // 重放到最后一步后与记录的比对结果
+	err      error              // 创建重放器或执行行动失败
+}
+
+// NewModel 创建查看器，从第 index 手开始
+func NewModel(hands []game.HandHistory, index int) *Model {
+	m := &Model{hands: hands}
+	m.load(index)
+	return m
+}
+
+// Init 初始化
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+// load 切换到第 index 手牌
+func (m *Model) load(index int) {
+	if index < 0 || index >= len(m.hands) {
+		return
+	}
+	m.index = index
+	m.replayer, m.err = replay.New(m.hands[index])
+	m.verify = nil
+}
+
+// Update 更新模型
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch key.String() {
+	case "ctrl+c", "q", "esc":
+		return m, tea.Quit
+
+	case "n", "]", "pgdown":
+		m.load(m.index + 1)
+
+	case "p", "[", "pgup":
+		m.load(m.index - 1)
+	}
+
+	if m.replayer == nil {
+		return m, nil
+	}
+
+	var err error
+	switch key.String() {
+	case "right", "l", " ":
+		if m.replayer.Position() < m.replayer.Len() {
+			err = m.replayer.Forward()
+		}
+
+	case "left", "h":
+		if m.replayer.Position() > 0 {
+			err = m.replayer.Backward()
+		}
+
+	case "home", "g":
+		err = m.replayer.Seek(0)
+
+	case "end", "G":
+		err = m.replayer.Seek(m.replayer.Len())
+	}
+	if err != nil {
+		m.err = err
+	}
+
+	// 到达最后一步时与记录比对
+	if m.err == nil && m.verify == nil && m.replayer.Position() == m.replayer.Len() {
+		m.verify = m.replayer.Verify()
+	}
+
+	return m, nil
+}
+
+// View 渲染视图
+func (m *Model) View() string {
+	if len(m.hands) == 0 {
+		return styleBox.Render("没有可重放的手牌\n\n" + styleInactive.Render("[Q] 退出"))
+	}
+
+	hand := m.hands[m.index]
+	var content strings.Builder
+
+	content.WriteString(styleTitle.Render(fmt.Sprintf("手牌 #%d 重放  (%d/%d)", hand.HandID, m.index+1, len(m.hands))))
+	content.WriteString("\n")
+	content.WriteString(styleInactive.Render(fmt.Sprintf("%s  盲注 %d/%d  前注 %d",
+		hand.Timestamp.Format("2006-01-02 15:04:05"), hand.SmallBlind, hand.BigBlind, hand.Ante)))
+	content.WriteString("\n\n")
+
+	if m.replayer == nil {
+		content.WriteString(styleError.Render(fmt.Sprintf("无法重放: %v", m.err)))
+		content.WriteString("\n\n")
+		content.WriteString(styleInactive.Render("[N/P] 下一手/上一手  [Q] 退出"))
+		return styleBox.Render(content.String())
+	}
+
+	state := m.replayer.State()
+	content.WriteString(m.renderTable(state))
+	content.WriteString("\n")
+	content.WriteString(m.renderActions())
+
+	if m.replayer.Position() == m.replayer.Len() {
+		content.WriteString("\n")
+		content.WriteString(renderShowdown(state))
+		switch {
+		case m.verify != nil:
+			content.WriteString(styleError.Render(fmt.Sprintf("✗ %v", m.verify)))
+		case m.err == nil:
+			content.WriteString(styleActive.Render("✓ 重放结果与记录一致"))
+		}
+		content.WriteString("\n")
+	}
+	if m.err != nil {
+		content.WriteString(styleError.Render(fmt.Sprintf("错误: %v", m.err)))
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(styleInactive.Render("[←/→] 上一步/下一步  [Home/End] 开始/结果  [N/P] 下一手/上一手  [Q] 退出"))
+	return styleBox.Render(content.String())
+}
+
+// renderTable 渲染公共牌、底池和玩家
+func (m *Model) renderTable(state *game.GameState) string {
+	var content strings.Builder
+
+	content.WriteString(styleSubtitle.Render(fmt.Sprintf("%s  ", state.Stage)))
+	var board []card.Card
+	for _, c := range state.CommunityCards {
+		if c.Rank != 0 {
+			board = append(board, c)
+		}
+	}
+	if len(board) > 0 {
+		content.WriteString(components.RenderCardsCompact(board, true))
+	} else {
+		content.WriteString(styleInactive.Render("-"))
+	}
+	content.WriteString("\n")
+
+	pot := state.Pot
+	for _, sp := range state.SidePots {
+		pot += sp.Amount
+	}
+	content.WriteString(stylePot.Render(fmt.Sprintf("底池: %d", pot)))
+	content.WriteString(fmt.Sprintf("   当前下注: %d\n\n", state.CurrentBet))
+
+	inHand := state.Stage.IsBetting()
+	for i, p := range state.Players {
+		dealer := " "
+		if p.IsDealer {
+			dealer = "D"
+		}
+		turn := "  "
+		if inHand && i == state.CurrentPlayer {
+			turn = "▶ "
+		}
+		line := fmt.Sprintf("%s%s 座位%d %-10s 筹码 %6d  下注 %5d  %-4s ",
+			turn, dealer, p.Seat+1, p.Name, p.Chips, p.CurrentBet, p.Status)
+
+		switch {
+		case inHand && i == state.CurrentPlayer:
+			line = styleCurrent.Render(line)
+		case p.Status == models.PlayerStatusFolded:
+			line = styleInactive.Render(line)
+		}
+		content.WriteString(line)
+		if p.HoleCards[0].Rank != 0 {
+			content.WriteString(components.RenderCardsCompact(p.HoleCards[:], true))
+		}
+		content.WriteString("\n")
+	}
+	return content.String()
+}
+
+// renderActions 渲染已执行的行动
+func (m *Model) renderActions() string {
+	var content strings.Builder
+	pos := m.replayer.Position()
+	content.WriteString(styleSubtitle.Render(fmt.Sprintf("行动 (%d/%d)", pos, m.replayer.Len())))
+	content.WriteString("\n")
+
+	actions := m.replayer.Hand().Actions[:pos]
+	from := max(len(actions)-maxActionLines, 0)
+	for i := from; i < len(actions); i++ {
+		a := actions[i]
+		line := fmt.Sprintf("  [%s] %s %s", a.Round.ShortString(), a.PlayerName, a.Action)
+		if a.Amount > 0 {
+			line += fmt.Sprintf(" %d", a.Amount)
+		}
+		if i == len(actions)-1 {
+			line = styleActive.Render(line)
+		}
+		content.WriteString(line)
+		content.WriteString("\n")
+	}
+	return content.String()
+}
+
+// renderShowdown 渲染结算结果
+func renderShowdown(state *game.GameState) string {
+	if state.LastShowdown == nil {
+		return ""
+	}
+
+	var content strings.Builder
+	for _, r := range state.LastShowdown.Players {
+		if r.IsFolded {
+			continue
+		}
+		line := fmt.Sprintf("  %s", r.PlayerName)
+		if r.HandName != "" {
+			line += ": " + r.HandName
+		}
+		if r.WonAmount > 0 {
+			line = styleActive.Render(fmt.Sprintf("%s  赢得 %d", line, r.WonAmount))
+		}
+		content.WriteString(line)
+		content.WriteString("\n")
+	}
+	return content.String()
+}
+
+// Start 启动重放查看器（阻塞直到退出）
+func Start(hands []game.HandHistory, index int) error {
+	p := tea.NewProgram(NewModel(hands, index), tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("TUI 运行错误: %w", err)
+	}
+	return nil
+}